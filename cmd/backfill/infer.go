@@ -0,0 +1,145 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+)
+
+// supplierMatcher finds the supplier of an archived file by the names in its path
+type supplierMatcher struct {
+	normalizer *service.NameNormalizer
+	suppliers  []domain.Supplier
+}
+
+// newSupplierMatcher creates a matcher over suppliers
+func newSupplierMatcher(suppliers []domain.Supplier) *supplierMatcher {
+	return &supplierMatcher{
+		normalizer: service.NewNameNormalizer(),
+		suppliers:  suppliers,
+	}
+}
+
+// byName returns the supplier with name or alias equal to name, nil when none has
+func (m *supplierMatcher) byName(name string) *domain.Supplier {
+	for i := range m.suppliers {
+		for _, candidate := range supplierNames(&m.suppliers[i]) {
+			if m.normalizer.Equal(name, candidate) {
+				return &m.suppliers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// match returns the supplier of the outermost directory, or else the file name, that names
+// one. A component equal to a supplier name or alias wins; otherwise the supplier with the
+// longest name contained in the component, e.g. "皇家旅行社 2024年3月报价" for 皇家旅行社.
+func (m *supplierMatcher) match(relPath string) *domain.Supplier {
+	for _, component := range strings.Split(filepath.ToSlash(relPath), "/") {
+		component = strings.TrimSuffix(component, filepath.Ext(component))
+		if supplier := m.byName(component); supplier != nil {
+			return supplier
+		}
+
+		normalized := m.normalizer.Normalize(component)
+		var best *domain.Supplier
+		bestLength := 0
+		for i := range m.suppliers {
+			for _, candidate := range supplierNames(&m.suppliers[i]) {
+				name := m.normalizer.Normalize(candidate)
+				length := utf8.RuneCountInString(name)
+				// Single characters would match nearly any path
+				if length < 2 || length <= bestLength || !strings.Contains(normalized, name) {
+					continue
+				}
+				best, bestLength = &m.suppliers[i], length
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return nil
+}
+
+// supplierNames returns the name and aliases of a supplier
+func supplierNames(supplier *domain.Supplier) []string {
+	return append([]string{supplier.Name}, supplier.Aliases...)
+}
+
+var (
+	// 2024-03-15, 2024_03_15, 2024.3.15, 2024/03/15 (directories) or 2024年3月15日
+	fullDatePattern = regexp.MustCompile(`(?:^|\D)((?:19|20)\d{2})[-_./年](\d{1,2})[-_./月](\d{1,2})(?:\D|$)`)
+	// 20240315
+	compactDatePattern = regexp.MustCompile(`(?:^|\D)((?:19|20)\d{2})(\d{2})(\d{2})(?:\D|$)`)
+	// 2024-03 or 2024年3月, taken as the first of the month
+	monthPattern = regexp.MustCompile(`(?:^|\D)((?:19|20)\d{2})[-_./年](\d{1,2})(?:\D|$)`)
+)
+
+// inferQuoteDate returns the quote date stated by an archive path. The file name is searched
+// before its directories, and within each full dates before compact and month-only ones; of
+// several matches the last, i.e. most specific, is taken. Dates after today are not quote
+// dates but e.g. departure dates, and are ignored. It returns nil when the path has no date.
+func inferQuoteDate(relPath string, today time.Time) *time.Time {
+	slashed := filepath.ToSlash(relPath)
+	base := strings.TrimSuffix(filepath.Base(slashed), filepath.Ext(slashed))
+	dir := filepath.ToSlash(filepath.Dir(slashed))
+	if dir == "." {
+		dir = ""
+	}
+
+	for _, text := range []string{base, dir} {
+		for _, pattern := range []*regexp.Regexp{fullDatePattern, compactDatePattern, monthPattern} {
+			matches := pattern.FindAllStringSubmatch(text, -1)
+			for i := len(matches) - 1; i >= 0; i-- {
+				if date, ok := matchedDate(matches[i]); ok && !date.After(today) {
+					return &date
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchedDate builds the date of a pattern match of year, month and optionally day
+func matchedDate(match []string) (time.Time, bool) {
+	year, _ := strconv.Atoi(match[1])
+	month, _ := strconv.Atoi(match[2])
+	day := 1
+	if len(match) > 3 {
+		day, _ = strconv.Atoi(match[3])
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// Reject out of range parts, which time.Date would normalize, e.g. 2024-02-30
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// sortByQuoteDate orders archive paths by the quote date inferQuoteDate gives them, oldest
+// first, so the price history is built up in order. Undated paths come last; paths of the
+// same date keep their order.
+func sortByQuoteDate(relPaths []string, today time.Time) {
+	dates := make(map[string]*time.Time, len(relPaths))
+	for _, relPath := range relPaths {
+		dates[relPath] = inferQuoteDate(relPath, today)
+	}
+
+	sort.SliceStable(relPaths, func(i, j int) bool {
+		a, b := dates[relPaths[i]], dates[relPaths[j]]
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortByQuoteDate(t *testing.T) {
+	today := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	files := []string{
+		"a/2024-03-15 报价.pdf",
+		"a/说明.docx",
+		"b/2023-11-02 报价.xlsx",
+		"b/2024/20240105.pdf",
+		"c/2024-03-15 价格表.pdf",
+	}
+
+	sortByQuoteDate(files, today)
+
+	want := []string{
+		"b/2023-11-02 报价.xlsx",
+		"b/2024/20240105.pdf",
+		"a/2024-03-15 报价.pdf",
+		"c/2024-03-15 价格表.pdf",
+		"a/说明.docx",
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Fatalf("sortByQuoteDate() = %v, want %v", files, want)
+		}
+	}
+}
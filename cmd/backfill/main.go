@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"cruise-price-compare/internal/app"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// backfill imports an archive of supplier price lists (PDF, Word and Excel files) as import
+// jobs with their original dates. The supplier and quote date of each file are inferred from
+// its path, e.g. archive/皇家旅行社/2024/2024-03-15 报价.pdf; a quote date stated in the
+// document itself still wins when the job is processed. Files already imported, by content
+// hash, are skipped, so the archive can be walked again after adding files. Files are imported
+// oldest quote date first, undated ones last, so the price history is built up in order.
+// Backfilled quotes are history: they raise no price alerts, anomaly checks or webhooks.
+//
+// Jobs are left PENDING for the worker unless -process is given. With -dry-run nothing is
+// stored: each file is parsed and the outcome reported, which works offline with -llm fake.
+func main() {
+	// Parse flags
+	dir := flag.String("dir", "", "Archive directory to walk")
+	userID := flag.Uint64("user", 0, "ID of the admin user that creates the jobs (not needed with -dry-run)")
+	supplierName := flag.String("supplier", "", "Supplier name or alias of all files (default: inferred from each path)")
+	llmProvider := flag.String("llm", "", "LLM provider: ollama or fake (default: LLM_PROVIDER)")
+	dryRun := flag.Bool("dry-run", false, "Parse and report the files without creating jobs")
+	process := flag.Bool("process", false, "Process each job right away instead of leaving it to the worker")
+	retryFailed := flag.Bool("retry-failed", false, "Import again files whose last import job failed")
+	allowUndated := flag.Bool("allow-undated", false, "Import files without a date in their path, dated by their content or else today")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("-dir is required")
+	}
+	if !*dryRun && *userID == 0 {
+		log.Fatal("-user is required unless -dry-run is given")
+	}
+
+	config := app.LoadConfigFromEnv()
+	if *llmProvider != "" {
+		config.LLMProvider = *llmProvider
+	}
+
+	container, err := app.NewContainer(config)
+	if err != nil {
+		log.Fatalf("Failed to create container: %v", err)
+	}
+	defer container.Close()
+
+	// Stop between files on interrupt, still printing the summary
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !*dryRun {
+		user, err := container.UserRepo.GetByID(ctx, *userID)
+		if err != nil {
+			log.Fatalf("Failed to get user: %v", err)
+		}
+		if user == nil {
+			log.Fatalf("User %d not found", *userID)
+		}
+		if !user.IsAdmin() {
+			log.Fatalf("User %d is not an admin", *userID)
+		}
+	}
+
+	suppliers, err := container.SupplierRepo.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list suppliers: %v", err)
+	}
+	matcher := newSupplierMatcher(suppliers)
+
+	var fixedSupplier *domain.Supplier
+	if *supplierName != "" {
+		if fixedSupplier = matcher.byName(*supplierName); fixedSupplier == nil {
+			log.Fatalf("Supplier %q not found", *supplierName)
+		}
+	}
+
+	files, unsupported, err := walkArchive(*dir)
+	if err != nil {
+		log.Fatalf("Failed to walk %s: %v", *dir, err)
+	}
+	today := time.Now()
+	sortByQuoteDate(files, today)
+
+	b := &backfill{
+		imports:      container.ImportJobService,
+		matcher:      matcher,
+		supplier:     fixedSupplier,
+		userID:       *userID,
+		dryRun:       *dryRun,
+		process:      *process,
+		retryFailed:  *retryFailed,
+		allowUndated: *allowUndated,
+		today:        today,
+		seen:         make(map[string]string),
+		report:       backfillReport{Found: len(files), Unsupported: unsupported, PerSupplier: make(map[string]int)},
+	}
+
+	mode := "import"
+	if *dryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("Backfilling %d files from %s (%s, LLM provider %s)\n", len(files), *dir, mode, config.LLMProvider)
+
+	for i, relPath := range files {
+		if ctx.Err() != nil {
+			fmt.Println("Interrupted")
+			break
+		}
+		outcome := b.importFile(ctx, *dir, relPath)
+		fmt.Printf("[%d/%d] %s: %s\n", i+1, len(files), relPath, outcome)
+	}
+
+	b.report.print(*dryRun)
+}
+
+// walkArchive returns the paths, relative to dir and sorted, of the files import jobs can
+// parse, and how many other files it skipped. Hidden files and directories and Office lock
+// files (~$name) are ignored.
+func walkArchive(dir string) ([]string, int, error) {
+	var files []string
+	unsupported := 0
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if path != dir && strings.HasPrefix(name, ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || strings.HasPrefix(name, "~$") {
+			return nil
+		}
+		if !service.IsSupportedImportFile(name) {
+			unsupported++
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort.Strings(files)
+	return files, unsupported, nil
+}
+
+// backfill imports archived files one at a time
+type backfill struct {
+	imports      *service.ImportJobService
+	matcher      *supplierMatcher
+	supplier     *domain.Supplier // nil = inferred per file
+	userID       uint64
+	dryRun       bool
+	process      bool
+	retryFailed  bool
+	allowUndated bool
+	today        time.Time
+
+	seen   map[string]string // file hash -> path, for copies within the archive
+	report backfillReport
+}
+
+// importFile creates, and with -process runs, the import job of one file, or with -dry-run
+// parses it, and returns the outcome for the progress line
+func (b *backfill) importFile(ctx context.Context, dir, relPath string) string {
+	supplier := b.supplier
+	if supplier == nil {
+		supplier = b.matcher.match(relPath)
+	}
+	if supplier == nil {
+		b.report.NoSupplier++
+		return "skipped, no supplier in path"
+	}
+
+	quotedAt := inferQuoteDate(relPath, b.today)
+	if quotedAt == nil && !b.allowUndated {
+		b.report.NoDate++
+		return "skipped, no date in path"
+	}
+	dated := "undated"
+	if quotedAt != nil {
+		dated = quotedAt.Format("2006-01-02")
+	}
+
+	path := filepath.Join(dir, relPath)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		b.report.Errors++
+		return fmt.Sprintf("error, %v", err)
+	}
+
+	hash := service.FileHash(content)
+	if other, ok := b.seen[hash]; ok {
+		b.report.Duplicates++
+		return fmt.Sprintf("skipped, same file as %s", other)
+	}
+	b.seen[hash] = relPath
+
+	existing, err := b.imports.GetLatestJobByFileHash(ctx, hash)
+	if err != nil {
+		b.report.Errors++
+		return fmt.Sprintf("error, %v", err)
+	}
+	if existing != nil && !(b.retryFailed && existing.Status == domain.ImportJobStatusFailed) {
+		b.report.Duplicates++
+		return fmt.Sprintf("skipped, already imported as job %d (%s)", existing.ID, existing.Status)
+	}
+
+	b.report.PerSupplier[supplier.Name]++
+
+	if b.dryRun {
+		result, err := b.imports.ParseFile(ctx, relPath, path)
+		if err != nil {
+			b.report.Failed++
+			return fmt.Sprintf("%s, %s, parse failed: %v", supplier.Name, dated, err)
+		}
+		b.report.Succeeded++
+		b.report.Quotes += len(result.Quotes)
+		if result.QuotedAt != "" {
+			dated = fmt.Sprintf("%s (document: %s)", dated, result.QuotedAt)
+		}
+		return fmt.Sprintf("%s, %s, would import %d quotes for %s %s on %s",
+			supplier.Name, dated, len(result.Quotes), result.ShipName, result.SailingCode, result.DepartureDate)
+	}
+
+	job, err := b.imports.CreateImportJob(ctx, service.CreateImportJobInput{
+		FileName:       filepath.Base(relPath),
+		FileContent:    content,
+		UserID:         b.userID,
+		SupplierID:     supplier.ID,
+		QuotedAt:       quotedAt,
+		Backfill:       true,
+		IdempotencyKey: uuid.New().String(),
+	})
+	if err != nil {
+		b.report.Errors++
+		return fmt.Sprintf("error, %v", err)
+	}
+	b.report.Imported++
+
+	if !b.process {
+		return fmt.Sprintf("%s, %s, created job %d", supplier.Name, dated, job.ID)
+	}
+
+	// The job records the failure itself; reload it for the outcome either way
+	processErr := b.imports.ProcessImportJob(ctx, job.ID)
+	processed, err := b.imports.GetJob(ctx, job.ID, b.userID, domain.UserRoleAdmin, 0)
+	if err != nil || processed == nil || processed.Status != domain.ImportJobStatusSucceeded {
+		b.report.Failed++
+		return fmt.Sprintf("%s, %s, job %d failed: %v", supplier.Name, dated, job.ID, processErr)
+	}
+
+	b.report.Succeeded++
+	outcome := fmt.Sprintf("%s, %s, job %d succeeded", supplier.Name, dated, job.ID)
+	if summary := processed.ResultSummary; summary != nil {
+		b.report.Quotes += summary.CreatedQuotes
+		b.report.Confirmed += summary.ConfirmedQuotes
+		outcome += fmt.Sprintf(", %d quotes created, %d confirmed, %d skipped",
+			summary.CreatedQuotes, summary.ConfirmedQuotes, summary.SkippedRows)
+	}
+	return outcome
+}
+
+// backfillReport counts the outcomes of a backfill
+type backfillReport struct {
+	Found       int
+	Unsupported int
+	NoSupplier  int
+	NoDate      int
+	Duplicates  int
+	Errors      int
+	Imported    int // jobs created
+	Succeeded   int // jobs processed successfully, or files parsed in a dry run
+	Failed      int // jobs processed unsuccessfully, or parse failures in a dry run
+	Quotes      int // quotes created, or parsed in a dry run
+	Confirmed   int
+	PerSupplier map[string]int
+}
+
+// print writes the summary of the backfill to stdout
+func (r *backfillReport) print(dryRun bool) {
+	fmt.Println()
+	fmt.Println("Backfill summary")
+	fmt.Printf("  Files found:          %d (%d other files ignored)\n", r.Found, r.Unsupported)
+	fmt.Printf("  No supplier in path:  %d\n", r.NoSupplier)
+	fmt.Printf("  No date in path:      %d\n", r.NoDate)
+	fmt.Printf("  Duplicate files:      %d\n", r.Duplicates)
+	fmt.Printf("  Errors:               %d\n", r.Errors)
+	if dryRun {
+		fmt.Printf("  Parsed:               %d\n", r.Succeeded)
+		fmt.Printf("  Parse failures:       %d\n", r.Failed)
+		fmt.Printf("  Quotes parsed:        %d\n", r.Quotes)
+	} else {
+		fmt.Printf("  Jobs created:         %d\n", r.Imported)
+		if r.Succeeded+r.Failed > 0 {
+			fmt.Printf("  Jobs succeeded:       %d\n", r.Succeeded)
+			fmt.Printf("  Jobs failed:          %d\n", r.Failed)
+			fmt.Printf("  Quotes created:       %d (%d confirmed unchanged)\n", r.Quotes, r.Confirmed)
+		}
+	}
+
+	names := make([]string, 0, len(r.PerSupplier))
+	for name := range r.PerSupplier {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		fmt.Println("  Files per supplier:")
+		for _, name := range names {
+			fmt.Printf("    %s: %d\n", name, r.PerSupplier[name])
+		}
+	}
+}
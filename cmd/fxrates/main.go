@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+	"cruise-price-compare/internal/service"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// fxrates loads exchange rates from a local CSV (date,base,quote,rate) or
+// ECB-style XML file (e.g. eurofxref-hist.xml) into the fx_rate table.
+func main() {
+	// Parse flags
+	file := flag.String("file", "", "Rate file (.csv or ECB .xml)")
+	format := flag.String("format", "", "File format: csv or ecb_xml (default: from file extension)")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+
+	fileFormat := *format
+	if fileFormat == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			fileFormat = service.FXFileFormatCSV
+		case ".xml":
+			fileFormat = service.FXFileFormatECBXML
+		default:
+			log.Fatalf("Cannot infer format of %s, use -format", *file)
+		}
+	}
+
+	port, err := strconv.Atoi(getEnvOrDefault("DB_PORT", "3306"))
+	if err != nil {
+		log.Fatalf("Invalid DB_PORT: %v", err)
+	}
+
+	// Initialize database
+	db, err := repo.NewDB(repo.Config{
+		Host:     getEnvOrDefault("DB_HOST", "localhost"),
+		Port:     port,
+		User:     getEnvOrDefault("DB_USER", "root"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Database: getEnvOrDefault("DB_NAME", "cruise_price"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	logger := obs.NewLogger(obs.LogConfig{
+		Level:  obs.LogLevelInfo,
+		Format: "text",
+	})
+	auditService := obs.NewAuditService(repo.NewAuditLogRepository(db), logger)
+	fxService := service.NewFXService(repo.NewFXRateRepository(db), auditService, logger)
+
+	result, err := fxService.ImportFile(context.Background(), *file, fileFormat, nil)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if result.FromDate != nil && result.ToDate != nil {
+		log.Printf("Imported %d rates for %s from %s to %s", result.Imported, strings.Join(result.Currencies, ", "),
+			result.FromDate.Format("2006-01-02"), result.ToDate.Format("2006-01-02"))
+	}
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
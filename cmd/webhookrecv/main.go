@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"cruise-price-compare/internal/service"
+)
+
+// webhookrecv is a local webhook receiver for development. It verifies the
+// signature of every request, prints the event and answers with -status, so
+// retries can be exercised by answering with a 5xx status.
+func main() {
+	// Parse flags
+	addr := flag.String("addr", ":9090", "Listen address")
+	secret := flag.String("secret", "", "Signing secret of the webhook endpoint")
+	status := flag.Int("status", http.StatusOK, "HTTP status to answer with")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "Accepted clock skew of the signature timestamp (0 disables the check)")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("-secret is required")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		err = service.VerifyWebhookSignature(*secret,
+			r.Header.Get(service.WebhookHeaderTimestamp),
+			r.Header.Get(service.WebhookHeaderSignature),
+			body, *tolerance, time.Now())
+		if err != nil {
+			log.Printf("Rejected delivery %s: %v", r.Header.Get(service.WebhookHeaderDelivery), err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("Received %s (event %s, delivery %s), answering %d\n%s",
+			r.Header.Get(service.WebhookHeaderEvent),
+			r.Header.Get(service.WebhookHeaderEventID),
+			r.Header.Get(service.WebhookHeaderDelivery),
+			*status, pretty.String())
+
+		w.WriteHeader(*status)
+	})
+
+	log.Printf("Listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatalf("Receiver stopped: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/service"
+)
+
+// webhookSubscriber is the outbox cursor name of the webhook service
+const webhookSubscriber = "webhooks"
+
+// EventDispatcher periodically hands new outbox events to the in-process subscribers
+type EventDispatcher struct {
+	service  *service.EventService
+	logger   *obs.Logger
+	interval time.Duration
+}
+
+// NewEventDispatcher creates a new event dispatcher
+func NewEventDispatcher(service *service.EventService, logger *obs.Logger, interval time.Duration) *EventDispatcher {
+	return &EventDispatcher{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run dispatches on every tick until the context is cancelled
+func (d *EventDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Event dispatcher stopping...")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch hands out events until the subscribers have caught up, so a backlog or a
+// replay drains without waiting for further ticks
+func (d *EventDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		startTime := time.Now()
+
+		moved, err := d.service.Dispatch(ctx)
+		if err != nil {
+			d.logger.WithField("events", moved).WithError(err).Error("Event dispatch failed")
+			return
+		}
+		if moved == 0 {
+			return
+		}
+
+		d.logger.WithField("events", moved).
+			WithField("duration_ms", time.Since(startTime).Milliseconds()).
+			Info("Dispatched domain events")
+	}
+}
+
+// webhookEventTypes lists the outbox event types the webhook service subscribes to
+func webhookEventTypes() []string {
+	types := make([]string, len(domain.WebhookEventTypes))
+	for i, t := range domain.WebhookEventTypes {
+		types[i] = string(t)
+	}
+	return types
+}
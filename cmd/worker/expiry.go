@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/service"
+)
+
+// ExpirySweeper periodically moves quotes past their valid_until date to EXPIRED
+type ExpirySweeper struct {
+	service  *service.QuoteService
+	logger   *obs.Logger
+	interval time.Duration
+}
+
+// NewExpirySweeper creates a new expiry sweeper
+func NewExpirySweeper(service *service.QuoteService, logger *obs.Logger, interval time.Duration) *ExpirySweeper {
+	return &ExpirySweeper{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run sweeps once at start and then on every tick until the context is cancelled
+func (s *ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Expiry sweeper stopping...")
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// sweep expires the quotes past their validity date
+func (s *ExpirySweeper) sweep(ctx context.Context) {
+	startTime := time.Now()
+
+	expired, err := s.service.ExpireQuotes(ctx, startTime)
+	if err != nil {
+		s.logger.WithField("expired", expired).WithError(err).Error("Quote expiry sweep failed")
+		return
+	}
+
+	if expired > 0 {
+		s.logger.WithField("expired", expired).
+			WithField("duration_ms", time.Since(startTime).Milliseconds()).
+			Info("Expired quotes past valid_until")
+	}
+}
@@ -34,12 +34,77 @@ func main() {
 		ollamaModel = "llama2"
 	}
 
+	llmProvider := os.Getenv("LLM_PROVIDER")
+	if llmProvider == "" {
+		llmProvider = llm.LLMProviderOllama
+	}
+
+	embeddingProvider := os.Getenv("EMBEDDING_PROVIDER")
+	if embeddingProvider == "" {
+		embeddingProvider = llm.EmbeddingProviderOllama
+	}
+
+	embeddingModel := os.Getenv("OLLAMA_EMBED_MODEL")
+	if embeddingModel == "" {
+		embeddingModel = "bge-m3"
+	}
+
 	uploadDir := os.Getenv("UPLOAD_DIR")
 	if uploadDir == "" {
 		uploadDir = "./uploads"
 	}
 
 	pollInterval := 5 * time.Second
+
+	expiryInterval := time.Hour
+	if v := os.Getenv("QUOTE_EXPIRY_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid QUOTE_EXPIRY_INTERVAL: %q", v)
+		}
+		expiryInterval = d
+	}
+
+	webhookInterval := 10 * time.Second
+	if v := os.Getenv("WEBHOOK_DELIVERY_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid WEBHOOK_DELIVERY_INTERVAL: %q", v)
+		}
+		webhookInterval = d
+	}
+
+	eventInterval := 2 * time.Second
+	if v := os.Getenv("EVENT_DISPATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid EVENT_DISPATCH_INTERVAL: %q", v)
+		}
+		eventInterval = d
+	}
+
+	rfqReminderInterval := 15 * time.Minute
+	if v := os.Getenv("RFQ_REMINDER_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid RFQ_REMINDER_INTERVAL: %q", v)
+		}
+		rfqReminderInterval = d
+	}
+
+	reportDir := os.Getenv("REPORT_DIR")
+	if reportDir == "" {
+		reportDir = "./reports"
+	}
+
+	reportInterval := time.Hour
+	if v := os.Getenv("SUPPLIER_REPORT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid SUPPLIER_REPORT_INTERVAL: %q", v)
+		}
+		reportInterval = d
+	}
 	maxConcurrent := 1 // Process one job at a time
 
 	// Initialize database
@@ -68,40 +133,132 @@ func main() {
 	cabinTypeRepo := repo.NewCabinTypeRepository(db)
 	shipRepo := repo.NewShipRepository(db)
 	cruiseLineRepo := repo.NewCruiseLineRepository(db)
+	cabinCategoryRepo := repo.NewCabinCategoryRepository(db)
+	portRepo := repo.NewPortRepository(db)
 	supplierRepo := repo.NewSupplierRepository(db)
 	auditRepo := repo.NewAuditLogRepository(db)
+	embeddingRepo := repo.NewCatalogEmbeddingRepository(db)
+	userRepo := repo.NewUserRepository(db)
+	fxRateRepo := repo.NewFXRateRepository(db)
+	alertRuleRepo := repo.NewAlertRuleRepository(db)
+	priceAlertRepo := repo.NewPriceAlertRepository(db)
+	webhookEndpointRepo := repo.NewWebhookEndpointRepository(db)
+	webhookDeliveryRepo := repo.NewWebhookDeliveryRepository(db)
+	domainEventRepo := repo.NewDomainEventRepository(db)
+	reviewFlagRepo := repo.NewQuoteReviewFlagRepository(db)
+	rfqRepo := repo.NewRFQRepository(db)
 
 	// Initialize services
 	fileStorage := service.NewFileStorageService(uploadDir)
-	ollamaClient := llm.NewOllamaClient(ollamaURL, ollamaModel)
 	auditService := obs.NewAuditService(auditRepo, logger)
 
+	generator, err := llm.NewGenerator(llmProvider, ollamaURL, ollamaModel)
+	if err != nil {
+		log.Fatalf("Failed to create LLM generator: %v", err)
+	}
+
+	embedder, err := llm.NewEmbedder(embeddingProvider, ollamaURL, embeddingModel)
+	if err != nil {
+		log.Fatalf("Failed to create embedder: %v", err)
+	}
+	embeddingService := service.NewEmbeddingIndexService(
+		embeddingRepo,
+		cabinTypeRepo,
+		shipRepo,
+		cabinCategoryRepo,
+		embedder,
+		logger,
+	)
+
 	dataMatcher := service.NewDataMatcher(
 		shipRepo,
 		sailingRepo,
 		cabinTypeRepo,
 		cruiseLineRepo,
+		cabinCategoryRepo,
+		portRepo,
+		embeddingService,
+	)
+
+	fxService := service.NewFXService(fxRateRepo, auditService, logger)
+	alertService := service.NewAlertService(
+		alertRuleRepo,
+		priceAlertRepo,
+		quoteRepo,
+		sailingRepo,
+		shipRepo,
+		cabinTypeRepo,
+		cabinCategoryRepo,
+		supplierRepo,
+		userRepo,
+		fxService,
+		auditService,
+		logger,
+	)
+
+	anomalyService := service.NewAnomalyService(
+		reviewFlagRepo,
+		quoteRepo,
+		cabinTypeRepo,
+		cabinCategoryRepo,
+		fxService,
+		auditService,
+		logger,
+	)
+
+	supplierMetricsService := service.NewSupplierMetricsService(
+		quoteRepo,
+		jobRepo,
+		sailingRepo,
+		supplierRepo,
+		fxService,
 	)
 
+	webhookService := service.NewWebhookService(webhookEndpointRepo, webhookDeliveryRepo, userRepo, auditService, logger)
+
+	// Outbox subscribers; each keeps its own cursor, a new one replays the whole history
+	eventService := service.NewEventService(domainEventRepo, logger)
+	eventService.Subscribe(webhookSubscriber, webhookEventTypes(), webhookService.HandleDomainEvent)
+
 	quoteService := service.NewQuoteService(
 		quoteRepo,
 		sailingRepo,
 		cabinTypeRepo,
 		supplierRepo,
+		alertService,
+		anomalyService,
+		auditService,
+	)
+
+	rfqService := service.NewRFQService(
+		rfqRepo,
+		quoteRepo,
+		sailingRepo,
+		shipRepo,
+		cruiseLineRepo,
+		cabinTypeRepo,
+		supplierRepo,
+		quoteService,
+		fxService,
 		auditService,
 	)
 
 	importJobService := service.NewImportJobService(
 		jobRepo,
 		fileStorage,
-		ollamaClient,
+		generator,
 		dataMatcher,
 		quoteService,
 		auditService,
 	)
 
-	// Create worker
+	// Create worker, quote expiry sweeper, RFQ reminder, supplier reporter, event dispatcher and webhook dispatcher
 	worker := NewWorker(importJobService, logger, pollInterval, maxConcurrent)
+	sweeper := NewExpirySweeper(quoteService, logger, expiryInterval)
+	rfqReminder := NewRFQReminder(rfqService, logger, rfqReminderInterval)
+	reporter := NewSupplierReporter(supplierMetricsService, logger, reportDir, reportInterval)
+	eventDispatcher := NewEventDispatcher(eventService, logger, eventInterval)
+	dispatcher := NewWebhookDispatcher(webhookService, logger, webhookInterval)
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -120,6 +277,21 @@ func main() {
 	logger.Info("Starting import job worker...")
 	logger.Info(fmt.Sprintf("Poll interval: %v, Max concurrent: %d", pollInterval, maxConcurrent))
 
+	logger.Info(fmt.Sprintf("Quote expiry interval: %v", expiryInterval))
+	go sweeper.Run(ctx)
+
+	logger.Info(fmt.Sprintf("RFQ reminder interval: %v", rfqReminderInterval))
+	go rfqReminder.Run(ctx)
+
+	logger.Info(fmt.Sprintf("Supplier report interval: %v, directory: %s", reportInterval, reportDir))
+	go reporter.Run(ctx)
+
+	logger.Info(fmt.Sprintf("Event dispatch interval: %v", eventInterval))
+	go eventDispatcher.Run(ctx)
+
+	logger.Info(fmt.Sprintf("Webhook delivery interval: %v", webhookInterval))
+	go dispatcher.Run(ctx)
+
 	if err := worker.Run(ctx); err != nil {
 		logger.WithError(err).Error("Worker stopped with error")
 		os.Exit(1)
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/service"
+)
+
+// RFQReminder periodically reminds suppliers of open RFQs nearing their deadline
+type RFQReminder struct {
+	service  *service.RFQService
+	logger   *obs.Logger
+	interval time.Duration
+}
+
+// NewRFQReminder creates a new RFQ reminder
+func NewRFQReminder(service *service.RFQService, logger *obs.Logger, interval time.Duration) *RFQReminder {
+	return &RFQReminder{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run sends due reminders once at start and then on every tick until the context is cancelled
+func (r *RFQReminder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.remind(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("RFQ reminder stopping...")
+			return
+		case <-ticker.C:
+			r.remind(ctx)
+		}
+	}
+}
+
+// remind sends the deadline reminders that are due
+func (r *RFQReminder) remind(ctx context.Context) {
+	startTime := time.Now()
+
+	sent, err := r.service.SendReminders(ctx, startTime)
+	if err != nil {
+		r.logger.WithField("sent", sent).WithError(err).Error("RFQ reminder run failed")
+		return
+	}
+
+	if sent > 0 {
+		r.logger.WithField("sent", sent).
+			WithField("duration_ms", time.Since(startTime).Milliseconds()).
+			Info("Sent RFQ deadline reminders")
+	}
+}
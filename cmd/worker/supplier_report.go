@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/service"
+)
+
+// SupplierReporter writes the supplier metrics of each finished month as an XLSX report into a
+// directory. It checks on every tick and writes a month's report once, when its file is missing.
+type SupplierReporter struct {
+	service  *service.SupplierMetricsService
+	logger   *obs.Logger
+	dir      string
+	interval time.Duration
+}
+
+// NewSupplierReporter creates a new supplier reporter
+func NewSupplierReporter(service *service.SupplierMetricsService, logger *obs.Logger, dir string, interval time.Duration) *SupplierReporter {
+	return &SupplierReporter{
+		service:  service,
+		logger:   logger,
+		dir:      dir,
+		interval: interval,
+	}
+}
+
+// Run checks once at start and then on every tick until the context is cancelled
+func (r *SupplierReporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.report(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Supplier reporter stopping...")
+			return
+		case <-ticker.C:
+			r.report(ctx)
+		}
+	}
+}
+
+// report writes the report of the previous month unless it already exists
+func (r *SupplierReporter) report(ctx context.Context) {
+	startTime := time.Now()
+	thisMonth := time.Date(startTime.Year(), startTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := thisMonth.AddDate(0, -1, 0)
+	to := thisMonth.AddDate(0, 0, -1)
+
+	path := filepath.Join(r.dir, fmt.Sprintf("supplier_report_%s.xlsx", from.Format("2006-01")))
+	logger := r.logger.WithField("path", path)
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+
+	if err := r.write(ctx, path, from, to); err != nil {
+		logger.WithError(err).Error("Supplier report failed")
+		return
+	}
+
+	logger.WithField("duration_ms", time.Since(startTime).Milliseconds()).
+		Info("Wrote monthly supplier report")
+}
+
+// write renders the report of [from, to] into path through a temporary file, so that a
+// failed run leaves no partial report behind and is retried on the next tick
+func (r *SupplierReporter) write(ctx context.Context, path string, from, to time.Time) error {
+	file, err := r.service.ExportSupplierMetrics(ctx, service.SupplierMetricsInput{From: &from, To: &to})
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(r.dir, ".supplier_report_*")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := file.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move report into place: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/service"
+)
+
+// WebhookDispatcher periodically sends due webhook deliveries, including retries
+type WebhookDispatcher struct {
+	service  *service.WebhookService
+	logger   *obs.Logger
+	interval time.Duration
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(service *service.WebhookService, logger *obs.Logger, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run dispatches on every tick until the context is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Webhook dispatcher stopping...")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch sends due deliveries until none are left, so a backlog drains without waiting for further ticks
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		startTime := time.Now()
+
+		attempted, err := d.service.DeliverDue(ctx, startTime)
+		if err != nil {
+			d.logger.WithField("attempted", attempted).WithError(err).Error("Webhook dispatch failed")
+			return
+		}
+		if attempted == 0 {
+			return
+		}
+
+		d.logger.WithField("attempted", attempted).
+			WithField("duration_ms", time.Since(startTime).Milliseconds()).
+			Info("Attempted webhook deliveries")
+	}
+}
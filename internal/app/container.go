@@ -41,6 +41,11 @@ type Config struct {
 	UploadDir   string
 	OllamaURL   string
 	OllamaModel string
+	LLMProvider string // ollama, fake
+
+	// Embeddings
+	EmbeddingProvider string // ollama, fake
+	EmbeddingModel    string
 }
 
 // LoadConfigFromEnv loads configuration from environment variables
@@ -66,6 +71,10 @@ func LoadConfigFromEnv() *Config {
 		UploadDir:   getEnv("UPLOAD_DIR", "./uploads"),
 		OllamaURL:   getEnv("OLLAMA_URL", "http://localhost:11434"),
 		OllamaModel: getEnv("OLLAMA_MODEL", "llama2"),
+		LLMProvider: getEnv("LLM_PROVIDER", "ollama"),
+
+		EmbeddingProvider: getEnv("EMBEDDING_PROVIDER", "ollama"),
+		EmbeddingModel:    getEnv("OLLAMA_EMBED_MODEL", "bge-m3"),
 	}
 }
 
@@ -87,17 +96,44 @@ type Container struct {
 	PriceQuoteRepo    *repo.PriceQuoteRepository
 	ImportJobRepo     *repo.ImportJobRepository
 	AuditLogRepo      *repo.AuditLogRepository
+	EmbeddingRepo     *repo.CatalogEmbeddingRepository
+	FXRateRepo        *repo.FXRateRepository
+	AlertRuleRepo     *repo.AlertRuleRepository
+	PriceAlertRepo    *repo.PriceAlertRepository
+	WebhookRepo       *repo.WebhookEndpointRepository
+	WebhookDelivRepo  *repo.WebhookDeliveryRepository
+	DomainEventRepo   *repo.DomainEventRepository
+	ReviewFlagRepo    *repo.QuoteReviewFlagRepository
+	InventoryRepo     *repo.CabinInventoryReportRepository
+	PortRepo          *repo.PortRepository
+	RFQRepo           *repo.RFQRepository
 
 	// Services
-	JWTService            *auth.JWTService
-	PasswordService       *auth.PasswordService
-	AuthService           *auth.AuthService
-	AuditService          *obs.AuditService
-	CatalogService        *service.CatalogService
-	QuoteService          *service.QuoteService
-	ImportJobService      *service.ImportJobService
-	FileStorageService    *service.FileStorageService
-	TemplateImportService *service.TemplateImportService
+	JWTService             *auth.JWTService
+	PasswordService        *auth.PasswordService
+	AuthService            *auth.AuthService
+	AuditService           *obs.AuditService
+	CatalogService         *service.CatalogService
+	QuoteService           *service.QuoteService
+	ImportJobService       *service.ImportJobService
+	FileStorageService     *service.FileStorageService
+	TemplateImportService  *service.TemplateImportService
+	EmbeddingService       *service.EmbeddingIndexService
+	FXService              *service.FXService
+	ComparisonService      *service.ComparisonService
+	TrendService           *service.TrendService
+	CalendarService        *service.CalendarService
+	AnalyticsService       *service.AnalyticsService
+	SupplierMetricsService *service.SupplierMetricsService
+	SeasonalityService     *service.SeasonalityService
+	NormalizationService   *service.PriceNormalizationService
+	AlertService           *service.AlertService
+	WebhookService         *service.WebhookService
+	EventService           *service.EventService
+	AnomalyService         *service.AnomalyService
+	InventoryService       *service.InventoryService
+	RFQService             *service.RFQService
+	QuoteApprovalService   *service.QuoteApprovalService
 
 	// HTTP Handlers
 	Handlers *httpTransport.Handlers
@@ -141,6 +177,17 @@ func NewContainer(config *Config) (*Container, error) {
 	c.PriceQuoteRepo = repo.NewPriceQuoteRepository(db)
 	c.ImportJobRepo = repo.NewImportJobRepository(db)
 	c.AuditLogRepo = repo.NewAuditLogRepository(db)
+	c.EmbeddingRepo = repo.NewCatalogEmbeddingRepository(db)
+	c.FXRateRepo = repo.NewFXRateRepository(db)
+	c.AlertRuleRepo = repo.NewAlertRuleRepository(db)
+	c.PriceAlertRepo = repo.NewPriceAlertRepository(db)
+	c.WebhookRepo = repo.NewWebhookEndpointRepository(db)
+	c.WebhookDelivRepo = repo.NewWebhookDeliveryRepository(db)
+	c.DomainEventRepo = repo.NewDomainEventRepository(db)
+	c.ReviewFlagRepo = repo.NewQuoteReviewFlagRepository(db)
+	c.InventoryRepo = repo.NewCabinInventoryReportRepository(db)
+	c.PortRepo = repo.NewPortRepository(db)
+	c.RFQRepo = repo.NewRFQRepository(db)
 
 	// Initialize auth services
 	c.JWTService = auth.NewJWTService(auth.JWTConfig{
@@ -153,33 +200,88 @@ func NewContainer(config *Config) (*Container, error) {
 
 	// Initialize services
 	c.AuditService = obs.NewAuditService(c.AuditLogRepo, c.Logger)
+
+	// Initialize embedding index
+	embedder, err := llm.NewEmbedder(config.EmbeddingProvider, config.OllamaURL, config.EmbeddingModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedder: %w", err)
+	}
+	c.EmbeddingService = service.NewEmbeddingIndexService(
+		c.EmbeddingRepo,
+		c.CabinTypeRepo,
+		c.ShipRepo,
+		c.CabinCategoryRepo,
+		embedder,
+		c.Logger,
+	)
+
+	c.FXService = service.NewFXService(c.FXRateRepo, c.AuditService, c.Logger)
 	c.CatalogService = service.NewCatalogService(
 		c.CruiseLineRepo, c.ShipRepo, c.CabinCategoryRepo, c.CabinTypeRepo,
-		c.SailingRepo, c.SupplierRepo, c.AuditService, c.Logger,
+		c.SailingRepo, c.SupplierRepo, c.PortRepo, c.EmbeddingService, c.AuditService, c.Logger,
+		c.FXService,
+	)
+
+	// Initialize alert and anomaly services
+	c.AlertService = service.NewAlertService(
+		c.AlertRuleRepo,
+		c.PriceAlertRepo,
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.UserRepo,
+		c.FXService,
+		c.AuditService,
+		c.Logger,
 	)
 
+	c.AnomalyService = service.NewAnomalyService(
+		c.ReviewFlagRepo,
+		c.PriceQuoteRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.FXService,
+		c.AuditService,
+		c.Logger,
+	)
+
+	// Initialize webhook and domain event services
+	c.WebhookService = service.NewWebhookService(c.WebhookRepo, c.WebhookDelivRepo, c.UserRepo, c.AuditService, c.Logger)
+	c.EventService = service.NewEventService(c.DomainEventRepo, c.Logger)
+
 	// Initialize quote service
 	c.QuoteService = service.NewQuoteService(
 		c.PriceQuoteRepo,
 		c.SailingRepo,
 		c.CabinTypeRepo,
 		c.SupplierRepo,
+		c.AlertService,
+		c.AnomalyService,
 		c.AuditService,
 	)
 
 	// Initialize file storage and import services
 	c.FileStorageService = service.NewFileStorageService(config.UploadDir)
-	ollamaClient := llm.NewOllamaClient(config.OllamaURL, config.OllamaModel)
+	generator, err := llm.NewGenerator(config.LLMProvider, config.OllamaURL, config.OllamaModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM generator: %w", err)
+	}
 	dataMatcher := service.NewDataMatcher(
 		c.ShipRepo,
 		c.SailingRepo,
 		c.CabinTypeRepo,
 		c.CruiseLineRepo,
+		c.CabinCategoryRepo,
+		c.PortRepo,
+		c.EmbeddingService,
 	)
 	c.ImportJobService = service.NewImportJobService(
 		c.ImportJobRepo,
 		c.FileStorageService,
-		ollamaClient,
+		generator,
 		dataMatcher,
 		c.QuoteService,
 		c.AuditService,
@@ -192,17 +294,124 @@ func NewContainer(config *Config) (*Container, error) {
 		c.CabinCategoryRepo,
 		c.CabinTypeRepo,
 		c.SailingRepo,
+		c.SupplierRepo,
+		c.PortRepo,
+		c.QuoteService,
+		c.CatalogService,
+		c.EmbeddingService,
 		c.AuditService,
 		*c.Logger,
 	)
 
+	// Initialize comparison, trend, calendar and analytics services
+	c.ComparisonService = service.NewComparisonService(
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+	c.TrendService = service.NewTrendService(
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+	c.CalendarService = service.NewCalendarService(
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+	c.AnalyticsService = service.NewAnalyticsService(
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+	c.SupplierMetricsService = service.NewSupplierMetricsService(
+		c.PriceQuoteRepo,
+		c.ImportJobRepo,
+		c.SailingRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+	c.SeasonalityService = service.NewSeasonalityService(
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.FXService,
+	)
+
+	c.InventoryService = service.NewInventoryService(
+		c.PriceQuoteRepo,
+		c.InventoryRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.CabinCategoryRepo,
+		c.SupplierRepo,
+		c.AuditService,
+	)
+
+	c.RFQService = service.NewRFQService(
+		c.RFQRepo,
+		c.PriceQuoteRepo,
+		c.SailingRepo,
+		c.ShipRepo,
+		c.CruiseLineRepo,
+		c.CabinTypeRepo,
+		c.SupplierRepo,
+		c.QuoteService,
+		c.FXService,
+		c.AuditService,
+	)
+
+	c.QuoteApprovalService = service.NewQuoteApprovalService(
+		c.PriceQuoteRepo,
+		c.SupplierRepo,
+		c.AlertService,
+		c.AuditService,
+	)
+
+	c.NormalizationService = service.NewPriceNormalizationService(c.PriceQuoteRepo, c.SailingRepo, c.FXService)
+
 	// Initialize HTTP handlers
 	c.Handlers = &httpTransport.Handlers{
-		Auth:     httpTransport.NewAuthHandler(c.AuthService),
-		Catalog:  httpTransport.NewCatalogHandler(c.CatalogService),
-		Quote:    httpTransport.NewQuoteHandler(c.QuoteService),
-		Import:   httpTransport.NewImportHandler(c.ImportJobService),
-		Template: httpTransport.NewTemplateHandler(c.TemplateImportService),
+		Auth:          httpTransport.NewAuthHandler(c.AuthService),
+		Catalog:       httpTransport.NewCatalogHandler(c.CatalogService),
+		Quote:         httpTransport.NewQuoteHandler(c.QuoteService, c.NormalizationService),
+		Import:        httpTransport.NewImportHandler(c.ImportJobService),
+		Template:      httpTransport.NewTemplateHandler(c.TemplateImportService),
+		Embedding:     httpTransport.NewEmbeddingHandler(c.EmbeddingService),
+		Comparison:    httpTransport.NewComparisonHandler(c.ComparisonService, c.TrendService, c.CalendarService),
+		FX:            httpTransport.NewFXHandler(c.FXService),
+		Alert:         httpTransport.NewAlertHandler(c.AlertService),
+		Webhook:       httpTransport.NewWebhookHandler(c.WebhookService),
+		Event:         httpTransport.NewEventHandler(c.EventService),
+		QuoteReview:   httpTransport.NewQuoteReviewHandler(c.AnomalyService),
+		Inventory:     httpTransport.NewInventoryHandler(c.InventoryService),
+		Analytics:     httpTransport.NewAnalyticsHandler(c.AnalyticsService, c.SupplierMetricsService, c.SeasonalityService),
+		RFQ:           httpTransport.NewRFQHandler(c.RFQService),
+		QuoteApproval: httpTransport.NewQuoteApprovalHandler(c.QuoteApprovalService),
 	}
 
 	c.Logger.Info("application container initialized")
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AlertCondition represents the condition of an alert rule
+type AlertCondition string
+
+const (
+	AlertConditionBelowPrice        AlertCondition = "BELOW_PRICE"         // per-person price below Threshold
+	AlertConditionDropPercent       AlertCondition = "DROP_PERCENT"        // supplier's price drops by more than Threshold %
+	AlertConditionNewLowestSupplier AlertCondition = "NEW_LOWEST_SUPPLIER" // another supplier becomes the cheapest
+)
+
+// AlertRule watches the quotes of a sailing, or of every sailing of a ship, optionally
+// narrowed to a cabin type or category, and raises a PriceAlert when a new quote meets
+// the condition. Prices are compared per person at base occupancy.
+type AlertRule struct {
+	ID              uint64           `json:"id" db:"id"`
+	UserID          uint64           `json:"user_id" db:"user_id"`
+	Name            string           `json:"name" db:"name"`
+	SailingID       *uint64          `json:"sailing_id,omitempty" db:"sailing_id"`
+	ShipID          *uint64          `json:"ship_id,omitempty" db:"ship_id"`
+	CabinTypeID     *uint64          `json:"cabin_type_id,omitempty" db:"cabin_type_id"`
+	CabinCategoryID *uint64          `json:"cabin_category_id,omitempty" db:"cabin_category_id"`
+	Condition       AlertCondition   `json:"condition" db:"condition_type"`
+	Threshold       *decimal.Decimal `json:"threshold,omitempty" db:"threshold"`
+	Currency        string           `json:"currency,omitempty" db:"currency"` // BELOW_PRICE only
+	IsEnabled       bool             `json:"is_enabled" db:"is_enabled"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// PriceAlert is a hit of an alert rule, raised by the quote that met its condition
+type PriceAlert struct {
+	ID             uint64           `json:"id" db:"id"`
+	RuleID         uint64           `json:"rule_id" db:"rule_id"`
+	UserID         uint64           `json:"user_id" db:"user_id"`
+	QuoteID        uint64           `json:"quote_id" db:"quote_id"`
+	SailingID      uint64           `json:"sailing_id" db:"sailing_id"`
+	CabinTypeID    uint64           `json:"cabin_type_id" db:"cabin_type_id"`
+	SupplierID     uint64           `json:"supplier_id" db:"supplier_id"`
+	Condition      AlertCondition   `json:"condition" db:"condition_type"`
+	Price          decimal.Decimal  `json:"price" db:"price"` // per person
+	PreviousPrice  *decimal.Decimal `json:"previous_price,omitempty" db:"previous_price"`
+	Currency       string           `json:"currency" db:"currency"`
+	Message        string           `json:"message" db:"message"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+}
+
+// IsAcknowledged checks if the alert has been acknowledged
+func (a *PriceAlert) IsAcknowledged() bool {
+	return a.AcknowledgedAt != nil
+}
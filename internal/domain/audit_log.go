@@ -9,20 +9,25 @@ import (
 type AuditAction string
 
 const (
-	AuditActionCreate AuditAction = "CREATE"
-	AuditActionUpdate AuditAction = "UPDATE"
-	AuditActionDelete AuditAction = "DELETE"
-	AuditActionLogin  AuditAction = "LOGIN"
-	AuditActionLogout AuditAction = "LOGOUT"
-	AuditActionImport AuditAction = "IMPORT"
-	AuditActionExport AuditAction = "EXPORT"
-	AuditActionVoid   AuditAction = "VOID"
+	AuditActionCreate  AuditAction = "CREATE"
+	AuditActionUpdate  AuditAction = "UPDATE"
+	AuditActionDelete  AuditAction = "DELETE"
+	AuditActionLogin   AuditAction = "LOGIN"
+	AuditActionLogout  AuditAction = "LOGOUT"
+	AuditActionImport  AuditAction = "IMPORT"
+	AuditActionExport  AuditAction = "EXPORT"
+	AuditActionVoid    AuditAction = "VOID"
+	AuditActionExpire  AuditAction = "EXPIRE"
+	AuditActionConfirm AuditAction = "CONFIRM"
 )
 
+// SystemUserID attributes an audit entry to the system, e.g. a scheduled job; it is stored as NULL
+const SystemUserID uint64 = 0
+
 // AuditLog represents an audit log entry
 type AuditLog struct {
 	ID         uint64          `json:"id" db:"id"`
-	UserID     uint64          `json:"user_id" db:"user_id"`
+	UserID     uint64          `json:"user_id" db:"user_id"` // SystemUserID for system actions
 	SupplierID *uint64         `json:"supplier_id,omitempty" db:"supplier_id"`
 	Action     AuditAction     `json:"action" db:"action"`
 	EntityType string          `json:"entity_type" db:"entity_type"`
@@ -49,4 +54,11 @@ const (
 	EntityTypeSupplier      = "supplier"
 	EntityTypePriceQuote    = "price_quote"
 	EntityTypeImportJob     = "import_job"
+	EntityTypeFXRate        = "fx_rate"
+	EntityTypeAlertRule     = "alert_rule"
+	EntityTypeWebhook       = "webhook_endpoint"
+	EntityTypeReviewFlag    = "quote_review_flag"
+	EntityTypeInventory     = "cabin_inventory_report"
+	EntityTypePort          = "port"
+	EntityTypeRFQ           = "rfq"
 )
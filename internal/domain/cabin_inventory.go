@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+// InventoryState represents whether a supplier holds cabins of a cabin type on a sailing
+type InventoryState string
+
+const (
+	InventoryAvailable    InventoryState = "AVAILABLE"     // The latest quote offers the cabin
+	InventorySoldOut      InventoryState = "SOLD_OUT"      // The supplier's held cabins are gone
+	InventoryNoAllocation InventoryState = "NO_ALLOCATION" // The supplier holds no cabins of this type
+)
+
+// IsReportable checks if the state is one a supplier reports explicitly rather than through a quote
+func (s InventoryState) IsReportable() bool {
+	return s == InventorySoldOut || s == InventoryNoAllocation
+}
+
+// InventoryObservationKind identifies what an inventory observation was taken from
+type InventoryObservationKind string
+
+const (
+	InventoryFromQuote        InventoryObservationKind = "QUOTE"        // A new quote
+	InventoryFromConfirmation InventoryObservationKind = "CONFIRMATION" // An unchanged resubmission of a quote
+	InventoryFromReport       InventoryObservationKind = "REPORT"       // An explicit sold out / no allocation report
+)
+
+// CabinInventoryReport is an explicit statement of a supplier that it has no cabins of a
+// cabin type to offer on a sailing. A later quote makes the cabin available again.
+type CabinInventoryReport struct {
+	ID          uint64         `json:"id" db:"id"`
+	SailingID   uint64         `json:"sailing_id" db:"sailing_id"`
+	CabinTypeID uint64         `json:"cabin_type_id" db:"cabin_type_id"`
+	SupplierID  uint64         `json:"supplier_id" db:"supplier_id"`
+	State       InventoryState `json:"state" db:"state"`
+	Note        string         `json:"note,omitempty" db:"note"`
+	ReportedAt  time.Time      `json:"reported_at" db:"reported_at"`
+	CreatedBy   uint64         `json:"created_by" db:"created_by"`
+}
+
+// InventoryObservation is the inventory of a supplier for a cabin type at one point in time.
+// Quantity is the number of cabins held, nil when an available quote did not state it.
+type InventoryObservation struct {
+	Kind       InventoryObservationKind `json:"kind"`
+	State      InventoryState           `json:"state"`
+	Quantity   *int                     `json:"quantity,omitempty"`
+	ObservedAt time.Time                `json:"observed_at"`
+	QuoteID    *uint64                  `json:"quote_id,omitempty"`
+	ReportID   *uint64                  `json:"report_id,omitempty"`
+}
+
+// QuoteInventory returns the inventory stated by a quote. A quote holding zero cabins is sold out.
+func QuoteInventory(q *PriceQuote, kind InventoryObservationKind, at time.Time) InventoryObservation {
+	id := q.ID
+	obs := InventoryObservation{
+		Kind:       kind,
+		State:      InventoryAvailable,
+		Quantity:   q.CabinQuantity,
+		ObservedAt: at,
+		QuoteID:    &id,
+	}
+	if q.CabinQuantity != nil && *q.CabinQuantity <= 0 {
+		obs.State = InventorySoldOut
+	}
+	return obs
+}
+
+// ReportInventory returns the inventory stated by a report
+func ReportInventory(r *CabinInventoryReport) InventoryObservation {
+	id := r.ID
+	zero := 0
+	return InventoryObservation{
+		Kind:       InventoryFromReport,
+		State:      r.State,
+		Quantity:   &zero,
+		ObservedAt: r.ReportedAt,
+		ReportID:   &id,
+	}
+}
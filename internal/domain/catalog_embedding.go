@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// CatalogEmbedding is the embedding vector of a catalog entity's name, used for
+// semantic matching of supplier wording against cabin types, ships and categories
+type CatalogEmbedding struct {
+	ID          uint64    `json:"id" db:"id"`
+	EntityType  string    `json:"entity_type" db:"entity_type"` // EntityTypeCabinType, EntityTypeShip or EntityTypeCabinCategory
+	EntityID    uint64    `json:"entity_id" db:"entity_id"`
+	Model       string    `json:"model" db:"model"`
+	Content     string    `json:"content" db:"content"`           // Text that was embedded
+	ContentHash string    `json:"content_hash" db:"content_hash"` // SHA-256 of Content, used to skip unchanged entities
+	Dimensions  int       `json:"dimensions" db:"dimensions"`
+	Vector      []float32 `json:"-" db:"vector"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EmbeddableEntityTypes returns the entity types covered by the embedding index
+func EmbeddableEntityTypes() []string {
+	return []string{EntityTypeCabinType, EntityTypeShip, EntityTypeCabinCategory}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types. Catalog events are named "<entity type>.<action>", see EntityEventType.
+const (
+	DomainEventQuoteCreated          = "quote.created"
+	DomainEventQuoteBackfilled       = "quote.backfilled" // quote.created of a backfilled quote, not sent to webhooks
+	DomainEventQuoteVoided           = "quote.voided"
+	DomainEventQuoteCorrected        = "quote.corrected"
+	DomainEventQuoteExpired          = "quote.expired"
+	DomainEventQuoteConfirmed        = "quote.confirmed"
+	DomainEventQuoteApproved         = "quote.approved"
+	DomainEventQuoteRejected         = "quote.rejected"
+	DomainEventQuoteBackfillReviewed = "quote.backfill_reviewed" // quote.approved or quote.rejected of a backfilled quote, not sent to webhooks
+	DomainEventInventoryReported     = "inventory.reported"
+	DomainEventImportJobCreated      = "import_job.created"
+	DomainEventImportJobFinished     = "import_job.finished"
+	DomainEventRFQIssued             = "rfq.issued"
+	DomainEventRFQReminder           = "rfq.reminder"
+	DomainEventRFQDeclined           = "rfq.declined"
+	DomainEventRFQClosed             = "rfq.closed"
+)
+
+// Catalog entity event actions
+const (
+	DomainEventActionCreated = "created"
+	DomainEventActionUpdated = "updated"
+	DomainEventActionDeleted = "deleted"
+)
+
+// EntityEventType returns the event type of a catalog entity change, e.g. "ship.updated"
+func EntityEventType(entityType, action string) string {
+	return entityType + "." + action
+}
+
+// DomainEvent is a fact recorded in the outbox in the same transaction as the change it describes
+type DomainEvent struct {
+	ID            uint64          `json:"id" db:"id"`             // Position in the outbox, used as cursor
+	EventID       string          `json:"event_id" db:"event_id"` // UUID, stable across redeliveries
+	EventType     string          `json:"event_type" db:"event_type"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   uint64          `json:"aggregate_id" db:"aggregate_id"`
+	SupplierID    *uint64         `json:"supplier_id,omitempty" db:"supplier_id"`
+	UserID        *uint64         `json:"user_id,omitempty" db:"user_id"` // Nil for system actions
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	TraceID       string          `json:"trace_id,omitempty" db:"trace_id"`
+	OccurredAt    time.Time       `json:"occurred_at" db:"occurred_at"`
+
+	// Data is marshalled into Payload when the event is appended, after the change has
+	// been written, so it may point at the entity whose ID is assigned by the insert
+	Data interface{} `json:"-" db:"-"`
+}
+
+// NewDomainEvent creates an event about an aggregate. aggregateID may be zero for an
+// aggregate that is being created; it is filled in once the row has been inserted.
+func NewDomainEvent(eventType, aggregateType string, aggregateID uint64, userID uint64, supplierID *uint64, data interface{}) *DomainEvent {
+	event := &DomainEvent{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		SupplierID:    supplierID,
+		Data:          data,
+	}
+	if userID != SystemUserID {
+		event.UserID = &userID
+	}
+	return event
+}
+
+// EventCursor is the position of a subscriber in the outbox
+type EventCursor struct {
+	Subscriber  string    `json:"subscriber" db:"subscriber"`
+	LastEventID uint64    `json:"last_event_id" db:"last_event_id"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRateSource represents where an exchange rate came from
+type FXRateSource string
+
+const (
+	FXRateSourceManual FXRateSource = "MANUAL"
+	FXRateSourceCSV    FXRateSource = "CSV"
+	FXRateSourceECBXML FXRateSource = "ECB_XML"
+)
+
+// FXRate represents an exchange rate in effect from RateDate:
+// 1 unit of BaseCurrency = Rate units of QuoteCurrency
+type FXRate struct {
+	ID            uint64          `json:"id" db:"id"`
+	RateDate      time.Time       `json:"rate_date" db:"rate_date"`
+	BaseCurrency  string          `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string          `json:"quote_currency" db:"quote_currency"`
+	Rate          decimal.Decimal `json:"rate" db:"rate"`
+	Source        FXRateSource    `json:"source" db:"source"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+	CreatedBy     *uint64         `json:"created_by,omitempty" db:"created_by"`
+}
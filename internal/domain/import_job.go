@@ -34,6 +34,9 @@ type ImportResultSummary struct {
 	SkippedRows   int      `json:"skipped_rows"`
 	Warnings      []string `json:"warnings,omitempty"`
 	CreatedQuotes int      `json:"created_quotes,omitempty"`
+
+	// Rows that repeated the latest quote unchanged and confirmed it instead of creating one
+	ConfirmedQuotes int `json:"confirmed_quotes,omitempty"`
 }
 
 // ImportJob represents an import task
@@ -45,6 +48,9 @@ type ImportJob struct {
 	FileHash       string               `json:"file_hash,omitempty" db:"file_hash"`
 	FileSize       int64                `json:"file_size,omitempty" db:"file_size"`
 	FilePath       string               `json:"file_path,omitempty" db:"file_path"`
+	SupplierID     *uint64              `json:"supplier_id,omitempty" db:"supplier_id"` // nil = supplier of the creator
+	QuotedAt       *time.Time           `json:"quoted_at,omitempty" db:"quoted_at"`     // Fallback quote date when the document states none
+	Backfill       bool                 `json:"backfill,omitempty" db:"backfill"`       // Archived file; its quotes are QuoteSourceBackfill
 	RawText        string               `json:"raw_text,omitempty" db:"raw_text"`
 	IdempotencyKey string               `json:"idempotency_key,omitempty" db:"idempotency_key"`
 	ModelVersion   string               `json:"model_version,omitempty" db:"model_version"`
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Port represents a port of call
+type Port struct {
+	ID        uint64       `json:"id" db:"id"`
+	Name      string       `json:"name" db:"name"`
+	NameEN    string       `json:"name_en,omitempty" db:"name_en"`
+	Aliases   []string     `json:"aliases,omitempty" db:"aliases"`
+	Country   string       `json:"country,omitempty" db:"country"`
+	UNLocode  string       `json:"unlocode,omitempty" db:"unlocode"` // e.g. CNSHA
+	Status    EntityStatus `json:"status" db:"status"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+	CreatedBy *uint64      `json:"created_by,omitempty" db:"created_by"`
+}
+
+// IsActive checks if port is active
+func (p *Port) IsActive() bool {
+	return p.Status == EntityStatusActive
+}
+
+// Names returns every name the port may be referred to by, including its UN/LOCODE
+func (p *Port) Names() []string {
+	names := make([]string, 0, len(p.Aliases)+3)
+	names = append(names, p.Name)
+	if p.NameEN != "" {
+		names = append(names, p.NameEN)
+	}
+	if p.UNLocode != "" {
+		names = append(names, p.UNLocode)
+	}
+	return append(names, p.Aliases...)
+}
+
+// NormalizeUNLocode returns a UN/LOCODE in its stored form: upper case without the space
+// between the country and the location part, e.g. "cn sha" becomes "CNSHA"
+func NormalizeUNLocode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// ItineraryStop is one day of a sailing itinerary: a call at a port or a day at sea.
+// Times are local port times in HH:MM.
+type ItineraryStop struct {
+	ID         uint64  `json:"id" db:"id"`
+	SailingID  uint64  `json:"sailing_id" db:"sailing_id"`
+	DayNumber  int     `json:"day_number" db:"day_number"` // 1 is the departure day
+	PortID     *uint64 `json:"port_id,omitempty" db:"port_id"`
+	ArriveTime *string `json:"arrive_time,omitempty" db:"arrive_time"`
+	DepartTime *string `json:"depart_time,omitempty" db:"depart_time"`
+	IsSeaDay   bool    `json:"is_sea_day" db:"is_sea_day"`
+	Note       string  `json:"note,omitempty" db:"note"`
+
+	// Loaded relations
+	Port *Port `json:"port,omitempty" db:"-"`
+}
+
+// ItineraryPortIDs returns the ports a sailing calls at, in itinerary order without repeats
+func ItineraryPortIDs(stops []ItineraryStop) []uint64 {
+	seen := make(map[uint64]bool, len(stops))
+	ids := make([]uint64, 0, len(stops))
+	for _, stop := range stops {
+		if stop.PortID == nil || seen[*stop.PortID] {
+			continue
+		}
+		seen[*stop.PortID] = true
+		ids = append(ids, *stop.PortID)
+	}
+	return ids
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -23,44 +24,129 @@ const (
 	QuoteSourceFileImport     QuoteSource = "FILE_IMPORT"
 	QuoteSourceTextImport     QuoteSource = "TEXT_IMPORT"
 	QuoteSourceTemplateImport QuoteSource = "TEMPLATE_IMPORT"
+	QuoteSourceBackfill       QuoteSource = "BACKFILL" // Archived price list; historical, raises no alerts
 )
 
 // QuoteStatus represents the status of a price quote
 type QuoteStatus string
 
 const (
-	QuoteStatusActive    QuoteStatus = "ACTIVE"
-	QuoteStatusVoided    QuoteStatus = "VOIDED"
-	QuoteStatusCorrected QuoteStatus = "CORRECTED"
+	QuoteStatusPendingReview QuoteStatus = "PENDING_REVIEW" // Waiting for admin approval, see Supplier.RequiresApproval
+	QuoteStatusActive        QuoteStatus = "ACTIVE"
+	QuoteStatusVoided        QuoteStatus = "VOIDED"
+	QuoteStatusCorrected     QuoteStatus = "CORRECTED"
+	QuoteStatusExpired       QuoteStatus = "EXPIRED"
+	QuoteStatusRejected      QuoteStatus = "REJECTED" // Not approved, never published
 )
 
+// GuestType represents the type of guest an occupancy rate applies to
+type GuestType string
+
+const (
+	GuestTypeAdult GuestType = "ADULT"
+	GuestTypeChild GuestType = "CHILD"
+)
+
+// DefaultBaseOccupancy is the number of guests a cabin price covers when the quote does not say
+const DefaultBaseOccupancy = 2
+
+// OccupancyRate is the per-guest price of one berth of the quoted cabin,
+// e.g. the 3rd/4th guest rate or a child rate, in the quote currency
+type OccupancyRate struct {
+	ID          uint64          `json:"id" db:"id"`
+	QuoteID     uint64          `json:"quote_id" db:"quote_id"`
+	GuestSlot   int             `json:"guest_slot" db:"guest_slot"` // 1-based berth position
+	GuestType   GuestType       `json:"guest_type" db:"guest_type"`
+	Price       decimal.Decimal `json:"price" db:"price"`
+	MaxChildAge *int            `json:"max_child_age,omitempty" db:"max_child_age"`
+}
+
+// PriceComponentType represents the kind of charge a price component line covers
+type PriceComponentType string
+
+const (
+	PriceComponentBaseFare      PriceComponentType = "BASE_FARE"
+	PriceComponentPortTax       PriceComponentType = "PORT_TAX"
+	PriceComponentGratuity      PriceComponentType = "GRATUITY"
+	PriceComponentFuelSurcharge PriceComponentType = "FUEL_SURCHARGE"
+	PriceComponentOther         PriceComponentType = "OTHER"
+)
+
+// PriceComponent is one charge line of a quote, in the quote currency. Inclusive lines
+// are part of the quoted price; the others are payable on top of it.
+type PriceComponent struct {
+	ID            uint64             `json:"id" db:"id"`
+	QuoteID       uint64             `json:"quote_id" db:"quote_id"`
+	ComponentType PriceComponentType `json:"component_type" db:"component_type"`
+	Amount        decimal.Decimal    `json:"amount" db:"amount"`
+	PricingUnit   PricingUnit        `json:"pricing_unit" db:"pricing_unit"`
+	PerNight      bool               `json:"per_night" db:"per_night"`
+	Inclusive     bool               `json:"inclusive" db:"inclusive"`
+	Description   string             `json:"description,omitempty" db:"description"`
+}
+
 // PriceQuote represents a price quote record (append-only)
 type PriceQuote struct {
-	ID            uint64          `json:"id" db:"id"`
-	SailingID     uint64          `json:"sailing_id" db:"sailing_id"`
-	CabinTypeID   uint64          `json:"cabin_type_id" db:"cabin_type_id"`
-	SupplierID    uint64          `json:"supplier_id" db:"supplier_id"`
-	Price         decimal.Decimal `json:"price" db:"price"`
-	Currency      string          `json:"currency" db:"currency"`
-	PricingUnit   PricingUnit     `json:"pricing_unit" db:"pricing_unit"`
-	Conditions    string          `json:"conditions,omitempty" db:"conditions"`
-	GuestCount    *int            `json:"guest_count,omitempty" db:"guest_count"`
-	Promotion     string          `json:"promotion,omitempty" db:"promotion"`
-	CabinQuantity *int            `json:"cabin_quantity,omitempty" db:"cabin_quantity"`
-	ValidUntil    *time.Time      `json:"valid_until,omitempty" db:"valid_until"`
-	Notes         string          `json:"notes,omitempty" db:"notes"`
-	Source        QuoteSource     `json:"source" db:"source"`
-	SourceRef     string          `json:"source_ref,omitempty" db:"source_ref"`
-	ImportJobID   *uint64         `json:"import_job_id,omitempty" db:"import_job_id"`
-	Status        QuoteStatus     `json:"status" db:"status"`
-	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
-	CreatedBy     uint64          `json:"created_by" db:"created_by"`
+	ID           uint64          `json:"id" db:"id"`
+	SailingID    uint64          `json:"sailing_id" db:"sailing_id"`
+	CabinTypeID  uint64          `json:"cabin_type_id" db:"cabin_type_id"`
+	SupplierID   uint64          `json:"supplier_id" db:"supplier_id"`
+	Price        decimal.Decimal `json:"price" db:"price"`
+	Currency     string          `json:"currency" db:"currency"`
+	PricingUnit  PricingUnit     `json:"pricing_unit" db:"pricing_unit"`
+	Conditions   string          `json:"conditions,omitempty" db:"conditions"`
+	GuestCount   *int            `json:"guest_count,omitempty" db:"guest_count"`
+	MaxOccupancy *int            `json:"max_occupancy,omitempty" db:"max_occupancy"`
+
+	// Single occupancy surcharge on the per-person fare, as a percentage and/or fixed amount
+	SingleSupplementPct    *decimal.Decimal `json:"single_supplement_pct,omitempty" db:"single_supplement_pct"`
+	SingleSupplementAmount *decimal.Decimal `json:"single_supplement_amount,omitempty" db:"single_supplement_amount"`
+
+	Promotion     string      `json:"promotion,omitempty" db:"promotion"`
+	CabinQuantity *int        `json:"cabin_quantity,omitempty" db:"cabin_quantity"`
+	ValidFrom     *time.Time  `json:"valid_from,omitempty" db:"valid_from"`
+	ValidUntil    *time.Time  `json:"valid_until,omitempty" db:"valid_until"`
+	QuotedAt      time.Time   `json:"quoted_at" db:"quoted_at"` // When the supplier issued it; CreatedAt is when it was entered
+	Notes         string      `json:"notes,omitempty" db:"notes"`
+	Source        QuoteSource `json:"source" db:"source"`
+	SourceRef     string      `json:"source_ref,omitempty" db:"source_ref"`
+	ImportJobID   *uint64     `json:"import_job_id,omitempty" db:"import_job_id"`
+	RFQID         *uint64     `json:"rfq_id,omitempty" db:"rfq_id"` // RFQ the quote answers
+	Status        QuoteStatus `json:"status" db:"status"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy     uint64      `json:"created_by" db:"created_by"`
+
+	// When the status last changed; for voided and corrected quotes, when they were withdrawn
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty" db:"status_changed_at"`
+
+	// Approval of quotes of suppliers that require it; set when the quote left PENDING_REVIEW
+	ReviewedBy   *uint64    `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewReason string     `json:"review_reason,omitempty" db:"review_reason"`
+
+	// Unchanged resubmissions confirm the quote instead of creating a new one
+	LastConfirmedAt   *time.Time `json:"last_confirmed_at,omitempty" db:"last_confirmed_at"`
+	ConfirmationCount int        `json:"confirmation_count" db:"confirmation_count"`
 
 	// Loaded relations
-	Sailing   *Sailing   `json:"sailing,omitempty" db:"-"`
-	CabinType *CabinType `json:"cabin_type,omitempty" db:"-"`
-	Supplier  *Supplier  `json:"supplier,omitempty" db:"-"`
-	ImportJob *ImportJob `json:"import_job,omitempty" db:"-"`
+	OccupancyRates []OccupancyRate  `json:"occupancy_rates,omitempty" db:"-"`
+	Components     []PriceComponent `json:"components,omitempty" db:"-"`
+	Promotions     []Promotion      `json:"promotions,omitempty" db:"-"`
+	Sailing        *Sailing         `json:"sailing,omitempty" db:"-"`
+	CabinType      *CabinType       `json:"cabin_type,omitempty" db:"-"`
+	Supplier       *Supplier        `json:"supplier,omitempty" db:"-"`
+	ImportJob      *ImportJob       `json:"import_job,omitempty" db:"-"`
+}
+
+// QuoteConfirmation records that a supplier resubmitted an active quote unchanged
+type QuoteConfirmation struct {
+	ID          uint64      `json:"id" db:"id"`
+	QuoteID     uint64      `json:"quote_id" db:"quote_id"`
+	Source      QuoteSource `json:"source" db:"source"`
+	SourceRef   string      `json:"source_ref,omitempty" db:"source_ref"`
+	ImportJobID *uint64     `json:"import_job_id,omitempty" db:"import_job_id"`
+	ConfirmedAt time.Time   `json:"confirmed_at" db:"confirmed_at"`
+	CreatedBy   uint64      `json:"created_by" db:"created_by"`
 }
 
 // IsActive checks if quote is active
@@ -68,46 +154,293 @@ func (pq *PriceQuote) IsActive() bool {
 	return pq.Status == QuoteStatusActive
 }
 
+// IsBackfill checks if the quote was imported from an archived price list. Backfilled quotes
+// are history: they raise no price alerts, anomaly checks or webhooks.
+func (pq *PriceQuote) IsBackfill() bool {
+	return pq.Source == QuoteSourceBackfill
+}
+
+// IsPendingReview checks if the quote waits for admin approval
+func (pq *PriceQuote) IsPendingReview() bool {
+	return pq.Status == QuoteStatusPendingReview
+}
+
 // IsVoided checks if quote is voided
 func (pq *PriceQuote) IsVoided() bool {
 	return pq.Status == QuoteStatusVoided
 }
 
+// IsWithdrawn checks if the quote was voided or corrected
+func (pq *PriceQuote) IsWithdrawn() bool {
+	return pq.Status == QuoteStatusVoided || pq.Status == QuoteStatusCorrected
+}
+
 // IsValid checks if quote is still valid (not expired)
 func (pq *PriceQuote) IsValid() bool {
-	if !pq.IsActive() {
+	return pq.IsActive() && !pq.IsPastValidUntil(time.Now())
+}
+
+// PricedAt returns when the supplier issued the price: QuotedAt, or CreatedAt for quotes
+// without a quote date
+func (pq *PriceQuote) PricedAt() time.Time {
+	if pq.QuotedAt.IsZero() {
+		return pq.CreatedAt
+	}
+	return pq.QuotedAt
+}
+
+// IsPastValidUntil checks if the validity date of the quote lies before the day of t.
+// A quote stays valid through its valid_until date.
+func (pq *PriceQuote) IsPastValidUntil(t time.Time) bool {
+	if pq.ValidUntil == nil {
 		return false
 	}
-	if pq.ValidUntil != nil && pq.ValidUntil.Before(time.Now()) {
+	y, m, d := pq.ValidUntil.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1).Compare(t) <= 0
+}
+
+// IsExpired checks if the quote has been swept to EXPIRED or is active past its
+// validity date and awaiting the sweep
+func (pq *PriceQuote) IsExpired(t time.Time) bool {
+	return pq.Status == QuoteStatusExpired || (pq.IsActive() && pq.IsPastValidUntil(t))
+}
+
+// StatusAt returns the status the quote had at t, going by when its status last changed.
+// Like IsExpired, a quote counts as expired once past its validity date, swept or not.
+func (pq *PriceQuote) StatusAt(t time.Time) QuoteStatus {
+	if pq.Status != QuoteStatusActive && (pq.StatusChangedAt == nil || !pq.StatusChangedAt.After(t)) {
+		return pq.Status
+	}
+	if pq.IsPastValidUntil(t) {
+		return QuoteStatusExpired
+	}
+	return QuoteStatusActive
+}
+
+// IsConfirmedBy checks if a submission confirms the quote: an unchanged resubmission of it
+// (see HasSameTerms) issued no earlier than the quote. A document with the same terms issued
+// before the quote predates it and is history, not a confirmation.
+func (pq *PriceQuote) IsConfirmedBy(submission *PriceQuote) bool {
+	return !submission.QuotedAt.Before(pq.QuotedAt) && pq.HasSameTerms(submission)
+}
+
+// WithConfirmation returns a copy of the quote confirmed by a resubmission issued at
+// confirmedAt. The last confirmation never moves back, so a resubmission processed after a
+// newer one keeps the newer date.
+func (pq *PriceQuote) WithConfirmation(confirmedAt time.Time) PriceQuote {
+	confirmed := *pq
+	if confirmed.LastConfirmedAt == nil || confirmedAt.After(*confirmed.LastConfirmedAt) {
+		confirmed.LastConfirmedAt = &confirmedAt
+	}
+	confirmed.ConfirmationCount++
+	return confirmed
+}
+
+// HasSameTerms checks if other quotes the same cabin of the same sailing for the same
+// supplier at the same price, currency, pricing unit, occupancy, occupancy rates, single
+// supplement, price components, conditions, validity dates, held cabin quantity and
+// promotions, in answer to the same RFQ, i.e. whether other is an unchanged resubmission
+// of the quote. Both quotes must have their occupancy rates, components and promotions loaded.
+func (pq *PriceQuote) HasSameTerms(other *PriceQuote) bool {
+	return pq.SailingID == other.SailingID &&
+		pq.CabinTypeID == other.CabinTypeID &&
+		pq.SupplierID == other.SupplierID &&
+		pq.Price.Equal(other.Price) &&
+		strings.EqualFold(pq.Currency, other.Currency) &&
+		pq.PricingUnit == other.PricingUnit &&
+		sameInt(pq.GuestCount, other.GuestCount) &&
+		sameInt(pq.MaxOccupancy, other.MaxOccupancy) &&
+		pq.sameOccupancyRates(other) &&
+		sameDecimal(pq.SingleSupplementPct, other.SingleSupplementPct) &&
+		sameDecimal(pq.SingleSupplementAmount, other.SingleSupplementAmount) &&
+		sameComponents(pq.Components, other.Components) &&
+		strings.TrimSpace(pq.Conditions) == strings.TrimSpace(other.Conditions) &&
+		sameDate(pq.ValidFrom, other.ValidFrom) &&
+		sameDate(pq.ValidUntil, other.ValidUntil) &&
+		sameInt(pq.CabinQuantity, other.CabinQuantity) &&
+		strings.TrimSpace(pq.Promotion) == strings.TrimSpace(other.Promotion) &&
+		samePromotions(pq.Promotions, other.Promotions) &&
+		sameID(pq.RFQID, other.RFQID)
+}
+
+// sameOccupancyRates checks if two quotes charge the same for every guest slot and type
+func (pq *PriceQuote) sameOccupancyRates(other *PriceQuote) bool {
+	if len(pq.OccupancyRates) != len(other.OccupancyRates) {
 		return false
 	}
+	for _, rate := range pq.OccupancyRates {
+		match := other.OccupancyRate(rate.GuestSlot, rate.GuestType)
+		if match == nil || !match.Price.Equal(rate.Price) || !sameInt(match.MaxChildAge, rate.MaxChildAge) {
+			return false
+		}
+	}
 	return true
 }
 
-// PricePerPerson calculates the price per person based on pricing unit
+// sameComponents checks if two lists hold the same price component lines in any order
+func sameComponents(a, b []PriceComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+	for i := range a {
+		found := false
+		for j := range b {
+			if !used[j] && a[i].sameCharge(&b[j]) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sameCharge checks if two price component lines charge the same
+func (c *PriceComponent) sameCharge(other *PriceComponent) bool {
+	return c.ComponentType == other.ComponentType &&
+		c.Amount.Equal(other.Amount) &&
+		c.PricingUnit == other.PricingUnit &&
+		c.PerNight == other.PerNight &&
+		c.Inclusive == other.Inclusive &&
+		strings.TrimSpace(c.Description) == strings.TrimSpace(other.Description)
+}
+
+// sameID checks if two optional IDs are equal
+func sameID(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameInt checks if two optional numbers are equal
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameDate checks if two optional dates fall on the same day
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// BaseOccupancy returns the number of guests the quoted price covers
+func (pq *PriceQuote) BaseOccupancy(defaultGuestCount int) int {
+	if pq.GuestCount != nil && *pq.GuestCount > 0 {
+		return *pq.GuestCount
+	}
+	if defaultGuestCount > 0 {
+		return defaultGuestCount
+	}
+	return DefaultBaseOccupancy
+}
+
+// PricePerPerson calculates the price per person at base occupancy based on pricing unit.
+// It ignores occupancy rates; use the price normalization service for other occupancies.
 func (pq *PriceQuote) PricePerPerson(defaultGuestCount int) decimal.Decimal {
 	switch pq.PricingUnit {
 	case PricingUnitPerPerson:
 		return pq.Price
-	case PricingUnitPerCabin:
-		guests := defaultGuestCount
-		if pq.GuestCount != nil && *pq.GuestCount > 0 {
-			guests = *pq.GuestCount
+	case PricingUnitPerCabin, PricingUnitTotal:
+		return pq.Price.Div(decimal.NewFromInt(int64(pq.BaseOccupancy(defaultGuestCount))))
+	default:
+		return pq.Price
+	}
+}
+
+// OccupancyRate returns the rate for a berth and guest type, if the quote has one
+func (pq *PriceQuote) OccupancyRate(slot int, guestType GuestType) *OccupancyRate {
+	for i := range pq.OccupancyRates {
+		if pq.OccupancyRates[i].GuestSlot == slot && pq.OccupancyRates[i].GuestType == guestType {
+			return &pq.OccupancyRates[i]
 		}
-		if guests <= 0 {
-			guests = 2 // Default to 2 guests per cabin
+	}
+	return nil
+}
+
+// HasSingleSupplement checks if the quote states a single occupancy surcharge
+func (pq *PriceQuote) HasSingleSupplement() bool {
+	return pq.SingleSupplementPct != nil || pq.SingleSupplementAmount != nil
+}
+
+// HasExtraCharges checks if the quote lists charges payable on top of the quoted price
+func (pq *PriceQuote) HasExtraCharges() bool {
+	for _, c := range pq.Components {
+		if !c.Inclusive {
+			return true
 		}
-		return pq.Price.Div(decimal.NewFromInt(int64(guests)))
-	case PricingUnitTotal:
-		guests := defaultGuestCount
-		if pq.GuestCount != nil && *pq.GuestCount > 0 {
-			guests = *pq.GuestCount
+	}
+	return false
+}
+
+// ExtraCharges returns the charges payable on top of the quoted price for a party of
+// the given size, over the given number of nights. Per-person lines are charged for
+// every guest and per-cabin lines once. The result is false if a per-night line
+// cannot be priced because the number of nights is unknown.
+func (pq *PriceQuote) ExtraCharges(guests, nights int) (decimal.Decimal, bool) {
+	total := decimal.Zero
+	complete := true
+	for _, c := range pq.Components {
+		if c.Inclusive {
+			continue
 		}
-		if guests <= 0 {
-			guests = 2
+		amount, ok := c.amountForStay(nights)
+		if !ok {
+			complete = false
+			continue
 		}
-		return pq.Price.Div(decimal.NewFromInt(int64(guests)))
-	default:
-		return pq.Price
+		if c.PricingUnit == PricingUnitPerPerson {
+			amount = amount.Mul(decimal.NewFromInt(int64(guests)))
+		}
+		total = total.Add(amount)
+	}
+	return total, complete
+}
+
+// AllInPrice returns the quoted price plus the charges payable on top of it, in the
+// pricing unit of the quote, assuming base occupancy. The result is false if a
+// per-night line cannot be priced because the number of nights is unknown.
+func (pq *PriceQuote) AllInPrice(nights int) (decimal.Decimal, bool) {
+	base := decimal.NewFromInt(int64(pq.BaseOccupancy(0)))
+	total := pq.Price
+	complete := true
+	for _, c := range pq.Components {
+		if c.Inclusive {
+			continue
+		}
+		amount, ok := c.amountForStay(nights)
+		if !ok {
+			complete = false
+			continue
+		}
+		switch {
+		case c.PricingUnit == PricingUnitPerPerson && pq.PricingUnit != PricingUnitPerPerson:
+			amount = amount.Mul(base)
+		case c.PricingUnit != PricingUnitPerPerson && pq.PricingUnit == PricingUnitPerPerson:
+			amount = amount.Div(base)
+		}
+		total = total.Add(amount)
+	}
+	return total, complete
+}
+
+// amountForStay returns the amount of the line for the whole cruise
+func (c *PriceComponent) amountForStay(nights int) (decimal.Decimal, bool) {
+	if !c.PerNight {
+		return c.Amount, true
+	}
+	if nights <= 0 {
+		return decimal.Zero, false
 	}
+	return c.Amount.Mul(decimal.NewFromInt(int64(nights))), true
 }
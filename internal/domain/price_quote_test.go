@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// resubmissionBase returns a quote with occupancy rates, a single supplement and components
+func resubmissionBase() *PriceQuote {
+	guests, maxOccupancy := 2, 4
+	pct := decimal.RequireFromString("50")
+	return &PriceQuote{
+		SailingID:           1,
+		CabinTypeID:         2,
+		SupplierID:          3,
+		Price:               decimal.RequireFromString("8999.00"),
+		Currency:            "CNY",
+		PricingUnit:         PricingUnitPerPerson,
+		GuestCount:          &guests,
+		MaxOccupancy:        &maxOccupancy,
+		SingleSupplementPct: &pct,
+		OccupancyRates: []OccupancyRate{
+			{GuestSlot: 3, GuestType: GuestTypeAdult, Price: decimal.RequireFromString("4999")},
+			{GuestSlot: 3, GuestType: GuestTypeChild, Price: decimal.RequireFromString("2999")},
+		},
+		Components: []PriceComponent{
+			{ComponentType: PriceComponentPortTax, Amount: decimal.RequireFromString("800"), PricingUnit: PricingUnitPerPerson},
+			{ComponentType: PriceComponentGratuity, Amount: decimal.RequireFromString("16"), PricingUnit: PricingUnitPerPerson, PerNight: true},
+		},
+	}
+}
+
+func TestPriceQuoteHasSameTerms(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(q *PriceQuote)
+		want   bool
+	}{
+		{"unchanged", func(q *PriceQuote) {}, true},
+		{"same amounts at another scale", func(q *PriceQuote) {
+			q.Price = decimal.RequireFromString("8999")
+			q.OccupancyRates[0].Price = decimal.RequireFromString("4999.00")
+		}, true},
+		{"rates and components in another order", func(q *PriceQuote) {
+			q.OccupancyRates[0], q.OccupancyRates[1] = q.OccupancyRates[1], q.OccupancyRates[0]
+			q.Components[0], q.Components[1] = q.Components[1], q.Components[0]
+		}, true},
+		{"price", func(q *PriceQuote) { q.Price = decimal.RequireFromString("8899") }, false},
+		{"occupancy rate price", func(q *PriceQuote) { q.OccupancyRates[1].Price = decimal.RequireFromString("1999") }, false},
+		{"occupancy rate added", func(q *PriceQuote) {
+			q.OccupancyRates = append(q.OccupancyRates, OccupancyRate{GuestSlot: 4, GuestType: GuestTypeAdult, Price: decimal.RequireFromString("4999")})
+		}, false},
+		{"occupancy rates dropped", func(q *PriceQuote) { q.OccupancyRates = nil }, false},
+		{"component amount", func(q *PriceQuote) { q.Components[0].Amount = decimal.RequireFromString("900") }, false},
+		{"component inclusive", func(q *PriceQuote) { q.Components[1].Inclusive = true }, false},
+		{"component dropped", func(q *PriceQuote) { q.Components = q.Components[:1] }, false},
+		{"guest count", func(q *PriceQuote) { n := 3; q.GuestCount = &n }, false},
+		{"max occupancy cleared", func(q *PriceQuote) { q.MaxOccupancy = nil }, false},
+		{"single supplement pct", func(q *PriceQuote) { pct := decimal.RequireFromString("100"); q.SingleSupplementPct = &pct }, false},
+		{"single supplement amount added", func(q *PriceQuote) { amount := decimal.RequireFromString("3000"); q.SingleSupplementAmount = &amount }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := resubmissionBase()
+			resubmitted := resubmissionBase()
+			tt.change(resubmitted)
+
+			if got := latest.HasSameTerms(resubmitted); got != tt.want {
+				t.Errorf("HasSameTerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceQuoteIsConfirmedBy(t *testing.T) {
+	quotedAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		change func(q *PriceQuote)
+		want   bool
+	}{
+		{"issued the same day", func(q *PriceQuote) {}, true},
+		{"issued later", func(q *PriceQuote) { q.QuotedAt = quotedAt.AddDate(0, 0, 7) }, true},
+		{"issued earlier", func(q *PriceQuote) { q.QuotedAt = quotedAt.AddDate(0, 0, -7) }, false},
+		{"issued later with another price", func(q *PriceQuote) {
+			q.QuotedAt = quotedAt.AddDate(0, 0, 7)
+			q.Price = decimal.RequireFromString("8899")
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := resubmissionBase()
+			quote.QuotedAt = quotedAt
+			submission := resubmissionBase()
+			submission.QuotedAt = quotedAt
+			tt.change(submission)
+			if got := quote.IsConfirmedBy(submission); got != tt.want {
+				t.Errorf("IsConfirmedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceQuoteWithConfirmation(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		lastConfirmed *time.Time
+		confirmedAt   time.Time
+		want          time.Time
+	}{
+		{"first confirmation", nil, day(9), day(9)},
+		{"newer confirmation", func() *time.Time { d := day(9); return &d }(), day(16), day(16)},
+		{"older confirmation keeps the newer date", func() *time.Time { d := day(16); return &d }(), day(9), day(16)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := resubmissionBase()
+			quote.LastConfirmedAt = tt.lastConfirmed
+			quote.ConfirmationCount = 1
+
+			confirmed := quote.WithConfirmation(tt.confirmedAt)
+			if confirmed.LastConfirmedAt == nil || !confirmed.LastConfirmedAt.Equal(tt.want) {
+				t.Errorf("LastConfirmedAt = %v, want %v", confirmed.LastConfirmedAt, tt.want)
+			}
+			if confirmed.ConfirmationCount != 2 {
+				t.Errorf("ConfirmationCount = %d, want 2", confirmed.ConfirmationCount)
+			}
+			if quote.ConfirmationCount != 1 {
+				t.Errorf("WithConfirmation changed the quote")
+			}
+		})
+	}
+}
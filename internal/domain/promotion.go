@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PromotionType represents the kind of deal a promotion is
+type PromotionType string
+
+const (
+	PromotionEarlyBird     PromotionType = "EARLY_BIRD"
+	PromotionLastMinute    PromotionType = "LAST_MINUTE"
+	PromotionGuestDiscount PromotionType = "GUEST_DISCOUNT" // e.g. 2nd guest 50% off, 3rd/4th guest free
+	PromotionFlashSale     PromotionType = "FLASH_SALE"
+	PromotionMember        PromotionType = "MEMBER" // Loyalty or member-only rate, not everyone qualifies
+	PromotionOnboardCredit PromotionType = "ONBOARD_CREDIT"
+	PromotionOther         PromotionType = "OTHER"
+)
+
+// promotionPricePrecision is the number of decimal places of effective prices
+const promotionPricePrecision = 2
+
+// Promotion is a deal attached to a quote. DiscountPct takes a percentage off the fare of each
+// applicable guest; DiscountAmount, in the quote currency, is taken off per applicable guest
+// when PricingUnit is PER_PERSON and once per cabin otherwise. GuestSlots lists the 1-based
+// berths the discount applies to, empty for every guest.
+type Promotion struct {
+	ID             uint64           `json:"id" db:"id"`
+	QuoteID        uint64           `json:"quote_id" db:"quote_id"`
+	PromotionType  PromotionType    `json:"promotion_type" db:"promotion_type"`
+	DiscountPct    *decimal.Decimal `json:"discount_pct,omitempty" db:"discount_pct"`
+	DiscountAmount *decimal.Decimal `json:"discount_amount,omitempty" db:"discount_amount"`
+	PricingUnit    PricingUnit      `json:"pricing_unit" db:"pricing_unit"`
+	GuestSlots     []int            `json:"guest_slots,omitempty" db:"-"`
+	BookingFrom    *time.Time       `json:"booking_from,omitempty" db:"booking_from"`
+	BookingUntil   *time.Time       `json:"booking_until,omitempty" db:"booking_until"`
+	Stackable      bool             `json:"stackable" db:"stackable"`
+	Description    string           `json:"description,omitempty" db:"description"`
+}
+
+// InBookingWindow checks if a booking made at t qualifies. The window includes both dates.
+func (p *Promotion) InBookingWindow(t time.Time) bool {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if p.BookingFrom != nil {
+		fy, fm, fd := p.BookingFrom.Date()
+		if day.Before(time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)) {
+			return false
+		}
+	}
+	if p.BookingUntil != nil {
+		uy, um, ud := p.BookingUntil.Date()
+		if day.After(time.Date(uy, um, ud, 0, 0, 0, 0, time.UTC)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReducesFare checks if the promotion takes money off the fare. Onboard credit does not.
+func (p *Promotion) ReducesFare() bool {
+	return p.PromotionType != PromotionOnboardCredit && (p.DiscountPct != nil || p.DiscountAmount != nil)
+}
+
+// IsEligible checks if anyone booking at t gets the promotion: it reduces the fare, the
+// booking window is open and it is not restricted to members
+func (p *Promotion) IsEligible(t time.Time) bool {
+	return p.ReducesFare() && p.InBookingWindow(t) && p.PromotionType != PromotionMember
+}
+
+// appliesTo checks if the discount covers the guest in a 1-based slot
+func (p *Promotion) appliesTo(slot int) bool {
+	if len(p.GuestSlots) == 0 {
+		return true
+	}
+	for _, s := range p.GuestSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the amount the promotion takes off a cabin of guests guests paying
+// farePerGuest each
+func (p *Promotion) Discount(farePerGuest decimal.Decimal, guests int) decimal.Decimal {
+	applicable := 0
+	for slot := 1; slot <= guests; slot++ {
+		if p.appliesTo(slot) {
+			applicable++
+		}
+	}
+
+	discount := decimal.Zero
+	if p.DiscountPct != nil {
+		discount = discount.Add(farePerGuest.Mul(*p.DiscountPct).Div(decimal.NewFromInt(100)).Mul(decimal.NewFromInt(int64(applicable))))
+	}
+	if p.DiscountAmount != nil {
+		if p.PricingUnit == PricingUnitPerPerson {
+			discount = discount.Add(p.DiscountAmount.Mul(decimal.NewFromInt(int64(applicable))))
+		} else if applicable > 0 {
+			discount = discount.Add(*p.DiscountAmount)
+		}
+	}
+	return discount
+}
+
+// HasPromotion checks if the quote carries a promotion of the given type
+func (pq *PriceQuote) HasPromotion(promotionType PromotionType) bool {
+	for i := range pq.Promotions {
+		if pq.Promotions[i].PromotionType == promotionType {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectivePrice returns the quoted price after the promotions anyone booking at t gets, in
+// the pricing unit of the quote at base occupancy, and the promotions applied. The best
+// non-stackable promotion applies together with every stackable one; the price does not
+// drop below zero.
+func (pq *PriceQuote) EffectivePrice(t time.Time) (decimal.Decimal, []Promotion) {
+	guests := pq.BaseOccupancy(0)
+	cabinTotal := pq.Price
+	if pq.PricingUnit == PricingUnitPerPerson {
+		cabinTotal = pq.Price.Mul(decimal.NewFromInt(int64(guests)))
+	}
+	farePerGuest := cabinTotal.Div(decimal.NewFromInt(int64(guests)))
+
+	var applied []Promotion
+	discount := decimal.Zero
+	best := -1
+	bestDiscount := decimal.Zero
+	for i := range pq.Promotions {
+		p := &pq.Promotions[i]
+		if !p.IsEligible(t) {
+			continue
+		}
+		d := p.Discount(farePerGuest, guests)
+		if p.Stackable {
+			applied = append(applied, *p)
+			discount = discount.Add(d)
+			continue
+		}
+		if best < 0 || d.GreaterThan(bestDiscount) {
+			best = i
+			bestDiscount = d
+		}
+	}
+	if best >= 0 {
+		applied = append(applied, pq.Promotions[best])
+		discount = discount.Add(bestDiscount)
+	}
+
+	effective := cabinTotal.Sub(discount)
+	if effective.IsNegative() {
+		effective = decimal.Zero
+	}
+	if pq.PricingUnit == PricingUnitPerPerson {
+		effective = effective.Div(decimal.NewFromInt(int64(guests)))
+	}
+
+	return effective.Round(promotionPricePrecision), applied
+}
+
+// samePromotions checks if two promotion lists describe the same deals in the same order
+func samePromotions(a, b []Promotion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].sameDeal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDeal checks if two promotions describe the same deal
+func (p *Promotion) sameDeal(other *Promotion) bool {
+	if p.PromotionType != other.PromotionType || p.PricingUnit != other.PricingUnit || p.Stackable != other.Stackable ||
+		!sameDecimal(p.DiscountPct, other.DiscountPct) || !sameDecimal(p.DiscountAmount, other.DiscountAmount) ||
+		!sameDate(p.BookingFrom, other.BookingFrom) || !sameDate(p.BookingUntil, other.BookingUntil) ||
+		len(p.GuestSlots) != len(other.GuestSlots) {
+		return false
+	}
+	for i := range p.GuestSlots {
+		if p.GuestSlots[i] != other.GuestSlots[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDecimal checks if two optional amounts are equal
+func sameDecimal(a, b *decimal.Decimal) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AnomalyCheck identifies the check that found a quote suspicious
+type AnomalyCheck string
+
+const (
+	AnomalyCheckSupplierHistory AnomalyCheck = "SUPPLIER_HISTORY" // far off the supplier's earlier prices for the cabin
+	AnomalyCheckMarketPrice     AnomalyCheck = "MARKET_PRICE"     // far off other suppliers' current prices for the cabin
+	AnomalyCheckCategoryOrder   AnomalyCheck = "CATEGORY_ORDER"   // out of line with the supplier's prices of other categories
+)
+
+// ReviewFlagStatus represents the review state of a flagged quote
+type ReviewFlagStatus string
+
+const (
+	ReviewFlagOpen      ReviewFlagStatus = "OPEN"      // Waiting for review
+	ReviewFlagDismissed ReviewFlagStatus = "DISMISSED" // Reviewed, the price is fine
+	ReviewFlagConfirmed ReviewFlagStatus = "CONFIRMED" // Reviewed, the price is wrong
+)
+
+// AnomalyReason explains why a quote was flagged. Prices are per person at base
+// occupancy in the currency of the flagged quote.
+type AnomalyReason struct {
+	Check     AnomalyCheck    `json:"check"`
+	Message   string          `json:"message"`
+	Price     decimal.Decimal `json:"price"`
+	Reference decimal.Decimal `json:"reference"`
+	Currency  string          `json:"currency"`
+}
+
+// QuoteReviewFlag marks a quote whose price looks like a data entry error for admin review
+type QuoteReviewFlag struct {
+	ID          uint64           `json:"id" db:"id"`
+	QuoteID     uint64           `json:"quote_id" db:"quote_id"`
+	SailingID   uint64           `json:"sailing_id" db:"sailing_id"`
+	CabinTypeID uint64           `json:"cabin_type_id" db:"cabin_type_id"`
+	SupplierID  uint64           `json:"supplier_id" db:"supplier_id"`
+	Reasons     []AnomalyReason  `json:"reasons" db:"-"`
+	Status      ReviewFlagStatus `json:"status" db:"status"`
+	ReviewNote  string           `json:"review_note,omitempty" db:"review_note"`
+	ReviewedBy  *uint64          `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt  *time.Time       `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+
+	// Loaded relations
+	Quote *PriceQuote `json:"quote,omitempty" db:"-"`
+}
+
+// IsOpen checks if the flag still waits for review
+func (f *QuoteReviewFlag) IsOpen() bool {
+	return f.Status == ReviewFlagOpen
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "time"
+
+// RFQStatus represents the status of a request for quote
+type RFQStatus string
+
+const (
+	RFQStatusOpen      RFQStatus = "OPEN"      // Suppliers may respond until the deadline
+	RFQStatusClosed    RFQStatus = "CLOSED"    // Closed by an admin, responses are kept
+	RFQStatusCancelled RFQStatus = "CANCELLED" // Withdrawn by an admin
+)
+
+// RFQResponseStatus represents how far an invited supplier has answered an RFQ
+type RFQResponseStatus string
+
+const (
+	RFQResponsePending   RFQResponseStatus = "PENDING"   // No quote yet
+	RFQResponsePartial   RFQResponseStatus = "PARTIAL"   // Quoted some of the requested cabins
+	RFQResponseResponded RFQResponseStatus = "RESPONDED" // Quoted every requested cabin
+	RFQResponseDeclined  RFQResponseStatus = "DECLINED"  // Declined to quote
+)
+
+// RFQ is a request for quote an admin issues to chosen suppliers: prices for a group of
+// CabinQuantity cabins of each requested sailing cabin type, due by the deadline. Suppliers
+// answer with quotes linked to the RFQ.
+type RFQ struct {
+	ID            uint64     `json:"id" db:"id"`
+	Title         string     `json:"title" db:"title"`
+	Notes         string     `json:"notes,omitempty" db:"notes"`
+	CabinQuantity int        `json:"cabin_quantity" db:"cabin_quantity"`
+	Deadline      time.Time  `json:"deadline" db:"deadline"`
+	Status        RFQStatus  `json:"status" db:"status"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedBy     uint64     `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Loaded relations
+	Items     []RFQItem     `json:"items,omitempty" db:"-"`
+	Suppliers []RFQSupplier `json:"suppliers,omitempty" db:"-"`
+}
+
+// RFQItem is a sailing cabin type an RFQ asks prices for
+type RFQItem struct {
+	ID          uint64 `json:"id" db:"id"`
+	RFQID       uint64 `json:"rfq_id" db:"rfq_id"`
+	SailingID   uint64 `json:"sailing_id" db:"sailing_id"`
+	CabinTypeID uint64 `json:"cabin_type_id" db:"cabin_type_id"`
+}
+
+// RFQSupplier is a supplier an RFQ is issued to
+type RFQSupplier struct {
+	RFQID         uint64     `json:"rfq_id" db:"rfq_id"`
+	SupplierID    uint64     `json:"supplier_id" db:"supplier_id"`
+	DeclinedAt    *time.Time `json:"declined_at,omitempty" db:"declined_at"`
+	DeclineReason string     `json:"decline_reason,omitempty" db:"decline_reason"`
+	RemindedAt    *time.Time `json:"reminded_at,omitempty" db:"reminded_at"`
+	ReminderCount int        `json:"reminder_count" db:"reminder_count"`
+}
+
+// IsOpen checks if suppliers may still respond to the RFQ
+func (r *RFQ) IsOpen(now time.Time) bool {
+	return r.Status == RFQStatusOpen && now.Before(r.Deadline)
+}
+
+// HasItem checks if the RFQ asks prices for a sailing cabin type
+func (r *RFQ) HasItem(sailingID, cabinTypeID uint64) bool {
+	for _, item := range r.Items {
+		if item.SailingID == sailingID && item.CabinTypeID == cabinTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Invitation returns the invitation of a supplier, nil when the RFQ is not issued to it
+func (r *RFQ) Invitation(supplierID uint64) *RFQSupplier {
+	for i := range r.Suppliers {
+		if r.Suppliers[i].SupplierID == supplierID {
+			return &r.Suppliers[i]
+		}
+	}
+	return nil
+}
+
+// ForSupplier returns a copy of the RFQ as an invited supplier sees it: the other suppliers
+// it was issued to are left out
+func (r *RFQ) ForSupplier(supplierID uint64) *RFQ {
+	view := *r
+	view.Suppliers = nil
+	if invitation := r.Invitation(supplierID); invitation != nil {
+		view.Suppliers = []RFQSupplier{*invitation}
+	}
+	return &view
+}
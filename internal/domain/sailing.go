@@ -29,8 +29,9 @@ type Sailing struct {
 	CreatedBy     *uint64       `json:"created_by,omitempty" db:"created_by"`
 
 	// Loaded relations
-	Ship        *Ship        `json:"ship,omitempty" db:"-"`
-	PriceQuotes []PriceQuote `json:"price_quotes,omitempty" db:"-"`
+	Ship        *Ship           `json:"ship,omitempty" db:"-"`
+	PriceQuotes []PriceQuote    `json:"price_quotes,omitempty" db:"-"`
+	Itinerary   []ItineraryStop `json:"itinerary,omitempty" db:"-"`
 }
 
 // IsActive checks if sailing is active
@@ -9,6 +9,7 @@ type Ship struct {
 	ID           uint64       `json:"id" db:"id"`
 	CruiseLineID uint64       `json:"cruise_line_id" db:"cruise_line_id"`
 	Name         string       `json:"name" db:"name"`
+	NameEN       string       `json:"name_en,omitempty" db:"name_en"`
 	Aliases      []string     `json:"aliases,omitempty" db:"aliases"`
 	Status       EntityStatus `json:"status" db:"status"`
 	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
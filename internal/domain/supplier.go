@@ -24,6 +24,9 @@ type Supplier struct {
 	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
 	CreatedBy   *uint64            `json:"created_by,omitempty" db:"created_by"`
 
+	// New quotes of the supplier wait as PENDING_REVIEW until an admin approves them
+	RequiresApproval bool `json:"requires_approval" db:"requires_approval"`
+
 	// Loaded relations
 	Users       []User       `json:"users,omitempty" db:"-"`
 	PriceQuotes []PriceQuote `json:"price_quotes,omitempty" db:"-"`
@@ -3,9 +3,12 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"time"
 	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
 )
 
 // Validation errors
@@ -255,6 +258,64 @@ func ValidateSailing(s *Sailing) ValidationErrors {
 	return v.Errors()
 }
 
+const (
+	// unlocodePattern matches a UN/LOCODE without the space: ISO country code plus a 3-character location
+	unlocodePattern = `^[A-Z]{2}[A-Z2-9]{3}$`
+
+	// itineraryTimePattern matches a local time in HH:MM
+	itineraryTimePattern = `^([01][0-9]|2[0-3]):[0-5][0-9]$`
+)
+
+// ValidatePort validates a port entity
+func ValidatePort(p *Port) ValidationErrors {
+	v := NewValidator()
+
+	v.Required("name", p.Name)
+	if p.Name != "" {
+		v.LengthRange("name", p.Name, 2, 100)
+	}
+	v.MaxLength("country", p.Country, 100)
+	if p.UNLocode != "" {
+		v.Pattern("unlocode", p.UNLocode, unlocodePattern)
+	}
+
+	return v.Errors()
+}
+
+// ValidateItinerary validates the stops of a sailing itinerary. Days run from 1, the
+// departure day, to nights+1, the return day; a port day names its port and a sea day none.
+func ValidateItinerary(stops []ItineraryStop, nights int) ValidationErrors {
+	v := NewValidator()
+
+	for i, stop := range stops {
+		field := fmt.Sprintf("itinerary[%d]", i)
+
+		if stop.DayNumber < 1 || (nights > 0 && stop.DayNumber > nights+1) {
+			v.errors.AddMsg(field+".day_number", fmt.Sprintf("must be between 1 and %d", nights+1))
+		}
+		if i > 0 && stop.DayNumber < stops[i-1].DayNumber {
+			v.errors.AddMsg(field+".day_number", "stops must be in day order")
+		}
+
+		if stop.IsSeaDay && stop.PortID != nil {
+			v.errors.AddMsg(field+".port_id", "must be empty on a sea day")
+		}
+		if !stop.IsSeaDay && stop.PortID == nil {
+			v.errors.Add(field+".port_id", ErrFieldRequired)
+		}
+
+		if stop.ArriveTime != nil {
+			v.Pattern(field+".arrive_time", *stop.ArriveTime, itineraryTimePattern)
+		}
+		if stop.DepartTime != nil {
+			v.Pattern(field+".depart_time", *stop.DepartTime, itineraryTimePattern)
+		}
+		v.MaxLength(field+".note", stop.Note, 255)
+	}
+
+	return v.Errors()
+}
+
 // ValidateCabinType validates a cabin type entity
 func ValidateCabinType(ct *CabinType) ValidationErrors {
 	v := NewValidator()
@@ -308,7 +369,256 @@ func ValidatePriceQuote(pq *PriceQuote) ValidationErrors {
 		string(QuoteSourceFileImport),
 		string(QuoteSourceTextImport),
 		string(QuoteSourceTemplateImport),
+		string(QuoteSourceBackfill),
+	})
+
+	for _, err := range ValidateOccupancyPricing(pq) {
+		v.errors = append(v.errors, err)
+	}
+	for _, err := range ValidatePriceComponents(pq) {
+		v.errors = append(v.errors, err)
+	}
+	for _, err := range ValidatePromotions(pq) {
+		v.errors = append(v.errors, err)
+	}
+
+	return v.Errors()
+}
+
+// ValidateOccupancyPricing validates the occupancy rates and single supplement of a price quote
+func ValidateOccupancyPricing(pq *PriceQuote) ValidationErrors {
+	v := NewValidator()
+
+	if pq.MaxOccupancy != nil {
+		v.PositiveInt("max_occupancy", int64(*pq.MaxOccupancy))
+		if pq.GuestCount != nil && *pq.GuestCount > *pq.MaxOccupancy {
+			v.errors.AddMsg("guest_count", "must not exceed max_occupancy")
+		}
+	}
+
+	if pq.SingleSupplementPct != nil && pq.SingleSupplementPct.IsNegative() {
+		v.errors.AddMsg("single_supplement_pct", "must not be negative")
+	}
+	if pq.SingleSupplementAmount != nil && pq.SingleSupplementAmount.IsNegative() {
+		v.errors.AddMsg("single_supplement_amount", "must not be negative")
+	}
+
+	seen := make(map[string]bool, len(pq.OccupancyRates))
+	for i, rate := range pq.OccupancyRates {
+		field := fmt.Sprintf("occupancy_rates[%d]", i)
+
+		v.PositiveInt(field+".guest_slot", int64(rate.GuestSlot))
+		if pq.MaxOccupancy != nil && rate.GuestSlot > *pq.MaxOccupancy {
+			v.errors.AddMsg(field+".guest_slot", "must not exceed max_occupancy")
+		}
+
+		v.OneOf(field+".guest_type", string(rate.GuestType), []string{
+			string(GuestTypeAdult),
+			string(GuestTypeChild),
+		})
+
+		if rate.Price.IsNegative() {
+			v.errors.AddMsg(field+".price", "must not be negative")
+		}
+
+		if rate.MaxChildAge != nil && rate.GuestType != GuestTypeChild {
+			v.errors.AddMsg(field+".max_child_age", "only applies to child rates")
+		}
+
+		key := fmt.Sprintf("%d|%s", rate.GuestSlot, rate.GuestType)
+		if seen[key] {
+			v.errors.AddMsg(field, "duplicate guest_slot and guest_type")
+		}
+		seen[key] = true
+	}
+
+	return v.Errors()
+}
+
+// ValidatePriceComponents validates the price component lines of a price quote
+func ValidatePriceComponents(pq *PriceQuote) ValidationErrors {
+	v := NewValidator()
+
+	for i, c := range pq.Components {
+		field := fmt.Sprintf("components[%d]", i)
+
+		v.OneOf(field+".component_type", string(c.ComponentType), []string{
+			string(PriceComponentBaseFare),
+			string(PriceComponentPortTax),
+			string(PriceComponentGratuity),
+			string(PriceComponentFuelSurcharge),
+			string(PriceComponentOther),
+		})
+
+		v.OneOf(field+".pricing_unit", string(c.PricingUnit), []string{
+			string(PricingUnitPerPerson),
+			string(PricingUnitPerCabin),
+			string(PricingUnitTotal),
+		})
+
+		if c.Amount.IsNegative() {
+			v.errors.AddMsg(field+".amount", "must not be negative")
+		}
+
+		if c.ComponentType == PriceComponentBaseFare && !c.Inclusive {
+			v.errors.AddMsg(field+".inclusive", "base fare is always part of the quoted price")
+		}
+
+		v.MaxLength(field+".description", c.Description, 255)
+	}
+
+	return v.Errors()
+}
+
+// ValidatePromotions validates the structured promotions of a price quote
+func ValidatePromotions(pq *PriceQuote) ValidationErrors {
+	v := NewValidator()
+	hundred := decimal.NewFromInt(100)
+
+	for i, p := range pq.Promotions {
+		field := fmt.Sprintf("promotions[%d]", i)
+
+		v.OneOf(field+".promotion_type", string(p.PromotionType), []string{
+			string(PromotionEarlyBird),
+			string(PromotionLastMinute),
+			string(PromotionGuestDiscount),
+			string(PromotionFlashSale),
+			string(PromotionMember),
+			string(PromotionOnboardCredit),
+			string(PromotionOther),
+		})
+
+		v.OneOf(field+".pricing_unit", string(p.PricingUnit), []string{
+			string(PricingUnitPerPerson),
+			string(PricingUnitPerCabin),
+			string(PricingUnitTotal),
+		})
+
+		if p.DiscountPct != nil && (!p.DiscountPct.IsPositive() || p.DiscountPct.GreaterThan(hundred)) {
+			v.errors.AddMsg(field+".discount_pct", "must be greater than 0 and at most 100")
+		}
+		if p.DiscountAmount != nil && p.DiscountAmount.IsNegative() {
+			v.errors.AddMsg(field+".discount_amount", "must not be negative")
+		}
+
+		for j, slot := range p.GuestSlots {
+			slotField := fmt.Sprintf("%s.guest_slots[%d]", field, j)
+			v.PositiveInt(slotField, int64(slot))
+			if pq.MaxOccupancy != nil && slot > *pq.MaxOccupancy {
+				v.errors.AddMsg(slotField, "must not exceed max_occupancy")
+			}
+		}
+
+		if p.BookingFrom != nil && p.BookingUntil != nil && p.BookingUntil.Before(*p.BookingFrom) {
+			v.errors.Add(field+".booking_until", ErrDateOrderInvalid)
+		}
+
+		v.MaxLength(field+".description", p.Description, 255)
+	}
+
+	return v.Errors()
+}
+
+// ValidateAlertRule validates an alert rule entity
+func ValidateAlertRule(r *AlertRule) ValidationErrors {
+	v := NewValidator()
+
+	if v.Required("name", r.Name) {
+		v.MaxLength("name", r.Name, 100)
+	}
+
+	if (r.SailingID == nil) == (r.ShipID == nil) {
+		v.errors.AddMsg("sailing_id", "exactly one of sailing_id and ship_id is required")
+	}
+	if r.CabinTypeID != nil && r.CabinCategoryID != nil {
+		v.errors.AddMsg("cabin_type_id", "cannot be combined with cabin_category_id")
+	}
+
+	v.OneOf("condition", string(r.Condition), []string{
+		string(AlertConditionBelowPrice),
+		string(AlertConditionDropPercent),
+		string(AlertConditionNewLowestSupplier),
 	})
 
+	switch r.Condition {
+	case AlertConditionBelowPrice:
+		if r.Threshold == nil || !r.Threshold.IsPositive() {
+			v.errors.Add("threshold", ErrFieldMustBePositive)
+		}
+		v.Pattern("currency", r.Currency, `^[A-Z]{3}$`)
+	case AlertConditionDropPercent:
+		if r.Threshold == nil || !r.Threshold.IsPositive() || r.Threshold.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+			v.errors.AddMsg("threshold", "must be a percentage between 0 and 100")
+		}
+	case AlertConditionNewLowestSupplier:
+		if r.Threshold != nil {
+			v.errors.AddMsg("threshold", "does not apply to NEW_LOWEST_SUPPLIER")
+		}
+	}
+
+	return v.Errors()
+}
+
+// ValidateFXRate validates an exchange rate entity
+func ValidateFXRate(r *FXRate) ValidationErrors {
+	v := NewValidator()
+
+	if r.RateDate.IsZero() {
+		v.errors.Add("rate_date", ErrFieldRequired)
+	}
+
+	v.Pattern("base_currency", r.BaseCurrency, `^[A-Z]{3}$`)
+	v.Pattern("quote_currency", r.QuoteCurrency, `^[A-Z]{3}$`)
+	if r.BaseCurrency != "" && r.BaseCurrency == r.QuoteCurrency {
+		v.errors.AddMsg("quote_currency", "must differ from base_currency")
+	}
+
+	if !r.Rate.IsPositive() {
+		v.errors.Add("rate", ErrFieldMustBePositive)
+	}
+
+	v.OneOf("source", string(r.Source), []string{
+		string(FXRateSourceManual),
+		string(FXRateSourceCSV),
+		string(FXRateSourceECBXML),
+	})
+
+	return v.Errors()
+}
+
+// ValidateWebhookEndpoint validates a webhook endpoint entity
+func ValidateWebhookEndpoint(e *WebhookEndpoint) ValidationErrors {
+	v := NewValidator()
+
+	if v.Required("name", e.Name) {
+		v.MaxLength("name", e.Name, 100)
+	}
+
+	if v.Required("url", e.URL) && v.MaxLength("url", e.URL, 500) {
+		u, err := url.Parse(e.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.errors.AddMsg("url", "must be an absolute http or https URL")
+		}
+	}
+
+	if v.Required("secret", e.Secret) {
+		v.LengthRange("secret", e.Secret, 16, 128)
+	}
+
+	if len(e.Events) == 0 {
+		v.errors.Add("events", ErrFieldRequired)
+	}
+	allowed := make([]string, len(WebhookEventTypes))
+	for i, t := range WebhookEventTypes {
+		allowed[i] = string(t)
+	}
+	for i, t := range e.Events {
+		v.OneOf(fmt.Sprintf("events[%d]", i), string(t), allowed)
+	}
+
+	for i, id := range e.SupplierIDs {
+		v.PositiveInt(fmt.Sprintf("supplier_ids[%d]", i), int64(id))
+	}
+
 	return v.Errors()
 }
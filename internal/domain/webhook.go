@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventType represents the type of event sent to webhook endpoints
+type WebhookEventType string
+
+const (
+	WebhookEventQuoteCreated      WebhookEventType = "quote.created"
+	WebhookEventQuoteVoided       WebhookEventType = "quote.voided"
+	WebhookEventQuoteCorrected    WebhookEventType = "quote.corrected"
+	WebhookEventQuoteApproved     WebhookEventType = "quote.approved"
+	WebhookEventQuoteRejected     WebhookEventType = "quote.rejected"
+	WebhookEventImportJobFinished WebhookEventType = "import_job.finished"
+	WebhookEventRFQIssued         WebhookEventType = "rfq.issued"
+	WebhookEventRFQReminder       WebhookEventType = "rfq.reminder"
+)
+
+// WebhookEventTypes lists every event type an endpoint can subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventQuoteCreated,
+	WebhookEventQuoteVoided,
+	WebhookEventQuoteCorrected,
+	WebhookEventQuoteApproved,
+	WebhookEventQuoteRejected,
+	WebhookEventImportJobFinished,
+	WebhookEventRFQIssued,
+	WebhookEventRFQReminder,
+}
+
+// WebhookDeliveryStatus represents the status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"   // Waiting for its next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED" // Receiver answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"    // Retries exhausted
+)
+
+// WebhookEndpoint is a receiver URL that gets signed event payloads
+type WebhookEndpoint struct {
+	ID          uint64             `json:"id" db:"id"`
+	Name        string             `json:"name" db:"name"`
+	URL         string             `json:"url" db:"url"`
+	Secret      string             `json:"-" db:"secret"` // HMAC-SHA256 signing key
+	Events      []WebhookEventType `json:"events" db:"events"`
+	SupplierIDs []uint64           `json:"supplier_ids,omitempty" db:"supplier_ids"` // Empty: every supplier
+	IsEnabled   bool               `json:"is_enabled" db:"is_enabled"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+	CreatedBy   *uint64            `json:"created_by,omitempty" db:"created_by"`
+}
+
+// Subscribes checks if the endpoint wants an event of the given type and supplier.
+// An endpoint with a supplier filter only receives events that belong to one of its suppliers.
+func (e *WebhookEndpoint) Subscribes(eventType WebhookEventType, supplierID *uint64) bool {
+	if !e.IsEnabled {
+		return false
+	}
+
+	subscribed := false
+	for _, t := range e.Events {
+		if t == eventType {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		return false
+	}
+
+	if len(e.SupplierIDs) == 0 {
+		return true
+	}
+	if supplierID == nil {
+		return false
+	}
+	for _, id := range e.SupplierIDs {
+		if id == *supplierID {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent to one endpoint, together with the outcome of its latest attempt
+type WebhookDelivery struct {
+	ID             uint64                `json:"id" db:"id"`
+	EndpointID     uint64                `json:"endpoint_id" db:"endpoint_id"`
+	EventID        string                `json:"event_id" db:"event_id"` // Same for redeliveries of an event
+	EventType      WebhookEventType      `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	AttemptCount   int                   `json:"attempt_count" db:"attempt_count"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	ResponseBody   string                `json:"response_body,omitempty" db:"response_body"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	RedeliveryOf   *uint64               `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// IsFinal checks if no further attempts will be made
+func (d *WebhookDelivery) IsFinal() bool {
+	return d.Status == WebhookDeliverySucceeded || d.Status == WebhookDeliveryFailed
+}
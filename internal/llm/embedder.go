@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns text into a dense vector for semantic similarity
+type Embedder interface {
+	// Embed returns the embedding vector of text
+	Embed(ctx context.Context, text string) ([]float32, error)
+	// Model identifies the embedding space; vectors from different models are not comparable
+	Model() string
+}
+
+// Ensure OllamaClient implements Embedder
+var _ Embedder = (*OllamaClient)(nil)
+
+// FakeEmbedder is a deterministic, offline Embedder based on hashed character
+// n-grams. It has no real semantic knowledge, but keeps the embedding pipeline
+// usable in development and dry runs without an Ollama instance.
+type FakeEmbedder struct {
+	dimensions int
+}
+
+// NewFakeEmbedder creates a new fake embedder
+func NewFakeEmbedder(dimensions int) *FakeEmbedder {
+	if dimensions <= 0 {
+		dimensions = 256
+	}
+	return &FakeEmbedder{dimensions: dimensions}
+}
+
+// Embed returns a normalized bag-of-n-grams vector of text
+func (e *FakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vector := make([]float32, e.dimensions)
+
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	// Unigrams and bigrams of runes
+	for n := 1; n <= 2; n++ {
+		for i := 0; i+n <= len(runes); i++ {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(string(runes[i : i+n])))
+			vector[h.Sum32()%uint32(e.dimensions)] += float32(n)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+
+	return vector, nil
+}
+
+// Model returns the fake model name
+func (e *FakeEmbedder) Model() string {
+	return fmt.Sprintf("fake-ngram-%d", e.dimensions)
+}
+
+// CosineSimilarity calculates the cosine similarity of two vectors (-1.0 to 1.0).
+// Vectors of different length are not comparable and yield 0.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Embedding providers
+const (
+	EmbeddingProviderOllama = "ollama"
+	EmbeddingProviderFake   = "fake"
+)
+
+// NewEmbedder creates the Embedder for a provider name
+func NewEmbedder(provider, baseURL, model string) (Embedder, error) {
+	switch provider {
+	case EmbeddingProviderOllama, "":
+		return NewOllamaClient(baseURL, model), nil
+	case EmbeddingProviderFake:
+		return NewFakeEmbedder(0), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s", provider)
+	}
+}
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Generator completes a prompt with text
+type Generator interface {
+	// Generate returns the completion of prompt
+	Generate(ctx context.Context, prompt string) (string, error)
+}
+
+// Ensure OllamaClient implements Generator
+var _ Generator = (*OllamaClient)(nil)
+
+// fakeQuoteResponse is the fixed answer of the fake generator; it passes response validation
+// but names no real sailing, so imports run through extraction, parsing and matching and then
+// fail with the sailing not found
+const fakeQuoteResponse = `{
+  "sailing_code": "FAKE-0001",
+  "ship_name": "Fake Ship",
+  "departure_date": "2000-01-01",
+  "nights": 1,
+  "route": "",
+  "ports": [],
+  "quotes": [
+    {
+      "cabin_type_name": "Fake Cabin",
+      "cabin_category": "内舱",
+      "price": 1,
+      "currency": "CNY",
+      "pricing_unit": "PER_PERSON",
+      "components": [],
+      "promotions": []
+    }
+  ]
+}`
+
+// FakeGenerator is an offline Generator that answers every prompt with a fixed quote parse
+// result. It has no language understanding, but keeps the import pipeline usable in development
+// and dry runs without an Ollama instance.
+type FakeGenerator struct{}
+
+// NewFakeGenerator creates a new fake generator
+func NewFakeGenerator() *FakeGenerator {
+	return &FakeGenerator{}
+}
+
+// Generate returns the fixed quote parse result
+func (g *FakeGenerator) Generate(ctx context.Context, prompt string) (string, error) {
+	return fakeQuoteResponse, nil
+}
+
+// LLM providers for text generation
+const (
+	LLMProviderOllama = "ollama"
+	LLMProviderFake   = "fake"
+)
+
+// NewGenerator creates the Generator for a provider name
+func NewGenerator(provider, baseURL, model string) (Generator, error) {
+	switch provider {
+	case LLMProviderOllama, "":
+		return NewOllamaClient(baseURL, model), nil
+	case LLMProviderFake:
+		return NewFakeGenerator(), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %s", provider)
+	}
+}
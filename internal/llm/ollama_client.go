@@ -76,3 +76,63 @@ func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, err
 
 	return genResp.Response, nil
 }
+
+// EmbeddingRequest represents an embedding request
+type EmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// EmbeddingResponse represents an embedding response
+type EmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// Embed returns the embedding vector of text using Ollama's /api/embeddings endpoint
+func (c *OllamaClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	reqBody := EmbeddingRequest{
+		Model:  c.model,
+		Prompt: text,
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/embeddings", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var embResp EmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(embResp.Embedding) == 0 {
+		return nil, fmt.Errorf("ollama returned an empty embedding for model %s", c.model)
+	}
+
+	vector := make([]float32, len(embResp.Embedding))
+	for i, v := range embResp.Embedding {
+		vector[i] = float32(v)
+	}
+
+	return vector, nil
+}
+
+// Model returns the model name used by this client
+func (c *OllamaClient) Model() string {
+	return c.model
+}
@@ -10,6 +10,9 @@ func QuoteParsePrompt(text string) string {
 - departure_date: 出发日期 (YYYY-MM-DD)
 - nights: 晚数
 - route: 航线
+- ports: 停靠港口列表，按行程顺序，如 ["上海", "福冈", "长崎", "上海"]，文本未提及则返回空数组
+- quoted_at: 报价日期，即报价单或文件的出具日期 (YYYY-MM-DD)，文本未提及则省略
+- valid_from: 价格生效日期 (YYYY-MM-DD)，文本未提及则省略
 - quotes: 报价列表，每个报价包含:
   - cabin_type_name: 房型名称
   - cabin_category: 房型大类 (内舱/海景/阳台/套房)
@@ -19,6 +22,23 @@ func QuoteParsePrompt(text string) string {
   - conditions: 适用条件
   - promotion: 促销信息
   - notes: 备注
+  - components: 价格构成明细（可选，文本未提及则返回空数组），每项包含:
+    - type: 费用类型 (BASE_FARE 基础船票/PORT_TAX 港务费及税费/GRATUITY 小费/FUEL_SURCHARGE 燃油附加费/OTHER 其他)
+    - amount: 金额
+    - pricing_unit: 计价口径 (PER_PERSON/PER_CABIN/TOTAL)，与报价相同时可省略
+    - per_night: 是否按晚收取 (true/false)，如小费每人每晚
+    - inclusive: 是否已包含在报价价格中 (true/false)，如"含港务费"为 true，"不含小费"为 false
+    - description: 原文说明
+  - promotions: 结构化促销列表（可选，文本未提及则返回空数组），每项包含:
+    - type: 促销类型 (EARLY_BIRD 早鸟/LAST_MINUTE 尾单/GUEST_DISCOUNT 第三四人优惠/FLASH_SALE 限时特价/MEMBER 会员专享/ONBOARD_CREDIT 船上消费金/OTHER 其他)
+    - discount_pct: 折扣比例，如"立减10%"为 10，无则省略
+    - discount_amount: 折扣金额，如"每人减500"为 500，无则省略
+    - pricing_unit: 折扣金额的计价口径 (PER_PERSON/PER_CABIN/TOTAL)，与报价相同时可省略
+    - guest_slots: 适用的第几位客人，如"第三四人免费"为 [3,4]，适用所有客人时返回空数组
+    - booking_from: 预订开始日期 (YYYY-MM-DD)，无则省略
+    - booking_until: 预订截止日期 (YYYY-MM-DD)，无则省略
+    - stackable: 是否可与其他优惠叠加 (true/false)
+    - description: 原文说明
 
 文本内容：
 ` + text + `
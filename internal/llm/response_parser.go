@@ -16,6 +16,9 @@ type QuoteParseResult struct {
 	DepartureDate string        `json:"departure_date"` // YYYY-MM-DD
 	Nights        int           `json:"nights"`
 	Route         string        `json:"route"`
+	Ports         []string      `json:"ports"`      // Ports of call in itinerary order
+	QuotedAt      string        `json:"quoted_at"`  // YYYY-MM-DD, when the document was issued
+	ValidFrom     string        `json:"valid_from"` // YYYY-MM-DD, when the prices take effect
 	Quotes        []ParsedQuote `json:"quotes"`
 }
 
@@ -29,6 +32,32 @@ type ParsedQuote struct {
 	Conditions    string  `json:"conditions"`
 	Promotion     string  `json:"promotion"`
 	Notes         string  `json:"notes"`
+
+	Components []ParsedPriceComponent `json:"components"`
+	Promotions []ParsedPromotion      `json:"promotions"`
+}
+
+// ParsedPriceComponent represents a charge line of a parsed quote
+type ParsedPriceComponent struct {
+	Type        string  `json:"type"` // BASE_FARE/PORT_TAX/GRATUITY/FUEL_SURCHARGE/OTHER
+	Amount      float64 `json:"amount"`
+	PricingUnit string  `json:"pricing_unit"` // PER_PERSON/PER_CABIN/TOTAL, empty = same as the quote
+	PerNight    bool    `json:"per_night"`
+	Inclusive   *bool   `json:"inclusive"` // nil if the text does not say
+	Description string  `json:"description"`
+}
+
+// ParsedPromotion represents a structured promotion of a parsed quote
+type ParsedPromotion struct {
+	Type           string   `json:"type"` // EARLY_BIRD/LAST_MINUTE/GUEST_DISCOUNT/FLASH_SALE/MEMBER/ONBOARD_CREDIT/OTHER
+	DiscountPct    *float64 `json:"discount_pct"`
+	DiscountAmount *float64 `json:"discount_amount"`
+	PricingUnit    string   `json:"pricing_unit"` // PER_PERSON/PER_CABIN/TOTAL, empty = same as the quote
+	GuestSlots     []int    `json:"guest_slots"`
+	BookingFrom    string   `json:"booking_from"` // YYYY-MM-DD
+	BookingUntil   string   `json:"booking_until"`
+	Stackable      bool     `json:"stackable"`
+	Description    string   `json:"description"`
 }
 
 // ResponseParser handles parsing of LLM responses
@@ -106,6 +135,18 @@ func (p *ResponseParser) validateResult(result *QuoteParseResult) error {
 		}
 	}
 
+	// Validate quote date formats
+	if result.QuotedAt != "" {
+		if _, err := time.Parse("2006-01-02", result.QuotedAt); err != nil {
+			return fmt.Errorf("quoted_at must be in YYYY-MM-DD format: %w", err)
+		}
+	}
+	if result.ValidFrom != "" {
+		if _, err := time.Parse("2006-01-02", result.ValidFrom); err != nil {
+			return fmt.Errorf("valid_from must be in YYYY-MM-DD format: %w", err)
+		}
+	}
+
 	// Validate quotes
 	if len(result.Quotes) == 0 {
 		return fmt.Errorf("at least one quote is required")
@@ -145,6 +186,35 @@ func (p *ResponseParser) validateQuote(quote *ParsedQuote, index int) error {
 		return fmt.Errorf("pricing_unit must be one of: PER_PERSON, PER_CABIN, TOTAL")
 	}
 
+	for i, component := range quote.Components {
+		if component.Amount < 0 {
+			return fmt.Errorf("components[%d].amount must not be negative", i)
+		}
+		if component.PricingUnit != "" && !validUnits[component.PricingUnit] {
+			return fmt.Errorf("components[%d].pricing_unit must be one of: PER_PERSON, PER_CABIN, TOTAL", i)
+		}
+	}
+
+	for i, promotion := range quote.Promotions {
+		if promotion.DiscountPct != nil && (*promotion.DiscountPct < 0 || *promotion.DiscountPct > 100) {
+			return fmt.Errorf("promotions[%d].discount_pct must be between 0 and 100", i)
+		}
+		if promotion.DiscountAmount != nil && *promotion.DiscountAmount < 0 {
+			return fmt.Errorf("promotions[%d].discount_amount must not be negative", i)
+		}
+		if promotion.PricingUnit != "" && !validUnits[promotion.PricingUnit] {
+			return fmt.Errorf("promotions[%d].pricing_unit must be one of: PER_PERSON, PER_CABIN, TOTAL", i)
+		}
+		for _, date := range []string{promotion.BookingFrom, promotion.BookingUntil} {
+			if date == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return fmt.Errorf("promotions[%d] booking dates must be in YYYY-MM-DD format: %w", i, err)
+			}
+		}
+	}
+
 	// Validate cabin category if provided
 	if quote.CabinCategory != "" {
 		validCategories := map[string]bool{
@@ -175,6 +245,53 @@ func (p *ResponseParser) ConvertPricingUnit(unit string) domain.PricingUnit {
 	}
 }
 
+// ConvertComponentType converts a price component type, English or Chinese, to the
+// domain enum. Unknown charges fall back to OTHER.
+func (p *ResponseParser) ConvertComponentType(componentType string) domain.PriceComponentType {
+	switch strings.ToUpper(strings.TrimSpace(componentType)) {
+	case "BASE_FARE", "基础船票", "船票":
+		return domain.PriceComponentBaseFare
+	case "PORT_TAX", "港务费", "港务税费", "税费":
+		return domain.PriceComponentPortTax
+	case "GRATUITY", "小费", "服务费":
+		return domain.PriceComponentGratuity
+	case "FUEL_SURCHARGE", "燃油附加费":
+		return domain.PriceComponentFuelSurcharge
+	default:
+		return domain.PriceComponentOther
+	}
+}
+
+// ComponentInclusive reports whether a parsed component is part of the quoted price.
+// The base fare always is; other charges the text does not qualify are treated as extra.
+func (p *ResponseParser) ComponentInclusive(component ParsedPriceComponent) bool {
+	if p.ConvertComponentType(component.Type) == domain.PriceComponentBaseFare {
+		return true
+	}
+	return component.Inclusive != nil && *component.Inclusive
+}
+
+// ConvertPromotionType converts a promotion type, English or Chinese, to the domain enum.
+// Unknown deals fall back to OTHER.
+func (p *ResponseParser) ConvertPromotionType(promotionType string) domain.PromotionType {
+	switch strings.ToUpper(strings.TrimSpace(promotionType)) {
+	case "EARLY_BIRD", "早鸟", "早鸟价", "早订优惠":
+		return domain.PromotionEarlyBird
+	case "LAST_MINUTE", "尾单", "尾舱", "临期特价":
+		return domain.PromotionLastMinute
+	case "GUEST_DISCOUNT", "第三四人优惠", "第二人半价", "同行优惠":
+		return domain.PromotionGuestDiscount
+	case "FLASH_SALE", "限时特价", "限时优惠", "秒杀":
+		return domain.PromotionFlashSale
+	case "MEMBER", "会员专享", "会员价":
+		return domain.PromotionMember
+	case "ONBOARD_CREDIT", "船上消费金", "船上消费额度":
+		return domain.PromotionOnboardCredit
+	default:
+		return domain.PromotionOther
+	}
+}
+
 // ExtractSailingInfo extracts sailing information from parse result
 func (p *ResponseParser) ExtractSailingInfo(result *QuoteParseResult) map[string]interface{} {
 	return map[string]interface{}{
@@ -183,6 +300,7 @@ func (p *ResponseParser) ExtractSailingInfo(result *QuoteParseResult) map[string
 		"departure_date": result.DepartureDate,
 		"nights":         result.Nights,
 		"route":          result.Route,
+		"ports":          result.Ports,
 	}
 }
 
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SpreadsheetExtractor handles text extraction from Excel workbooks (.xlsx)
+type SpreadsheetExtractor struct{}
+
+// NewSpreadsheetExtractor creates a new spreadsheet extractor
+func NewSpreadsheetExtractor() *SpreadsheetExtractor {
+	return &SpreadsheetExtractor{}
+}
+
+// ExtractText extracts the cell text of every sheet, one line per row with cells separated by
+// tabs and each sheet headed by its name, so the LLM can read price tables of any layout
+func (e *SpreadsheetExtractor) ExtractText(filePath string) (string, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open spreadsheet: %w", err)
+	}
+	defer f.Close()
+
+	var sheets []string
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return "", fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+		}
+
+		var lines []string
+		for _, row := range rows {
+			line := strings.TrimRight(strings.Join(row, "\t"), "\t ")
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			sheets = append(sheets, fmt.Sprintf("[%s]\n%s", sheet, strings.Join(lines, "\n")))
+		}
+	}
+
+	if len(sheets) == 0 {
+		return "", fmt.Errorf("spreadsheet contains no text")
+	}
+
+	return strings.Join(sheets, "\n\n"), nil
+}
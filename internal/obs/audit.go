@@ -50,6 +50,16 @@ func (s *AuditService) LogVoid(ctx context.Context, userID uint64, supplierID *u
 	return s.log(ctx, userID, supplierID, domain.AuditActionVoid, entityType, entityID, entity, nil)
 }
 
+// LogExpire logs an expiry action; userID is domain.SystemUserID for scheduled expiry
+func (s *AuditService) LogExpire(ctx context.Context, userID uint64, supplierID *uint64, entityType string, entityID uint64, oldEntity, newEntity interface{}) error {
+	return s.log(ctx, userID, supplierID, domain.AuditActionExpire, entityType, entityID, oldEntity, newEntity)
+}
+
+// LogConfirm logs the confirmation of an unchanged quote
+func (s *AuditService) LogConfirm(ctx context.Context, userID uint64, supplierID *uint64, entityType string, entityID uint64, confirmation interface{}) error {
+	return s.log(ctx, userID, supplierID, domain.AuditActionConfirm, entityType, entityID, nil, confirmation)
+}
+
 // LogImport logs an import action
 func (s *AuditService) LogImport(ctx context.Context, userID uint64, supplierID *uint64, entityID uint64, summary interface{}) error {
 	return s.log(ctx, userID, supplierID, domain.AuditActionImport, domain.EntityTypeImportJob, entityID, nil, summary)
@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/xuri/excelize/v2"
@@ -338,6 +340,227 @@ func (g *ExcelTemplateGenerator) GenerateCabinTypeTemplate() (*excelize.File, er
 	return f, nil
 }
 
+// GenerateQuoteTemplate 生成报价导入模板
+func (g *ExcelTemplateGenerator) GenerateQuoteTemplate() (*excelize.File, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			// Log error but don't fail
+		}
+	}()
+
+	sheetName := "报价数据"
+	index, err := f.NewSheet(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	// 设置表头
+	headers := []string{
+		"供应商",
+		"邮轮名称",
+		"出发日期",
+		"房型名称",
+		"价格",
+		"币种",
+		"计价口径",
+		"入住人数",
+		"基础船票",
+		"港务费",
+		"港务费已含",
+		"小费",
+		"小费已含",
+		"燃油附加费",
+		"燃油附加费已含",
+		"其他费用",
+		"其他费用已含",
+		"有效期至",
+		"促销信息",
+		"备注",
+		"促销类型",
+		"折扣比例(%)",
+		"折扣金额",
+		"适用客人",
+		"预订开始",
+		"预订截止",
+		"可叠加",
+		"报价日期",
+		"生效日期",
+	}
+
+	// 设置表头样式
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold:   true,
+			Size:   12,
+			Color:  "FFFFFF",
+			Family: "Arial",
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"4472C4"},
+			Pattern: 1,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	// 写入表头（超过 26 列，列名需用 CoordinatesToCellName 生成）
+	for i, header := range headers {
+		cell, err := excelize.CoordinatesToCellName(i+1, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cell name: %w", err)
+		}
+		if err := f.SetCellValue(sheetName, cell, header); err != nil {
+			return nil, fmt.Errorf("failed to set header: %w", err)
+		}
+		if err := f.SetCellStyle(sheetName, cell, cell, headerStyle); err != nil {
+			return nil, fmt.Errorf("failed to set header style: %w", err)
+		}
+	}
+
+	// 设置列宽
+	columnWidths := []struct {
+		col   string
+		width float64
+	}{
+		{"A", 15},  // 供应商
+		{"B", 15},  // 邮轮名称
+		{"C", 12},  // 出发日期
+		{"D", 20},  // 房型名称
+		{"E", 10},  // 价格
+		{"F", 8},   // 币种
+		{"G", 10},  // 计价口径
+		{"H", 10},  // 入住人数
+		{"I", 10},  // 基础船票
+		{"J", 10},  // 港务费
+		{"K", 10},  // 港务费已含
+		{"L", 10},  // 小费
+		{"M", 10},  // 小费已含
+		{"N", 12},  // 燃油附加费
+		{"O", 14},  // 燃油附加费已含
+		{"P", 10},  // 其他费用
+		{"Q", 12},  // 其他费用已含
+		{"R", 12},  // 有效期至
+		{"S", 20},  // 促销信息
+		{"T", 20},  // 备注
+		{"U", 14},  // 促销类型
+		{"V", 12},  // 折扣比例(%)
+		{"W", 10},  // 折扣金额
+		{"X", 10},  // 适用客人
+		{"Y", 12},  // 预订开始
+		{"Z", 12},  // 预订截止
+		{"AA", 8},  // 可叠加
+		{"AB", 12}, // 报价日期
+		{"AC", 12}, // 生效日期
+	}
+	for _, cw := range columnWidths {
+		if err := f.SetColWidth(sheetName, cw.col, cw.col, cw.width); err != nil {
+			return nil, fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+
+	// 添加示例数据
+	exampleData := [][]interface{}{
+		{"示例旅行社", "海洋量子号", "2026-05-15", "内舱房", 3999, "CNY", "每人", 2, 3399, 600, "是", 80, "否", "", "", "", "", "2026-04-30", "早鸟优惠", "", "早鸟", 10, "", "", "", "2026-03-31", "否", "2026-01-10", "2026-01-15"},
+		{"示例旅行社", "海洋量子号", "2026-05-15", "豪华阳台房", 12999, "CNY", "每间", 2, "", 1200, "否", "", "", 300, "否", "", "", "", "", "小费已含", "第三四人优惠", 100, "", "3,4", "", "", "是", "", ""},
+	}
+
+	exampleStyle, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"FFF2CC"},
+			Pattern: 1,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create example style: %w", err)
+	}
+
+	for rowIdx, rowData := range exampleData {
+		for colIdx, value := range rowData {
+			cell, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get cell name: %w", err)
+			}
+			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+				return nil, fmt.Errorf("failed to set example data: %w", err)
+			}
+			if err := f.SetCellStyle(sheetName, cell, cell, exampleStyle); err != nil {
+				return nil, fmt.Errorf("failed to set example style: %w", err)
+			}
+		}
+	}
+
+	// 添加说明页
+	instructionSheet := "填写说明"
+	instructionIndex, err := f.NewSheet(instructionSheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instruction sheet: %w", err)
+	}
+	_ = instructionIndex
+
+	instructions := []string{
+		"报价数据导入说明",
+		"",
+		"1. 必填字段：",
+		"   - 供应商：必须是系统中已存在的供应商名称",
+		"   - 邮轮名称：必须是系统中已存在的邮轮名称",
+		"   - 出发日期：格式为 YYYY-MM-DD，必须是该邮轮已存在的航次",
+		"   - 房型名称：必须是该邮轮已存在的房型名称",
+		"   - 价格：大于 0 的数字",
+		"   - 计价口径：每人、每间或总价",
+		"",
+		"2. 可选字段：",
+		"   - 币种：三位货币代码（默认为 CNY）",
+		"   - 入住人数：价格对应的入住人数（默认为 2）",
+		"   - 有效期至：格式为 YYYY-MM-DD",
+		"   - 报价日期：供应商出具报价的日期，格式为 YYYY-MM-DD，不能晚于今天；留空表示导入当天",
+		"   - 生效日期：价格开始生效的日期，格式为 YYYY-MM-DD，不能晚于有效期至；留空表示立即生效",
+		"   - 促销信息、备注：其他补充信息",
+		"",
+		"3. 价格构成（可选）：",
+		"   - 基础船票：价格中的船票部分，始终视为已含在价格内",
+		"   - 港务费、小费、燃油附加费、其他费用：填写金额，计价口径与报价相同，按整个航程计算",
+		"   - 对应的“已含”列：填写“是”表示已包含在价格内，填写“否”或留空表示需另付",
+		"   - 比价时系统会将需另付的费用加到价格上，得出全包价",
+		"",
+		"4. 结构化促销（可选，每行一项）：",
+		"   - 促销类型：早鸟、尾单、第三四人优惠、限时特价、会员专享、船上消费金或其他",
+		"   - 折扣比例(%)：每位适用客人船票的折扣百分比，如立减 10% 填 10，第三四人免费填 100",
+		"   - 折扣金额：按报价计价口径减免的金额；船上消费金也填写在此列，但不计入优惠后价格",
+		"   - 适用客人：适用的第几位客人，多个用逗号分隔，如 3,4；留空表示所有客人",
+		"   - 预订开始、预订截止：格式为 YYYY-MM-DD，留空表示不限",
+		"   - 可叠加：填写“是”表示可与其他促销同时使用",
+		"   - 比价时系统按当前可享受的促销计算优惠后价格；会员专享和船上消费金不计入",
+		"",
+		"5. 注意事项：",
+		"   - 黄色背景行是示例数据，请删除后填入真实数据",
+		"   - 不要修改表头（第一行）",
+		"   - 报价只能追加；与该供应商当前最新报价完全相同的行不会新增记录，只记为一次确认",
+	}
+
+	for i, instruction := range instructions {
+		cell := fmt.Sprintf("A%d", i+1)
+		if err := f.SetCellValue(instructionSheet, cell, instruction); err != nil {
+			return nil, fmt.Errorf("failed to set instruction: %w", err)
+		}
+	}
+
+	if err := f.SetColWidth(instructionSheet, "A", "A", 60); err != nil {
+		return nil, fmt.Errorf("failed to set instruction column width: %w", err)
+	}
+
+	return f, nil
+}
+
 // SailingRowData 航次行数据
 type SailingRowData struct {
 	RowNumber      int
@@ -363,6 +586,79 @@ type CabinTypeRowData struct {
 	SortOrder      int
 }
 
+// 报价计价口径取值，与 domain.PricingUnit 一致
+const (
+	QuotePricingUnitPerPerson = "PER_PERSON"
+	QuotePricingUnitPerCabin  = "PER_CABIN"
+	QuotePricingUnitTotal     = "TOTAL"
+)
+
+// 价格构成类型，与 domain.PriceComponentType 一致
+const (
+	QuoteChargePortTax       = "PORT_TAX"
+	QuoteChargeGratuity      = "GRATUITY"
+	QuoteChargeFuelSurcharge = "FUEL_SURCHARGE"
+	QuoteChargeOther         = "OTHER"
+)
+
+// quoteChargeNames 附加费用在模板中的列名
+var quoteChargeNames = map[string]string{
+	QuoteChargePortTax:       "港务费",
+	QuoteChargeGratuity:      "小费",
+	QuoteChargeFuelSurcharge: "燃油附加费",
+	QuoteChargeOther:         "其他费用",
+}
+
+// 促销类型，与 domain.PromotionType 一致
+const (
+	QuotePromotionEarlyBird     = "EARLY_BIRD"
+	QuotePromotionLastMinute    = "LAST_MINUTE"
+	QuotePromotionGuestDiscount = "GUEST_DISCOUNT"
+	QuotePromotionFlashSale     = "FLASH_SALE"
+	QuotePromotionMember        = "MEMBER"
+	QuotePromotionOnboardCredit = "ONBOARD_CREDIT"
+	QuotePromotionOther         = "OTHER"
+)
+
+// QuotePromotionData 报价行中的结构化促销
+type QuotePromotionData struct {
+	Type           string
+	DiscountPct    string
+	DiscountAmount string
+	GuestSlots     string // 逗号分隔的客人序号，留空表示所有客人
+	BookingFrom    string
+	BookingUntil   string
+	Stackable      string // 是/否
+}
+
+// QuoteChargeData 报价行中的一项附加费用
+type QuoteChargeData struct {
+	Type     string
+	Amount   string
+	Included string // 是/否，留空视为需另付
+}
+
+// QuoteRowData 报价行数据
+type QuoteRowData struct {
+	RowNumber     int
+	SupplierName  string
+	ShipName      string
+	DepartureDate string
+	CabinTypeName string
+	Price         string
+	Currency      string
+	PricingUnit   string
+	GuestCount    string
+	BaseFare      string
+	Charges       []QuoteChargeData // 仅包含填写了金额的费用
+	ValidFrom     string
+	ValidUntil    string
+	QuotedAt      string
+	Promotion     string
+	Notes         string
+	Promotions    []QuotePromotionData // 未填写促销类型时为空
+}
+
 // ParseSailingExcel 解析航次 Excel 文件
 func ParseSailingExcel(filePath string) ([]SailingRowData, error) {
 	f, err := excelize.OpenFile(filePath)
@@ -516,6 +812,177 @@ func ParseCabinTypeExcel(filePath string) ([]CabinTypeRowData, error) {
 	return result, nil
 }
 
+// ParseQuoteExcel 解析报价 Excel 文件
+func ParseQuoteExcel(filePath string) ([]QuoteRowData, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	sheetName := "报价数据"
+	sheets := f.GetSheetList()
+	found := false
+	for _, s := range sheets {
+		if s == sheetName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("sheet '报价数据' not found")
+	}
+
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows: %w", err)
+	}
+
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("no data rows found")
+	}
+
+	// 附加费用列：金额列与“已含”列
+	chargeColumns := []struct {
+		chargeType string
+		amount     int
+		included   int
+	}{
+		{QuoteChargePortTax, 9, 10},
+		{QuoteChargeGratuity, 11, 12},
+		{QuoteChargeFuelSurcharge, 13, 14},
+		{QuoteChargeOther, 15, 16},
+	}
+
+	var result []QuoteRowData
+	for i, row := range rows {
+		if i == 0 {
+			// Skip header
+			continue
+		}
+
+		// Skip empty rows
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+
+		cell := func(col int) string {
+			if len(row) > col {
+				return strings.TrimSpace(row[col])
+			}
+			return ""
+		}
+
+		data := QuoteRowData{
+			RowNumber:     i + 1,
+			SupplierName:  cell(0),
+			ShipName:      cell(1),
+			DepartureDate: cell(2),
+			CabinTypeName: cell(3),
+			Price:         cell(4),
+			Currency:      strings.ToUpper(cell(5)),
+			PricingUnit:   cell(6),
+			GuestCount:    cell(7),
+			BaseFare:      cell(8),
+			ValidUntil:    cell(17),
+			Promotion:     cell(18),
+			Notes:         cell(19),
+			QuotedAt:      cell(27),
+			ValidFrom:     cell(28),
+		}
+
+		if promotionType := cell(20); promotionType != "" {
+			data.Promotions = append(data.Promotions, QuotePromotionData{
+				Type:           promotionType,
+				DiscountPct:    cell(21),
+				DiscountAmount: cell(22),
+				GuestSlots:     cell(23),
+				BookingFrom:    cell(24),
+				BookingUntil:   cell(25),
+				Stackable:      cell(26),
+			})
+		}
+
+		for _, cc := range chargeColumns {
+			if amount := cell(cc.amount); amount != "" {
+				data.Charges = append(data.Charges, QuoteChargeData{
+					Type:     cc.chargeType,
+					Amount:   amount,
+					Included: cell(cc.included),
+				})
+			}
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// ParseQuotePricingUnit 将模板中的计价口径转换为标准取值
+func ParseQuotePricingUnit(value string) (string, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "每人", "PER_PERSON":
+		return QuotePricingUnitPerPerson, true
+	case "每间", "每房", "PER_CABIN":
+		return QuotePricingUnitPerCabin, true
+	case "总价", "TOTAL":
+		return QuotePricingUnitTotal, true
+	default:
+		return "", false
+	}
+}
+
+// ParseIncluded 解析“已含”列，留空视为需另付
+func ParseIncluded(value string) (bool, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "是", "Y", "YES", "含", "已含":
+		return true, true
+	case "", "否", "N", "NO", "不含":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ParsePromotionType 将模板中的促销类型转换为标准取值
+func ParsePromotionType(value string) (string, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "早鸟", "早鸟优惠", "EARLY_BIRD":
+		return QuotePromotionEarlyBird, true
+	case "尾单", "尾舱", "LAST_MINUTE":
+		return QuotePromotionLastMinute, true
+	case "第三四人优惠", "同行优惠", "GUEST_DISCOUNT":
+		return QuotePromotionGuestDiscount, true
+	case "限时特价", "限时优惠", "FLASH_SALE":
+		return QuotePromotionFlashSale, true
+	case "会员专享", "会员价", "MEMBER":
+		return QuotePromotionMember, true
+	case "船上消费金", "ONBOARD_CREDIT":
+		return QuotePromotionOnboardCredit, true
+	case "其他", "OTHER":
+		return QuotePromotionOther, true
+	default:
+		return "", false
+	}
+}
+
+// ParseGuestSlots 解析适用客人列，支持逗号、顿号或空格分隔
+func ParseGuestSlots(value string) ([]int, bool) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == ' '
+	})
+	slots := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n <= 0 {
+			return nil, false
+		}
+		slots = append(slots, n)
+	}
+	return slots, true
+}
+
 // ValidateSailingRow 验证航次行数据
 func ValidateSailingRow(row SailingRowData) []string {
 	var errors []string
@@ -587,3 +1054,104 @@ func ValidateCabinTypeRow(row CabinTypeRowData) []string {
 
 	return errors
 }
+
+// ValidateQuoteRow 验证报价行数据
+func ValidateQuoteRow(row QuoteRowData) []string {
+	var errors []string
+
+	if row.SupplierName == "" {
+		errors = append(errors, "供应商不能为空")
+	}
+	if row.ShipName == "" {
+		errors = append(errors, "邮轮名称不能为空")
+	}
+	if row.DepartureDate == "" {
+		errors = append(errors, "出发日期不能为空")
+	} else if _, err := time.Parse("2006-01-02", row.DepartureDate); err != nil {
+		errors = append(errors, "出发日期格式错误，应为 YYYY-MM-DD")
+	}
+	if row.CabinTypeName == "" {
+		errors = append(errors, "房型名称不能为空")
+	}
+	if row.Price == "" {
+		errors = append(errors, "价格不能为空")
+	} else if price, err := strconv.ParseFloat(row.Price, 64); err != nil || price <= 0 {
+		errors = append(errors, "价格必须是大于 0 的数字")
+	}
+	if row.Currency != "" && len(row.Currency) != 3 {
+		errors = append(errors, "币种必须是三位货币代码")
+	}
+	if row.PricingUnit == "" {
+		errors = append(errors, "计价口径不能为空")
+	} else if _, ok := ParseQuotePricingUnit(row.PricingUnit); !ok {
+		errors = append(errors, "计价口径必须是：每人、每间、总价之一")
+	}
+	if row.GuestCount != "" {
+		if n, err := strconv.Atoi(row.GuestCount); err != nil || n <= 0 {
+			errors = append(errors, "入住人数必须是正整数")
+		}
+	}
+	if row.BaseFare != "" {
+		if amount, err := strconv.ParseFloat(row.BaseFare, 64); err != nil || amount < 0 {
+			errors = append(errors, "基础船票必须是不小于 0 的数字")
+		}
+	}
+	for _, charge := range row.Charges {
+		if amount, err := strconv.ParseFloat(charge.Amount, 64); err != nil || amount < 0 {
+			errors = append(errors, fmt.Sprintf("%s必须是不小于 0 的数字", quoteChargeNames[charge.Type]))
+		}
+		if _, ok := ParseIncluded(charge.Included); !ok {
+			errors = append(errors, fmt.Sprintf("%s已含必须填写“是”或“否”", quoteChargeNames[charge.Type]))
+		}
+	}
+	if row.ValidUntil != "" {
+		if _, err := time.Parse("2006-01-02", row.ValidUntil); err != nil {
+			errors = append(errors, "有效期至格式错误，应为 YYYY-MM-DD")
+		}
+	}
+	if row.QuotedAt != "" {
+		if quotedAt, err := time.Parse("2006-01-02", row.QuotedAt); err != nil {
+			errors = append(errors, "报价日期格式错误，应为 YYYY-MM-DD")
+		} else if quotedAt.After(time.Now()) {
+			errors = append(errors, "报价日期不能晚于今天")
+		}
+	}
+	if row.ValidFrom != "" {
+		if validFrom, err := time.Parse("2006-01-02", row.ValidFrom); err != nil {
+			errors = append(errors, "生效日期格式错误，应为 YYYY-MM-DD")
+		} else if validUntil, err := time.Parse("2006-01-02", row.ValidUntil); err == nil && validFrom.After(validUntil) {
+			errors = append(errors, "生效日期不能晚于有效期至")
+		}
+	}
+	for _, promotion := range row.Promotions {
+		if _, ok := ParsePromotionType(promotion.Type); !ok {
+			errors = append(errors, "促销类型必须是：早鸟、尾单、第三四人优惠、限时特价、会员专享、船上消费金、其他之一")
+		}
+		if promotion.DiscountPct != "" {
+			if pct, err := strconv.ParseFloat(promotion.DiscountPct, 64); err != nil || pct <= 0 || pct > 100 {
+				errors = append(errors, "折扣比例必须是大于 0 且不超过 100 的数字")
+			}
+		}
+		if promotion.DiscountAmount != "" {
+			if amount, err := strconv.ParseFloat(promotion.DiscountAmount, 64); err != nil || amount <= 0 {
+				errors = append(errors, "折扣金额必须是大于 0 的数字")
+			}
+		}
+		if _, ok := ParseGuestSlots(promotion.GuestSlots); !ok {
+			errors = append(errors, "适用客人必须是正整数，多个用逗号分隔")
+		}
+		for _, date := range []struct{ name, value string }{{"预订开始", promotion.BookingFrom}, {"预订截止", promotion.BookingUntil}} {
+			if date.value == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", date.value); err != nil {
+				errors = append(errors, fmt.Sprintf("%s格式错误，应为 YYYY-MM-DD", date.name))
+			}
+		}
+		if _, ok := ParseIncluded(promotion.Stackable); !ok {
+			errors = append(errors, "可叠加必须填写“是”或“否”")
+		}
+	}
+
+	return errors
+}
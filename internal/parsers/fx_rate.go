@@ -0,0 +1,182 @@
+package parsers
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ECBBaseCurrency ECB 参考汇率的基准币种
+const ECBBaseCurrency = "EUR"
+
+// FXRateRowData 汇率行数据：1 BaseCurrency = Rate QuoteCurrency
+type FXRateRowData struct {
+	RowNumber     int
+	RateDate      time.Time
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          decimal.Decimal
+}
+
+// fxRateCSVColumns CSV 表头别名，键为规范列名
+var fxRateCSVColumns = map[string][]string{
+	"date":  {"date", "rate_date", "日期"},
+	"base":  {"base", "base_currency", "基准币种"},
+	"quote": {"quote", "quote_currency", "目标币种"},
+	"rate":  {"rate", "汇率"},
+}
+
+// ParseFXRateCSV 解析汇率 CSV 文件
+// 第一行为表头，须包含 date、base、quote、rate 四列（顺序不限），日期格式 YYYY-MM-DD
+func ParseFXRateCSV(filePath string) ([]FXRateRowData, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no data rows found")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	// 定位各列
+	index := make(map[string]int, len(fxRateCSVColumns))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for column, aliases := range fxRateCSVColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					index[column] = i
+				}
+			}
+		}
+	}
+	for column := range fxRateCSVColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("missing column '%s'", column)
+		}
+	}
+
+	var result []FXRateRowData
+	for rowNumber := 2; ; rowNumber++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+		}
+
+		// 跳过空行
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+
+		field := func(column string) string {
+			if i := index[column]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row, err := newFXRateRow(rowNumber, field("date"), field("base"), field("quote"), field("rate"))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no data rows found")
+	}
+
+	return result, nil
+}
+
+// ecbEnvelope ECB eurofxref XML 结构：Cube > Cube[time] > Cube[currency, rate]
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ParseECBRatesXML 解析 ECB 参考汇率 XML 文件（eurofxref-daily / hist），基准币种为 EUR
+func ParseECBRatesXML(filePath string) ([]FXRateRowData, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(file).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode ECB XML: %w", err)
+	}
+
+	var result []FXRateRowData
+	rowNumber := 0
+	for _, day := range envelope.Cube.Days {
+		for _, r := range day.Rates {
+			rowNumber++
+			row, err := newFXRateRow(rowNumber, day.Time, ECBBaseCurrency, r.Currency, r.Rate)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, row)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no rates found")
+	}
+
+	return result, nil
+}
+
+// newFXRateRow 校验并构造汇率行
+func newFXRateRow(rowNumber int, date, base, quote, rate string) (FXRateRowData, error) {
+	row := FXRateRowData{
+		RowNumber:     rowNumber,
+		BaseCurrency:  strings.ToUpper(strings.TrimSpace(base)),
+		QuoteCurrency: strings.ToUpper(strings.TrimSpace(quote)),
+	}
+
+	rateDate, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return row, fmt.Errorf("row %d: invalid date '%s', expected YYYY-MM-DD", rowNumber, date)
+	}
+	row.RateDate = rateDate
+
+	if len(row.BaseCurrency) != 3 || len(row.QuoteCurrency) != 3 {
+		return row, fmt.Errorf("row %d: currency must be a 3-letter code", rowNumber)
+	}
+
+	value, err := decimal.NewFromString(strings.TrimSpace(rate))
+	if err != nil || !value.IsPositive() {
+		return row, fmt.Errorf("row %d: invalid rate '%s'", rowNumber, rate)
+	}
+	row.Rate = value
+
+	return row, nil
+}
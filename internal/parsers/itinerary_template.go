@@ -0,0 +1,299 @@
+package parsers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ItineraryRowData 行程行数据，每行为航次的一天
+type ItineraryRowData struct {
+	RowNumber     int
+	ShipName      string
+	DepartureDate string
+	DayNumber     string
+	PortName      string
+	ArriveTime    string
+	DepartTime    string
+	SeaDay        string // 是/否
+	Note          string
+}
+
+// GenerateItineraryTemplate 生成行程导入模板
+func (g *ExcelTemplateGenerator) GenerateItineraryTemplate() (*excelize.File, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			// Log error but don't fail
+		}
+	}()
+
+	sheetName := "行程数据"
+	index, err := f.NewSheet(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	// 设置表头
+	headers := []string{
+		"邮轮名称",
+		"出发日期",
+		"第几天",
+		"港口",
+		"抵达时间",
+		"离港时间",
+		"海上巡游日",
+		"备注",
+	}
+
+	// 设置表头样式
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold:   true,
+			Size:   12,
+			Color:  "FFFFFF",
+			Family: "Arial",
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"4472C4"},
+			Pattern: 1,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	// 写入表头
+	for i, header := range headers {
+		cell := fmt.Sprintf("%c1", 'A'+i)
+		if err := f.SetCellValue(sheetName, cell, header); err != nil {
+			return nil, fmt.Errorf("failed to set header: %w", err)
+		}
+		if err := f.SetCellStyle(sheetName, cell, cell, headerStyle); err != nil {
+			return nil, fmt.Errorf("failed to set header style: %w", err)
+		}
+	}
+
+	// 设置列宽
+	columnWidths := []struct {
+		col   string
+		width float64
+	}{
+		{"A", 15}, // 邮轮名称
+		{"B", 12}, // 出发日期
+		{"C", 8},  // 第几天
+		{"D", 20}, // 港口
+		{"E", 10}, // 抵达时间
+		{"F", 10}, // 离港时间
+		{"G", 12}, // 海上巡游日
+		{"H", 20}, // 备注
+	}
+	for _, cw := range columnWidths {
+		if err := f.SetColWidth(sheetName, cw.col, cw.col, cw.width); err != nil {
+			return nil, fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+
+	// 添加示例数据：时间列以文本写入，避免 Excel 转换为小数
+	exampleData := [][]interface{}{
+		{"海洋量子号", "2026-05-15", 1, "上海", "", "16:30", "否", ""},
+		{"海洋量子号", "2026-05-15", 2, "", "", "", "是", ""},
+		{"海洋量子号", "2026-05-15", 3, "福冈", "08:00", "17:00", "否", ""},
+		{"海洋量子号", "2026-05-15", 4, "长崎", "09:00", "18:00", "否", ""},
+		{"海洋量子号", "2026-05-15", 5, "", "", "", "是", ""},
+		{"海洋量子号", "2026-05-15", 6, "CNSHA", "06:00", "", "否", "可填写 UN/LOCODE"},
+	}
+
+	exampleStyle, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"FFF2CC"},
+			Pattern: 1,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create example style: %w", err)
+	}
+
+	for rowIdx, rowData := range exampleData {
+		for colIdx, value := range rowData {
+			cell := fmt.Sprintf("%c%d", 'A'+colIdx, rowIdx+2)
+			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+				return nil, fmt.Errorf("failed to set example data: %w", err)
+			}
+			if err := f.SetCellStyle(sheetName, cell, cell, exampleStyle); err != nil {
+				return nil, fmt.Errorf("failed to set example style: %w", err)
+			}
+		}
+	}
+
+	// 添加说明页
+	instructionSheet := "填写说明"
+	instructionIndex, err := f.NewSheet(instructionSheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instruction sheet: %w", err)
+	}
+	_ = instructionIndex
+
+	instructions := []string{
+		"航次行程导入说明",
+		"",
+		"1. 必填字段：",
+		"   - 邮轮名称、出发日期：确定航次，出发日期格式为 YYYY-MM-DD，航次必须已存在",
+		"   - 第几天：1 表示出发当天，最大为航次晚数加 1（返回当天）",
+		"   - 港口：非海上巡游日必填，可填写港口名称、英文名、别名或 UN/LOCODE（如 CNSHA），港口必须已存在",
+		"",
+		"2. 可选字段：",
+		"   - 抵达时间、离港时间：当地时间，格式为 HH:MM；出发当天无抵达时间，返回当天无离港时间",
+		"   - 海上巡游日：填写“是”表示全天在海上，此时港口与时间留空",
+		"   - 备注：其他补充信息",
+		"",
+		"3. 注意事项：",
+		"   - 黄色背景行是示例数据，请删除后填入真实数据",
+		"   - 不要修改表头（第一行）",
+		"   - 每个航次的所有天需放在同一文件中；导入会整体替换该航次已有的行程",
+		"   - 同一航次任一行有错误时，该航次的行程不会导入",
+		"   - 导入后航次的停靠港口会按行程更新",
+	}
+
+	for i, instruction := range instructions {
+		cell := fmt.Sprintf("A%d", i+1)
+		if err := f.SetCellValue(instructionSheet, cell, instruction); err != nil {
+			return nil, fmt.Errorf("failed to set instruction: %w", err)
+		}
+	}
+
+	if err := f.SetColWidth(instructionSheet, "A", "A", 80); err != nil {
+		return nil, fmt.Errorf("failed to set instruction column width: %w", err)
+	}
+
+	return f, nil
+}
+
+// ParseItineraryExcel 解析行程 Excel 文件
+func ParseItineraryExcel(filePath string) ([]ItineraryRowData, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	sheetName := "行程数据"
+	sheets := f.GetSheetList()
+	found := false
+	for _, s := range sheets {
+		if s == sheetName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("sheet '行程数据' not found")
+	}
+
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows: %w", err)
+	}
+
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("no data rows found")
+	}
+
+	var result []ItineraryRowData
+	for i, row := range rows {
+		if i == 0 {
+			// Skip header
+			continue
+		}
+
+		// Skip empty rows
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+
+		cell := func(col int) string {
+			if len(row) > col {
+				return strings.TrimSpace(row[col])
+			}
+			return ""
+		}
+
+		result = append(result, ItineraryRowData{
+			RowNumber:     i + 1,
+			ShipName:      cell(0),
+			DepartureDate: cell(1),
+			DayNumber:     cell(2),
+			PortName:      cell(3),
+			ArriveTime:    cell(4),
+			DepartTime:    cell(5),
+			SeaDay:        cell(6),
+			Note:          cell(7),
+		})
+	}
+
+	return result, nil
+}
+
+// ParseItineraryTime 将模板中的时间转换为 HH:MM，支持 H:MM 与 HH:MM:SS
+func ParseItineraryTime(value string) (string, bool) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("15:04"), true
+		}
+	}
+	return "", false
+}
+
+// ValidateItineraryRow 验证行程行数据
+func ValidateItineraryRow(row ItineraryRowData) []string {
+	var errors []string
+
+	if row.ShipName == "" {
+		errors = append(errors, "邮轮名称不能为空")
+	}
+	if row.DepartureDate == "" {
+		errors = append(errors, "出发日期不能为空")
+	} else if _, err := time.Parse("2006-01-02", row.DepartureDate); err != nil {
+		errors = append(errors, "出发日期格式错误，应为 YYYY-MM-DD")
+	}
+	if row.DayNumber == "" {
+		errors = append(errors, "第几天不能为空")
+	} else if n, err := strconv.Atoi(row.DayNumber); err != nil || n <= 0 {
+		errors = append(errors, "第几天必须是正整数")
+	}
+
+	seaDay, ok := ParseIncluded(row.SeaDay)
+	if !ok {
+		errors = append(errors, "海上巡游日必须填写“是”或“否”")
+	}
+	if seaDay && row.PortName != "" {
+		errors = append(errors, "海上巡游日不能填写港口")
+	}
+	if ok && !seaDay && row.PortName == "" {
+		errors = append(errors, "港口不能为空")
+	}
+
+	if row.ArriveTime != "" {
+		if _, ok := ParseItineraryTime(row.ArriveTime); !ok {
+			errors = append(errors, "抵达时间格式错误，应为 HH:MM")
+		}
+	}
+	if row.DepartTime != "" {
+		if _, ok := ParseItineraryTime(row.DepartTime); !ok {
+			errors = append(errors, "离港时间格式错误，应为 HH:MM")
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"cruise-price-compare/internal/domain"
+)
+
+// alertRuleColumns is the column list selected into domain.AlertRule
+const alertRuleColumns = `id, user_id, name, sailing_id, ship_id, cabin_type_id, cabin_category_id,
+              condition_type, threshold, COALESCE(currency, '') AS currency, is_enabled, created_at, updated_at`
+
+// AlertRuleRepository handles alert rule data access
+type AlertRuleRepository struct {
+	db *DB
+}
+
+// NewAlertRuleRepository creates a new alert rule repository
+func NewAlertRuleRepository(db *DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+// GetByID retrieves an alert rule by ID
+func (r *AlertRuleRepository) GetByID(ctx context.Context, id uint64) (*domain.AlertRule, error) {
+	var rule domain.AlertRule
+	query := `SELECT ` + alertRuleColumns + `
+              FROM alert_rule WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &rule, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert rule by id: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// ListByUser retrieves the alert rules of a user with pagination, newest first
+func (r *AlertRuleRepository) ListByUser(ctx context.Context, pagination Pagination, userID uint64) (PaginatedResult[domain.AlertRule], error) {
+	var rules []domain.AlertRule
+	var total int64
+
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM alert_rule WHERE user_id = ?", userID); err != nil {
+		return PaginatedResult[domain.AlertRule]{}, fmt.Errorf("failed to count alert rules: %w", err)
+	}
+
+	query := `SELECT ` + alertRuleColumns + `
+              FROM alert_rule WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+
+	if err := r.db.SelectContext(ctx, &rules, query, userID, pagination.Limit(), pagination.Offset()); err != nil {
+		return PaginatedResult[domain.AlertRule]{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	return NewPaginatedResult(rules, total, pagination), nil
+}
+
+// ListMatching retrieves the enabled rules watching a quote of the given sailing
+// (directly or through its ship) and cabin type (directly or through its category)
+func (r *AlertRuleRepository) ListMatching(ctx context.Context, sailingID, shipID, cabinTypeID, cabinCategoryID uint64) ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	query := `SELECT ` + alertRuleColumns + `
+              FROM alert_rule
+              WHERE is_enabled = TRUE
+                AND (sailing_id = ? OR ship_id = ?)
+                AND (cabin_type_id IS NULL OR cabin_type_id = ?)
+                AND (cabin_category_id IS NULL OR cabin_category_id = ?)
+              ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &rules, query, sailingID, shipID, cabinTypeID, cabinCategoryID); err != nil {
+		return nil, fmt.Errorf("failed to list matching alert rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Create creates a new alert rule
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	query := `INSERT INTO alert_rule (user_id, name, sailing_id, ship_id, cabin_type_id, cabin_category_id,
+              condition_type, threshold, currency, is_enabled)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, rule.UserID, rule.Name, rule.SailingID, rule.ShipID,
+		rule.CabinTypeID, rule.CabinCategoryID, rule.Condition, rule.Threshold,
+		sql.NullString{String: rule.Currency, Valid: rule.Currency != ""}, rule.IsEnabled)
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	rule.ID = uint64(id)
+
+	return nil
+}
+
+// SetEnabled enables or disables an alert rule
+func (r *AlertRuleRepository) SetEnabled(ctx context.Context, id uint64, enabled bool) error {
+	query := `UPDATE alert_rule SET is_enabled = ? WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, enabled, id); err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes an alert rule together with its alerts
+func (r *AlertRuleRepository) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM alert_rule WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	return nil
+}
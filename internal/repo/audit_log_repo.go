@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -25,7 +26,8 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *domain.AuditLog) e
               old_value, new_value, trace_id, ip_address, user_agent) 
               VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, log.UserID, log.SupplierID, log.Action,
+	userID := sql.NullInt64{Int64: int64(log.UserID), Valid: log.UserID != domain.SystemUserID}
+	result, err := r.db.ExecContext(ctx, query, userID, log.SupplierID, log.Action,
 		log.EntityType, log.EntityID, log.OldValue, log.NewValue, log.TraceID,
 		log.IPAddress, log.UserAgent)
 	if err != nil {
@@ -47,7 +49,7 @@ func (r *AuditLogRepository) List(ctx context.Context, pagination Pagination, us
 	var total int64
 
 	countQuery := "SELECT COUNT(*) FROM audit_log WHERE 1=1"
-	selectQuery := `SELECT id, user_id, supplier_id, action, entity_type, entity_id, 
+	selectQuery := `SELECT id, COALESCE(user_id, 0) AS user_id, supplier_id, action, entity_type, entity_id, 
                     old_value, new_value, trace_id, ip_address, user_agent, created_at 
                     FROM audit_log WHERE 1=1`
 	var args []interface{}
@@ -105,7 +107,7 @@ func (r *AuditLogRepository) List(ctx context.Context, pagination Pagination, us
 // ListByEntity retrieves audit logs for a specific entity
 func (r *AuditLogRepository) ListByEntity(ctx context.Context, entityType string, entityID uint64, limit int) ([]domain.AuditLog, error) {
 	var logs []domain.AuditLog
-	query := `SELECT id, user_id, supplier_id, action, entity_type, entity_id, 
+	query := `SELECT id, COALESCE(user_id, 0) AS user_id, supplier_id, action, entity_type, entity_id, 
               old_value, new_value, trace_id, ip_address, user_agent, created_at 
               FROM audit_log WHERE entity_type = ? AND entity_id = ? 
               ORDER BY created_at DESC LIMIT ?`
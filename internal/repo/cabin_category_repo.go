@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // CabinCategoryRepository handles cabin category data access
@@ -78,46 +80,52 @@ func (r *CabinCategoryRepository) ListDefaults(ctx context.Context) ([]domain.Ca
 }
 
 // Create creates a new cabin category
-func (r *CabinCategoryRepository) Create(ctx context.Context, cc *domain.CabinCategory) error {
+func (r *CabinCategoryRepository) Create(ctx context.Context, cc *domain.CabinCategory, events ...*domain.DomainEvent) error {
 	query := `INSERT INTO cabin_category (name, name_en, sort_order, is_default) 
               VALUES (?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, cc.Name, cc.NameEN, cc.SortOrder, cc.IsDefault)
-	if err != nil {
-		return fmt.Errorf("failed to create cabin category: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, cc.Name, cc.NameEN, cc.SortOrder, cc.IsDefault)
+		if err != nil {
+			return fmt.Errorf("failed to create cabin category: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	cc.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		cc.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, cc.ID, events)
+	})
 }
 
 // Update updates a cabin category
-func (r *CabinCategoryRepository) Update(ctx context.Context, cc *domain.CabinCategory) error {
+func (r *CabinCategoryRepository) Update(ctx context.Context, cc *domain.CabinCategory, events ...*domain.DomainEvent) error {
 	query := `UPDATE cabin_category SET name = ?, name_en = ?, sort_order = ?, is_default = ? WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, cc.Name, cc.NameEN, cc.SortOrder, cc.IsDefault, cc.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update cabin category: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, cc.Name, cc.NameEN, cc.SortOrder, cc.IsDefault, cc.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update cabin category: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, cc.ID, events)
+	})
 }
 
 // Delete deletes a cabin category
-func (r *CabinCategoryRepository) Delete(ctx context.Context, id uint64) error {
+func (r *CabinCategoryRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM cabin_category WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete cabin category: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete cabin category: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByName checks if a cabin category name exists
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CabinInventoryReportRepository handles supplier inventory report data access
+type CabinInventoryReportRepository struct {
+	db *DB
+}
+
+// NewCabinInventoryReportRepository creates a new cabin inventory report repository
+func NewCabinInventoryReportRepository(db *DB) *CabinInventoryReportRepository {
+	return &CabinInventoryReportRepository{db: db}
+}
+
+// Create creates an inventory report
+func (r *CabinInventoryReportRepository) Create(ctx context.Context, report *domain.CabinInventoryReport, events ...*domain.DomainEvent) error {
+	query := `INSERT INTO cabin_inventory_report (sailing_id, cabin_type_id, supplier_id, state, note, reported_at, created_by)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, report.SailingID, report.CabinTypeID, report.SupplierID, report.State,
+			sql.NullString{String: report.Note, Valid: report.Note != ""}, report.ReportedAt, report.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create inventory report: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		report.ID = uint64(id)
+
+		return appendDomainEvents(ctx, tx, report.ID, events)
+	})
+}
+
+// ListBySailing retrieves the inventory reports of a sailing in a time range, oldest first.
+// cabinTypeID optionally restricts them to one cabin type.
+func (r *CabinInventoryReportRepository) ListBySailing(ctx context.Context, sailingID uint64, cabinTypeID *uint64, from, to *time.Time) ([]domain.CabinInventoryReport, error) {
+	var reports []domain.CabinInventoryReport
+	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, state, COALESCE(note, '') AS note, reported_at, created_by
+              FROM cabin_inventory_report WHERE sailing_id = ?`
+	args := []interface{}{sailingID}
+
+	if cabinTypeID != nil {
+		query += " AND cabin_type_id = ?"
+		args = append(args, *cabinTypeID)
+	}
+
+	if from != nil {
+		query += " AND reported_at >= ?"
+		args = append(args, *from)
+	}
+
+	if to != nil {
+		query += " AND reported_at <= ?"
+		args = append(args, *to)
+	}
+
+	query += " ORDER BY reported_at ASC, id ASC"
+
+	if err := r.db.SelectContext(ctx, &reports, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list inventory reports: %w", err)
+	}
+
+	return reports, nil
+}
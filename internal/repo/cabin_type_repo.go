@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // CabinTypeRepository handles cabin type data access
@@ -91,6 +93,19 @@ func (r *CabinTypeRepository) ListByShip(ctx context.Context, shipID uint64) ([]
 	return cabinTypes, nil
 }
 
+// ListAll retrieves all enabled cabin types
+func (r *CabinTypeRepository) ListAll(ctx context.Context) ([]domain.CabinType, error) {
+	var cabinTypes []domain.CabinType
+	query := `SELECT id, ship_id, category_id, name, code, description, sort_order, is_enabled, created_at, updated_at 
+              FROM cabin_type WHERE is_enabled = 1 ORDER BY ship_id, category_id, sort_order, name`
+
+	if err := r.db.SelectContext(ctx, &cabinTypes, query); err != nil {
+		return nil, fmt.Errorf("failed to list all cabin types: %w", err)
+	}
+
+	return cabinTypes, nil
+}
+
 // ListByShipAndCategory retrieves cabin types for a ship and category
 func (r *CabinTypeRepository) ListByShipAndCategory(ctx context.Context, shipID, categoryID uint64) ([]domain.CabinType, error) {
 	var cabinTypes []domain.CabinType
@@ -105,46 +120,52 @@ func (r *CabinTypeRepository) ListByShipAndCategory(ctx context.Context, shipID,
 }
 
 // Create creates a new cabin type
-func (r *CabinTypeRepository) Create(ctx context.Context, ct *domain.CabinType) error {
+func (r *CabinTypeRepository) Create(ctx context.Context, ct *domain.CabinType, events ...*domain.DomainEvent) error {
 	query := `INSERT INTO cabin_type (ship_id, category_id, name, code, description, sort_order, is_enabled) 
               VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, ct.ShipID, ct.CategoryID, ct.Name, ct.Code, ct.Description, ct.SortOrder, ct.IsEnabled)
-	if err != nil {
-		return fmt.Errorf("failed to create cabin type: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, ct.ShipID, ct.CategoryID, ct.Name, ct.Code, ct.Description, ct.SortOrder, ct.IsEnabled)
+		if err != nil {
+			return fmt.Errorf("failed to create cabin type: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	ct.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		ct.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, ct.ID, events)
+	})
 }
 
 // Update updates a cabin type
-func (r *CabinTypeRepository) Update(ctx context.Context, ct *domain.CabinType) error {
+func (r *CabinTypeRepository) Update(ctx context.Context, ct *domain.CabinType, events ...*domain.DomainEvent) error {
 	query := `UPDATE cabin_type SET ship_id = ?, category_id = ?, name = ?, code = ?, description = ?, sort_order = ?, is_enabled = ? WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, ct.ShipID, ct.CategoryID, ct.Name, ct.Code, ct.Description, ct.SortOrder, ct.IsEnabled, ct.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update cabin type: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, ct.ShipID, ct.CategoryID, ct.Name, ct.Code, ct.Description, ct.SortOrder, ct.IsEnabled, ct.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update cabin type: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, ct.ID, events)
+	})
 }
 
 // Delete deletes a cabin type
-func (r *CabinTypeRepository) Delete(ctx context.Context, id uint64) error {
+func (r *CabinTypeRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM cabin_type WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete cabin type: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete cabin type: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByName checks if a cabin type name exists for a ship and category
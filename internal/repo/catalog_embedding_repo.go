@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CatalogEmbeddingRepository handles catalog embedding data access
+type CatalogEmbeddingRepository struct {
+	db *DB
+}
+
+// NewCatalogEmbeddingRepository creates a new catalog embedding repository
+func NewCatalogEmbeddingRepository(db *DB) *CatalogEmbeddingRepository {
+	return &CatalogEmbeddingRepository{db: db}
+}
+
+// GetByEntity retrieves the embedding of an entity for a model
+func (r *CatalogEmbeddingRepository) GetByEntity(ctx context.Context, entityType string, entityID uint64, model string) (*domain.CatalogEmbedding, error) {
+	var row catalogEmbeddingRow
+	query := `SELECT id, entity_type, entity_id, model, content, content_hash, dimensions, vector, created_at, updated_at
+              FROM catalog_embedding WHERE entity_type = ? AND entity_id = ? AND model = ?`
+
+	if err := r.db.GetContext(ctx, &row, query, entityType, entityID, model); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get catalog embedding: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListByEntities retrieves the embeddings of the given entities for a model.
+// A nil entityIDs lists every embedding of the entity type.
+func (r *CatalogEmbeddingRepository) ListByEntities(ctx context.Context, entityType string, model string, entityIDs []uint64) ([]domain.CatalogEmbedding, error) {
+	if entityIDs != nil && len(entityIDs) == 0 {
+		return []domain.CatalogEmbedding{}, nil
+	}
+
+	query := `SELECT id, entity_type, entity_id, model, content, content_hash, dimensions, vector, created_at, updated_at
+              FROM catalog_embedding WHERE entity_type = ? AND model = ?`
+	args := []interface{}{entityType, model}
+
+	if entityIDs != nil {
+		inQuery, inArgs, err := sqlx.In(" AND entity_id IN (?)", entityIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build entity filter: %w", err)
+		}
+		query += inQuery
+		args = append(args, inArgs...)
+	}
+
+	var rows []catalogEmbeddingRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list catalog embeddings: %w", err)
+	}
+
+	items := make([]domain.CatalogEmbedding, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
+// Upsert creates or replaces the embedding of an entity for a model
+func (r *CatalogEmbeddingRepository) Upsert(ctx context.Context, e *domain.CatalogEmbedding) error {
+	vectorJSON, err := json.Marshal(e.Vector)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vector: %w", err)
+	}
+
+	query := `INSERT INTO catalog_embedding (entity_type, entity_id, model, content, content_hash, dimensions, vector)
+              VALUES (?, ?, ?, ?, ?, ?, ?)
+              ON DUPLICATE KEY UPDATE content = VALUES(content), content_hash = VALUES(content_hash),
+              dimensions = VALUES(dimensions), vector = VALUES(vector)`
+
+	_, err = r.db.ExecContext(ctx, query, e.EntityType, e.EntityID, e.Model, e.Content, e.ContentHash, e.Dimensions, vectorJSON)
+	if err != nil {
+		return fmt.Errorf("failed to upsert catalog embedding: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByEntity deletes the embeddings of an entity for all models
+func (r *CatalogEmbeddingRepository) DeleteByEntity(ctx context.Context, entityType string, entityID uint64) error {
+	query := `DELETE FROM catalog_embedding WHERE entity_type = ? AND entity_id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, entityType, entityID)
+	if err != nil {
+		return fmt.Errorf("failed to delete catalog embedding: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExcept deletes the embeddings of an entity type and model whose entity is not in keepIDs
+func (r *CatalogEmbeddingRepository) DeleteExcept(ctx context.Context, entityType string, model string, keepIDs []uint64) (int64, error) {
+	query := `DELETE FROM catalog_embedding WHERE entity_type = ? AND model = ?`
+	args := []interface{}{entityType, model}
+
+	if len(keepIDs) > 0 {
+		inQuery, inArgs, err := sqlx.In(" AND entity_id NOT IN (?)", keepIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to build entity filter: %w", err)
+		}
+		query += inQuery
+		args = append(args, inArgs...)
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale catalog embeddings: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// CountByModel counts embeddings per entity type for a model
+func (r *CatalogEmbeddingRepository) CountByModel(ctx context.Context, model string) (map[string]int64, error) {
+	var rows []struct {
+		EntityType string `db:"entity_type"`
+		Count      int64  `db:"cnt"`
+	}
+	query := `SELECT entity_type, COUNT(*) AS cnt FROM catalog_embedding WHERE model = ? GROUP BY entity_type`
+
+	if err := r.db.SelectContext(ctx, &rows, query, model); err != nil {
+		return nil, fmt.Errorf("failed to count catalog embeddings: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EntityType] = row.Count
+	}
+
+	return counts, nil
+}
+
+// catalogEmbeddingRow is the database row structure for catalog_embedding
+type catalogEmbeddingRow struct {
+	ID          uint64       `db:"id"`
+	EntityType  string       `db:"entity_type"`
+	EntityID    uint64       `db:"entity_id"`
+	Model       string       `db:"model"`
+	Content     string       `db:"content"`
+	ContentHash string       `db:"content_hash"`
+	Dimensions  int          `db:"dimensions"`
+	Vector      []byte       `db:"vector"`
+	CreatedAt   sql.NullTime `db:"created_at"`
+	UpdatedAt   sql.NullTime `db:"updated_at"`
+}
+
+func (r *catalogEmbeddingRow) toDomain() *domain.CatalogEmbedding {
+	e := &domain.CatalogEmbedding{
+		ID:          r.ID,
+		EntityType:  r.EntityType,
+		EntityID:    r.EntityID,
+		Model:       r.Model,
+		Content:     r.Content,
+		ContentHash: r.ContentHash,
+		Dimensions:  r.Dimensions,
+	}
+
+	if r.Vector != nil {
+		_ = json.Unmarshal(r.Vector, &e.Vector)
+	}
+
+	if r.CreatedAt.Valid {
+		e.CreatedAt = r.CreatedAt.Time
+	}
+
+	if r.UpdatedAt.Valid {
+		e.UpdatedAt = r.UpdatedAt.Time
+	}
+
+	return e
+}
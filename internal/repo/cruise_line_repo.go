@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // CruiseLineRepository handles cruise line data access
@@ -108,7 +110,7 @@ func (r *CruiseLineRepository) ListAll(ctx context.Context) ([]domain.CruiseLine
 }
 
 // Create creates a new cruise line
-func (r *CruiseLineRepository) Create(ctx context.Context, cl *domain.CruiseLine) error {
+func (r *CruiseLineRepository) Create(ctx context.Context, cl *domain.CruiseLine, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(cl.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
@@ -117,22 +119,24 @@ func (r *CruiseLineRepository) Create(ctx context.Context, cl *domain.CruiseLine
 	query := `INSERT INTO cruise_line (name, name_en, aliases, status, created_by) 
               VALUES (?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, cl.Name, cl.NameEN, aliasesJSON, cl.Status, cl.CreatedBy)
-	if err != nil {
-		return fmt.Errorf("failed to create cruise line: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, cl.Name, cl.NameEN, aliasesJSON, cl.Status, cl.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create cruise line: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	cl.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		cl.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, cl.ID, events)
+	})
 }
 
 // Update updates a cruise line
-func (r *CruiseLineRepository) Update(ctx context.Context, cl *domain.CruiseLine) error {
+func (r *CruiseLineRepository) Update(ctx context.Context, cl *domain.CruiseLine, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(cl.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
@@ -140,24 +144,28 @@ func (r *CruiseLineRepository) Update(ctx context.Context, cl *domain.CruiseLine
 
 	query := `UPDATE cruise_line SET name = ?, name_en = ?, aliases = ?, status = ? WHERE id = ?`
 
-	_, err = r.db.ExecContext(ctx, query, cl.Name, cl.NameEN, aliasesJSON, cl.Status, cl.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update cruise line: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err = tx.ExecContext(ctx, query, cl.Name, cl.NameEN, aliasesJSON, cl.Status, cl.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update cruise line: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, cl.ID, events)
+	})
 }
 
 // Delete deletes a cruise line
-func (r *CruiseLineRepository) Delete(ctx context.Context, id uint64) error {
+func (r *CruiseLineRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM cruise_line WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete cruise line: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete cruise line: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByName checks if a cruise line name exists
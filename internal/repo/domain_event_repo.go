@@ -0,0 +1,171 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const domainEventColumns = `id, event_id, event_type, aggregate_type, aggregate_id, supplier_id, user_id,
+              payload, COALESCE(trace_id, '') AS trace_id, occurred_at`
+
+// DomainEventRepository handles the domain event outbox and the cursors of its subscribers
+type DomainEventRepository struct {
+	db *DB
+}
+
+// NewDomainEventRepository creates a new domain event repository
+func NewDomainEventRepository(db *DB) *DomainEventRepository {
+	return &DomainEventRepository{db: db}
+}
+
+// appendDomainEvents writes events to the outbox using q, which is the transaction of the
+// change they describe. Events without an aggregate ID get aggregateID, the ID of the row
+// the transaction has just created.
+func appendDomainEvents(ctx context.Context, q Querier, aggregateID uint64, events []*domain.DomainEvent) error {
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		if event.AggregateID == 0 {
+			event.AggregateID = aggregateID
+		}
+		if event.EventID == "" {
+			event.EventID = uuid.New().String()
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = time.Now()
+		}
+		if event.Data != nil {
+			payload, err := json.Marshal(event.Data)
+			if err != nil {
+				return fmt.Errorf("failed to marshal domain event payload: %w", err)
+			}
+			event.Payload = payload
+		}
+		if len(event.Payload) == 0 {
+			event.Payload = json.RawMessage("{}")
+		}
+
+		result, err := q.ExecContext(ctx, `INSERT INTO domain_event (event_id, event_type, aggregate_type,
+              aggregate_id, supplier_id, user_id, payload, trace_id, occurred_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			event.EventID, event.EventType, event.AggregateType, event.AggregateID, event.SupplierID,
+			event.UserID, []byte(event.Payload), sql.NullString{String: event.TraceID, Valid: event.TraceID != ""},
+			event.OccurredAt)
+		if err != nil {
+			return fmt.Errorf("failed to append domain event: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		event.ID = uint64(id)
+	}
+
+	return nil
+}
+
+// EventPosition is the position of an outbox event and when it was appended
+type EventPosition struct {
+	ID         uint64    `db:"id"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+// ListPositionsAfter retrieves the positions of up to limit events after afterID, of any
+// type, oldest first. Positions missing in between belong to transactions not committed
+// yet or rolled back.
+func (r *DomainEventRepository) ListPositionsAfter(ctx context.Context, afterID uint64, limit int) ([]EventPosition, error) {
+	var positions []EventPosition
+	query := `SELECT id, occurred_at FROM domain_event WHERE id > ? ORDER BY id LIMIT ?`
+
+	if err := r.db.SelectContext(ctx, &positions, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list domain event positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// ListAfter retrieves up to limit events positioned after afterID and up to uptoID, oldest
+// first. eventTypes optionally restricts the event types returned.
+func (r *DomainEventRepository) ListAfter(ctx context.Context, afterID, uptoID uint64, eventTypes []string, limit int) ([]domain.DomainEvent, error) {
+	var events []domain.DomainEvent
+	query := `SELECT ` + domainEventColumns + ` FROM domain_event WHERE id > ? AND id <= ?`
+	args := []interface{}{afterID, uptoID}
+
+	if len(eventTypes) > 0 {
+		query += " AND event_type IN (?)"
+		args = append(args, eventTypes)
+	}
+
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, limit)
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build event type filter: %w", err)
+	}
+
+	if err := r.db.SelectContext(ctx, &events, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list domain events: %w", err)
+	}
+
+	return events, nil
+}
+
+// LatestID returns the position of the newest event, 0 when the outbox is empty
+func (r *DomainEventRepository) LatestID(ctx context.Context) (uint64, error) {
+	var id uint64
+	if err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM domain_event`); err != nil {
+		return 0, fmt.Errorf("failed to get latest domain event id: %w", err)
+	}
+	return id, nil
+}
+
+// GetCursor retrieves the cursor of a subscriber, nil when it has never consumed
+func (r *DomainEventRepository) GetCursor(ctx context.Context, subscriber string) (*domain.EventCursor, error) {
+	var cursor domain.EventCursor
+	query := `SELECT subscriber, last_event_id, updated_at FROM domain_event_cursor WHERE subscriber = ?`
+
+	if err := r.db.GetContext(ctx, &cursor, query, subscriber); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get event cursor: %w", err)
+	}
+
+	return &cursor, nil
+}
+
+// ListCursors retrieves the cursors of every subscriber
+func (r *DomainEventRepository) ListCursors(ctx context.Context) ([]domain.EventCursor, error) {
+	var cursors []domain.EventCursor
+	query := `SELECT subscriber, last_event_id, updated_at FROM domain_event_cursor ORDER BY subscriber`
+
+	if err := r.db.SelectContext(ctx, &cursors, query); err != nil {
+		return nil, fmt.Errorf("failed to list event cursors: %w", err)
+	}
+
+	return cursors, nil
+}
+
+// SaveCursor moves the cursor of a subscriber to lastEventID, creating it when missing
+func (r *DomainEventRepository) SaveCursor(ctx context.Context, subscriber string, lastEventID uint64) error {
+	query := `INSERT INTO domain_event_cursor (subscriber, last_event_id) VALUES (?, ?)
+              ON DUPLICATE KEY UPDATE last_event_id = VALUES(last_event_id)`
+
+	if _, err := r.db.ExecContext(ctx, query, subscriber, lastEventID); err != nil {
+		return fmt.Errorf("failed to save event cursor: %w", err)
+	}
+
+	return nil
+}
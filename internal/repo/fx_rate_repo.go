@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// FXRateRepository handles exchange rate data access
+type FXRateRepository struct {
+	db *DB
+}
+
+// NewFXRateRepository creates a new exchange rate repository
+func NewFXRateRepository(db *DB) *FXRateRepository {
+	return &FXRateRepository{db: db}
+}
+
+// GetByID retrieves an exchange rate by ID
+func (r *FXRateRepository) GetByID(ctx context.Context, id uint64) (*domain.FXRate, error) {
+	var row fxRateRow
+	query := `SELECT id, rate_date, base_currency, quote_currency, rate, source, created_at, updated_at, created_by
+              FROM fx_rate WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get fx rate by id: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// List retrieves exchange rates with pagination and filters, newest first
+func (r *FXRateRepository) List(ctx context.Context, pagination Pagination, baseCurrency, quoteCurrency *string, fromDate, toDate *time.Time) (PaginatedResult[domain.FXRate], error) {
+	var rows []fxRateRow
+	var total int64
+
+	countQuery := "SELECT COUNT(*) FROM fx_rate WHERE 1=1"
+	selectQuery := `SELECT id, rate_date, base_currency, quote_currency, rate, source, created_at, updated_at, created_by
+                    FROM fx_rate WHERE 1=1`
+	var args []interface{}
+
+	if baseCurrency != nil {
+		countQuery += " AND base_currency = ?"
+		selectQuery += " AND base_currency = ?"
+		args = append(args, *baseCurrency)
+	}
+
+	if quoteCurrency != nil {
+		countQuery += " AND quote_currency = ?"
+		selectQuery += " AND quote_currency = ?"
+		args = append(args, *quoteCurrency)
+	}
+
+	if fromDate != nil {
+		countQuery += " AND rate_date >= ?"
+		selectQuery += " AND rate_date >= ?"
+		args = append(args, *fromDate)
+	}
+
+	if toDate != nil {
+		countQuery += " AND rate_date <= ?"
+		selectQuery += " AND rate_date <= ?"
+		args = append(args, *toDate)
+	}
+
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.FXRate]{}, fmt.Errorf("failed to count fx rates: %w", err)
+	}
+
+	selectQuery += " ORDER BY rate_date DESC, base_currency, quote_currency LIMIT ? OFFSET ?"
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &rows, selectQuery, args...); err != nil {
+		return PaginatedResult[domain.FXRate]{}, fmt.Errorf("failed to list fx rates: %w", err)
+	}
+
+	items := make([]domain.FXRate, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return NewPaginatedResult(items, total, pagination), nil
+}
+
+// GetEffective retrieves the rate for a currency pair in effect on the given date,
+// i.e. the latest rate whose rate_date is not after it
+func (r *FXRateRepository) GetEffective(ctx context.Context, baseCurrency, quoteCurrency string, at time.Time) (*domain.FXRate, error) {
+	var row fxRateRow
+	query := `SELECT id, rate_date, base_currency, quote_currency, rate, source, created_at, updated_at, created_by
+              FROM fx_rate
+              WHERE base_currency = ? AND quote_currency = ? AND rate_date <= ?
+              ORDER BY rate_date DESC LIMIT 1`
+
+	if err := r.db.GetContext(ctx, &row, query, baseCurrency, quoteCurrency, at.Format("2006-01-02")); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get effective fx rate: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// Upsert creates a rate or replaces the rate of the same pair and date
+func (r *FXRateRepository) Upsert(ctx context.Context, rate *domain.FXRate) error {
+	return r.upsert(ctx, r.db, rate)
+}
+
+// BulkUpsert upserts rates in a single transaction
+func (r *FXRateRepository) BulkUpsert(ctx context.Context, rates []domain.FXRate) error {
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		for i := range rates {
+			if err := r.upsert(ctx, tx, &rates[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *FXRateRepository) upsert(ctx context.Context, q Querier, rate *domain.FXRate) error {
+	query := `INSERT INTO fx_rate (rate_date, base_currency, quote_currency, rate, source, created_by)
+              VALUES (?, ?, ?, ?, ?, ?)
+              ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), rate = VALUES(rate), source = VALUES(source),
+              created_by = VALUES(created_by)`
+
+	result, err := q.ExecContext(ctx, query, rate.RateDate.Format("2006-01-02"), rate.BaseCurrency,
+		rate.QuoteCurrency, rate.Rate, rate.Source, rate.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to upsert fx rate: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	rate.ID = uint64(id)
+
+	return nil
+}
+
+// Delete deletes an exchange rate
+func (r *FXRateRepository) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM fx_rate WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete fx rate: %w", err)
+	}
+
+	return nil
+}
+
+// fxRateRow is the database row structure for fx_rate
+type fxRateRow struct {
+	ID            uint64          `db:"id"`
+	RateDate      time.Time       `db:"rate_date"`
+	BaseCurrency  string          `db:"base_currency"`
+	QuoteCurrency string          `db:"quote_currency"`
+	Rate          decimal.Decimal `db:"rate"`
+	Source        string          `db:"source"`
+	CreatedAt     sql.NullTime    `db:"created_at"`
+	UpdatedAt     sql.NullTime    `db:"updated_at"`
+	CreatedBy     sql.NullInt64   `db:"created_by"`
+}
+
+func (r *fxRateRow) toDomain() *domain.FXRate {
+	rate := &domain.FXRate{
+		ID:            r.ID,
+		RateDate:      r.RateDate,
+		BaseCurrency:  r.BaseCurrency,
+		QuoteCurrency: r.QuoteCurrency,
+		Rate:          r.Rate,
+		Source:        domain.FXRateSource(r.Source),
+	}
+
+	if r.CreatedAt.Valid {
+		rate.CreatedAt = r.CreatedAt.Time
+	}
+
+	if r.UpdatedAt.Valid {
+		rate.UpdatedAt = r.UpdatedAt.Time
+	}
+
+	if r.CreatedBy.Valid {
+		createdBy := uint64(r.CreatedBy.Int64)
+		rate.CreatedBy = &createdBy
+	}
+
+	return rate
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // ImportJobRepository handles import job data access
@@ -24,7 +26,7 @@ func NewImportJobRepository(db *DB) *ImportJobRepository {
 // GetByID retrieves an import job by ID
 func (r *ImportJobRepository) GetByID(ctx context.Context, id uint64) (*domain.ImportJob, error) {
 	var row importJobRow
-	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, raw_text, 
+	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, supplier_id, quoted_at, backfill, raw_text, 
               idempotency_key, model_version, prompt_version, result_summary, error_message, 
               started_at, completed_at, duration_ms, created_at, created_by 
               FROM import_job WHERE id = ?`
@@ -42,7 +44,7 @@ func (r *ImportJobRepository) GetByID(ctx context.Context, id uint64) (*domain.I
 // GetByIdempotencyKey retrieves an import job by idempotency key
 func (r *ImportJobRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.ImportJob, error) {
 	var row importJobRow
-	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, raw_text, 
+	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, supplier_id, quoted_at, backfill, raw_text, 
               idempotency_key, model_version, prompt_version, result_summary, error_message, 
               started_at, completed_at, duration_ms, created_at, created_by 
               FROM import_job WHERE idempotency_key = ?`
@@ -57,13 +59,31 @@ func (r *ImportJobRepository) GetByIdempotencyKey(ctx context.Context, key strin
 	return row.toDomain(), nil
 }
 
+// GetLatestByFileHash retrieves the most recent import job of a file by its SHA-256 hash
+func (r *ImportJobRepository) GetLatestByFileHash(ctx context.Context, hash string) (*domain.ImportJob, error) {
+	var row importJobRow
+	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, supplier_id, quoted_at, backfill, raw_text, 
+              idempotency_key, model_version, prompt_version, result_summary, error_message, 
+              started_at, completed_at, duration_ms, created_at, created_by 
+              FROM import_job WHERE file_hash = ? ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	if err := r.db.GetContext(ctx, &row, query, hash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get import job by file hash: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
 // List retrieves import jobs with pagination
 func (r *ImportJobRepository) List(ctx context.Context, pagination Pagination, userID *uint64, status *domain.ImportJobStatus, jobType *domain.ImportJobType) (PaginatedResult[domain.ImportJob], error) {
 	var rows []importJobRow
 	var total int64
 
 	countQuery := "SELECT COUNT(*) FROM import_job WHERE 1=1"
-	selectQuery := `SELECT id, type, status, file_name, file_hash, file_size, file_path, raw_text, 
+	selectQuery := `SELECT id, type, status, file_name, file_hash, file_size, file_path, supplier_id, quoted_at, backfill, raw_text, 
                     idempotency_key, model_version, prompt_version, result_summary, error_message, 
                     started_at, completed_at, duration_ms, created_at, created_by FROM import_job WHERE 1=1`
 	var args []interface{}
@@ -106,7 +126,7 @@ func (r *ImportJobRepository) List(ctx context.Context, pagination Pagination, u
 }
 
 // Create creates a new import job
-func (r *ImportJobRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+func (r *ImportJobRepository) Create(ctx context.Context, job *domain.ImportJob, events ...*domain.DomainEvent) error {
 	var resultJSON []byte
 	if job.ResultSummary != nil {
 		var err error
@@ -117,24 +137,26 @@ func (r *ImportJobRepository) Create(ctx context.Context, job *domain.ImportJob)
 	}
 
 	query := `INSERT INTO import_job (type, status, file_name, file_hash, file_size, file_path, 
-              raw_text, idempotency_key, model_version, prompt_version, result_summary, 
-              error_message, started_at, completed_at, created_by) 
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	result, err := r.db.ExecContext(ctx, query, job.Type, job.Status, job.FileName, job.FileHash,
-		job.FileSize, job.FilePath, job.RawText, job.IdempotencyKey, job.ModelVersion,
-		job.PromptVersion, resultJSON, job.ErrorMessage, job.StartedAt, job.CompletedAt, job.CreatedBy)
-	if err != nil {
-		return fmt.Errorf("failed to create import job: %w", err)
-	}
+              supplier_id, quoted_at, backfill, raw_text, idempotency_key, model_version, prompt_version, 
+              result_summary, error_message, started_at, completed_at, created_by) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, job.Type, job.Status, job.FileName, job.FileHash,
+			job.FileSize, job.FilePath, job.SupplierID, job.QuotedAt, job.Backfill, job.RawText, job.IdempotencyKey, job.ModelVersion,
+			job.PromptVersion, resultJSON, job.ErrorMessage, job.StartedAt, job.CompletedAt, job.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create import job: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	job.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		job.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, job.ID, events)
+	})
 }
 
 // UpdateStatus updates an import job status
@@ -163,7 +185,7 @@ func (r *ImportJobRepository) UpdateStarted(ctx context.Context, id uint64) erro
 }
 
 // UpdateCompleted marks job as completed
-func (r *ImportJobRepository) UpdateCompleted(ctx context.Context, id uint64, status domain.ImportJobStatus, summary *domain.ImportResultSummary, errorMsg string) error {
+func (r *ImportJobRepository) UpdateCompleted(ctx context.Context, id uint64, status domain.ImportJobStatus, summary *domain.ImportResultSummary, errorMsg string, events ...*domain.DomainEvent) error {
 	now := time.Now()
 	var resultJSON []byte
 	if summary != nil {
@@ -176,18 +198,20 @@ func (r *ImportJobRepository) UpdateCompleted(ctx context.Context, id uint64, st
 
 	query := `UPDATE import_job SET status = ?, result_summary = ?, error_message = ?, completed_at = ? WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, status, resultJSON, errorMsg, now, id)
-	if err != nil {
-		return fmt.Errorf("failed to update import job completed: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, status, resultJSON, errorMsg, now, id)
+		if err != nil {
+			return fmt.Errorf("failed to update import job completed: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ListPending retrieves pending import jobs
 func (r *ImportJobRepository) ListPending(ctx context.Context, limit int) ([]domain.ImportJob, error) {
 	var rows []importJobRow
-	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, raw_text, 
+	query := `SELECT id, type, status, file_name, file_hash, file_size, file_path, supplier_id, quoted_at, backfill, raw_text, 
               idempotency_key, model_version, prompt_version, result_summary, error_message, 
               started_at, completed_at, duration_ms, created_at, created_by 
               FROM import_job WHERE status = 'PENDING' ORDER BY created_at LIMIT ?`
@@ -204,6 +228,35 @@ func (r *ImportJobRepository) ListPending(ctx context.Context, limit int) ([]dom
 	return items, nil
 }
 
+// ImportJobSupplierStats counts the import jobs created by the users of a supplier by outcome
+type ImportJobSupplierStats struct {
+	SupplierID        uint64 `db:"supplier_id"`
+	Total             int    `db:"total"`
+	Succeeded         int    `db:"succeeded"`
+	Failed            int    `db:"failed"`
+	NeedsConfirmation int    `db:"needs_confirmation"`
+}
+
+// CountBySupplier counts the import jobs created in [from, to) per supplier. Jobs are attributed
+// to their own supplier, else to the supplier of the vendor user who created them, so jobs
+// created by admins without a supplier are not counted.
+func (r *ImportJobRepository) CountBySupplier(ctx context.Context, from, to time.Time) ([]ImportJobSupplierStats, error) {
+	var stats []ImportJobSupplierStats
+	query := `SELECT COALESCE(j.supplier_id, u.supplier_id) AS supplier_id, COUNT(*) AS total,
+              SUM(CASE WHEN j.status = 'SUCCEEDED' THEN 1 ELSE 0 END) AS succeeded,
+              SUM(CASE WHEN j.status = 'FAILED' THEN 1 ELSE 0 END) AS failed,
+              SUM(CASE WHEN j.status = 'NEEDS_CONFIRMATION' THEN 1 ELSE 0 END) AS needs_confirmation
+              FROM import_job j JOIN users u ON u.id = j.created_by
+              WHERE COALESCE(j.supplier_id, u.supplier_id) IS NOT NULL AND j.created_at >= ? AND j.created_at < ?
+              GROUP BY COALESCE(j.supplier_id, u.supplier_id)`
+
+	if err := r.db.SelectContext(ctx, &stats, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to count import jobs by supplier: %w", err)
+	}
+
+	return stats, nil
+}
+
 type importJobRow struct {
 	ID             uint64         `db:"id"`
 	Type           string         `db:"type"`
@@ -212,6 +265,8 @@ type importJobRow struct {
 	FileHash       sql.NullString `db:"file_hash"`
 	FileSize       sql.NullInt64  `db:"file_size"`
 	FilePath       sql.NullString `db:"file_path"`
+	SupplierID     sql.NullInt64  `db:"supplier_id"`
+	QuotedAt       sql.NullTime   `db:"quoted_at"`
 	RawText        sql.NullString `db:"raw_text"`
 	IdempotencyKey sql.NullString `db:"idempotency_key"`
 	ModelVersion   sql.NullString `db:"model_version"`
@@ -245,6 +300,13 @@ func (r *importJobRow) toDomain() *domain.ImportJob {
 	if r.FilePath.Valid {
 		job.FilePath = r.FilePath.String
 	}
+	if r.SupplierID.Valid {
+		supplierID := uint64(r.SupplierID.Int64)
+		job.SupplierID = &supplierID
+	}
+	if r.QuotedAt.Valid {
+		job.QuotedAt = &r.QuotedAt.Time
+	}
 	if r.RawText.Valid {
 		job.RawText = r.RawText.String
 	}
@@ -0,0 +1,276 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// portColumns is the column list selected into portRow
+const portColumns = `id, name, name_en, aliases, country, unlocode, status, created_at, updated_at, created_by`
+
+// PortRepository handles port data access
+type PortRepository struct {
+	db *DB
+}
+
+// NewPortRepository creates a new port repository
+func NewPortRepository(db *DB) *PortRepository {
+	return &PortRepository{db: db}
+}
+
+// GetByID retrieves a port by ID
+func (r *PortRepository) GetByID(ctx context.Context, id uint64) (*domain.Port, error) {
+	var row portRow
+	query := `SELECT ` + portColumns + ` FROM port WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get port by id: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// List retrieves ports with pagination
+func (r *PortRepository) List(ctx context.Context, pagination Pagination, country string, status *domain.EntityStatus) (PaginatedResult[domain.Port], error) {
+	var rows []portRow
+	var total int64
+
+	countQuery := "SELECT COUNT(*) FROM port WHERE 1=1"
+	selectQuery := `SELECT ` + portColumns + ` FROM port WHERE 1=1`
+	var args []interface{}
+
+	if country != "" {
+		countQuery += " AND country = ?"
+		selectQuery += " AND country = ?"
+		args = append(args, country)
+	}
+
+	if status != nil {
+		countQuery += " AND status = ?"
+		selectQuery += " AND status = ?"
+		args = append(args, *status)
+	}
+
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.Port]{}, fmt.Errorf("failed to count ports: %w", err)
+	}
+
+	selectQuery += " ORDER BY name LIMIT ? OFFSET ?"
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &rows, selectQuery, args...); err != nil {
+		return PaginatedResult[domain.Port]{}, fmt.Errorf("failed to list ports: %w", err)
+	}
+
+	items := make([]domain.Port, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return NewPaginatedResult(items, total, pagination), nil
+}
+
+// ListAll retrieves all active ports
+func (r *PortRepository) ListAll(ctx context.Context) ([]domain.Port, error) {
+	var rows []portRow
+	query := `SELECT ` + portColumns + ` FROM port WHERE status = 'ACTIVE' ORDER BY name`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list all ports: %w", err)
+	}
+
+	items := make([]domain.Port, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
+// ListByIDs retrieves ports by ID, whatever their status
+func (r *PortRepository) ListByIDs(ctx context.Context, ids []uint64) ([]domain.Port, error) {
+	if len(ids) == 0 {
+		return []domain.Port{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT `+portColumns+` FROM port WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build port query: %w", err)
+	}
+
+	var rows []portRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list ports by id: %w", err)
+	}
+
+	items := make([]domain.Port, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
+// Create creates a new port
+func (r *PortRepository) Create(ctx context.Context, port *domain.Port, events ...*domain.DomainEvent) error {
+	aliasesJSON, err := json.Marshal(port.Aliases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases: %w", err)
+	}
+
+	query := `INSERT INTO port (name, name_en, aliases, country, unlocode, status, created_by)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, port.Name, sql.NullString{String: port.NameEN, Valid: port.NameEN != ""}, aliasesJSON,
+			sql.NullString{String: port.Country, Valid: port.Country != ""}, sql.NullString{String: port.UNLocode, Valid: port.UNLocode != ""}, port.Status, port.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create port: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		port.ID = uint64(id)
+
+		return appendDomainEvents(ctx, tx, port.ID, events)
+	})
+}
+
+// Update updates a port
+func (r *PortRepository) Update(ctx context.Context, port *domain.Port, events ...*domain.DomainEvent) error {
+	aliasesJSON, err := json.Marshal(port.Aliases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases: %w", err)
+	}
+
+	query := `UPDATE port SET name = ?, name_en = ?, aliases = ?, country = ?, unlocode = ?, status = ? WHERE id = ?`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, port.Name, sql.NullString{String: port.NameEN, Valid: port.NameEN != ""}, aliasesJSON,
+			sql.NullString{String: port.Country, Valid: port.Country != ""}, sql.NullString{String: port.UNLocode, Valid: port.UNLocode != ""}, port.Status, port.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update port: %w", err)
+		}
+
+		return appendDomainEvents(ctx, tx, port.ID, events)
+	})
+}
+
+// Delete deletes a port
+func (r *PortRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
+	query := `DELETE FROM port WHERE id = ?`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete port: %w", err)
+		}
+
+		return appendDomainEvents(ctx, tx, id, events)
+	})
+}
+
+// ExistsByName checks if a port name exists
+func (r *PortRepository) ExistsByName(ctx context.Context, name string, excludeID *uint64) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM port WHERE name = ?`
+	args := []interface{}{name}
+
+	if excludeID != nil {
+		query += " AND id != ?"
+		args = append(args, *excludeID)
+	}
+
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return false, fmt.Errorf("failed to check port exists: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ExistsByUNLocode checks if a UN/LOCODE is already assigned to a port
+func (r *PortRepository) ExistsByUNLocode(ctx context.Context, unlocode string, excludeID *uint64) (bool, error) {
+	if unlocode == "" {
+		return false, nil
+	}
+
+	var count int
+	query := `SELECT COUNT(*) FROM port WHERE unlocode = ?`
+	args := []interface{}{unlocode}
+
+	if excludeID != nil {
+		query += " AND id != ?"
+		args = append(args, *excludeID)
+	}
+
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return false, fmt.Errorf("failed to check port unlocode exists: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// CountItineraryStops counts the itinerary stops calling at a port
+func (r *PortRepository) CountItineraryStops(ctx context.Context, portID uint64) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM sailing_itinerary WHERE port_id = ?`, portID); err != nil {
+		return 0, fmt.Errorf("failed to count itinerary stops: %w", err)
+	}
+	return count, nil
+}
+
+// portRow is the database row structure for port
+type portRow struct {
+	ID        uint64         `db:"id"`
+	Name      string         `db:"name"`
+	NameEN    sql.NullString `db:"name_en"`
+	Aliases   []byte         `db:"aliases"`
+	Country   sql.NullString `db:"country"`
+	UNLocode  sql.NullString `db:"unlocode"`
+	Status    string         `db:"status"`
+	CreatedAt sql.NullTime   `db:"created_at"`
+	UpdatedAt sql.NullTime   `db:"updated_at"`
+	CreatedBy sql.NullInt64  `db:"created_by"`
+}
+
+func (r *portRow) toDomain() *domain.Port {
+	port := &domain.Port{
+		ID:       r.ID,
+		Name:     r.Name,
+		NameEN:   r.NameEN.String,
+		Country:  r.Country.String,
+		UNLocode: r.UNLocode.String,
+		Status:   domain.EntityStatus(r.Status),
+	}
+
+	if r.Aliases != nil {
+		_ = json.Unmarshal(r.Aliases, &port.Aliases)
+	}
+
+	if r.CreatedAt.Valid {
+		port.CreatedAt = r.CreatedAt.Time
+	}
+
+	if r.UpdatedAt.Valid {
+		port.UpdatedAt = r.UpdatedAt.Time
+	}
+
+	if r.CreatedBy.Valid {
+		createdBy := uint64(r.CreatedBy.Int64)
+		port.CreatedBy = &createdBy
+	}
+
+	return port
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+)
+
+// priceAlertColumns is the column list selected into domain.PriceAlert
+const priceAlertColumns = `id, rule_id, user_id, quote_id, sailing_id, cabin_type_id, supplier_id,
+              condition_type, price, previous_price, currency, message, acknowledged_at, created_at`
+
+// PriceAlertRepository handles price alert data access
+type PriceAlertRepository struct {
+	db *DB
+}
+
+// NewPriceAlertRepository creates a new price alert repository
+func NewPriceAlertRepository(db *DB) *PriceAlertRepository {
+	return &PriceAlertRepository{db: db}
+}
+
+// GetByID retrieves a price alert by ID
+func (r *PriceAlertRepository) GetByID(ctx context.Context, id uint64) (*domain.PriceAlert, error) {
+	var alert domain.PriceAlert
+	query := `SELECT ` + priceAlertColumns + `
+              FROM price_alert WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &alert, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get price alert by id: %w", err)
+	}
+
+	return &alert, nil
+}
+
+// ListByUser retrieves the alerts of a user with pagination, newest first.
+// acknowledged filters on the acknowledgement state when set.
+func (r *PriceAlertRepository) ListByUser(ctx context.Context, pagination Pagination, userID uint64, acknowledged *bool) (PaginatedResult[domain.PriceAlert], error) {
+	var alerts []domain.PriceAlert
+	var total int64
+
+	countQuery := "SELECT COUNT(*) FROM price_alert WHERE user_id = ?"
+	selectQuery := `SELECT ` + priceAlertColumns + `
+              FROM price_alert WHERE user_id = ?`
+	args := []interface{}{userID}
+
+	if acknowledged != nil {
+		condition := " AND acknowledged_at IS NULL"
+		if *acknowledged {
+			condition = " AND acknowledged_at IS NOT NULL"
+		}
+		countQuery += condition
+		selectQuery += condition
+	}
+
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.PriceAlert]{}, fmt.Errorf("failed to count price alerts: %w", err)
+	}
+
+	selectQuery += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &alerts, selectQuery, args...); err != nil {
+		return PaginatedResult[domain.PriceAlert]{}, fmt.Errorf("failed to list price alerts: %w", err)
+	}
+
+	return NewPaginatedResult(alerts, total, pagination), nil
+}
+
+// Create creates a price alert; a rule raises at most one alert per quote, so a
+// duplicate is ignored and reported as false
+func (r *PriceAlertRepository) Create(ctx context.Context, alert *domain.PriceAlert) (bool, error) {
+	query := `INSERT IGNORE INTO price_alert (rule_id, user_id, quote_id, sailing_id, cabin_type_id, supplier_id,
+              condition_type, price, previous_price, currency, message)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, alert.RuleID, alert.UserID, alert.QuoteID, alert.SailingID,
+		alert.CabinTypeID, alert.SupplierID, alert.Condition, alert.Price, alert.PreviousPrice,
+		alert.Currency, alert.Message)
+	if err != nil {
+		return false, fmt.Errorf("failed to create price alert: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	alert.ID = uint64(id)
+
+	return true, nil
+}
+
+// Acknowledge marks a price alert as acknowledged; acknowledging twice keeps the first time
+func (r *PriceAlertRepository) Acknowledge(ctx context.Context, id uint64, at time.Time) error {
+	query := `UPDATE price_alert SET acknowledged_at = ? WHERE id = ? AND acknowledged_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, at, id); err != nil {
+		return fmt.Errorf("failed to acknowledge price alert: %w", err)
+	}
+
+	return nil
+}
+
+// AcknowledgeAll marks every unacknowledged alert of a user as acknowledged and returns how many were
+func (r *PriceAlertRepository) AcknowledgeAll(ctx context.Context, userID uint64, at time.Time) (int64, error) {
+	query := `UPDATE price_alert SET acknowledged_at = ? WHERE user_id = ? AND acknowledged_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, at, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acknowledge price alerts: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected, nil
+}
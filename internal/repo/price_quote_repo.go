@@ -3,15 +3,44 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"cruise-price-compare/internal/domain"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/shopspring/decimal"
 )
 
+// priceQuoteColumns is the column list selected into domain.PriceQuote
+const priceQuoteColumns = `id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit,
+              conditions, guest_count, max_occupancy, single_supplement_pct, single_supplement_amount,
+              promotion, cabin_quantity, valid_from, valid_until, quoted_at, notes, source,
+              source_ref, import_job_id, rfq_id, status, status_changed_at, reviewed_by, reviewed_at,
+              COALESCE(review_reason, '') AS review_reason, created_at, created_by, last_confirmed_at, confirmation_count`
+
+// currentQuoteFilter restricts price_quote rows to current prices: active quotes that have
+// taken effect and whose validity has not lapsed. With includeExpired, expired quotes count
+// as well; with includePending, quotes waiting for approval count as if they were active.
+func currentQuoteFilter(includeExpired, includePending bool) string {
+	statuses := "'ACTIVE'"
+	if includePending {
+		statuses += ", 'PENDING_REVIEW'"
+	}
+	started := " AND (valid_from IS NULL OR valid_from <= CURDATE())"
+	if includeExpired {
+		return "status IN (" + statuses + ", 'EXPIRED')" + started
+	}
+	return "status IN (" + statuses + ")" + started + " AND (valid_until IS NULL OR valid_until >= CURDATE())"
+}
+
+// latestQuoteOrder orders price_quote rows newest first: by the date the supplier issued the
+// quote, then by entry, so a quote entered late with an older quote date does not replace a
+// newer price
+const latestQuoteOrder = "quoted_at DESC, created_at DESC, id DESC"
+
 // PriceQuoteRepository handles price quote data access
 type PriceQuoteRepository struct {
 	db *DB
@@ -25,9 +54,7 @@ func NewPriceQuoteRepository(db *DB) *PriceQuoteRepository {
 // GetByID retrieves a price quote by ID
 func (r *PriceQuoteRepository) GetByID(ctx context.Context, id uint64) (*domain.PriceQuote, error) {
 	var pq domain.PriceQuote
-	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-              conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-              source_ref, import_job_id, status, created_at, created_by 
+	query := `SELECT ` + priceQuoteColumns + `
               FROM price_quote WHERE id = ?`
 
 	if err := r.db.GetContext(ctx, &pq, query, id); err != nil {
@@ -37,18 +64,28 @@ func (r *PriceQuoteRepository) GetByID(ctx context.Context, id uint64) (*domain.
 		return nil, fmt.Errorf("failed to get price quote by id: %w", err)
 	}
 
-	return &pq, nil
+	quotes := []domain.PriceQuote{pq}
+	if err := r.LoadOccupancyRates(ctx, quotes); err != nil {
+		return nil, err
+	}
+	if err := r.LoadComponents(ctx, quotes); err != nil {
+		return nil, err
+	}
+	if err := r.LoadPromotions(ctx, quotes); err != nil {
+		return nil, err
+	}
+
+	return &quotes[0], nil
 }
 
 // List retrieves price quotes with pagination and filters
-func (r *PriceQuoteRepository) List(ctx context.Context, pagination Pagination, sailingID, cabinTypeID, supplierID *uint64, status *domain.QuoteStatus) (PaginatedResult[domain.PriceQuote], error) {
+func (r *PriceQuoteRepository) List(ctx context.Context, pagination Pagination, sailingID, cabinTypeID, supplierID *uint64, status *domain.QuoteStatus, promotionType *domain.PromotionType) (PaginatedResult[domain.PriceQuote], error) {
 	var quotes []domain.PriceQuote
 	var total int64
 
 	countQuery := "SELECT COUNT(*) FROM price_quote WHERE 1=1"
-	selectQuery := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-                    conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-                    source_ref, import_job_id, status, created_at, created_by FROM price_quote WHERE 1=1`
+	selectQuery := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE 1=1`
 	var args []interface{}
 
 	if sailingID != nil {
@@ -75,6 +112,14 @@ func (r *PriceQuoteRepository) List(ctx context.Context, pagination Pagination,
 		args = append(args, *status)
 	}
 
+	if promotionType != nil {
+		filter := ` AND EXISTS (SELECT 1 FROM price_quote_promotion pp 
+              WHERE pp.quote_id = price_quote.id AND pp.promotion_type = ?)`
+		countQuery += filter
+		selectQuery += filter
+		args = append(args, *promotionType)
+	}
+
 	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
 		return PaginatedResult[domain.PriceQuote]{}, fmt.Errorf("failed to count price quotes: %w", err)
 	}
@@ -89,13 +134,31 @@ func (r *PriceQuoteRepository) List(ctx context.Context, pagination Pagination,
 	return NewPaginatedResult(quotes, total, pagination), nil
 }
 
-// ListBySailing retrieves all active quotes for a sailing
-func (r *PriceQuoteRepository) ListBySailing(ctx context.Context, sailingID uint64) ([]domain.PriceQuote, error) {
+// ListByIDs retrieves quotes by ID regardless of status
+func (r *PriceQuoteRepository) ListByIDs(ctx context.Context, ids []uint64) ([]domain.PriceQuote, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT `+priceQuoteColumns+` FROM price_quote WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build quote filter: %w", err)
+	}
+
 	var quotes []domain.PriceQuote
-	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-              conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-              source_ref, import_job_id, status, created_at, created_by 
-              FROM price_quote WHERE sailing_id = ? AND status = 'ACTIVE' ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &quotes, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list quotes by ids: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ListBySailing retrieves the current quotes for a sailing, newest quote date first,
+// optionally including expired quotes and quotes waiting for approval
+func (r *PriceQuoteRepository) ListBySailing(ctx context.Context, sailingID uint64, includeExpired, includePending bool) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+	query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE sailing_id = ? AND ` + currentQuoteFilter(includeExpired, includePending) + ` ORDER BY ` + latestQuoteOrder
 
 	if err := r.db.SelectContext(ctx, &quotes, query, sailingID); err != nil {
 		return nil, fmt.Errorf("failed to list quotes by sailing: %w", err)
@@ -104,12 +167,143 @@ func (r *PriceQuoteRepository) ListBySailing(ctx context.Context, sailingID uint
 	return quotes, nil
 }
 
+// ListByRFQ retrieves the active and expired quotes answering an RFQ, newest first,
+// optionally including quotes waiting for approval
+func (r *PriceQuoteRepository) ListByRFQ(ctx context.Context, rfqID uint64, includePending bool) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+	query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE rfq_id = ? AND ` + currentQuoteFilter(true, includePending) + `
+              ORDER BY ` + latestQuoteOrder
+
+	if err := r.db.SelectContext(ctx, &quotes, query, rfqID); err != nil {
+		return nil, fmt.Errorf("failed to list quotes by rfq: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ListBySailingAsOf retrieves the quotes of a sailing as they stood at the given time, newest
+// quote date first: quotes issued by then and in effect on that day that were not yet voided
+// or corrected, including those withdrawn since. With includeExpired, quotes past their
+// validity date on that day count as well. Withdrawn quotes without a recorded withdrawal
+// time are left out, as are quotes not yet approved by then.
+func (r *PriceQuoteRepository) ListBySailingAsOf(ctx context.Context, sailingID uint64, asOf time.Time, includeExpired bool) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+	query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote 
+              WHERE sailing_id = ? AND quoted_at <= ? AND (valid_from IS NULL OR valid_from <= ?)
+                AND (status IN ('ACTIVE', 'EXPIRED') OR status_changed_at > ?)
+                AND (reviewed_at IS NULL OR reviewed_at <= ?)`
+	args := []interface{}{sailingID, asOf, asOf.Format("2006-01-02"), asOf, asOf}
+
+	if !includeExpired {
+		query += " AND (valid_until IS NULL OR valid_until >= ?)"
+		args = append(args, asOf.Format("2006-01-02"))
+	}
+
+	query += " ORDER BY " + latestQuoteOrder
+
+	if err := r.db.SelectContext(ctx, &quotes, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list quotes by sailing as of time: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ListLatestBySailings retrieves the latest current quote of each sailing + cabin type + supplier
+// of several sailings in one query, ordered by sailing
+func (r *PriceQuoteRepository) ListLatestBySailings(ctx context.Context, sailingIDs []uint64) ([]domain.PriceQuote, error) {
+	if len(sailingIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT `+priceQuoteColumns+`
+              FROM (
+                  SELECT price_quote.*, ROW_NUMBER() OVER (
+                      PARTITION BY sailing_id, cabin_type_id, supplier_id ORDER BY `+latestQuoteOrder+`) AS latest_rank
+                  FROM price_quote
+                  WHERE sailing_id IN (?) AND `+currentQuoteFilter(false, false)+`
+              ) latest
+              WHERE latest_rank = 1
+              ORDER BY sailing_id, `+latestQuoteOrder, sailingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build latest quote query: %w", err)
+	}
+
+	var quotes []domain.PriceQuote
+	if err := r.db.SelectContext(ctx, &quotes, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list latest quotes by sailings: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ListForTrend retrieves current quotes for a sailing + cabin type quoted in a time range, oldest
+// quote date first, optionally including expired quotes and quotes waiting for approval. An
+// empty supplierIDs includes every supplier. A quote quoted before the range is included when
+// it was confirmed within it.
+func (r *PriceQuoteRepository) ListForTrend(ctx context.Context, sailingID, cabinTypeID uint64, supplierIDs []uint64, from, to *time.Time, includeExpired, includePending bool) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+	query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE sailing_id = ? AND cabin_type_id = ? AND ` + currentQuoteFilter(includeExpired, includePending)
+	args := []interface{}{sailingID, cabinTypeID}
+
+	if len(supplierIDs) > 0 {
+		inQuery, inArgs, err := sqlx.In(" AND supplier_id IN (?)", supplierIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build supplier filter: %w", err)
+		}
+		query += inQuery
+		args = append(args, inArgs...)
+	}
+
+	if from != nil {
+		query += " AND COALESCE(last_confirmed_at, quoted_at) >= ?"
+		args = append(args, *from)
+	}
+
+	if to != nil {
+		query += " AND quoted_at <= ?"
+		args = append(args, *to)
+	}
+
+	query += " ORDER BY quoted_at ASC, created_at ASC, id ASC"
+
+	if err := r.db.SelectContext(ctx, &quotes, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list quotes for trend: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ListHistory retrieves the active and expired quotes of sailings in the cabin types of a
+// category, their price history for forecasts and seasonal comparisons, oldest quote date first
+func (r *PriceQuoteRepository) ListHistory(ctx context.Context, sailingIDs []uint64, categoryID uint64) ([]domain.PriceQuote, error) {
+	if len(sailingIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT `+priceQuoteColumns+`
+              FROM price_quote
+              WHERE sailing_id IN (?) AND status IN ('ACTIVE', 'EXPIRED')
+                AND cabin_type_id IN (SELECT id FROM cabin_type WHERE category_id = ?)
+              ORDER BY quoted_at ASC, created_at ASC, id ASC`, sailingIDs, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build quote history query: %w", err)
+	}
+
+	var quotes []domain.PriceQuote
+	if err := r.db.SelectContext(ctx, &quotes, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list quote history: %w", err)
+	}
+
+	return quotes, nil
+}
+
 // ListBySupplier retrieves quotes by supplier with time range
 func (r *PriceQuoteRepository) ListBySupplier(ctx context.Context, supplierID uint64, from, to *time.Time) ([]domain.PriceQuote, error) {
 	var quotes []domain.PriceQuote
-	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-              conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-              source_ref, import_job_id, status, created_at, created_by 
+	query := `SELECT ` + priceQuoteColumns + `
               FROM price_quote WHERE supplier_id = ?`
 	args := []interface{}{supplierID}
 
@@ -132,17 +326,42 @@ func (r *PriceQuoteRepository) ListBySupplier(ctx context.Context, supplierID ui
 	return quotes, nil
 }
 
-// Create creates a new price quote (append-only)
-func (r *PriceQuoteRepository) Create(ctx context.Context, pq *domain.PriceQuote) error {
+// ListCreatedBetween retrieves the quotes of all suppliers created in [from, to), whatever
+// their status, oldest first
+func (r *PriceQuoteRepository) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+	query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE created_at >= ? AND created_at < ? ORDER BY created_at ASC, id ASC`
+
+	if err := r.db.SelectContext(ctx, &quotes, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to list quotes created in range: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// Create creates a new price quote (append-only) together with its occupancy rates and price components
+func (r *PriceQuoteRepository) Create(ctx context.Context, pq *domain.PriceQuote, events ...*domain.DomainEvent) error {
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		if err := r.create(ctx, tx, pq); err != nil {
+			return err
+		}
+		return appendDomainEvents(ctx, tx, pq.ID, events)
+	})
+}
+
+func (r *PriceQuoteRepository) create(ctx context.Context, q Querier, pq *domain.PriceQuote) error {
 	query := `INSERT INTO price_quote (sailing_id, cabin_type_id, supplier_id, price, currency, 
-              pricing_unit, conditions, guest_count, promotion, cabin_quantity, valid_until, 
-              notes, source, source_ref, import_job_id, status, created_by) 
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	result, err := r.db.ExecContext(ctx, query, pq.SailingID, pq.CabinTypeID, pq.SupplierID,
-		pq.Price, pq.Currency, pq.PricingUnit, pq.Conditions, pq.GuestCount, pq.Promotion,
-		pq.CabinQuantity, pq.ValidUntil, pq.Notes, pq.Source, pq.SourceRef, pq.ImportJobID,
-		pq.Status, pq.CreatedBy)
+              pricing_unit, conditions, guest_count, max_occupancy, single_supplement_pct, 
+              single_supplement_amount, promotion, cabin_quantity, valid_from, valid_until, quoted_at, 
+              notes, source, source_ref, import_job_id, rfq_id, status, created_by) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := q.ExecContext(ctx, query, pq.SailingID, pq.CabinTypeID, pq.SupplierID,
+		pq.Price, pq.Currency, pq.PricingUnit, pq.Conditions, pq.GuestCount, pq.MaxOccupancy,
+		pq.SingleSupplementPct, pq.SingleSupplementAmount, pq.Promotion,
+		pq.CabinQuantity, pq.ValidFrom, pq.ValidUntil, pq.QuotedAt, pq.Notes, pq.Source, pq.SourceRef, pq.ImportJobID,
+		pq.RFQID, pq.Status, pq.CreatedBy)
 	if err != nil {
 		return fmt.Errorf("failed to create price quote: %w", err)
 	}
@@ -153,39 +372,464 @@ func (r *PriceQuoteRepository) Create(ctx context.Context, pq *domain.PriceQuote
 	}
 	pq.ID = uint64(id)
 
+	for i := range pq.OccupancyRates {
+		rate := &pq.OccupancyRates[i]
+		rate.QuoteID = pq.ID
+
+		result, err := q.ExecContext(ctx, `INSERT INTO price_quote_occupancy_rate 
+              (quote_id, guest_slot, guest_type, price, max_child_age) VALUES (?, ?, ?, ?, ?)`,
+			rate.QuoteID, rate.GuestSlot, rate.GuestType, rate.Price, rate.MaxChildAge)
+		if err != nil {
+			return fmt.Errorf("failed to create occupancy rate: %w", err)
+		}
+
+		rateID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		rate.ID = uint64(rateID)
+	}
+
+	for i := range pq.Components {
+		component := &pq.Components[i]
+		component.QuoteID = pq.ID
+
+		result, err := q.ExecContext(ctx, `INSERT INTO price_quote_component 
+              (quote_id, component_type, amount, pricing_unit, per_night, inclusive, description) 
+              VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			component.QuoteID, component.ComponentType, component.Amount, component.PricingUnit,
+			component.PerNight, component.Inclusive, sql.NullString{String: component.Description, Valid: component.Description != ""})
+		if err != nil {
+			return fmt.Errorf("failed to create price component: %w", err)
+		}
+
+		componentID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		component.ID = uint64(componentID)
+	}
+
+	for i := range pq.Promotions {
+		promotion := &pq.Promotions[i]
+		promotion.QuoteID = pq.ID
+
+		var guestSlots []byte
+		if len(promotion.GuestSlots) > 0 {
+			var err error
+			if guestSlots, err = json.Marshal(promotion.GuestSlots); err != nil {
+				return fmt.Errorf("failed to marshal guest slots: %w", err)
+			}
+		}
+
+		result, err := q.ExecContext(ctx, `INSERT INTO price_quote_promotion 
+              (quote_id, promotion_type, discount_pct, discount_amount, pricing_unit, guest_slots, 
+              booking_from, booking_until, stackable, description) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			promotion.QuoteID, promotion.PromotionType, promotion.DiscountPct, promotion.DiscountAmount,
+			promotion.PricingUnit, guestSlots, promotion.BookingFrom, promotion.BookingUntil, promotion.Stackable,
+			sql.NullString{String: promotion.Description, Valid: promotion.Description != ""})
+		if err != nil {
+			return fmt.Errorf("failed to create promotion: %w", err)
+		}
+
+		promotionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		promotion.ID = uint64(promotionID)
+	}
+
+	return nil
+}
+
+// LoadOccupancyRates loads the occupancy rates of the given quotes in place
+func (r *PriceQuoteRepository) LoadOccupancyRates(ctx context.Context, quotes []domain.PriceQuote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(quotes))
+	for i := range quotes {
+		ids[i] = quotes[i].ID
+	}
+
+	query, args, err := sqlx.In(`SELECT id, quote_id, guest_slot, guest_type, price, max_child_age 
+              FROM price_quote_occupancy_rate WHERE quote_id IN (?) ORDER BY quote_id, guest_slot, guest_type`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build quote filter: %w", err)
+	}
+
+	var rates []domain.OccupancyRate
+	if err := r.db.SelectContext(ctx, &rates, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load occupancy rates: %w", err)
+	}
+
+	byQuote := make(map[uint64][]domain.OccupancyRate, len(quotes))
+	for _, rate := range rates {
+		byQuote[rate.QuoteID] = append(byQuote[rate.QuoteID], rate)
+	}
+	for i := range quotes {
+		quotes[i].OccupancyRates = byQuote[quotes[i].ID]
+	}
+
+	return nil
+}
+
+// LoadComponents loads the price components of the given quotes in place
+func (r *PriceQuoteRepository) LoadComponents(ctx context.Context, quotes []domain.PriceQuote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(quotes))
+	for i := range quotes {
+		ids[i] = quotes[i].ID
+	}
+
+	query, args, err := sqlx.In(`SELECT id, quote_id, component_type, amount, pricing_unit, per_night, 
+              inclusive, COALESCE(description, '') AS description 
+              FROM price_quote_component WHERE quote_id IN (?) ORDER BY quote_id, id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build quote filter: %w", err)
+	}
+
+	var components []domain.PriceComponent
+	if err := r.db.SelectContext(ctx, &components, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load price components: %w", err)
+	}
+
+	byQuote := make(map[uint64][]domain.PriceComponent, len(quotes))
+	for _, component := range components {
+		byQuote[component.QuoteID] = append(byQuote[component.QuoteID], component)
+	}
+	for i := range quotes {
+		quotes[i].Components = byQuote[quotes[i].ID]
+	}
+
 	return nil
 }
 
-// VoidQuote marks a quote as voided (no updates, append new status)
-func (r *PriceQuoteRepository) VoidQuote(ctx context.Context, id uint64) error {
-	query := `UPDATE price_quote SET status = 'VOIDED' WHERE id = ? AND status = 'ACTIVE'`
+// Confirm records that an active quote was resubmitted unchanged: it stamps the quote with
+// the confirmation time and stores the confirmation. Returns false when the quote is no longer active.
+func (r *PriceQuoteRepository) Confirm(ctx context.Context, confirmation *domain.QuoteConfirmation, events ...*domain.DomainEvent) (bool, error) {
+	confirmed := false
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		// An older resubmission processed late never moves the last confirmation back
+		result, err := tx.ExecContext(ctx, `UPDATE price_quote 
+              SET last_confirmed_at = GREATEST(COALESCE(last_confirmed_at, ?), ?), confirmation_count = confirmation_count + 1 
+              WHERE id = ? AND status = 'ACTIVE'`, confirmation.ConfirmedAt, confirmation.ConfirmedAt, confirmation.QuoteID)
+		if err != nil {
+			return fmt.Errorf("failed to confirm quote: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+
+		result, err = tx.ExecContext(ctx, `INSERT INTO quote_confirmation 
+              (quote_id, source, source_ref, import_job_id, confirmed_at, created_by) VALUES (?, ?, ?, ?, ?, ?)`,
+			confirmation.QuoteID, confirmation.Source,
+			sql.NullString{String: confirmation.SourceRef, Valid: confirmation.SourceRef != ""},
+			confirmation.ImportJobID, confirmation.ConfirmedAt, confirmation.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create quote confirmation: %w", err)
+		}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		confirmation.ID = uint64(id)
+		confirmed = true
+
+		return appendDomainEvents(ctx, tx, confirmation.QuoteID, events)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to void quote: %w", err)
+		return false, err
+	}
+
+	return confirmed, nil
+}
+
+// ListConfirmations retrieves the confirmations of the given quotes in a time range, oldest first
+func (r *PriceQuoteRepository) ListConfirmations(ctx context.Context, quoteIDs []uint64, from, to *time.Time) ([]domain.QuoteConfirmation, error) {
+	if len(quoteIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, quote_id, source, COALESCE(source_ref, '') AS source_ref, import_job_id, 
+              confirmed_at, created_by FROM quote_confirmation WHERE quote_id IN (?)`
+	args := []interface{}{quoteIDs}
+
+	if from != nil {
+		query += " AND confirmed_at >= ?"
+		args = append(args, *from)
+	}
+
+	if to != nil {
+		query += " AND confirmed_at <= ?"
+		args = append(args, *to)
 	}
 
-	affected, err := result.RowsAffected()
+	query += " ORDER BY confirmed_at ASC, id ASC"
+
+	query, args, err := sqlx.In(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+		return nil, fmt.Errorf("failed to build quote filter: %w", err)
 	}
 
-	if affected == 0 {
-		return errors.New("quote not found or already voided")
+	var confirmations []domain.QuoteConfirmation
+	if err := r.db.SelectContext(ctx, &confirmations, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list quote confirmations: %w", err)
+	}
+
+	return confirmations, nil
+}
+
+// LoadPromotions loads the promotions of the given quotes in place
+func (r *PriceQuoteRepository) LoadPromotions(ctx context.Context, quotes []domain.PriceQuote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(quotes))
+	for i := range quotes {
+		ids[i] = quotes[i].ID
+	}
+
+	query, args, err := sqlx.In(`SELECT id, quote_id, promotion_type, discount_pct, discount_amount, pricing_unit, 
+              guest_slots, booking_from, booking_until, stackable, COALESCE(description, '') AS description 
+              FROM price_quote_promotion WHERE quote_id IN (?) ORDER BY quote_id, id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build quote filter: %w", err)
+	}
+
+	var rows []promotionRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load promotions: %w", err)
+	}
+
+	byQuote := make(map[uint64][]domain.Promotion, len(quotes))
+	for i := range rows {
+		byQuote[rows[i].QuoteID] = append(byQuote[rows[i].QuoteID], rows[i].toDomain())
+	}
+	for i := range quotes {
+		quotes[i].Promotions = byQuote[quotes[i].ID]
 	}
 
 	return nil
 }
 
-// GetLatestPrice gets the latest active price for a sailing + cabin type + supplier combination
+// promotionRow is the database representation of a promotion
+type promotionRow struct {
+	domain.Promotion
+	GuestSlots []byte `db:"guest_slots"`
+}
+
+func (r *promotionRow) toDomain() domain.Promotion {
+	promotion := r.Promotion
+	if r.GuestSlots != nil {
+		_ = json.Unmarshal(r.GuestSlots, &promotion.GuestSlots)
+	}
+	return promotion
+}
+
+// VoidQuote marks an active or expired quote as voided (no updates, append new status)
+func (r *PriceQuoteRepository) VoidQuote(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
+	query := `UPDATE price_quote SET status = 'VOIDED', status_changed_at = CURRENT_TIMESTAMP 
+              WHERE id = ? AND status IN ('ACTIVE', 'EXPIRED')`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to void quote: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		if affected == 0 {
+			return errors.New("quote not found or already voided")
+		}
+
+		return appendDomainEvents(ctx, tx, id, events)
+	})
+}
+
+// CorrectQuote marks an active or expired quote as CORRECTED and creates its replacement in one transaction
+func (r *PriceQuoteRepository) CorrectQuote(ctx context.Context, id uint64, replacement *domain.PriceQuote, events ...*domain.DomainEvent) error {
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE price_quote SET status = 'CORRECTED', status_changed_at = CURRENT_TIMESTAMP 
+              WHERE id = ? AND status IN ('ACTIVE', 'EXPIRED')`, id)
+		if err != nil {
+			return fmt.Errorf("failed to mark quote as corrected: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return errors.New("quote is no longer active")
+		}
+
+		if err := r.create(ctx, tx, replacement); err != nil {
+			return err
+		}
+		return appendDomainEvents(ctx, tx, replacement.ID, events)
+	})
+}
+
+// ExpireQuotes marks up to limit active quotes whose valid_until lies before the given
+// date as EXPIRED and returns them as they were before the change. eventFor, when set,
+// builds the domain event recorded for each expired quote.
+func (r *PriceQuoteRepository) ExpireQuotes(ctx context.Context, before time.Time, limit int, eventFor func(q *domain.PriceQuote) *domain.DomainEvent) ([]domain.PriceQuote, error) {
+	var quotes []domain.PriceQuote
+
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		query := `SELECT ` + priceQuoteColumns + `
+              FROM price_quote WHERE status = 'ACTIVE' AND valid_until < ?
+              ORDER BY id LIMIT ? FOR UPDATE`
+		if err := tx.SelectContext(ctx, &quotes, query, before.Format("2006-01-02"), limit); err != nil {
+			return fmt.Errorf("failed to list expirable quotes: %w", err)
+		}
+		if len(quotes) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(quotes))
+		for i := range quotes {
+			ids[i] = quotes[i].ID
+		}
+
+		update, args, err := sqlx.In(`UPDATE price_quote SET status = 'EXPIRED', status_changed_at = CURRENT_TIMESTAMP 
+              WHERE id IN (?) AND status = 'ACTIVE'`, ids)
+		if err != nil {
+			return fmt.Errorf("failed to build quote filter: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(update), args...); err != nil {
+			return fmt.Errorf("failed to expire quotes: %w", err)
+		}
+
+		if eventFor == nil {
+			return nil
+		}
+		events := make([]*domain.DomainEvent, len(quotes))
+		for i := range quotes {
+			events[i] = eventFor(&quotes[i])
+		}
+		return appendDomainEvents(ctx, tx, 0, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// ListPendingReview retrieves the quotes waiting for approval with pagination, oldest first,
+// optionally of one supplier or sailing
+func (r *PriceQuoteRepository) ListPendingReview(ctx context.Context, pagination Pagination, supplierID, sailingID *uint64) (PaginatedResult[domain.PriceQuote], error) {
+	var quotes []domain.PriceQuote
+	var total int64
+
+	where := " WHERE status = 'PENDING_REVIEW'"
+	var args []interface{}
+
+	if supplierID != nil {
+		where += " AND supplier_id = ?"
+		args = append(args, *supplierID)
+	}
+
+	if sailingID != nil {
+		where += " AND sailing_id = ?"
+		args = append(args, *sailingID)
+	}
+
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM price_quote"+where, args...); err != nil {
+		return PaginatedResult[domain.PriceQuote]{}, fmt.Errorf("failed to count pending quotes: %w", err)
+	}
+
+	query := `SELECT ` + priceQuoteColumns + ` FROM price_quote` + where + ` ORDER BY created_at ASC, id ASC LIMIT ? OFFSET ?`
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &quotes, query, args...); err != nil {
+		return PaginatedResult[domain.PriceQuote]{}, fmt.Errorf("failed to list pending quotes: %w", err)
+	}
+
+	return NewPaginatedResult(quotes, total, pagination), nil
+}
+
+// ReviewQuotes moves the given quotes that wait for approval to status, ACTIVE when approved
+// or REJECTED, recording the reviewer and reason, and returns them as they were before the
+// change. Quotes no longer waiting for approval are skipped. eventsFor, when set, builds the
+// domain events recorded for each reviewed quote.
+func (r *PriceQuoteRepository) ReviewQuotes(ctx context.Context, ids []uint64, status domain.QuoteStatus, reviewedBy uint64, reason string, reviewedAt time.Time, eventsFor func(q *domain.PriceQuote) []*domain.DomainEvent) ([]domain.PriceQuote, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var quotes []domain.PriceQuote
+
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		query, args, err := sqlx.In(`SELECT `+priceQuoteColumns+`
+              FROM price_quote WHERE id IN (?) AND status = 'PENDING_REVIEW'
+              ORDER BY id FOR UPDATE`, ids)
+		if err != nil {
+			return fmt.Errorf("failed to build quote filter: %w", err)
+		}
+		if err := tx.SelectContext(ctx, &quotes, tx.Rebind(query), args...); err != nil {
+			return fmt.Errorf("failed to list pending quotes: %w", err)
+		}
+		if len(quotes) == 0 {
+			return nil
+		}
+
+		pending := make([]uint64, len(quotes))
+		for i := range quotes {
+			pending[i] = quotes[i].ID
+		}
+
+		update, args, err := sqlx.In(`UPDATE price_quote SET status = ?, status_changed_at = ?,
+              reviewed_by = ?, reviewed_at = ?, review_reason = ?
+              WHERE id IN (?) AND status = 'PENDING_REVIEW'`,
+			status, reviewedAt, reviewedBy, reviewedAt, sql.NullString{String: reason, Valid: reason != ""}, pending)
+		if err != nil {
+			return fmt.Errorf("failed to build quote filter: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(update), args...); err != nil {
+			return fmt.Errorf("failed to review quotes: %w", err)
+		}
+
+		if eventsFor == nil {
+			return nil
+		}
+		var events []*domain.DomainEvent
+		for i := range quotes {
+			events = append(events, eventsFor(&quotes[i])...)
+		}
+		return appendDomainEvents(ctx, tx, 0, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// GetLatestPrice gets the latest current price for a sailing + cabin type + supplier combination
 func (r *PriceQuoteRepository) GetLatestPrice(ctx context.Context, sailingID, cabinTypeID, supplierID uint64) (*domain.PriceQuote, error) {
 	var pq domain.PriceQuote
-	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-              conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-              source_ref, import_job_id, status, created_at, created_by 
+	query := `SELECT ` + priceQuoteColumns + `
               FROM price_quote 
-              WHERE sailing_id = ? AND cabin_type_id = ? AND supplier_id = ? AND status = 'ACTIVE'
-              ORDER BY created_at DESC LIMIT 1`
+              WHERE sailing_id = ? AND cabin_type_id = ? AND supplier_id = ? AND ` + currentQuoteFilter(false, false) + `
+              ORDER BY ` + latestQuoteOrder + ` LIMIT 1`
 
 	if err := r.db.GetContext(ctx, &pq, query, sailingID, cabinTypeID, supplierID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -200,12 +844,10 @@ func (r *PriceQuoteRepository) GetLatestPrice(ctx context.Context, sailingID, ca
 // GetPriceHistory gets price history for a sailing + cabin type + supplier
 func (r *PriceQuoteRepository) GetPriceHistory(ctx context.Context, sailingID, cabinTypeID, supplierID uint64, limit int) ([]domain.PriceQuote, error) {
 	var quotes []domain.PriceQuote
-	query := `SELECT id, sailing_id, cabin_type_id, supplier_id, price, currency, pricing_unit, 
-              conditions, guest_count, promotion, cabin_quantity, valid_until, notes, source, 
-              source_ref, import_job_id, status, created_at, created_by 
+	query := `SELECT ` + priceQuoteColumns + `
               FROM price_quote 
               WHERE sailing_id = ? AND cabin_type_id = ? AND supplier_id = ?
-              ORDER BY created_at DESC LIMIT ?`
+              ORDER BY ` + latestQuoteOrder + ` LIMIT ?`
 
 	if err := r.db.SelectContext(ctx, &quotes, query, sailingID, cabinTypeID, supplierID, limit); err != nil {
 		return nil, fmt.Errorf("failed to get price history: %w", err)
@@ -217,19 +859,16 @@ func (r *PriceQuoteRepository) GetPriceHistory(ctx context.Context, sailingID, c
 // GetComparisonData retrieves latest prices for comparison view
 func (r *PriceQuoteRepository) GetComparisonData(ctx context.Context, sailingID uint64) ([]ComparisonRow, error) {
 	var rows []ComparisonRow
-	query := `SELECT pq.cabin_type_id, pq.supplier_id, pq.price, pq.currency, pq.pricing_unit, pq.created_at
-              FROM price_quote pq
-              INNER JOIN (
-                  SELECT cabin_type_id, supplier_id, MAX(created_at) as max_created
-                  FROM price_quote 
-                  WHERE sailing_id = ? AND status = 'ACTIVE'
-                  GROUP BY cabin_type_id, supplier_id
-              ) latest ON pq.cabin_type_id = latest.cabin_type_id 
-                       AND pq.supplier_id = latest.supplier_id 
-                       AND pq.created_at = latest.max_created
-              WHERE pq.sailing_id = ? AND pq.status = 'ACTIVE'`
-
-	if err := r.db.SelectContext(ctx, &rows, query, sailingID, sailingID); err != nil {
+	query := `SELECT cabin_type_id, supplier_id, price, currency, pricing_unit, created_at
+              FROM (
+                  SELECT price_quote.*, ROW_NUMBER() OVER (
+                      PARTITION BY cabin_type_id, supplier_id ORDER BY ` + latestQuoteOrder + `) AS latest_rank
+                  FROM price_quote
+                  WHERE sailing_id = ? AND ` + currentQuoteFilter(false, false) + `
+              ) latest
+              WHERE latest_rank = 1`
+
+	if err := r.db.SelectContext(ctx, &rows, query, sailingID); err != nil {
 		return nil, fmt.Errorf("failed to get comparison data: %w", err)
 	}
 
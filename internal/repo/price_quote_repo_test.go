@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCurrentQuoteFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeExpired bool
+		includePending bool
+		wantStatuses   string
+		wantValidUntil bool
+	}{
+		{"current", false, false, "status IN ('ACTIVE')", true},
+		{"with expired", true, false, "status IN ('ACTIVE', 'EXPIRED')", false},
+		{"with pending", false, true, "status IN ('ACTIVE', 'PENDING_REVIEW')", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := currentQuoteFilter(tt.includeExpired, tt.includePending)
+			if !strings.HasPrefix(filter, tt.wantStatuses) {
+				t.Errorf("filter %q does not start with %q", filter, tt.wantStatuses)
+			}
+			// A price that only takes effect later is never current
+			if !strings.Contains(filter, "(valid_from IS NULL OR valid_from <= CURDATE())") {
+				t.Errorf("filter %q does not check valid_from", filter)
+			}
+			if got := strings.Contains(filter, "valid_until"); got != tt.wantValidUntil {
+				t.Errorf("filter %q checks valid_until = %v, want %v", filter, got, tt.wantValidUntil)
+			}
+		})
+	}
+}
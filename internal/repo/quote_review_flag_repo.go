@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+)
+
+// quoteReviewFlagColumns is the column list selected into quoteReviewFlagRow
+const quoteReviewFlagColumns = `id, quote_id, sailing_id, cabin_type_id, supplier_id, reasons, status,
+              COALESCE(review_note, '') AS review_note, reviewed_by, reviewed_at, created_at`
+
+// QuoteReviewFlagRepository handles quote review flag data access
+type QuoteReviewFlagRepository struct {
+	db *DB
+}
+
+// NewQuoteReviewFlagRepository creates a new quote review flag repository
+func NewQuoteReviewFlagRepository(db *DB) *QuoteReviewFlagRepository {
+	return &QuoteReviewFlagRepository{db: db}
+}
+
+// GetByID retrieves a review flag by ID
+func (r *QuoteReviewFlagRepository) GetByID(ctx context.Context, id uint64) (*domain.QuoteReviewFlag, error) {
+	var row quoteReviewFlagRow
+	query := `SELECT ` + quoteReviewFlagColumns + ` FROM quote_review_flag WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quote review flag by id: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// List retrieves review flags with pagination and filters, oldest first so the queue is worked in order
+func (r *QuoteReviewFlagRepository) List(ctx context.Context, pagination Pagination, status *domain.ReviewFlagStatus, supplierID, sailingID *uint64) (PaginatedResult[domain.QuoteReviewFlag], error) {
+	var rows []quoteReviewFlagRow
+	var total int64
+
+	countQuery := "SELECT COUNT(*) FROM quote_review_flag WHERE 1=1"
+	selectQuery := `SELECT ` + quoteReviewFlagColumns + ` FROM quote_review_flag WHERE 1=1`
+	var args []interface{}
+
+	if status != nil {
+		countQuery += " AND status = ?"
+		selectQuery += " AND status = ?"
+		args = append(args, *status)
+	}
+
+	if supplierID != nil {
+		countQuery += " AND supplier_id = ?"
+		selectQuery += " AND supplier_id = ?"
+		args = append(args, *supplierID)
+	}
+
+	if sailingID != nil {
+		countQuery += " AND sailing_id = ?"
+		selectQuery += " AND sailing_id = ?"
+		args = append(args, *sailingID)
+	}
+
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.QuoteReviewFlag]{}, fmt.Errorf("failed to count quote review flags: %w", err)
+	}
+
+	selectQuery += " ORDER BY created_at ASC, id ASC LIMIT ? OFFSET ?"
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &rows, selectQuery, args...); err != nil {
+		return PaginatedResult[domain.QuoteReviewFlag]{}, fmt.Errorf("failed to list quote review flags: %w", err)
+	}
+
+	items := make([]domain.QuoteReviewFlag, len(rows))
+	for i := range rows {
+		items[i] = *rows[i].toDomain()
+	}
+
+	return NewPaginatedResult(items, total, pagination), nil
+}
+
+// Create creates a review flag for a quote
+func (r *QuoteReviewFlagRepository) Create(ctx context.Context, flag *domain.QuoteReviewFlag) error {
+	reasonsJSON, err := json.Marshal(flag.Reasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reasons: %w", err)
+	}
+
+	query := `INSERT INTO quote_review_flag (quote_id, sailing_id, cabin_type_id, supplier_id, reasons, status)
+              VALUES (?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, flag.QuoteID, flag.SailingID, flag.CabinTypeID, flag.SupplierID,
+		reasonsJSON, flag.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create quote review flag: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	flag.ID = uint64(id)
+
+	return nil
+}
+
+// Review records the decision on an open flag. Returns false when the flag is no longer open.
+func (r *QuoteReviewFlagRepository) Review(ctx context.Context, id uint64, status domain.ReviewFlagStatus, note string, reviewedBy uint64, at time.Time) (bool, error) {
+	query := `UPDATE quote_review_flag SET status = ?, review_note = ?, reviewed_by = ?, reviewed_at = ?
+              WHERE id = ? AND status = 'OPEN'`
+
+	result, err := r.db.ExecContext(ctx, query, status, sql.NullString{String: note, Valid: note != ""}, reviewedBy, at, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to review quote review flag: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// quoteReviewFlagRow is the database representation of a review flag
+type quoteReviewFlagRow struct {
+	ID          uint64                  `db:"id"`
+	QuoteID     uint64                  `db:"quote_id"`
+	SailingID   uint64                  `db:"sailing_id"`
+	CabinTypeID uint64                  `db:"cabin_type_id"`
+	SupplierID  uint64                  `db:"supplier_id"`
+	Reasons     []byte                  `db:"reasons"`
+	Status      domain.ReviewFlagStatus `db:"status"`
+	ReviewNote  string                  `db:"review_note"`
+	ReviewedBy  sql.NullInt64           `db:"reviewed_by"`
+	ReviewedAt  sql.NullTime            `db:"reviewed_at"`
+	CreatedAt   time.Time               `db:"created_at"`
+}
+
+func (r *quoteReviewFlagRow) toDomain() *domain.QuoteReviewFlag {
+	flag := &domain.QuoteReviewFlag{
+		ID:          r.ID,
+		QuoteID:     r.QuoteID,
+		SailingID:   r.SailingID,
+		CabinTypeID: r.CabinTypeID,
+		SupplierID:  r.SupplierID,
+		Status:      r.Status,
+		ReviewNote:  r.ReviewNote,
+		CreatedAt:   r.CreatedAt,
+	}
+
+	if r.Reasons != nil {
+		_ = json.Unmarshal(r.Reasons, &flag.Reasons)
+	}
+
+	if r.ReviewedBy.Valid {
+		reviewedBy := uint64(r.ReviewedBy.Int64)
+		flag.ReviewedBy = &reviewedBy
+	}
+
+	if r.ReviewedAt.Valid {
+		reviewedAt := r.ReviewedAt.Time
+		flag.ReviewedAt = &reviewedAt
+	}
+
+	return flag
+}
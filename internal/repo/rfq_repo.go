@@ -0,0 +1,290 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// rfqColumns is the column list selected into domain.RFQ
+const rfqColumns = `id, title, COALESCE(notes, '') AS notes, cabin_quantity, deadline, status, closed_at,
+              created_by, created_at, updated_at`
+
+// rfqSupplierColumns is the column list selected into domain.RFQSupplier
+const rfqSupplierColumns = `rfq_id, supplier_id, declined_at, COALESCE(decline_reason, '') AS decline_reason,
+              reminded_at, reminder_count`
+
+// RFQRepository handles request for quote data access
+type RFQRepository struct {
+	db *DB
+}
+
+// NewRFQRepository creates a new RFQ repository
+func NewRFQRepository(db *DB) *RFQRepository {
+	return &RFQRepository{db: db}
+}
+
+// GetByID retrieves an RFQ by ID with its items and suppliers
+func (r *RFQRepository) GetByID(ctx context.Context, id uint64) (*domain.RFQ, error) {
+	var rfq domain.RFQ
+	query := `SELECT ` + rfqColumns + ` FROM rfq WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &rfq, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get rfq by id: %w", err)
+	}
+
+	rfqs := []domain.RFQ{rfq}
+	if err := r.loadRelations(ctx, rfqs); err != nil {
+		return nil, err
+	}
+
+	return &rfqs[0], nil
+}
+
+// List retrieves RFQs with pagination, newest first, with their items and suppliers.
+// With supplierID, only the RFQs issued to that supplier are listed; with deadlineAfter,
+// only those due after that time.
+func (r *RFQRepository) List(ctx context.Context, pagination Pagination, status *domain.RFQStatus, supplierID *uint64, deadlineAfter *time.Time) (PaginatedResult[domain.RFQ], error) {
+	var rfqs []domain.RFQ
+	var total int64
+
+	where := " WHERE 1=1"
+	args := []interface{}{}
+
+	if status != nil {
+		where += " AND status = ?"
+		args = append(args, *status)
+	}
+
+	if supplierID != nil {
+		where += " AND id IN (SELECT rfq_id FROM rfq_supplier WHERE supplier_id = ?)"
+		args = append(args, *supplierID)
+	}
+
+	if deadlineAfter != nil {
+		where += " AND deadline > ?"
+		args = append(args, *deadlineAfter)
+	}
+
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM rfq"+where, args...); err != nil {
+		return PaginatedResult[domain.RFQ]{}, fmt.Errorf("failed to count rfqs: %w", err)
+	}
+
+	query := `SELECT ` + rfqColumns + ` FROM rfq` + where + ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &rfqs, query, args...); err != nil {
+		return PaginatedResult[domain.RFQ]{}, fmt.Errorf("failed to list rfqs: %w", err)
+	}
+
+	if err := r.loadRelations(ctx, rfqs); err != nil {
+		return PaginatedResult[domain.RFQ]{}, err
+	}
+
+	return NewPaginatedResult(rfqs, total, pagination), nil
+}
+
+// ListOpenDeadlineBetween retrieves the open RFQs due in (from, to] with their items and
+// suppliers, earliest deadline first
+func (r *RFQRepository) ListOpenDeadlineBetween(ctx context.Context, from, to time.Time) ([]domain.RFQ, error) {
+	var rfqs []domain.RFQ
+	query := `SELECT ` + rfqColumns + `
+              FROM rfq WHERE status = 'OPEN' AND deadline > ? AND deadline <= ?
+              ORDER BY deadline ASC, id ASC`
+
+	if err := r.db.SelectContext(ctx, &rfqs, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to list open rfqs by deadline: %w", err)
+	}
+
+	if err := r.loadRelations(ctx, rfqs); err != nil {
+		return nil, err
+	}
+
+	return rfqs, nil
+}
+
+// loadRelations loads the items and suppliers of RFQs
+func (r *RFQRepository) loadRelations(ctx context.Context, rfqs []domain.RFQ) error {
+	if len(rfqs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(rfqs))
+	index := make(map[uint64]int, len(rfqs))
+	for i := range rfqs {
+		ids[i] = rfqs[i].ID
+		index[rfqs[i].ID] = i
+	}
+
+	query, args, err := sqlx.In(`SELECT id, rfq_id, sailing_id, cabin_type_id
+              FROM rfq_item WHERE rfq_id IN (?) ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build rfq item query: %w", err)
+	}
+
+	var items []domain.RFQItem
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load rfq items: %w", err)
+	}
+	for _, item := range items {
+		rfq := &rfqs[index[item.RFQID]]
+		rfq.Items = append(rfq.Items, item)
+	}
+
+	query, args, err = sqlx.In(`SELECT `+rfqSupplierColumns+`
+              FROM rfq_supplier WHERE rfq_id IN (?) ORDER BY supplier_id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build rfq supplier query: %w", err)
+	}
+
+	var suppliers []domain.RFQSupplier
+	if err := r.db.SelectContext(ctx, &suppliers, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load rfq suppliers: %w", err)
+	}
+	for _, supplier := range suppliers {
+		rfq := &rfqs[index[supplier.RFQID]]
+		rfq.Suppliers = append(rfq.Suppliers, supplier)
+	}
+
+	return nil
+}
+
+// Create creates an RFQ together with its items and suppliers
+func (r *RFQRepository) Create(ctx context.Context, rfq *domain.RFQ, events ...*domain.DomainEvent) error {
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `INSERT INTO rfq (title, notes, cabin_quantity, deadline, status, created_by)
+              VALUES (?, ?, ?, ?, ?, ?)`,
+			rfq.Title, sql.NullString{String: rfq.Notes, Valid: rfq.Notes != ""},
+			rfq.CabinQuantity, rfq.Deadline, rfq.Status, rfq.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create rfq: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		rfq.ID = uint64(id)
+
+		for i := range rfq.Items {
+			item := &rfq.Items[i]
+			item.RFQID = rfq.ID
+			result, err := tx.ExecContext(ctx, `INSERT INTO rfq_item (rfq_id, sailing_id, cabin_type_id) VALUES (?, ?, ?)`,
+				item.RFQID, item.SailingID, item.CabinTypeID)
+			if err != nil {
+				return fmt.Errorf("failed to create rfq item: %w", err)
+			}
+			itemID, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+			item.ID = uint64(itemID)
+		}
+
+		for i := range rfq.Suppliers {
+			rfq.Suppliers[i].RFQID = rfq.ID
+			if _, err := tx.ExecContext(ctx, `INSERT INTO rfq_supplier (rfq_id, supplier_id) VALUES (?, ?)`,
+				rfq.ID, rfq.Suppliers[i].SupplierID); err != nil {
+				return fmt.Errorf("failed to create rfq supplier: %w", err)
+			}
+		}
+
+		return appendDomainEvents(ctx, tx, rfq.ID, events)
+	})
+}
+
+// UpdateStatus moves an open RFQ to a final status. It reports false when the RFQ is no longer open.
+func (r *RFQRepository) UpdateStatus(ctx context.Context, id uint64, status domain.RFQStatus, events ...*domain.DomainEvent) (bool, error) {
+	updated := false
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE rfq SET status = ?, closed_at = CURRENT_TIMESTAMP
+              WHERE id = ? AND status = 'OPEN'`, status, id)
+		if err != nil {
+			return fmt.Errorf("failed to update rfq status: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+		updated = true
+
+		return appendDomainEvents(ctx, tx, id, events)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// Decline records that a supplier declined to quote an RFQ. It reports false when the
+// supplier has already declined.
+func (r *RFQRepository) Decline(ctx context.Context, rfqID, supplierID uint64, reason string, events ...*domain.DomainEvent) (bool, error) {
+	declined := false
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE rfq_supplier SET declined_at = CURRENT_TIMESTAMP, decline_reason = ?
+              WHERE rfq_id = ? AND supplier_id = ? AND declined_at IS NULL`,
+			sql.NullString{String: reason, Valid: reason != ""}, rfqID, supplierID)
+		if err != nil {
+			return fmt.Errorf("failed to decline rfq: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+		declined = true
+
+		return appendDomainEvents(ctx, tx, rfqID, events)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return declined, nil
+}
+
+// MarkReminded records the deadline reminder sent to a supplier, raising its reminder count to
+// count. It reports false when a reminder of that count has already been recorded.
+func (r *RFQRepository) MarkReminded(ctx context.Context, rfqID, supplierID uint64, count int, at time.Time, events ...*domain.DomainEvent) (bool, error) {
+	marked := false
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE rfq_supplier SET reminded_at = ?, reminder_count = ?
+              WHERE rfq_id = ? AND supplier_id = ? AND reminder_count < ?`, at, count, rfqID, supplierID, count)
+		if err != nil {
+			return fmt.Errorf("failed to mark rfq reminder: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+		marked = true
+
+		return appendDomainEvents(ctx, tx, rfqID, events)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return marked, nil
+}
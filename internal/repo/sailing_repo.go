@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 )
 
 // SailingRepository handles sailing data access
@@ -37,6 +40,31 @@ func (r *SailingRepository) GetByID(ctx context.Context, id uint64) (*domain.Sai
 	return row.toDomain(), nil
 }
 
+// ListByIDs retrieves the sailings with the given IDs
+func (r *SailingRepository) ListByIDs(ctx context.Context, ids []uint64) ([]domain.Sailing, error) {
+	if len(ids) == 0 {
+		return []domain.Sailing{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT id, ship_id, sailing_code, departure_date, return_date, nights, route, ports, description, status, created_at, updated_at, created_by 
+              FROM sailing WHERE id IN (?) ORDER BY departure_date, id`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sailing filter: %w", err)
+	}
+
+	var rows []sailingRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list sailings by ids: %w", err)
+	}
+
+	items := make([]domain.Sailing, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
 // GetByCode retrieves a sailing by sailing code
 func (r *SailingRepository) GetByCode(ctx context.Context, code string) (*domain.Sailing, error) {
 	var row sailingRow
@@ -145,7 +173,7 @@ func (r *SailingRepository) ListUpcoming(ctx context.Context, limit int) ([]doma
 }
 
 // Create creates a new sailing
-func (r *SailingRepository) Create(ctx context.Context, sailing *domain.Sailing) error {
+func (r *SailingRepository) Create(ctx context.Context, sailing *domain.Sailing, events ...*domain.DomainEvent) error {
 	portsJSON, err := json.Marshal(sailing.Ports)
 	if err != nil {
 		return fmt.Errorf("failed to marshal ports: %w", err)
@@ -154,23 +182,25 @@ func (r *SailingRepository) Create(ctx context.Context, sailing *domain.Sailing)
 	query := `INSERT INTO sailing (ship_id, sailing_code, departure_date, return_date, route, ports, description, status, created_by) 
               VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, sailing.ShipID, sailing.SailingCode, sailing.DepartureDate, sailing.ReturnDate,
-		sailing.Route, portsJSON, sailing.Description, sailing.Status, sailing.CreatedBy)
-	if err != nil {
-		return fmt.Errorf("failed to create sailing: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, sailing.ShipID, sailing.SailingCode, sailing.DepartureDate, sailing.ReturnDate,
+			sailing.Route, portsJSON, sailing.Description, sailing.Status, sailing.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create sailing: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	sailing.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		sailing.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, sailing.ID, events)
+	})
 }
 
 // Update updates a sailing
-func (r *SailingRepository) Update(ctx context.Context, sailing *domain.Sailing) error {
+func (r *SailingRepository) Update(ctx context.Context, sailing *domain.Sailing, events ...*domain.DomainEvent) error {
 	portsJSON, err := json.Marshal(sailing.Ports)
 	if err != nil {
 		return fmt.Errorf("failed to marshal ports: %w", err)
@@ -178,25 +208,29 @@ func (r *SailingRepository) Update(ctx context.Context, sailing *domain.Sailing)
 
 	query := `UPDATE sailing SET ship_id = ?, sailing_code = ?, departure_date = ?, return_date = ?, route = ?, ports = ?, description = ?, status = ? WHERE id = ?`
 
-	_, err = r.db.ExecContext(ctx, query, sailing.ShipID, sailing.SailingCode, sailing.DepartureDate, sailing.ReturnDate,
-		sailing.Route, portsJSON, sailing.Description, sailing.Status, sailing.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update sailing: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err = tx.ExecContext(ctx, query, sailing.ShipID, sailing.SailingCode, sailing.DepartureDate, sailing.ReturnDate,
+			sailing.Route, portsJSON, sailing.Description, sailing.Status, sailing.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update sailing: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, sailing.ID, events)
+	})
 }
 
 // Delete deletes a sailing
-func (r *SailingRepository) Delete(ctx context.Context, id uint64) error {
+func (r *SailingRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM sailing WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete sailing: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete sailing: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByCode checks if a sailing code exists
@@ -221,6 +255,366 @@ func (r *SailingRepository) ExistsByCode(ctx context.Context, code string, exclu
 	return count > 0, nil
 }
 
+// SailingSort is a sort order of the sailing search
+type SailingSort string
+
+const (
+	SailingSortDeparture   SailingSort = "departure_date"
+	SailingSortNights      SailingSort = "nights"
+	SailingSortLowestPrice SailingSort = "lowest_price"
+)
+
+// SailingSearchFilter holds the criteria of a sailing search; nil and empty fields do not filter
+type SailingSearchFilter struct {
+	FromDate     *time.Time
+	ToDate       *time.Time
+	MinNights    *int
+	MaxNights    *int
+	CruiseLineID *uint64
+	ShipID       *uint64
+	EmbarkPortID *uint64 // port of day 1
+	PortID       *uint64 // any port of the itinerary
+	RouteKeyword string
+	Status       *domain.SailingStatus
+
+	// Only sailings with a current quote of this supplier
+	QuotedBySupplierID *uint64
+
+	// Sort order of Search; SailingSortLowestPrice is left to the caller, which has to
+	// convert prices between currencies, and sorts by departure date here
+	Sort     SailingSort
+	SortDesc bool
+}
+
+// perPersonPriceExpr is the per-person price at base occupancy of price_quote pq, as computed by
+// domain.PriceQuote.PricePerPerson with domain.DefaultBaseOccupancy as the fallback guest count
+const perPersonPriceExpr = `ROUND(CASE WHEN pq.pricing_unit = 'PER_PERSON' THEN pq.price
+              ELSE pq.price / (CASE WHEN pq.guest_count > 0 THEN pq.guest_count ELSE 2 END) END, 2)`
+
+// latestPricesFrom returns the FROM and WHERE clauses selecting as pq the latest current quote of
+// each sailing + cabin type + supplier, joined to its cabin type as ct. A non-empty sailingIDs
+// restricts the sailings and must be expanded with sqlx.In.
+func latestPricesFrom(supplierID *uint64, sailingIDs []uint64) (string, []interface{}) {
+	var args []interface{}
+	current := currentQuoteFilter(false, false)
+	if supplierID != nil {
+		current += " AND supplier_id = ?"
+		args = append(args, *supplierID)
+	}
+	if len(sailingIDs) > 0 {
+		current += " AND sailing_id IN (?)"
+		args = append(args, sailingIDs)
+	}
+
+	from := ` FROM (
+                  SELECT price_quote.*, ROW_NUMBER() OVER (
+                      PARTITION BY sailing_id, cabin_type_id, supplier_id ORDER BY ` + latestQuoteOrder + `) AS latest_rank
+                  FROM price_quote WHERE ` + current + `
+              ) pq
+              INNER JOIN cabin_type ct ON ct.id = pq.cabin_type_id
+              WHERE pq.latest_rank = 1`
+
+	return from, args
+}
+
+// Search retrieves sailings matching the filter with pagination. Embarkation and port filters use
+// the structured itinerary, falling back to the ports column for sailings without one.
+func (r *SailingRepository) Search(ctx context.Context, pagination Pagination, filter SailingSearchFilter) (PaginatedResult[domain.Sailing], error) {
+	var rows []sailingRow
+	var total int64
+
+	where, args := searchWhere(filter)
+
+	// Count total
+	countQuery := "SELECT COUNT(*) FROM sailing s INNER JOIN ship sh ON sh.id = s.ship_id" + where
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.Sailing]{}, fmt.Errorf("failed to count sailings: %w", err)
+	}
+
+	direction := " ASC"
+	if filter.SortDesc {
+		direction = " DESC"
+	}
+
+	selectQuery := `SELECT ` + searchColumns + ` FROM sailing s INNER JOIN ship sh ON sh.id = s.ship_id`
+	var orderBy string
+
+	switch filter.Sort {
+	case SailingSortNights:
+		orderBy = " ORDER BY s.nights" + direction + ", s.departure_date, s.id"
+	default:
+		orderBy = " ORDER BY s.departure_date" + direction + ", s.id"
+	}
+
+	// Get paginated results
+	selectQuery += where + orderBy + " LIMIT ? OFFSET ?"
+	selectArgs := append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &rows, selectQuery, selectArgs...); err != nil {
+		return PaginatedResult[domain.Sailing]{}, fmt.Errorf("failed to search sailings: %w", err)
+	}
+
+	items := make([]domain.Sailing, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return NewPaginatedResult(items, total, pagination), nil
+}
+
+// ListMatching retrieves every sailing matching the filter in departure order; the sort
+// fields of the filter are ignored
+func (r *SailingRepository) ListMatching(ctx context.Context, filter SailingSearchFilter) ([]domain.Sailing, error) {
+	var rows []sailingRow
+	where, args := searchWhere(filter)
+	query := `SELECT ` + searchColumns + ` FROM sailing s INNER JOIN ship sh ON sh.id = s.ship_id` +
+		where + ` ORDER BY s.departure_date, s.id`
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list matching sailings: %w", err)
+	}
+
+	items := make([]domain.Sailing, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
+// searchColumns is the column list of sailing s selected into sailingRow
+const searchColumns = `s.id, s.ship_id, s.sailing_code, s.departure_date, s.return_date, s.nights, s.route, s.ports,
+              s.description, s.status, s.created_at, s.updated_at, s.created_by`
+
+// searchWhere builds the WHERE clause of the filter criteria over sailing s joined to ship sh
+func searchWhere(filter SailingSearchFilter) (string, []interface{}) {
+	where := " WHERE 1=1"
+	var args []interface{}
+
+	if filter.FromDate != nil {
+		where += " AND s.departure_date >= ?"
+		args = append(args, *filter.FromDate)
+	}
+
+	if filter.ToDate != nil {
+		where += " AND s.departure_date <= ?"
+		args = append(args, *filter.ToDate)
+	}
+
+	if filter.MinNights != nil {
+		where += " AND s.nights >= ?"
+		args = append(args, *filter.MinNights)
+	}
+
+	if filter.MaxNights != nil {
+		where += " AND s.nights <= ?"
+		args = append(args, *filter.MaxNights)
+	}
+
+	if filter.CruiseLineID != nil {
+		where += " AND sh.cruise_line_id = ?"
+		args = append(args, *filter.CruiseLineID)
+	}
+
+	if filter.ShipID != nil {
+		where += " AND s.ship_id = ?"
+		args = append(args, *filter.ShipID)
+	}
+
+	if filter.Status != nil {
+		where += " AND s.status = ?"
+		args = append(args, *filter.Status)
+	}
+
+	if filter.RouteKeyword != "" {
+		where += " AND INSTR(s.route, ?) > 0"
+		args = append(args, filter.RouteKeyword)
+	}
+
+	const noItinerary = `NOT EXISTS (SELECT 1 FROM sailing_itinerary si WHERE si.sailing_id = s.id)`
+
+	if filter.EmbarkPortID != nil {
+		where += ` AND (EXISTS (SELECT 1 FROM sailing_itinerary si WHERE si.sailing_id = s.id AND si.day_number = 1 AND si.port_id = ?)
+                 OR (` + noItinerary + ` AND JSON_UNQUOTE(JSON_EXTRACT(s.ports, '$[0]')) = (SELECT name FROM port WHERE id = ?)))`
+		args = append(args, *filter.EmbarkPortID, *filter.EmbarkPortID)
+	}
+
+	if filter.PortID != nil {
+		where += ` AND (EXISTS (SELECT 1 FROM sailing_itinerary si WHERE si.sailing_id = s.id AND si.port_id = ?)
+                 OR (` + noItinerary + ` AND JSON_CONTAINS(s.ports, JSON_QUOTE((SELECT name FROM port WHERE id = ?)))))`
+		args = append(args, *filter.PortID, *filter.PortID)
+	}
+
+	if filter.QuotedBySupplierID != nil {
+		// Unqualified columns of the filter resolve to price_quote, the innermost table
+		where += ` AND EXISTS (SELECT 1 FROM price_quote WHERE supplier_id = ? AND sailing_id = s.id
+                 AND ` + currentQuoteFilter(false, false) + `)`
+		args = append(args, *filter.QuotedBySupplierID)
+	}
+
+	return where, args
+}
+
+// CategoryPriceRow is the latest current price of a sailing + cabin type + supplier with the
+// cabin category of the cabin type
+type CategoryPriceRow struct {
+	SailingID       uint64          `db:"sailing_id"`
+	CabinCategoryID uint64          `db:"category_id"`
+	CabinTypeID     uint64          `db:"cabin_type_id"`
+	SupplierID      uint64          `db:"supplier_id"`
+	QuoteID         uint64          `db:"quote_id"`
+	Price           decimal.Decimal `db:"price"`
+	Currency        string          `db:"currency"`
+	PricingUnit     string          `db:"pricing_unit"`
+	PerPersonPrice  decimal.Decimal `db:"per_person_price"` // in Currency
+	QuotedAt        time.Time       `db:"quoted_at"`
+	CreatedAt       time.Time       `db:"created_at"`
+}
+
+// ListLatestCategoryPrices retrieves the latest current prices of the given sailings, in any
+// currency, optionally of one supplier
+func (r *SailingRepository) ListLatestCategoryPrices(ctx context.Context, sailingIDs []uint64, supplierID *uint64) ([]CategoryPriceRow, error) {
+	if len(sailingIDs) == 0 {
+		return nil, nil
+	}
+
+	from, args := latestPricesFrom(supplierID, sailingIDs)
+	query := `SELECT pq.sailing_id, ct.category_id, pq.cabin_type_id, pq.supplier_id, pq.id AS quote_id, pq.price,
+              pq.currency, pq.pricing_unit, ` + perPersonPriceExpr + ` AS per_person_price, pq.quoted_at, pq.created_at` +
+		from + ` AND pq.sailing_id IN (?)
+              ORDER BY pq.sailing_id, pq.id`
+	args = append(args, sailingIDs)
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build category price query: %w", err)
+	}
+
+	var rows []CategoryPriceRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list latest category prices: %w", err)
+	}
+
+	return rows, nil
+}
+
+// itineraryColumns is the column list selected into itineraryRow
+const itineraryColumns = `id, sailing_id, day_number, port_id, arrive_time, depart_time, is_sea_day, COALESCE(note, '') AS note`
+
+// ListItinerary retrieves the itinerary of a sailing in day order
+func (r *SailingRepository) ListItinerary(ctx context.Context, sailingID uint64) ([]domain.ItineraryStop, error) {
+	itineraries, err := r.ListItineraries(ctx, []uint64{sailingID})
+	if err != nil {
+		return nil, err
+	}
+	return itineraries[sailingID], nil
+}
+
+// ListItineraries retrieves the itineraries of several sailings in day order, keyed by sailing ID.
+// Sailings without an itinerary are missing from the map.
+func (r *SailingRepository) ListItineraries(ctx context.Context, sailingIDs []uint64) (map[uint64][]domain.ItineraryStop, error) {
+	itineraries := make(map[uint64][]domain.ItineraryStop)
+	if len(sailingIDs) == 0 {
+		return itineraries, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT `+itineraryColumns+` FROM sailing_itinerary
+              WHERE sailing_id IN (?) ORDER BY sailing_id, day_number, id`, sailingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build itinerary query: %w", err)
+	}
+
+	var rows []itineraryRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list itineraries: %w", err)
+	}
+
+	for i := range rows {
+		stop := rows[i].toDomain()
+		itineraries[stop.SailingID] = append(itineraries[stop.SailingID], stop)
+	}
+
+	return itineraries, nil
+}
+
+// ReplaceItinerary replaces the itinerary of a sailing and, in the same transaction, sets
+// its ports column to ports so readers of the plain port list stay in step
+func (r *SailingRepository) ReplaceItinerary(ctx context.Context, sailingID uint64, stops []domain.ItineraryStop, ports []string, events ...*domain.DomainEvent) error {
+	portsJSON, err := json.Marshal(ports)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ports: %w", err)
+	}
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM sailing_itinerary WHERE sailing_id = ?`, sailingID); err != nil {
+			return fmt.Errorf("failed to delete itinerary: %w", err)
+		}
+
+		query := `INSERT INTO sailing_itinerary (sailing_id, day_number, port_id, arrive_time, depart_time, is_sea_day, note)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+		for i := range stops {
+			stop := &stops[i]
+			stop.SailingID = sailingID
+			result, err := tx.ExecContext(ctx, query, sailingID, stop.DayNumber, stop.PortID, stop.ArriveTime,
+				stop.DepartTime, stop.IsSeaDay, sql.NullString{String: stop.Note, Valid: stop.Note != ""})
+			if err != nil {
+				return fmt.Errorf("failed to create itinerary stop: %w", err)
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+			stop.ID = uint64(id)
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE sailing SET ports = ? WHERE id = ?`, portsJSON, sailingID); err != nil {
+			return fmt.Errorf("failed to update sailing ports: %w", err)
+		}
+
+		return appendDomainEvents(ctx, tx, sailingID, events)
+	})
+}
+
+// itineraryRow is the database row structure for sailing_itinerary
+type itineraryRow struct {
+	ID         uint64         `db:"id"`
+	SailingID  uint64         `db:"sailing_id"`
+	DayNumber  int            `db:"day_number"`
+	PortID     sql.NullInt64  `db:"port_id"`
+	ArriveTime sql.NullString `db:"arrive_time"`
+	DepartTime sql.NullString `db:"depart_time"`
+	IsSeaDay   bool           `db:"is_sea_day"`
+	Note       string         `db:"note"`
+}
+
+func (r *itineraryRow) toDomain() domain.ItineraryStop {
+	stop := domain.ItineraryStop{
+		ID:        r.ID,
+		SailingID: r.SailingID,
+		DayNumber: r.DayNumber,
+		IsSeaDay:  r.IsSeaDay,
+		Note:      r.Note,
+	}
+
+	if r.PortID.Valid {
+		portID := uint64(r.PortID.Int64)
+		stop.PortID = &portID
+	}
+
+	// TIME columns come back as HH:MM:SS; itineraries are kept to the minute
+	if r.ArriveTime.Valid && len(r.ArriveTime.String) >= 5 {
+		arrive := r.ArriveTime.String[:5]
+		stop.ArriveTime = &arrive
+	}
+	if r.DepartTime.Valid && len(r.DepartTime.String) >= 5 {
+		depart := r.DepartTime.String[:5]
+		stop.DepartTime = &depart
+	}
+
+	return stop
+}
+
 // sailingRow is the database row structure for sailing
 type sailingRow struct {
 	ID            uint64         `db:"id"`
@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // ShipRepository handles ship data access
@@ -23,7 +25,7 @@ func NewShipRepository(db *DB) *ShipRepository {
 // GetByID retrieves a ship by ID
 func (r *ShipRepository) GetByID(ctx context.Context, id uint64) (*domain.Ship, error) {
 	var row shipRow
-	query := `SELECT id, cruise_line_id, name, aliases, status, created_at, updated_at, created_by 
+	query := `SELECT id, cruise_line_id, name, name_en, aliases, status, created_at, updated_at, created_by 
               FROM ship WHERE id = ?`
 
 	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
@@ -43,7 +45,7 @@ func (r *ShipRepository) List(ctx context.Context, pagination Pagination, cruise
 
 	// Build query
 	countQuery := "SELECT COUNT(*) FROM ship WHERE 1=1"
-	selectQuery := `SELECT id, cruise_line_id, name, aliases, status, created_at, updated_at, created_by FROM ship WHERE 1=1`
+	selectQuery := `SELECT id, cruise_line_id, name, name_en, aliases, status, created_at, updated_at, created_by FROM ship WHERE 1=1`
 	var args []interface{}
 
 	if cruiseLineID != nil {
@@ -82,7 +84,7 @@ func (r *ShipRepository) List(ctx context.Context, pagination Pagination, cruise
 // ListByCruiseLine retrieves all ships for a cruise line
 func (r *ShipRepository) ListByCruiseLine(ctx context.Context, cruiseLineID uint64) ([]domain.Ship, error) {
 	var rows []shipRow
-	query := `SELECT id, cruise_line_id, name, aliases, status, created_at, updated_at, created_by 
+	query := `SELECT id, cruise_line_id, name, name_en, aliases, status, created_at, updated_at, created_by 
               FROM ship WHERE cruise_line_id = ? AND status = 'ACTIVE' ORDER BY name`
 
 	if err := r.db.SelectContext(ctx, &rows, query, cruiseLineID); err != nil {
@@ -97,57 +99,81 @@ func (r *ShipRepository) ListByCruiseLine(ctx context.Context, cruiseLineID uint
 	return items, nil
 }
 
+// ListAll retrieves all active ships
+func (r *ShipRepository) ListAll(ctx context.Context) ([]domain.Ship, error) {
+	var rows []shipRow
+	query := `SELECT id, cruise_line_id, name, name_en, aliases, status, created_at, updated_at, created_by 
+              FROM ship WHERE status = 'ACTIVE' ORDER BY name`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list all ships: %w", err)
+	}
+
+	items := make([]domain.Ship, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
 // Create creates a new ship
-func (r *ShipRepository) Create(ctx context.Context, ship *domain.Ship) error {
+func (r *ShipRepository) Create(ctx context.Context, ship *domain.Ship, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(ship.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
 	}
 
-	query := `INSERT INTO ship (cruise_line_id, name, aliases, status, created_by) 
-              VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO ship (cruise_line_id, name, name_en, aliases, status, created_by) 
+              VALUES (?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, ship.CruiseLineID, ship.Name, aliasesJSON, ship.Status, ship.CreatedBy)
-	if err != nil {
-		return fmt.Errorf("failed to create ship: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, ship.CruiseLineID, ship.Name, ship.NameEN, aliasesJSON, ship.Status, ship.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create ship: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	ship.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		ship.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, ship.ID, events)
+	})
 }
 
 // Update updates a ship
-func (r *ShipRepository) Update(ctx context.Context, ship *domain.Ship) error {
+func (r *ShipRepository) Update(ctx context.Context, ship *domain.Ship, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(ship.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
 	}
 
-	query := `UPDATE ship SET cruise_line_id = ?, name = ?, aliases = ?, status = ? WHERE id = ?`
+	query := `UPDATE ship SET cruise_line_id = ?, name = ?, name_en = ?, aliases = ?, status = ? WHERE id = ?`
 
-	_, err = r.db.ExecContext(ctx, query, ship.CruiseLineID, ship.Name, aliasesJSON, ship.Status, ship.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update ship: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err = tx.ExecContext(ctx, query, ship.CruiseLineID, ship.Name, ship.NameEN, aliasesJSON, ship.Status, ship.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update ship: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, ship.ID, events)
+	})
 }
 
 // Delete deletes a ship
-func (r *ShipRepository) Delete(ctx context.Context, id uint64) error {
+func (r *ShipRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM ship WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete ship: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete ship: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByName checks if a ship name exists for a cruise line
@@ -170,14 +196,15 @@ func (r *ShipRepository) ExistsByName(ctx context.Context, cruiseLineID uint64,
 
 // shipRow is the database row structure for ship
 type shipRow struct {
-	ID           uint64        `db:"id"`
-	CruiseLineID uint64        `db:"cruise_line_id"`
-	Name         string        `db:"name"`
-	Aliases      []byte        `db:"aliases"`
-	Status       string        `db:"status"`
-	CreatedAt    sql.NullTime  `db:"created_at"`
-	UpdatedAt    sql.NullTime  `db:"updated_at"`
-	CreatedBy    sql.NullInt64 `db:"created_by"`
+	ID           uint64         `db:"id"`
+	CruiseLineID uint64         `db:"cruise_line_id"`
+	Name         string         `db:"name"`
+	NameEN       sql.NullString `db:"name_en"`
+	Aliases      []byte         `db:"aliases"`
+	Status       string         `db:"status"`
+	CreatedAt    sql.NullTime   `db:"created_at"`
+	UpdatedAt    sql.NullTime   `db:"updated_at"`
+	CreatedBy    sql.NullInt64  `db:"created_by"`
 }
 
 func (r *shipRow) toDomain() *domain.Ship {
@@ -185,6 +212,7 @@ func (r *shipRow) toDomain() *domain.Ship {
 		ID:           r.ID,
 		CruiseLineID: r.CruiseLineID,
 		Name:         r.Name,
+		NameEN:       r.NameEN.String,
 		Status:       domain.EntityStatus(r.Status),
 	}
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // SupplierRepository handles supplier data access
@@ -23,7 +25,7 @@ func NewSupplierRepository(db *DB) *SupplierRepository {
 // GetByID retrieves a supplier by ID
 func (r *SupplierRepository) GetByID(ctx context.Context, id uint64) (*domain.Supplier, error) {
 	var row supplierRow
-	query := `SELECT id, name, aliases, contact_info, visibility, status, created_at, updated_at, created_by 
+	query := `SELECT id, name, aliases, contact_info, visibility, requires_approval, status, created_at, updated_at, created_by 
               FROM supplier WHERE id = ?`
 
 	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
@@ -42,7 +44,7 @@ func (r *SupplierRepository) List(ctx context.Context, pagination Pagination, st
 	var total int64
 
 	countQuery := "SELECT COUNT(*) FROM supplier WHERE 1=1"
-	selectQuery := `SELECT id, name, aliases, contact_info, visibility, status, created_at, updated_at, created_by FROM supplier WHERE 1=1`
+	selectQuery := `SELECT id, name, aliases, contact_info, visibility, requires_approval, status, created_at, updated_at, created_by FROM supplier WHERE 1=1`
 	var args []interface{}
 
 	if status != nil {
@@ -73,7 +75,7 @@ func (r *SupplierRepository) List(ctx context.Context, pagination Pagination, st
 // ListAll retrieves all active suppliers
 func (r *SupplierRepository) ListAll(ctx context.Context) ([]domain.Supplier, error) {
 	var rows []supplierRow
-	query := `SELECT id, name, aliases, contact_info, visibility, status, created_at, updated_at, created_by 
+	query := `SELECT id, name, aliases, contact_info, visibility, requires_approval, status, created_at, updated_at, created_by 
               FROM supplier WHERE status = 'ACTIVE' ORDER BY name`
 
 	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
@@ -88,8 +90,33 @@ func (r *SupplierRepository) ListAll(ctx context.Context) ([]domain.Supplier, er
 	return items, nil
 }
 
+// ListByIDs retrieves suppliers by ID regardless of status
+func (r *SupplierRepository) ListByIDs(ctx context.Context, ids []uint64) ([]domain.Supplier, error) {
+	if len(ids) == 0 {
+		return []domain.Supplier{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT id, name, aliases, contact_info, visibility, requires_approval, status, created_at, updated_at, created_by 
+              FROM supplier WHERE id IN (?) ORDER BY name`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build supplier filter: %w", err)
+	}
+
+	var rows []supplierRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list suppliers by ids: %w", err)
+	}
+
+	items := make([]domain.Supplier, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
 // Create creates a new supplier
-func (r *SupplierRepository) Create(ctx context.Context, supplier *domain.Supplier) error {
+func (r *SupplierRepository) Create(ctx context.Context, supplier *domain.Supplier, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(supplier.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
@@ -98,22 +125,24 @@ func (r *SupplierRepository) Create(ctx context.Context, supplier *domain.Suppli
 	query := `INSERT INTO supplier (name, aliases, contact_info, visibility, status, created_by) 
               VALUES (?, ?, ?, ?, ?, ?)`
 
-	result, err := r.db.ExecContext(ctx, query, supplier.Name, aliasesJSON, supplier.ContactInfo, supplier.Visibility, supplier.Status, supplier.CreatedBy)
-	if err != nil {
-		return fmt.Errorf("failed to create supplier: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, query, supplier.Name, aliasesJSON, supplier.ContactInfo, supplier.Visibility, supplier.Status, supplier.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to create supplier: %w", err)
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-	supplier.ID = uint64(id)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		supplier.ID = uint64(id)
 
-	return nil
+		return appendDomainEvents(ctx, tx, supplier.ID, events)
+	})
 }
 
 // Update updates a supplier
-func (r *SupplierRepository) Update(ctx context.Context, supplier *domain.Supplier) error {
+func (r *SupplierRepository) Update(ctx context.Context, supplier *domain.Supplier, events ...*domain.DomainEvent) error {
 	aliasesJSON, err := json.Marshal(supplier.Aliases)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
@@ -121,24 +150,41 @@ func (r *SupplierRepository) Update(ctx context.Context, supplier *domain.Suppli
 
 	query := `UPDATE supplier SET name = ?, aliases = ?, contact_info = ?, visibility = ?, status = ? WHERE id = ?`
 
-	_, err = r.db.ExecContext(ctx, query, supplier.Name, aliasesJSON, supplier.ContactInfo, supplier.Visibility, supplier.Status, supplier.ID)
-	if err != nil {
-		return fmt.Errorf("failed to update supplier: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err = tx.ExecContext(ctx, query, supplier.Name, aliasesJSON, supplier.ContactInfo, supplier.Visibility, supplier.Status, supplier.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update supplier: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, supplier.ID, events)
+	})
 }
 
 // Delete deletes a supplier
-func (r *SupplierRepository) Delete(ctx context.Context, id uint64) error {
+func (r *SupplierRepository) Delete(ctx context.Context, id uint64, events ...*domain.DomainEvent) error {
 	query := `DELETE FROM supplier WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete supplier: %w", err)
-	}
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete supplier: %w", err)
+		}
+
+		return appendDomainEvents(ctx, tx, id, events)
+	})
+}
+
+// SetRequiresApproval sets whether new quotes of a supplier wait for admin approval
+func (r *SupplierRepository) SetRequiresApproval(ctx context.Context, id uint64, requiresApproval bool, events ...*domain.DomainEvent) error {
+	query := `UPDATE supplier SET requires_approval = ? WHERE id = ?`
+
+	return r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, requiresApproval, id); err != nil {
+			return fmt.Errorf("failed to update supplier approval policy: %w", err)
+		}
 
-	return nil
+		return appendDomainEvents(ctx, tx, id, events)
+	})
 }
 
 // ExistsByName checks if a supplier name exists
@@ -160,23 +206,25 @@ func (r *SupplierRepository) ExistsByName(ctx context.Context, name string, excl
 }
 
 type supplierRow struct {
-	ID          uint64         `db:"id"`
-	Name        string         `db:"name"`
-	Aliases     []byte         `db:"aliases"`
-	ContactInfo sql.NullString `db:"contact_info"`
-	Visibility  string         `db:"visibility"`
-	Status      string         `db:"status"`
-	CreatedAt   sql.NullTime   `db:"created_at"`
-	UpdatedAt   sql.NullTime   `db:"updated_at"`
-	CreatedBy   sql.NullInt64  `db:"created_by"`
+	ID               uint64         `db:"id"`
+	Name             string         `db:"name"`
+	Aliases          []byte         `db:"aliases"`
+	ContactInfo      sql.NullString `db:"contact_info"`
+	Visibility       string         `db:"visibility"`
+	RequiresApproval bool           `db:"requires_approval"`
+	Status           string         `db:"status"`
+	CreatedAt        sql.NullTime   `db:"created_at"`
+	UpdatedAt        sql.NullTime   `db:"updated_at"`
+	CreatedBy        sql.NullInt64  `db:"created_by"`
 }
 
 func (r *supplierRow) toDomain() *domain.Supplier {
 	s := &domain.Supplier{
-		ID:         r.ID,
-		Name:       r.Name,
-		Visibility: domain.SupplierVisibility(r.Visibility),
-		Status:     domain.EntityStatus(r.Status),
+		ID:               r.ID,
+		Name:             r.Name,
+		Visibility:       domain.SupplierVisibility(r.Visibility),
+		RequiresApproval: r.RequiresApproval,
+		Status:           domain.EntityStatus(r.Status),
 	}
 
 	if r.Aliases != nil {
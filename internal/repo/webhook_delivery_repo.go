@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempt_count,
+              next_attempt_at, last_attempt_at, response_status, COALESCE(response_body, '') AS response_body,
+              COALESCE(last_error, '') AS last_error, delivered_at, redelivery_of, created_at`
+
+// WebhookDeliveryRepository handles webhook delivery data access
+type WebhookDeliveryRepository struct {
+	db *DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// GetByID retrieves a webhook delivery by ID
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uint64) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_delivery WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &delivery, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery by id: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// List retrieves webhook deliveries with pagination and filters, newest first
+func (r *WebhookDeliveryRepository) List(ctx context.Context, pagination Pagination, endpointID *uint64, status *domain.WebhookDeliveryStatus, eventType *domain.WebhookEventType) (PaginatedResult[domain.WebhookDelivery], error) {
+	var deliveries []domain.WebhookDelivery
+	var total int64
+
+	countQuery := "SELECT COUNT(*) FROM webhook_delivery WHERE 1=1"
+	selectQuery := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_delivery WHERE 1=1`
+	var args []interface{}
+
+	if endpointID != nil {
+		countQuery += " AND endpoint_id = ?"
+		selectQuery += " AND endpoint_id = ?"
+		args = append(args, *endpointID)
+	}
+
+	if status != nil {
+		countQuery += " AND status = ?"
+		selectQuery += " AND status = ?"
+		args = append(args, *status)
+	}
+
+	if eventType != nil {
+		countQuery += " AND event_type = ?"
+		selectQuery += " AND event_type = ?"
+		args = append(args, *eventType)
+	}
+
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return PaginatedResult[domain.WebhookDelivery]{}, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	selectQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
+	args = append(args, pagination.Limit(), pagination.Offset())
+
+	if err := r.db.SelectContext(ctx, &deliveries, selectQuery, args...); err != nil {
+		return PaginatedResult[domain.WebhookDelivery]{}, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return NewPaginatedResult(deliveries, total, pagination), nil
+}
+
+// Create creates a pending webhook delivery
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `INSERT INTO webhook_delivery (endpoint_id, event_id, event_type, payload, status, next_attempt_at, redelivery_of) 
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, delivery.EndpointID, delivery.EventID, delivery.EventType,
+		[]byte(delivery.Payload), delivery.Status, delivery.NextAttemptAt, delivery.RedeliveryOf)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	delivery.ID = uint64(id)
+
+	return nil
+}
+
+// ExistsForEvent checks whether an endpoint already has a delivery of the event
+func (r *WebhookDeliveryRepository) ExistsForEvent(ctx context.Context, endpointID uint64, eventID string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM webhook_delivery WHERE endpoint_id = ? AND event_id = ?`
+
+	if err := r.db.GetContext(ctx, &count, query, endpointID, eventID); err != nil {
+		return false, fmt.Errorf("failed to check webhook delivery exists: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ClaimDue locks up to limit pending deliveries due at now and pushes their next attempt out by
+// lease, so that concurrent workers skip them while they are being sent. A delivery whose sender
+// dies becomes due again once the lease has passed.
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+
+	err := r.db.Transaction(ctx, func(tx *sqlx.Tx) error {
+		query := `SELECT ` + webhookDeliveryColumns + `
+              FROM webhook_delivery WHERE status = 'PENDING' AND next_attempt_at <= ?
+              ORDER BY next_attempt_at, id LIMIT ? FOR UPDATE`
+		if err := tx.SelectContext(ctx, &deliveries, query, now, limit); err != nil {
+			return fmt.Errorf("failed to list due webhook deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+
+		update, args, err := sqlx.In(`UPDATE webhook_delivery SET next_attempt_at = ? WHERE id IN (?)`, now.Add(lease), ids)
+		if err != nil {
+			return fmt.Errorf("failed to build delivery filter: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(update), args...); err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *WebhookDeliveryRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `UPDATE webhook_delivery SET status = ?, attempt_count = ?, next_attempt_at = ?, last_attempt_at = ?,
+              response_status = ?, response_body = ?, last_error = ?, delivered_at = ? 
+              WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, delivery.Status, delivery.AttemptCount, delivery.NextAttemptAt,
+		delivery.LastAttemptAt, delivery.ResponseStatus,
+		sql.NullString{String: delivery.ResponseBody, Valid: delivery.ResponseBody != ""},
+		sql.NullString{String: delivery.LastError, Valid: delivery.LastError != ""},
+		delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cruise-price-compare/internal/domain"
+)
+
+const webhookEndpointColumns = `id, name, url, secret, events, supplier_ids, is_enabled, created_at, updated_at, created_by`
+
+// WebhookEndpointRepository handles webhook endpoint data access
+type WebhookEndpointRepository struct {
+	db *DB
+}
+
+// NewWebhookEndpointRepository creates a new webhook endpoint repository
+func NewWebhookEndpointRepository(db *DB) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+// GetByID retrieves a webhook endpoint by ID
+func (r *WebhookEndpointRepository) GetByID(ctx context.Context, id uint64) (*domain.WebhookEndpoint, error) {
+	var row webhookEndpointRow
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoint WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint by id: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// List retrieves webhook endpoints with pagination
+func (r *WebhookEndpointRepository) List(ctx context.Context, pagination Pagination) (PaginatedResult[domain.WebhookEndpoint], error) {
+	var rows []webhookEndpointRow
+	var total int64
+
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM webhook_endpoint"); err != nil {
+		return PaginatedResult[domain.WebhookEndpoint]{}, fmt.Errorf("failed to count webhook endpoints: %w", err)
+	}
+
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoint ORDER BY id LIMIT ? OFFSET ?`
+	if err := r.db.SelectContext(ctx, &rows, query, pagination.Limit(), pagination.Offset()); err != nil {
+		return PaginatedResult[domain.WebhookEndpoint]{}, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	items := make([]domain.WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return NewPaginatedResult(items, total, pagination), nil
+}
+
+// ListEnabled retrieves all enabled webhook endpoints
+func (r *WebhookEndpointRepository) ListEnabled(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	var rows []webhookEndpointRow
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoint WHERE is_enabled = TRUE ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list enabled webhook endpoints: %w", err)
+	}
+
+	items := make([]domain.WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		items[i] = *row.toDomain()
+	}
+
+	return items, nil
+}
+
+// Create creates a new webhook endpoint
+func (r *WebhookEndpointRepository) Create(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	eventsJSON, supplierIDsJSON, err := marshalWebhookFilters(endpoint)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO webhook_endpoint (name, url, secret, events, supplier_ids, is_enabled, created_by) 
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, endpoint.Name, endpoint.URL, endpoint.Secret,
+		eventsJSON, supplierIDsJSON, endpoint.IsEnabled, endpoint.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	endpoint.ID = uint64(id)
+
+	return nil
+}
+
+// Update updates a webhook endpoint
+func (r *WebhookEndpointRepository) Update(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	eventsJSON, supplierIDsJSON, err := marshalWebhookFilters(endpoint)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE webhook_endpoint SET name = ?, url = ?, secret = ?, events = ?, supplier_ids = ?, is_enabled = ? 
+              WHERE id = ?`
+
+	_, err = r.db.ExecContext(ctx, query, endpoint.Name, endpoint.URL, endpoint.Secret,
+		eventsJSON, supplierIDsJSON, endpoint.IsEnabled, endpoint.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook endpoint together with its deliveries
+func (r *WebhookEndpointRepository) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM webhook_endpoint WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// marshalWebhookFilters encodes the event types and supplier filter of an endpoint; an empty
+// supplier filter is stored as NULL
+func marshalWebhookFilters(endpoint *domain.WebhookEndpoint) ([]byte, []byte, error) {
+	eventsJSON, err := json.Marshal(endpoint.Events)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	var supplierIDsJSON []byte
+	if len(endpoint.SupplierIDs) > 0 {
+		supplierIDsJSON, err = json.Marshal(endpoint.SupplierIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal supplier ids: %w", err)
+		}
+	}
+
+	return eventsJSON, supplierIDsJSON, nil
+}
+
+// webhookEndpointRow is the database row structure for webhook_endpoint
+type webhookEndpointRow struct {
+	ID          uint64        `db:"id"`
+	Name        string        `db:"name"`
+	URL         string        `db:"url"`
+	Secret      string        `db:"secret"`
+	Events      []byte        `db:"events"`
+	SupplierIDs []byte        `db:"supplier_ids"`
+	IsEnabled   bool          `db:"is_enabled"`
+	CreatedAt   sql.NullTime  `db:"created_at"`
+	UpdatedAt   sql.NullTime  `db:"updated_at"`
+	CreatedBy   sql.NullInt64 `db:"created_by"`
+}
+
+func (r *webhookEndpointRow) toDomain() *domain.WebhookEndpoint {
+	endpoint := &domain.WebhookEndpoint{
+		ID:        r.ID,
+		Name:      r.Name,
+		URL:       r.URL,
+		Secret:    r.Secret,
+		IsEnabled: r.IsEnabled,
+	}
+
+	if r.Events != nil {
+		_ = json.Unmarshal(r.Events, &endpoint.Events)
+	}
+
+	if r.SupplierIDs != nil {
+		_ = json.Unmarshal(r.SupplierIDs, &endpoint.SupplierIDs)
+	}
+
+	if r.CreatedAt.Valid {
+		endpoint.CreatedAt = r.CreatedAt.Time
+	}
+
+	if r.UpdatedAt.Valid {
+		endpoint.UpdatedAt = r.UpdatedAt.Time
+	}
+
+	if r.CreatedBy.Valid {
+		createdBy := uint64(r.CreatedBy.Int64)
+		endpoint.CreatedBy = &createdBy
+	}
+
+	return endpoint
+}
@@ -0,0 +1,531 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// Alert errors
+var (
+	ErrAlertRuleNotFound  = errors.New("alert rule not found")
+	ErrPriceAlertNotFound = errors.New("alert not found")
+)
+
+const (
+	// alertPricePrecision is the number of decimal places of per-person prices stored on alerts
+	alertPricePrecision = 2
+
+	// alertMessageMaxLength is the size of the price_alert.message column
+	alertMessageMaxLength = 500
+)
+
+// AlertService manages alert rules and evaluates them against new quotes
+type AlertService struct {
+	ruleRepo     *repo.AlertRuleRepository
+	alertRepo    *repo.PriceAlertRepository
+	quoteRepo    *repo.PriceQuoteRepository
+	sailingRepo  *repo.SailingRepository
+	shipRepo     *repo.ShipRepository
+	cabinRepo    *repo.CabinTypeRepository
+	categoryRepo *repo.CabinCategoryRepository
+	supplierRepo *repo.SupplierRepository
+	userRepo     *repo.UserRepository
+	fx           *FXService
+	audit        *obs.AuditService
+	logger       *obs.Logger
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(
+	ruleRepo *repo.AlertRuleRepository,
+	alertRepo *repo.PriceAlertRepository,
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cabinRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	userRepo *repo.UserRepository,
+	fx *FXService,
+	audit *obs.AuditService,
+	logger *obs.Logger,
+) *AlertService {
+	return &AlertService{
+		ruleRepo:     ruleRepo,
+		alertRepo:    alertRepo,
+		quoteRepo:    quoteRepo,
+		sailingRepo:  sailingRepo,
+		shipRepo:     shipRepo,
+		cabinRepo:    cabinRepo,
+		categoryRepo: categoryRepo,
+		supplierRepo: supplierRepo,
+		userRepo:     userRepo,
+		fx:           fx,
+		audit:        audit,
+		logger:       logger,
+	}
+}
+
+// CreateAlertRuleInput represents the input for creating an alert rule
+type CreateAlertRuleInput struct {
+	Name            string
+	SailingID       *uint64
+	ShipID          *uint64
+	CabinTypeID     *uint64
+	CabinCategoryID *uint64
+	Condition       domain.AlertCondition
+	Threshold       string // Optional, depends on the condition
+	Currency        string // BELOW_PRICE only
+	UserID          uint64 // From auth context
+}
+
+// CreateRule creates an alert rule owned by the user
+func (s *AlertService) CreateRule(ctx context.Context, input CreateAlertRuleInput) (*domain.AlertRule, error) {
+	rule := &domain.AlertRule{
+		UserID:          input.UserID,
+		Name:            strings.TrimSpace(input.Name),
+		SailingID:       input.SailingID,
+		ShipID:          input.ShipID,
+		CabinTypeID:     input.CabinTypeID,
+		CabinCategoryID: input.CabinCategoryID,
+		Condition:       input.Condition,
+		Currency:        strings.ToUpper(strings.TrimSpace(input.Currency)),
+		IsEnabled:       true,
+	}
+
+	if threshold := strings.TrimSpace(input.Threshold); threshold != "" {
+		value, err := decimal.NewFromString(threshold)
+		if err != nil {
+			return nil, domain.ValidationErrors{domain.NewValidationError("threshold", domain.ErrFieldInvalidFormat)}
+		}
+		rule.Threshold = &value
+	}
+
+	if errs := domain.ValidateAlertRule(rule); errs.HasErrors() {
+		return nil, errs
+	}
+
+	if err := s.checkRuleTargets(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	_ = s.audit.LogCreate(ctx, input.UserID, nil, domain.EntityTypeAlertRule, rule.ID, rule)
+
+	return rule, nil
+}
+
+// checkRuleTargets verifies that the sailing or ship and the cabin type or category of a rule exist,
+// and that a cabin type belongs to the watched ship
+func (s *AlertService) checkRuleTargets(ctx context.Context, rule *domain.AlertRule) error {
+	var shipID uint64
+	if rule.SailingID != nil {
+		sailing, err := s.sailingRepo.GetByID(ctx, *rule.SailingID)
+		if err != nil {
+			return err
+		}
+		if sailing == nil {
+			return ErrSailingNotFound
+		}
+		shipID = sailing.ShipID
+	} else {
+		ship, err := s.shipRepo.GetByID(ctx, *rule.ShipID)
+		if err != nil {
+			return err
+		}
+		if ship == nil {
+			return ErrShipNotFound
+		}
+		shipID = ship.ID
+	}
+
+	if rule.CabinTypeID != nil {
+		cabinType, err := s.cabinRepo.GetByID(ctx, *rule.CabinTypeID)
+		if err != nil {
+			return err
+		}
+		if cabinType == nil || cabinType.ShipID != shipID {
+			return ErrCabinTypeNotFound
+		}
+	}
+
+	if rule.CabinCategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *rule.CabinCategoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return ErrCabinCategoryNotFound
+		}
+	}
+
+	return nil
+}
+
+// ListRules lists the alert rules of a user
+func (s *AlertService) ListRules(ctx context.Context, pagination repo.Pagination, userID uint64) (repo.PaginatedResult[domain.AlertRule], error) {
+	return s.ruleRepo.ListByUser(ctx, pagination, userID)
+}
+
+// getOwnedRule returns a rule of the user; rules of other users are reported as not found
+func (s *AlertService) getOwnedRule(ctx context.Context, id, userID uint64) (*domain.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil || rule.UserID != userID {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// SetRuleEnabled enables or disables an alert rule of the user
+func (s *AlertService) SetRuleEnabled(ctx context.Context, id uint64, enabled bool, userID uint64) (*domain.AlertRule, error) {
+	rule, err := s.getOwnedRule(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.IsEnabled == enabled {
+		return rule, nil
+	}
+
+	if err := s.ruleRepo.SetEnabled(ctx, id, enabled); err != nil {
+		return nil, err
+	}
+
+	updated := *rule
+	updated.IsEnabled = enabled
+	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeAlertRule, id, rule, &updated)
+
+	return &updated, nil
+}
+
+// DeleteRule deletes an alert rule of the user together with its alerts
+func (s *AlertService) DeleteRule(ctx context.Context, id uint64, userID uint64) error {
+	rule, err := s.getOwnedRule(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeAlertRule, id, rule)
+
+	return nil
+}
+
+// ListAlerts lists the alerts of a user; acknowledged filters on the acknowledgement state when set
+func (s *AlertService) ListAlerts(ctx context.Context, pagination repo.Pagination, userID uint64, acknowledged *bool) (repo.PaginatedResult[domain.PriceAlert], error) {
+	return s.alertRepo.ListByUser(ctx, pagination, userID, acknowledged)
+}
+
+// AcknowledgeAlert marks an alert of the user as acknowledged
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, id uint64, userID uint64) (*domain.PriceAlert, error) {
+	alert, err := s.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil || alert.UserID != userID {
+		return nil, ErrPriceAlertNotFound
+	}
+	if alert.IsAcknowledged() {
+		return alert, nil
+	}
+
+	now := time.Now()
+	if err := s.alertRepo.Acknowledge(ctx, id, now); err != nil {
+		return nil, err
+	}
+	alert.AcknowledgedAt = &now
+
+	return alert, nil
+}
+
+// AcknowledgeAllAlerts marks every open alert of the user as acknowledged
+func (s *AlertService) AcknowledgeAllAlerts(ctx context.Context, userID uint64) (int64, error) {
+	return s.alertRepo.AcknowledgeAll(ctx, userID, time.Now())
+}
+
+// EvaluateQuote checks a newly created quote against the enabled rules watching its sailing
+// and cabin type and records an alert for every rule it meets. It runs after the quote has
+// been committed, so failures are logged rather than returned.
+func (s *AlertService) EvaluateQuote(ctx context.Context, quote *domain.PriceQuote) {
+	if err := s.evaluateQuote(ctx, quote); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("quote_id", quote.ID).Error("Failed to evaluate alert rules")
+	}
+}
+
+func (s *AlertService) evaluateQuote(ctx context.Context, quote *domain.PriceQuote) error {
+	sailing, err := s.sailingRepo.GetByID(ctx, quote.SailingID)
+	if err != nil {
+		return err
+	}
+	cabinType, err := s.cabinRepo.GetByID(ctx, quote.CabinTypeID)
+	if err != nil {
+		return err
+	}
+	if sailing == nil || cabinType == nil {
+		return nil
+	}
+
+	rules, err := s.ruleRepo.ListMatching(ctx, sailing.ID, sailing.ShipID, cabinType.ID, cabinType.CategoryID)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	quotes, err := s.quoteRepo.ListForTrend(ctx, sailing.ID, cabinType.ID, nil, nil, nil, false, false)
+	if err != nil {
+		return err
+	}
+
+	eval, err := s.newAlertEvaluation(ctx, quote, quotes)
+	if err != nil {
+		return err
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+
+		owner, err := eval.owner(ctx, rule.UserID)
+		if err != nil {
+			return err
+		}
+		if owner == nil || !eval.visibleTo(owner, quote.SupplierID) {
+			continue
+		}
+
+		alert, err := eval.check(ctx, rule, owner)
+		if err != nil {
+			if errors.Is(err, ErrFXRateNotFound) {
+				s.logger.WithContext(ctx).WithFields(map[string]any{
+					"rule_id":  rule.ID,
+					"quote_id": quote.ID,
+				}).Warn("Skipped alert rule without exchange rate")
+				continue
+			}
+			return err
+		}
+		if alert == nil {
+			continue
+		}
+
+		if _, err := s.alertRepo.Create(ctx, alert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// alertEvaluation holds what the rules of one new quote are evaluated against: the latest
+// current quote of every supplier for the sailing cabin type, before and after the new one
+type alertEvaluation struct {
+	service   *AlertService
+	quote     *domain.PriceQuote
+	at        time.Time                     // Rate date for currency conversion
+	previous  map[uint64]*domain.PriceQuote // Key: supplier ID, latest quote before the new one
+	suppliers map[uint64]*domain.Supplier
+	owners    map[uint64]*domain.User
+}
+
+func (s *AlertService) newAlertEvaluation(ctx context.Context, quote *domain.PriceQuote, quotes []domain.PriceQuote) (*alertEvaluation, error) {
+	eval := &alertEvaluation{
+		service:  s,
+		quote:    quote,
+		at:       quote.CreatedAt,
+		previous: make(map[uint64]*domain.PriceQuote),
+		owners:   make(map[uint64]*domain.User),
+	}
+	if eval.at.IsZero() {
+		// A quote that was just inserted has no database timestamp loaded
+		eval.at = time.Now()
+	}
+
+	supplierIDs := []uint64{quote.SupplierID}
+	for i := range quotes {
+		q := &quotes[i]
+		if q.ID == quote.ID {
+			continue
+		}
+		// quotes are ordered oldest first, so later ones replace earlier ones
+		if _, ok := eval.previous[q.SupplierID]; !ok {
+			supplierIDs = append(supplierIDs, q.SupplierID)
+		}
+		eval.previous[q.SupplierID] = q
+	}
+
+	suppliers, err := s.supplierRepo.ListByIDs(ctx, supplierIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppliers: %w", err)
+	}
+	eval.suppliers = make(map[uint64]*domain.Supplier, len(suppliers))
+	for i := range suppliers {
+		eval.suppliers[suppliers[i].ID] = &suppliers[i]
+	}
+
+	return eval, nil
+}
+
+// owner returns the owner of a rule, or nil when the user no longer exists
+func (e *alertEvaluation) owner(ctx context.Context, userID uint64) (*domain.User, error) {
+	if user, ok := e.owners[userID]; ok {
+		return user, nil
+	}
+	user, err := e.service.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	e.owners[userID] = user
+	return user, nil
+}
+
+// visibleTo checks whether a rule owner may see the quotes of a supplier; vendors only see
+// their own supplier and public suppliers
+func (e *alertEvaluation) visibleTo(owner *domain.User, supplierID uint64) bool {
+	supplier, ok := e.suppliers[supplierID]
+	if !ok {
+		return false
+	}
+	if owner.Role != domain.UserRoleVendor {
+		return true
+	}
+	return (owner.SupplierID != nil && *owner.SupplierID == supplier.ID) || supplier.IsPublic()
+}
+
+// perPerson returns the per-person price of a quote converted into the currency at the time of the new quote
+func (e *alertEvaluation) perPerson(ctx context.Context, q *domain.PriceQuote, currency string) (decimal.Decimal, error) {
+	converted, err := e.service.fx.Convert(ctx, q.PricePerPerson(0), q.Currency, currency, e.at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return converted.Amount.Round(alertPricePrecision), nil
+}
+
+// check evaluates one rule and returns the alert to record, or nil when the condition is not met
+func (e *alertEvaluation) check(ctx context.Context, rule *domain.AlertRule, owner *domain.User) (*domain.PriceAlert, error) {
+	quote := e.quote
+	alert := &domain.PriceAlert{
+		RuleID:      rule.ID,
+		UserID:      rule.UserID,
+		QuoteID:     quote.ID,
+		SailingID:   quote.SailingID,
+		CabinTypeID: quote.CabinTypeID,
+		SupplierID:  quote.SupplierID,
+		Condition:   rule.Condition,
+		Currency:    quote.Currency,
+	}
+	supplierName := e.suppliers[quote.SupplierID].Name
+
+	switch rule.Condition {
+	case domain.AlertConditionBelowPrice:
+		price, err := e.perPerson(ctx, quote, rule.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !price.LessThan(*rule.Threshold) {
+			return nil, nil
+		}
+		alert.Price = price
+		alert.Currency = rule.Currency
+		alert.Message = fmt.Sprintf("%s: %s quoted %s %s per person, below %s %s",
+			rule.Name, supplierName, price.StringFixed(alertPricePrecision), rule.Currency,
+			rule.Threshold.StringFixed(alertPricePrecision), rule.Currency)
+
+	case domain.AlertConditionDropPercent:
+		previous, ok := e.previous[quote.SupplierID]
+		if !ok {
+			return nil, nil
+		}
+		price, err := e.perPerson(ctx, quote, quote.Currency)
+		if err != nil {
+			return nil, err
+		}
+		previousPrice, err := e.perPerson(ctx, previous, quote.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !previousPrice.IsPositive() {
+			return nil, nil
+		}
+		drop := previousPrice.Sub(price).Div(previousPrice).Mul(decimal.NewFromInt(100))
+		if !drop.GreaterThan(*rule.Threshold) {
+			return nil, nil
+		}
+		alert.Price = price
+		alert.PreviousPrice = &previousPrice
+		alert.Message = fmt.Sprintf("%s: %s dropped %s%% from %s to %s %s per person",
+			rule.Name, supplierName, drop.StringFixed(1), previousPrice.StringFixed(alertPricePrecision),
+			price.StringFixed(alertPricePrecision), quote.Currency)
+
+	case domain.AlertConditionNewLowestSupplier:
+		price, err := e.perPerson(ctx, quote, quote.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		// The cheapest other supplier visible to the owner, before the new quote
+		var lowest *domain.PriceQuote
+		var lowestPrice decimal.Decimal
+		for supplierID, q := range e.previous {
+			if !e.visibleTo(owner, supplierID) {
+				continue
+			}
+			p, err := e.perPerson(ctx, q, quote.Currency)
+			if err != nil {
+				return nil, err
+			}
+			if lowest == nil || p.LessThan(lowestPrice) || (p.Equal(lowestPrice) && q.SupplierID < lowest.SupplierID) {
+				lowest = q
+				lowestPrice = p
+			}
+		}
+		if lowest == nil || lowest.SupplierID == quote.SupplierID {
+			return nil, nil
+		}
+
+		// The new quote must beat every other supplier, not just the previous cheapest
+		for supplierID, q := range e.previous {
+			if supplierID == quote.SupplierID || !e.visibleTo(owner, supplierID) {
+				continue
+			}
+			p, err := e.perPerson(ctx, q, quote.Currency)
+			if err != nil {
+				return nil, err
+			}
+			if !price.LessThan(p) {
+				return nil, nil
+			}
+		}
+
+		alert.Price = price
+		alert.PreviousPrice = &lowestPrice
+		previousName := e.suppliers[lowest.SupplierID].Name
+		alert.Message = fmt.Sprintf("%s: %s is now the lowest supplier at %s %s per person, previously %s at %s %s",
+			rule.Name, supplierName, price.StringFixed(alertPricePrecision), quote.Currency,
+			previousName, lowestPrice.StringFixed(alertPricePrecision), quote.Currency)
+
+	default:
+		return nil, nil
+	}
+
+	alert.Message = truncateRunes(alert.Message, alertMessageMaxLength)
+
+	return alert, nil
+}
@@ -0,0 +1,672 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// Analytics errors
+var (
+	ErrInvalidAnalyticsWindow  = errors.New("analytics window must not end before it starts")
+	ErrAnalyticsWindowTooLarge = errors.New("analytics window spans more than 366 days")
+)
+
+const (
+	// maxDispersionDays is the longest window of a price dispersion series
+	maxDispersionDays = 366
+	// defaultDispersionDays is the window of a price dispersion series without a start date
+	defaultDispersionDays = 90
+)
+
+// AnalyticsService computes market statistics of the latest prices per sailing and cabin
+// category: their spread across suppliers, the standing of each supplier and how the spread
+// evolved over time. Prices are compared per person, normalized for an occupancy and
+// converted into a display currency, in decimal arithmetic throughout.
+type AnalyticsService struct {
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinTypeRepo  *repo.CabinTypeRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	supplierRepo   *repo.SupplierRepository
+	fx             *FXService
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *AnalyticsService {
+	return &AnalyticsService{
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinTypeRepo:  cabinTypeRepo,
+		categoryRepo:   categoryRepo,
+		supplierRepo:   supplierRepo,
+		fx:             fx,
+	}
+}
+
+// MarketStatsInput represents the input for the market statistics of a sailing
+type MarketStatsInput struct {
+	SailingID       uint64
+	CabinCategoryID *uint64    // Optional, only this category
+	DisplayCurrency string     // Optional, defaults to SearchCurrencyDefault
+	Occupancy       *Occupancy // Optional, defaults to two adults
+	From            *time.Time // Dispersion window start, defaults to 90 days before To
+	To              *time.Time // Dispersion window end, defaults to today
+	UserRole        domain.UserRole
+	UserSupplier    uint64
+}
+
+// PriceStats summarizes a set of prices, one per supplier. SpreadPct is the spread as a
+// percentage of the minimum.
+type PriceStats struct {
+	Count     int             `json:"count"`
+	Min       decimal.Decimal `json:"min"`
+	Median    decimal.Decimal `json:"median"`
+	Max       decimal.Decimal `json:"max"`
+	Mean      decimal.Decimal `json:"mean"`
+	Spread    decimal.Decimal `json:"spread"`
+	SpreadPct decimal.Decimal `json:"spread_pct"`
+}
+
+// SupplierStanding is the lowest latest price of a supplier in a cabin category, ranked
+// against the other suppliers; suppliers at the same price share a rank
+type SupplierStanding struct {
+	Rank          int                `json:"rank"`
+	SupplierID    uint64             `json:"supplier_id"`
+	SupplierName  string             `json:"supplier_name"`
+	CabinTypeID   uint64             `json:"cabin_type_id"`
+	CabinTypeName string             `json:"cabin_type_name"`
+	QuoteID       uint64             `json:"quote_id"`
+	PerPerson     decimal.Decimal    `json:"per_person"` // normalized, in the display currency
+	GapToCheapest decimal.Decimal    `json:"gap_to_cheapest"`
+	GapPct        decimal.Decimal    `json:"gap_pct"`
+	Price         decimal.Decimal    `json:"price"` // as quoted
+	Currency      string             `json:"currency"`
+	PricingUnit   domain.PricingUnit `json:"pricing_unit"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// CategoryMarketStats is the market picture of a cabin category on a sailing
+type CategoryMarketStats struct {
+	CabinCategoryID   uint64             `json:"cabin_category_id"`
+	CabinCategoryName string             `json:"cabin_category_name"`
+	Stats             PriceStats         `json:"stats"`
+	Suppliers         []SupplierStanding `json:"suppliers"`
+}
+
+// MarketStats is the market picture of the latest prices of a sailing
+type MarketStats struct {
+	Sailing         SailingInfo           `json:"sailing"`
+	DisplayCurrency string                `json:"display_currency"`
+	Occupancy       Occupancy             `json:"occupancy"`
+	Categories      []CategoryMarketStats `json:"categories"`
+	Warnings        []string              `json:"warnings,omitempty"`
+}
+
+// DispersionPoint is the spread of the prices in effect at the end of a day
+type DispersionPoint struct {
+	Date  time.Time  `json:"date"`
+	Stats PriceStats `json:"stats"`
+}
+
+// CategoryDispersion is the daily price spread of a cabin category
+type CategoryDispersion struct {
+	CabinCategoryID   uint64            `json:"cabin_category_id"`
+	CabinCategoryName string            `json:"cabin_category_name"`
+	Points            []DispersionPoint `json:"points"`
+}
+
+// PriceDispersion is the daily price spread of each cabin category of a sailing
+type PriceDispersion struct {
+	Sailing         SailingInfo          `json:"sailing"`
+	DisplayCurrency string               `json:"display_currency"`
+	Occupancy       Occupancy            `json:"occupancy"`
+	From            time.Time            `json:"from"`
+	To              time.Time            `json:"to"`
+	Categories      []CategoryDispersion `json:"categories"`
+	Warnings        []string             `json:"warnings,omitempty"`
+}
+
+// marketContext holds what the statistics of a sailing are computed from
+type marketContext struct {
+	info          *SailingInfo
+	currency      string
+	occupancy     Occupancy
+	quotes        []domain.PriceQuote // current and expired quotes, newest first
+	suppliers     map[uint64]*domain.Supplier
+	cabinTypes    map[uint64]*domain.CabinType
+	categoryNames map[uint64]string
+	categoryOrder map[uint64]int
+	converter     *CurrencyConverter
+	warnings      *warningSet
+	prices        map[uint64]*decimal.Decimal // normalized display price by quote ID, nil if unpriceable
+}
+
+// GetMarketStats returns, per cabin category of a sailing, the min/median/max/mean and spread
+// of the latest current prices across the visible suppliers, and each supplier's rank and gap
+// to the cheapest. A supplier's price in a category is its lowest over the category's cabin types.
+func (s *AnalyticsService) GetMarketStats(ctx context.Context, input MarketStatsInput) (*MarketStats, error) {
+	mc, err := s.loadMarketContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quotes are newest first: the first current one per cabin type + supplier is the latest
+	now := time.Now()
+	latest := make(map[cellKey]*domain.PriceQuote)
+	for i := range mc.quotes {
+		q := &mc.quotes[i]
+		key := cellKey{q.CabinTypeID, q.SupplierID}
+		if _, ok := latest[key]; ok || q.IsExpired(now) {
+			continue
+		}
+		latest[key] = q
+	}
+
+	type standingKey struct{ categoryID, supplierID uint64 }
+	best := make(map[standingKey]*SupplierStanding)
+	for key, q := range latest {
+		price, err := mc.price(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if price == nil {
+			continue
+		}
+
+		cabinType := mc.cabinTypes[key.cabinTypeID]
+		sk := standingKey{cabinType.CategoryID, q.SupplierID}
+		if current, ok := best[sk]; ok && !price.LessThan(current.PerPerson) {
+			continue
+		}
+		best[sk] = &SupplierStanding{
+			SupplierID:    q.SupplierID,
+			SupplierName:  mc.suppliers[q.SupplierID].Name,
+			CabinTypeID:   cabinType.ID,
+			CabinTypeName: cabinType.Name,
+			QuoteID:       q.ID,
+			PerPerson:     *price,
+			Price:         q.Price,
+			Currency:      q.Currency,
+			PricingUnit:   q.PricingUnit,
+			UpdatedAt:     q.CreatedAt,
+		}
+	}
+
+	byCategory := make(map[uint64][]SupplierStanding)
+	for sk, standing := range best {
+		byCategory[sk.categoryID] = append(byCategory[sk.categoryID], *standing)
+	}
+
+	result := &MarketStats{
+		Sailing:         *mc.info,
+		DisplayCurrency: mc.currency,
+		Occupancy:       mc.occupancy,
+		Categories:      []CategoryMarketStats{},
+	}
+
+	for _, categoryID := range sortedCategories(mc, byCategory) {
+		standings := byCategory[categoryID]
+		sort.Slice(standings, func(i, j int) bool {
+			if !standings[i].PerPerson.Equal(standings[j].PerPerson) {
+				return standings[i].PerPerson.LessThan(standings[j].PerPerson)
+			}
+			return standings[i].SupplierName < standings[j].SupplierName
+		})
+
+		prices := make([]decimal.Decimal, len(standings))
+		for i := range standings {
+			prices[i] = standings[i].PerPerson
+		}
+		stats := computePriceStats(prices)
+
+		for i := range standings {
+			standing := &standings[i]
+			standing.Rank = i + 1
+			if i > 0 && standing.PerPerson.Equal(standings[i-1].PerPerson) {
+				standing.Rank = standings[i-1].Rank
+			}
+			standing.GapToCheapest = standing.PerPerson.Sub(stats.Min)
+			standing.GapPct = percentOf(standing.GapToCheapest, stats.Min)
+		}
+
+		result.Categories = append(result.Categories, CategoryMarketStats{
+			CabinCategoryID:   categoryID,
+			CabinCategoryName: mc.categoryNames[categoryID],
+			Stats:             stats,
+			Suppliers:         standings,
+		})
+	}
+
+	result.Warnings = mc.warnings.list()
+
+	return result, nil
+}
+
+// GetPriceDispersion returns, per cabin category of a sailing and per day of the window, the
+// spread of the prices in effect at the end of that day: the latest quote of each visible
+// supplier and cabin type created by then and still valid on that day. Voided and corrected
+// quotes are left out. Days without any price are skipped.
+func (s *AnalyticsService) GetPriceDispersion(ctx context.Context, input MarketStatsInput) (*PriceDispersion, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	if input.To != nil {
+		to = input.To.UTC().Truncate(24 * time.Hour)
+	}
+	from := to.AddDate(0, 0, -defaultDispersionDays)
+	if input.From != nil {
+		from = input.From.UTC().Truncate(24 * time.Hour)
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidAnalyticsWindow
+	}
+	if to.Sub(from) > maxDispersionDays*24*time.Hour {
+		return nil, ErrAnalyticsWindowTooLarge
+	}
+
+	mc, err := s.loadMarketContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the quotes oldest first, taking the latest of each cell at the end of each day
+	quotes := make([]*domain.PriceQuote, len(mc.quotes))
+	for i := range mc.quotes {
+		quotes[len(quotes)-1-i] = &mc.quotes[i]
+	}
+
+	points := make(map[uint64][]DispersionPoint)
+	latest := make(map[cellKey]*domain.PriceQuote)
+	next := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		for next < len(quotes) && quotes[next].CreatedAt.Before(dayEnd) {
+			q := quotes[next]
+			latest[cellKey{q.CabinTypeID, q.SupplierID}] = q
+			next++
+		}
+
+		type supplierKey struct{ categoryID, supplierID uint64 }
+		lowest := make(map[supplierKey]decimal.Decimal)
+		for key, q := range latest {
+			if q.IsPastValidUntil(day) {
+				continue
+			}
+			price, err := mc.price(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			if price == nil {
+				continue
+			}
+
+			sk := supplierKey{mc.cabinTypes[key.cabinTypeID].CategoryID, key.supplierID}
+			if current, ok := lowest[sk]; !ok || price.LessThan(current) {
+				lowest[sk] = *price
+			}
+		}
+
+		byCategory := make(map[uint64][]decimal.Decimal)
+		for sk, price := range lowest {
+			byCategory[sk.categoryID] = append(byCategory[sk.categoryID], price)
+		}
+		for categoryID, prices := range byCategory {
+			points[categoryID] = append(points[categoryID], DispersionPoint{Date: day, Stats: computePriceStats(prices)})
+		}
+	}
+
+	result := &PriceDispersion{
+		Sailing:         *mc.info,
+		DisplayCurrency: mc.currency,
+		Occupancy:       mc.occupancy,
+		From:            from,
+		To:              to,
+		Categories:      []CategoryDispersion{},
+	}
+	for _, categoryID := range sortedCategories(mc, points) {
+		result.Categories = append(result.Categories, CategoryDispersion{
+			CabinCategoryID:   categoryID,
+			CabinCategoryName: mc.categoryNames[categoryID],
+			Points:            points[categoryID],
+		})
+	}
+
+	result.Warnings = mc.warnings.list()
+
+	return result, nil
+}
+
+// loadMarketContext loads the sailing, its current and expired quotes of visible suppliers
+// and the catalog data needed to price and group them
+func (s *AnalyticsService) loadMarketContext(ctx context.Context, input MarketStatsInput) (*marketContext, error) {
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if displayCurrency == "" {
+		displayCurrency = SearchCurrencyDefault
+	}
+
+	occupancy := Occupancy{Adults: domain.DefaultBaseOccupancy}
+	if input.Occupancy != nil {
+		occupancy = *input.Occupancy
+	}
+	if occupancy.Adults < 1 || occupancy.Children < 0 {
+		return nil, ErrInvalidOccupancy
+	}
+
+	sailing, info, err := loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.quoteRepo.ListBySailing(ctx, sailing.ID, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := visibleSuppliers(ctx, s.supplierRepo, quotes, nil, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	shipCabinTypes, err := s.cabinTypeRepo.ListByShip(ctx, sailing.ShipID)
+	if err != nil {
+		return nil, err
+	}
+	cabinTypes := make(map[uint64]*domain.CabinType, len(shipCabinTypes))
+	for i := range shipCabinTypes {
+		ct := &shipCabinTypes[i]
+		if input.CabinCategoryID != nil && ct.CategoryID != *input.CabinCategoryID {
+			continue
+		}
+		cabinTypes[ct.ID] = ct
+	}
+
+	// Keep only the quotes the statistics are about
+	kept := quotes[:0]
+	for _, q := range quotes {
+		if _, ok := suppliers[q.SupplierID]; !ok {
+			continue
+		}
+		if _, ok := cabinTypes[q.CabinTypeID]; !ok {
+			continue
+		}
+		kept = append(kept, q)
+	}
+	if err := s.quoteRepo.LoadOccupancyRates(ctx, kept); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc := &marketContext{
+		info:          info,
+		currency:      displayCurrency,
+		occupancy:     occupancy,
+		quotes:        kept,
+		suppliers:     suppliers,
+		cabinTypes:    cabinTypes,
+		categoryNames: make(map[uint64]string, len(categories)),
+		categoryOrder: make(map[uint64]int, len(categories)),
+		converter:     s.fx.NewConverter(displayCurrency),
+		warnings:      newWarningSet(),
+		prices:        make(map[uint64]*decimal.Decimal),
+	}
+	for _, cc := range categories {
+		mc.categoryNames[cc.ID] = cc.Name
+		mc.categoryOrder[cc.ID] = cc.SortOrder
+	}
+
+	return mc, nil
+}
+
+// price returns the per-person price of a quote normalized for the occupancy, in the display
+// currency at the rate of the quote date. Quotes that cannot host the party or lack an exchange
+// rate yield nil and are reported as warnings.
+func (mc *marketContext) price(ctx context.Context, q *domain.PriceQuote) (*decimal.Decimal, error) {
+	if price, ok := mc.prices[q.ID]; ok {
+		return price, nil
+	}
+
+	mc.prices[q.ID] = nil
+	normalized, err := NormalizeQuotePrice(q, mc.occupancy, mc.info.Nights)
+	if err != nil {
+		mc.warnings.add(fmt.Sprintf("quote %d: %v", q.ID, err))
+		return nil, nil
+	}
+	display, err := convertForDisplay(ctx, mc.converter, q, mc.warnings)
+	if err != nil || display == nil {
+		return nil, err
+	}
+
+	price := normalized.PerPerson.Mul(display.Rate).Round(fxAmountPrecision)
+	mc.prices[q.ID] = &price
+	return &price, nil
+}
+
+// sortedCategories returns the category IDs of m in category sort order
+func sortedCategories[T any](mc *marketContext, m map[uint64]T) []uint64 {
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if mc.categoryOrder[ids[i]] != mc.categoryOrder[ids[j]] {
+			return mc.categoryOrder[ids[i]] < mc.categoryOrder[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// computePriceStats summarizes prices; the median of an even count is the mean of the middle two
+func computePriceStats(prices []decimal.Decimal) PriceStats {
+	if len(prices) == 0 {
+		return PriceStats{}
+	}
+
+	sorted := make([]decimal.Decimal, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	n := len(sorted)
+	two := decimal.NewFromInt(2)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = sorted[n/2-1].Add(sorted[n/2]).Div(two).Round(fxAmountPrecision)
+	}
+
+	stats := PriceStats{
+		Count:  n,
+		Min:    sorted[0],
+		Median: median,
+		Max:    sorted[n-1],
+		Mean:   decimal.Sum(sorted[0], sorted[1:]...).Div(decimal.NewFromInt(int64(n))).Round(fxAmountPrecision),
+		Spread: sorted[n-1].Sub(sorted[0]),
+	}
+	stats.SpreadPct = percentOf(stats.Spread, stats.Min)
+
+	return stats
+}
+
+// percentOf returns part as a percentage of whole, rounded to 2 places; zero when whole is zero
+func percentOf(part, whole decimal.Decimal) decimal.Decimal {
+	if whole.IsZero() {
+		return decimal.Zero
+	}
+	return part.Mul(decimal.NewFromInt(100)).Div(whole).Round(2)
+}
+
+// ExportMarketStats writes the market statistics, supplier standings and price dispersion of a
+// sailing into a workbook with one sheet each
+func (s *AnalyticsService) ExportMarketStats(ctx context.Context, input MarketStatsInput) (*excelize.File, error) {
+	stats, err := s.GetMarketStats(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	dispersion, err := s.GetPriceDispersion(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	w := &statsWorkbook{f: f}
+	if err := w.init(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	currency := stats.DisplayCurrency
+	summary := [][]interface{}{}
+	standings := [][]interface{}{}
+	for _, category := range stats.Categories {
+		st := category.Stats
+		summary = append(summary, []interface{}{
+			category.CabinCategoryName, st.Count, decimalCell(st.Min), decimalCell(st.Median), decimalCell(st.Max),
+			decimalCell(st.Mean), decimalCell(st.Spread), decimalCell(st.SpreadPct),
+		})
+		for _, standing := range category.Suppliers {
+			standings = append(standings, []interface{}{
+				category.CabinCategoryName, standing.Rank, standing.SupplierName, standing.CabinTypeName,
+				decimalCell(standing.PerPerson), decimalCell(standing.GapToCheapest), decimalCell(standing.GapPct),
+				decimalCell(standing.Price), standing.Currency, string(standing.PricingUnit),
+				standing.UpdatedAt.Format("2006-01-02 15:04"),
+			})
+		}
+	}
+
+	points := [][]interface{}{}
+	for _, category := range dispersion.Categories {
+		for _, point := range category.Points {
+			st := point.Stats
+			points = append(points, []interface{}{
+				point.Date.Format("2006-01-02"), category.CabinCategoryName, st.Count, decimalCell(st.Min),
+				decimalCell(st.Median), decimalCell(st.Max), decimalCell(st.Spread), decimalCell(st.SpreadPct),
+			})
+		}
+	}
+
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"市场统计", []string{"舱房类别", "报价供应商数", "最低价/人 (" + currency + ")", "中位价/人", "最高价/人", "平均价/人", "价差", "价差%"}, summary},
+		{"供应商排名", []string{"舱房类别", "排名", "供应商", "舱型", "价格/人 (" + currency + ")", "与最低价差额", "差额%", "报价", "币种", "计价单位", "报价时间"}, standings},
+		{"价格离散度", []string{"日期", "舱房类别", "报价供应商数", "最低价/人 (" + currency + ")", "中位价/人", "最高价/人", "价差", "价差%"}, points},
+	}
+	for i, sheet := range sheets {
+		if err := w.writeSheet(i == 0, sheet.name, sheet.headers, sheet.rows); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	info := stats.Sailing
+	title := fmt.Sprintf("%s %s %s %s", info.CruiseLineName, info.ShipName, info.DepartureDate.Format("2006-01-02"), info.Route)
+	if err := f.SetDocProps(&excelize.DocProperties{Title: title}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to set workbook properties: %w", err)
+	}
+
+	return f, nil
+}
+
+// statsWorkbook writes analytics tables into a workbook with a shared header style
+type statsWorkbook struct {
+	f           *excelize.File
+	headerStyle int
+	numberStyle int
+}
+
+func (w *statsWorkbook) init() error {
+	var err error
+	w.headerStyle, err = w.f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	numberFormat := "#,##0.00"
+	w.numberStyle, err = w.f.NewStyle(&excelize.Style{CustomNumFmt: &numberFormat})
+	if err != nil {
+		return fmt.Errorf("failed to create number style: %w", err)
+	}
+
+	return nil
+}
+
+// writeSheet writes a table; the first sheet replaces the default one
+func (w *statsWorkbook) writeSheet(first bool, name string, headers []string, rows [][]interface{}) error {
+	if first {
+		if err := w.f.SetSheetName("Sheet1", name); err != nil {
+			return fmt.Errorf("failed to rename sheet: %w", err)
+		}
+	} else if _, err := w.f.NewSheet(name); err != nil {
+		return fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := w.f.SetCellValue(name, cell, header); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+	last, _ := excelize.CoordinatesToCellName(len(headers), 1)
+	if err := w.f.SetCellStyle(name, "A1", last, w.headerStyle); err != nil {
+		return fmt.Errorf("failed to style header: %w", err)
+	}
+
+	for r, row := range rows {
+		for c, value := range row {
+			cell, _ := excelize.CoordinatesToCellName(c+1, r+2)
+			if err := w.f.SetCellValue(name, cell, value); err != nil {
+				return fmt.Errorf("failed to write cell %s: %w", cell, err)
+			}
+			if _, ok := value.(float64); ok {
+				if err := w.f.SetCellStyle(name, cell, cell, w.numberStyle); err != nil {
+					return fmt.Errorf("failed to style cell %s: %w", cell, err)
+				}
+			}
+		}
+	}
+
+	lastCol, _ := excelize.ColumnNumberToName(len(headers))
+	if err := w.f.SetColWidth(name, "A", lastCol, 16); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+
+	return nil
+}
+
+// decimalCell converts an amount for a spreadsheet cell. Amounts are kept to 2 decimal places,
+// which a spreadsheet number represents exactly enough for display.
+func decimalCell(d decimal.Decimal) float64 {
+	return d.Round(2).InexactFloat64()
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// Review flag errors
+var (
+	ErrReviewFlagNotFound  = errors.New("review flag not found")
+	ErrReviewFlagClosed    = errors.New("review flag has already been reviewed")
+	ErrInvalidReviewStatus = errors.New("invalid review status")
+)
+
+const (
+	// anomalyHistoryWindow is the number of the supplier's earlier quotes for the cabin compared against
+	anomalyHistoryWindow = 5
+
+	// anomalyHistoryRatio flags a price this many times above or below the supplier's earlier median
+	anomalyHistoryRatio = 1.6
+
+	// anomalyMarketRatio flags a price this many times above or below the other suppliers' median;
+	// it is wider than the history ratio because suppliers legitimately differ
+	anomalyMarketRatio = 1.8
+
+	// anomalyMinMarketQuotes is the number of other suppliers needed for a market comparison
+	anomalyMinMarketQuotes = 2
+
+	// anomalyPricePrecision is the number of decimal places of prices in anomaly reasons
+	anomalyPricePrecision = 2
+
+	// reviewNoteMaxLength is the size of the quote_review_flag.review_note column
+	reviewNoteMaxLength = 500
+)
+
+// AnomalyService flags new quotes whose price looks like a data entry error, such as a
+// missing digit or a per-cabin price entered per person, and manages the review queue
+type AnomalyService struct {
+	flagRepo     *repo.QuoteReviewFlagRepository
+	quoteRepo    *repo.PriceQuoteRepository
+	cabinRepo    *repo.CabinTypeRepository
+	categoryRepo *repo.CabinCategoryRepository
+	fx           *FXService
+	audit        *obs.AuditService
+	logger       *obs.Logger
+}
+
+// NewAnomalyService creates a new anomaly service
+func NewAnomalyService(
+	flagRepo *repo.QuoteReviewFlagRepository,
+	quoteRepo *repo.PriceQuoteRepository,
+	cabinRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	fx *FXService,
+	audit *obs.AuditService,
+	logger *obs.Logger,
+) *AnomalyService {
+	return &AnomalyService{
+		flagRepo:     flagRepo,
+		quoteRepo:    quoteRepo,
+		cabinRepo:    cabinRepo,
+		categoryRepo: categoryRepo,
+		fx:           fx,
+		audit:        audit,
+		logger:       logger,
+	}
+}
+
+// EvaluateQuote runs the anomaly checks against a newly stored quote and flags it for review
+// when any of them fails. It runs after the quote has been committed, so failures are logged
+// rather than returned.
+func (s *AnomalyService) EvaluateQuote(ctx context.Context, quote *domain.PriceQuote) {
+	if err := s.evaluateQuote(ctx, quote); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("quote_id", quote.ID).Error("Failed to check quote for anomalies")
+	}
+}
+
+func (s *AnomalyService) evaluateQuote(ctx context.Context, quote *domain.PriceQuote) error {
+	eval := &anomalyEvaluation{
+		service:   s,
+		quote:     quote,
+		price:     quote.PricePerPerson(0).Round(anomalyPricePrecision),
+		converter: s.fx.NewConverter(quote.Currency),
+		at:        quote.CreatedAt,
+	}
+	if eval.at.IsZero() {
+		// A quote that was just inserted has no database timestamp loaded
+		eval.at = time.Now()
+	}
+	if !eval.price.IsPositive() {
+		return nil
+	}
+
+	if err := eval.checkSupplierHistory(ctx); err != nil {
+		return err
+	}
+
+	current, err := s.quoteRepo.ListBySailing(ctx, quote.SailingID, false, false)
+	if err != nil {
+		return err
+	}
+	latest := latestPerSupplierCabin(current, quote.ID)
+
+	if err := eval.checkMarketPrice(ctx, latest); err != nil {
+		return err
+	}
+	if err := eval.checkCategoryOrder(ctx, latest); err != nil {
+		return err
+	}
+
+	if len(eval.reasons) == 0 {
+		return nil
+	}
+
+	flag := &domain.QuoteReviewFlag{
+		QuoteID:     quote.ID,
+		SailingID:   quote.SailingID,
+		CabinTypeID: quote.CabinTypeID,
+		SupplierID:  quote.SupplierID,
+		Reasons:     eval.reasons,
+		Status:      domain.ReviewFlagOpen,
+	}
+	if err := s.flagRepo.Create(ctx, flag); err != nil {
+		return err
+	}
+
+	checks := make([]string, len(eval.reasons))
+	for i, reason := range eval.reasons {
+		checks[i] = string(reason.Check)
+	}
+	s.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"quote_id": quote.ID,
+		"flag_id":  flag.ID,
+		"checks":   strings.Join(checks, ","),
+	}).Warn("Quote flagged for review")
+
+	return nil
+}
+
+// latestPerSupplierCabin keeps the newest quote of each supplier and cabin type, leaving
+// out the quote under evaluation. quotes must be ordered newest first.
+func latestPerSupplierCabin(quotes []domain.PriceQuote, excludeID uint64) []*domain.PriceQuote {
+	type key struct{ supplierID, cabinTypeID uint64 }
+	seen := make(map[key]bool)
+
+	var latest []*domain.PriceQuote
+	for i := range quotes {
+		q := &quotes[i]
+		k := key{q.SupplierID, q.CabinTypeID}
+		if q.ID == excludeID || seen[k] {
+			continue
+		}
+		seen[k] = true
+		latest = append(latest, q)
+	}
+	return latest
+}
+
+// anomalyEvaluation holds the state of checking one quote. Prices are compared per person
+// at base occupancy, converted into the currency of the quote.
+type anomalyEvaluation struct {
+	service   *AnomalyService
+	quote     *domain.PriceQuote
+	price     decimal.Decimal
+	converter *CurrencyConverter
+	at        time.Time
+	reasons   []domain.AnomalyReason
+}
+
+// perPerson returns the per-person price of a quote in the currency of the evaluated quote;
+// false when no exchange rate is available
+func (e *anomalyEvaluation) perPerson(ctx context.Context, q *domain.PriceQuote) (decimal.Decimal, bool, error) {
+	converted, err := e.converter.Convert(ctx, q.PricePerPerson(0), q.Currency, e.at)
+	if err != nil {
+		if errors.Is(err, ErrFXRateNotFound) {
+			return decimal.Zero, false, nil
+		}
+		return decimal.Zero, false, err
+	}
+	return converted.Amount.Round(anomalyPricePrecision), true, nil
+}
+
+func (e *anomalyEvaluation) addReason(check domain.AnomalyCheck, reference decimal.Decimal, message string) {
+	e.reasons = append(e.reasons, domain.AnomalyReason{
+		Check:     check,
+		Message:   message,
+		Price:     e.price,
+		Reference: reference,
+		Currency:  e.quote.Currency,
+	})
+}
+
+// deviation returns how many times the price lies above or below the reference, and whether it is above
+func (e *anomalyEvaluation) deviation(reference decimal.Decimal) (decimal.Decimal, bool) {
+	if e.price.GreaterThanOrEqual(reference) {
+		return e.price.Div(reference), true
+	}
+	return reference.Div(e.price), false
+}
+
+// describeDeviation formats a deviation for reason messages, e.g. "10.0x lower than"
+func describeDeviation(factor decimal.Decimal, above bool) string {
+	if above {
+		return factor.StringFixed(1) + "x higher than"
+	}
+	return factor.StringFixed(1) + "x lower than"
+}
+
+// checkSupplierHistory compares the price with the median of the supplier's recent earlier
+// quotes for the same sailing and cabin type, expired ones included
+func (e *anomalyEvaluation) checkSupplierHistory(ctx context.Context) error {
+	q := e.quote
+	history, err := e.service.quoteRepo.ListForTrend(ctx, q.SailingID, q.CabinTypeID, []uint64{q.SupplierID}, nil, nil, true, false)
+	if err != nil {
+		return err
+	}
+
+	var prices []decimal.Decimal
+	// history is ordered oldest first; walk it backwards for the most recent quotes
+	for i := len(history) - 1; i >= 0 && len(prices) < anomalyHistoryWindow; i-- {
+		if history[i].ID == q.ID {
+			continue
+		}
+		price, ok, err := e.perPerson(ctx, &history[i])
+		if err != nil {
+			return err
+		}
+		if ok && price.IsPositive() {
+			prices = append(prices, price)
+		}
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	reference := medianDecimal(prices)
+	factor, above := e.deviation(reference)
+	if factor.LessThan(decimal.NewFromFloat(anomalyHistoryRatio)) {
+		return nil
+	}
+
+	e.addReason(domain.AnomalyCheckSupplierHistory, reference, fmt.Sprintf(
+		"Price %s %s is %s the supplier's recent median of %s %s for this cabin",
+		e.price.StringFixed(anomalyPricePrecision), q.Currency, describeDeviation(factor, above),
+		reference.StringFixed(anomalyPricePrecision), q.Currency))
+	return nil
+}
+
+// checkMarketPrice compares the price with the median current price of the other suppliers
+// for the same sailing and cabin type
+func (e *anomalyEvaluation) checkMarketPrice(ctx context.Context, latest []*domain.PriceQuote) error {
+	q := e.quote
+
+	var prices []decimal.Decimal
+	for _, other := range latest {
+		if other.CabinTypeID != q.CabinTypeID || other.SupplierID == q.SupplierID {
+			continue
+		}
+		price, ok, err := e.perPerson(ctx, other)
+		if err != nil {
+			return err
+		}
+		if ok && price.IsPositive() {
+			prices = append(prices, price)
+		}
+	}
+	if len(prices) < anomalyMinMarketQuotes {
+		return nil
+	}
+
+	reference := medianDecimal(prices)
+	factor, above := e.deviation(reference)
+	if factor.LessThan(decimal.NewFromFloat(anomalyMarketRatio)) {
+		return nil
+	}
+
+	e.addReason(domain.AnomalyCheckMarketPrice, reference, fmt.Sprintf(
+		"Price %s %s is %s the median of %d other suppliers (%s %s) for this cabin",
+		e.price.StringFixed(anomalyPricePrecision), q.Currency, describeDeviation(factor, above),
+		len(prices), reference.StringFixed(anomalyPricePrecision), q.Currency))
+	return nil
+}
+
+// checkCategoryOrder compares the price with the supplier's current prices for the other
+// cabin categories of the sailing: it should not undercut the cheapest cabin of a lower
+// category, e.g. a suite cheaper than an interior cabin, nor exceed the cheapest cabin of a
+// higher one
+func (e *anomalyEvaluation) checkCategoryOrder(ctx context.Context, latest []*domain.PriceQuote) error {
+	q := e.quote
+
+	cabinType, err := e.service.cabinRepo.GetByID(ctx, q.CabinTypeID)
+	if err != nil {
+		return err
+	}
+	if cabinType == nil {
+		return nil
+	}
+
+	cabinTypes, err := e.service.cabinRepo.ListByShip(ctx, cabinType.ShipID)
+	if err != nil {
+		return err
+	}
+	categoryOf := make(map[uint64]uint64, len(cabinTypes))
+	for _, ct := range cabinTypes {
+		categoryOf[ct.ID] = ct.CategoryID
+	}
+
+	categories, err := e.service.categoryRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint64]*domain.CabinCategory, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+
+	own := byID[cabinType.CategoryID]
+	if own == nil {
+		return nil
+	}
+
+	// Cheapest current price of the supplier per other category
+	lowest := make(map[uint64]decimal.Decimal)
+	for _, other := range latest {
+		if other.SupplierID != q.SupplierID {
+			continue
+		}
+		categoryID, ok := categoryOf[other.CabinTypeID]
+		if !ok || categoryID == own.ID {
+			continue
+		}
+		price, ok, err := e.perPerson(ctx, other)
+		if err != nil {
+			return err
+		}
+		if !ok || !price.IsPositive() {
+			continue
+		}
+		if current, ok := lowest[categoryID]; !ok || price.LessThan(current) {
+			lowest[categoryID] = price
+		}
+	}
+
+	categoryIDs := make([]uint64, 0, len(lowest))
+	for id := range lowest {
+		categoryIDs = append(categoryIDs, id)
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	for _, id := range categoryIDs {
+		category := byID[id]
+		if category == nil || category.SortOrder == own.SortOrder {
+			continue
+		}
+		reference := lowest[id]
+
+		if category.SortOrder < own.SortOrder && e.price.LessThan(reference) {
+			e.addReason(domain.AnomalyCheckCategoryOrder, reference, fmt.Sprintf(
+				"%s price %s %s is below the supplier's cheapest %s price of %s %s",
+				own.Name, e.price.StringFixed(anomalyPricePrecision), q.Currency,
+				category.Name, reference.StringFixed(anomalyPricePrecision), q.Currency))
+		}
+		if category.SortOrder > own.SortOrder && e.price.GreaterThan(reference) {
+			e.addReason(domain.AnomalyCheckCategoryOrder, reference, fmt.Sprintf(
+				"%s price %s %s is above the supplier's cheapest %s price of %s %s",
+				own.Name, e.price.StringFixed(anomalyPricePrecision), q.Currency,
+				category.Name, reference.StringFixed(anomalyPricePrecision), q.Currency))
+		}
+	}
+
+	return nil
+}
+
+// medianDecimal returns the median of a non-empty list of values
+func medianDecimal(values []decimal.Decimal) decimal.Decimal {
+	sorted := make([]decimal.Decimal, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
+
+// ListFlags returns review flags with their quotes, oldest first. An unset status lists every flag.
+func (s *AnomalyService) ListFlags(ctx context.Context, pagination repo.Pagination, status *domain.ReviewFlagStatus, supplierID, sailingID *uint64) (repo.PaginatedResult[domain.QuoteReviewFlag], error) {
+	result, err := s.flagRepo.List(ctx, pagination, status, supplierID, sailingID)
+	if err != nil {
+		return result, err
+	}
+
+	ids := make([]uint64, len(result.Items))
+	for i := range result.Items {
+		ids[i] = result.Items[i].QuoteID
+	}
+	quotes, err := s.quoteRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+	byID := make(map[uint64]*domain.PriceQuote, len(quotes))
+	for i := range quotes {
+		byID[quotes[i].ID] = &quotes[i]
+	}
+	for i := range result.Items {
+		result.Items[i].Quote = byID[result.Items[i].QuoteID]
+	}
+
+	return result, nil
+}
+
+// GetFlag returns a review flag with its quote
+func (s *AnomalyService) GetFlag(ctx context.Context, id uint64) (*domain.QuoteReviewFlag, error) {
+	flag, err := s.flagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, ErrReviewFlagNotFound
+	}
+
+	flag.Quote, err = s.quoteRepo.GetByID(ctx, flag.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+// ReviewFlag records the decision on an open flag: DISMISSED when the price is fine,
+// CONFIRMED when it is wrong. A confirmed quote is then corrected or voided through the
+// quote endpoints.
+func (s *AnomalyService) ReviewFlag(ctx context.Context, id uint64, status domain.ReviewFlagStatus, note string, userID uint64) (*domain.QuoteReviewFlag, error) {
+	if status != domain.ReviewFlagDismissed && status != domain.ReviewFlagConfirmed {
+		return nil, ErrInvalidReviewStatus
+	}
+
+	old, err := s.flagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, ErrReviewFlagNotFound
+	}
+	if !old.IsOpen() {
+		return nil, ErrReviewFlagClosed
+	}
+
+	note = truncateRunes(strings.TrimSpace(note), reviewNoteMaxLength)
+	updated, err := s.flagRepo.Review(ctx, id, status, note, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrReviewFlagClosed
+	}
+
+	flag, err := s.GetFlag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.audit.LogUpdate(ctx, userID, &flag.SupplierID, domain.EntityTypeReviewFlag, flag.ID, old, flag)
+	return flag, nil
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// Calendar errors
+var (
+	ErrCalendarScopeRequired  = errors.New("calendar needs a ship, cruise line or route")
+	ErrInvalidCalendarWindow  = errors.New("calendar window must not end before it starts")
+	ErrCalendarWindowTooLarge = errors.New("calendar window spans more than 366 days")
+)
+
+// maxCalendarDays is the longest departure window of a price calendar
+const maxCalendarDays = 366
+
+// CalendarService builds departure price calendars: the lowest price per cabin category
+// of every sailing of a ship, cruise line or route in a date window
+type CalendarService struct {
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinTypeRepo  *repo.CabinTypeRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	supplierRepo   *repo.SupplierRepository
+	fx             *FXService
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *CalendarService {
+	return &CalendarService{
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinTypeRepo:  cabinTypeRepo,
+		categoryRepo:   categoryRepo,
+		supplierRepo:   supplierRepo,
+		fx:             fx,
+	}
+}
+
+// CalendarInput represents the input for a departure price calendar. At least one of
+// ShipID, CruiseLineID and Route is required.
+type CalendarInput struct {
+	ShipID          *uint64
+	CruiseLineID    *uint64
+	Route           string // Route keyword
+	From            time.Time
+	To              time.Time
+	CabinCategoryID *uint64    // Optional, only this category
+	DisplayCurrency string     // Optional, defaults to SearchCurrencyDefault
+	Occupancy       *Occupancy // Optional, defaults to two adults
+	UserRole        domain.UserRole
+	UserSupplier    uint64
+}
+
+// CalendarPrice is the lowest normalized price of a cabin category on a sailing
+type CalendarPrice struct {
+	CabinCategoryID   uint64             `json:"cabin_category_id"`
+	CabinCategoryName string             `json:"cabin_category_name"`
+	CabinTypeID       uint64             `json:"cabin_type_id"`
+	CabinTypeName     string             `json:"cabin_type_name"`
+	SupplierID        uint64             `json:"supplier_id"`
+	SupplierName      string             `json:"supplier_name"`
+	QuoteID           uint64             `json:"quote_id"`
+	Price             decimal.Decimal    `json:"price"` // as quoted
+	Currency          string             `json:"currency"`
+	PricingUnit       domain.PricingUnit `json:"pricing_unit"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+
+	// Normalized for the calendar occupancy, in the display currency
+	PerPerson decimal.Decimal `json:"per_person"`
+	PerCabin  decimal.Decimal `json:"per_cabin"`
+	Estimated bool            `json:"estimated"`
+
+	Cheapest bool `json:"cheapest"` // lowest of the category across the calendar
+}
+
+// CalendarSailing is a departure of the calendar
+type CalendarSailing struct {
+	Sailing SailingInfo     `json:"sailing"`
+	Prices  []CalendarPrice `json:"prices"`
+}
+
+// PriceCalendar is the departure price calendar of a ship, cruise line or route
+type PriceCalendar struct {
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	DisplayCurrency string            `json:"display_currency"`
+	Occupancy       Occupancy         `json:"occupancy"`
+	Sailings        []CalendarSailing `json:"sailings"`
+	Warnings        []string          `json:"warnings,omitempty"`
+}
+
+// GetPriceCalendar returns, for every active sailing departing in the window, the lowest
+// current price of each cabin category across the visible suppliers. Prices are compared
+// normalized for the occupancy and converted into the display currency; quotes that cannot
+// be normalized or converted are left out and reported as warnings.
+func (s *CalendarService) GetPriceCalendar(ctx context.Context, input CalendarInput) (*PriceCalendar, error) {
+	if input.ShipID == nil && input.CruiseLineID == nil && input.Route == "" {
+		return nil, ErrCalendarScopeRequired
+	}
+	if input.To.Before(input.From) {
+		return nil, ErrInvalidCalendarWindow
+	}
+	if input.To.Sub(input.From) > maxCalendarDays*24*time.Hour {
+		return nil, ErrCalendarWindowTooLarge
+	}
+
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if displayCurrency == "" {
+		displayCurrency = SearchCurrencyDefault
+	}
+
+	occupancy := Occupancy{Adults: domain.DefaultBaseOccupancy}
+	if input.Occupancy != nil {
+		occupancy = *input.Occupancy
+	}
+	if occupancy.Adults < 1 || occupancy.Children < 0 {
+		return nil, ErrInvalidOccupancy
+	}
+
+	active := domain.SailingStatusActive
+	sailings, err := s.sailingRepo.ListMatching(ctx, repo.SailingSearchFilter{
+		FromDate:     &input.From,
+		ToDate:       &input.To,
+		ShipID:       input.ShipID,
+		CruiseLineID: input.CruiseLineID,
+		RouteKeyword: input.Route,
+		Status:       &active,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PriceCalendar{
+		From:            input.From,
+		To:              input.To,
+		DisplayCurrency: displayCurrency,
+		Occupancy:       occupancy,
+		Sailings:        make([]CalendarSailing, 0, len(sailings)),
+	}
+	if len(sailings) == 0 {
+		return result, nil
+	}
+
+	sailingIDs := make([]uint64, len(sailings))
+	for i := range sailings {
+		sailingIDs[i] = sailings[i].ID
+	}
+
+	quotes, err := s.quoteRepo.ListLatestBySailings(ctx, sailingIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.quoteRepo.LoadOccupancyRates(ctx, quotes); err != nil {
+		return nil, err
+	}
+
+	suppliers, err := visibleSuppliers(ctx, s.supplierRepo, quotes, nil, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, cabinTypes, err := s.loadCalendarCatalog(ctx, sailings)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uint64]string, len(categories))
+	categoryOrder := make(map[uint64]int, len(categories))
+	for _, cc := range categories {
+		categoryNames[cc.ID] = cc.Name
+		categoryOrder[cc.ID] = cc.SortOrder
+	}
+
+	converter := s.fx.NewConverter(displayCurrency)
+	warnings := newWarningSet()
+
+	type priceKey struct{ sailingID, categoryID uint64 }
+	lowest := make(map[priceKey]*CalendarPrice)
+	for i := range quotes {
+		q := &quotes[i]
+		supplier, ok := suppliers[q.SupplierID]
+		if !ok {
+			continue
+		}
+		cabinType, ok := cabinTypes[q.CabinTypeID]
+		if !ok {
+			continue
+		}
+		if input.CabinCategoryID != nil && cabinType.CategoryID != *input.CabinCategoryID {
+			continue
+		}
+
+		normalized, err := NormalizeQuotePrice(q, occupancy, infos[q.SailingID].Nights)
+		if err != nil {
+			warnings.add(fmt.Sprintf("quote %d: %v", q.ID, err))
+			continue
+		}
+		display, err := convertForDisplay(ctx, converter, q, warnings)
+		if err != nil {
+			return nil, err
+		}
+		if display == nil {
+			continue
+		}
+		normalized = normalized.Convert(display.Rate, display.Currency)
+
+		key := priceKey{q.SailingID, cabinType.CategoryID}
+		if current, ok := lowest[key]; ok && !normalized.PerPerson.LessThan(current.PerPerson) {
+			continue
+		}
+		lowest[key] = &CalendarPrice{
+			CabinCategoryID:   cabinType.CategoryID,
+			CabinCategoryName: categoryNames[cabinType.CategoryID],
+			CabinTypeID:       cabinType.ID,
+			CabinTypeName:     cabinType.Name,
+			SupplierID:        supplier.ID,
+			SupplierName:      supplier.Name,
+			QuoteID:           q.ID,
+			Price:             q.Price,
+			Currency:          q.Currency,
+			PricingUnit:       q.PricingUnit,
+			UpdatedAt:         q.CreatedAt,
+			PerPerson:         normalized.PerPerson,
+			PerCabin:          normalized.PerCabin,
+			Estimated:         normalized.Estimated,
+		}
+	}
+
+	// Mark the cheapest departure of each category; ties go to the earliest departure
+	cheapest := make(map[uint64]priceKey)
+	for key, price := range lowest {
+		current, ok := cheapest[key.categoryID]
+		if !ok {
+			cheapest[key.categoryID] = key
+			continue
+		}
+		currentPrice := lowest[current].PerPerson
+		if price.PerPerson.LessThan(currentPrice) ||
+			(price.PerPerson.Equal(currentPrice) && infos[key.sailingID].DepartureDate.Before(infos[current.sailingID].DepartureDate)) {
+			cheapest[key.categoryID] = key
+		}
+	}
+	for _, key := range cheapest {
+		price := lowest[key]
+		price.Cheapest = true
+	}
+
+	pricesBySailing := make(map[uint64][]CalendarPrice)
+	for key, price := range lowest {
+		pricesBySailing[key.sailingID] = append(pricesBySailing[key.sailingID], *price)
+	}
+
+	for _, sailing := range sailings {
+		prices := pricesBySailing[sailing.ID]
+		if prices == nil {
+			prices = []CalendarPrice{}
+		}
+		sort.Slice(prices, func(i, j int) bool {
+			if categoryOrder[prices[i].CabinCategoryID] != categoryOrder[prices[j].CabinCategoryID] {
+				return categoryOrder[prices[i].CabinCategoryID] < categoryOrder[prices[j].CabinCategoryID]
+			}
+			return prices[i].CabinCategoryID < prices[j].CabinCategoryID
+		})
+		result.Sailings = append(result.Sailings, CalendarSailing{Sailing: *infos[sailing.ID], Prices: prices})
+	}
+
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// loadCalendarCatalog loads the sailing summaries and the cabin types of the ships of the
+// sailings, one lookup per ship rather than per sailing
+func (s *CalendarService) loadCalendarCatalog(ctx context.Context, sailings []domain.Sailing) (map[uint64]*SailingInfo, map[uint64]*domain.CabinType, error) {
+	infos := make(map[uint64]*SailingInfo, len(sailings))
+	cabinTypes := make(map[uint64]*domain.CabinType)
+	ships := make(map[uint64]*domain.Ship)
+	cruiseLines := make(map[uint64]*domain.CruiseLine)
+
+	for _, sailing := range sailings {
+		info := &SailingInfo{
+			ID:            sailing.ID,
+			SailingCode:   sailing.SailingCode,
+			DepartureDate: sailing.DepartureDate,
+			ReturnDate:    sailing.ReturnDate,
+			Nights:        sailing.Nights,
+			Route:         sailing.Route,
+		}
+		if info.Nights == 0 {
+			info.Nights = sailing.CalculateNights()
+		}
+		infos[sailing.ID] = info
+
+		ship, ok := ships[sailing.ShipID]
+		if !ok {
+			var err error
+			ship, err = s.shipRepo.GetByID(ctx, sailing.ShipID)
+			if err != nil {
+				return nil, nil, err
+			}
+			ships[sailing.ShipID] = ship
+
+			shipCabinTypes, err := s.cabinTypeRepo.ListByShip(ctx, sailing.ShipID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range shipCabinTypes {
+				cabinTypes[shipCabinTypes[i].ID] = &shipCabinTypes[i]
+			}
+		}
+		if ship == nil {
+			continue
+		}
+		info.ShipName = ship.Name
+
+		cruiseLine, ok := cruiseLines[ship.CruiseLineID]
+		if !ok {
+			var err error
+			cruiseLine, err = s.cruiseLineRepo.GetByID(ctx, ship.CruiseLineID)
+			if err != nil {
+				return nil, nil, err
+			}
+			cruiseLines[ship.CruiseLineID] = cruiseLine
+		}
+		if cruiseLine != nil {
+			info.CruiseLineName = cruiseLine.Name
+		}
+	}
+
+	return infos, cabinTypes, nil
+}
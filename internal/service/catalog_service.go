@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"cruise-price-compare/internal/domain"
 	"cruise-price-compare/internal/obs"
 	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
 )
 
 var (
@@ -18,6 +22,9 @@ var (
 	ErrSailingNotFound       = errors.New("sailing not found")
 	ErrSupplierNotFound      = errors.New("supplier not found")
 	ErrDuplicateName         = errors.New("duplicate name")
+	ErrPortNotFound          = errors.New("port not found")
+	ErrDuplicateUNLocode     = errors.New("duplicate UN/LOCODE")
+	ErrPortInUse             = errors.New("port is used by sailing itineraries")
 )
 
 // CatalogService handles catalog operations
@@ -28,8 +35,12 @@ type CatalogService struct {
 	cabinTypeRepo     *repo.CabinTypeRepository
 	sailingRepo       *repo.SailingRepository
 	supplierRepo      *repo.SupplierRepository
+	portRepo          *repo.PortRepository
+	embeddings        *EmbeddingIndexService
 	audit             *obs.AuditService
 	logger            *obs.Logger
+	normalizer        *NameNormalizer
+	fx                *FXService
 }
 
 // NewCatalogService creates a new catalog service
@@ -40,8 +51,11 @@ func NewCatalogService(
 	cabinTypeRepo *repo.CabinTypeRepository,
 	sailingRepo *repo.SailingRepository,
 	supplierRepo *repo.SupplierRepository,
+	portRepo *repo.PortRepository,
+	embeddings *EmbeddingIndexService,
 	audit *obs.AuditService,
 	logger *obs.Logger,
+	fx *FXService,
 ) *CatalogService {
 	return &CatalogService{
 		cruiseLineRepo:    cruiseLineRepo,
@@ -50,8 +64,12 @@ func NewCatalogService(
 		cabinTypeRepo:     cabinTypeRepo,
 		sailingRepo:       sailingRepo,
 		supplierRepo:      supplierRepo,
+		portRepo:          portRepo,
+		embeddings:        embeddings,
 		audit:             audit,
 		logger:            logger,
+		normalizer:        NewNameNormalizer(),
+		fx:                fx,
 	}
 }
 
@@ -78,7 +96,8 @@ func (s *CatalogService) CreateCruiseLine(ctx context.Context, userID uint64, cl
 	createdBy := userID
 	cl.CreatedBy = &createdBy
 
-	if err := s.cruiseLineRepo.Create(ctx, cl); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCruiseLine, domain.DomainEventActionCreated, 0, userID, cl)
+	if err := s.cruiseLineRepo.Create(ctx, cl, event); err != nil {
 		return fmt.Errorf("failed to create cruise line: %w", err)
 	}
 
@@ -103,7 +122,8 @@ func (s *CatalogService) UpdateCruiseLine(ctx context.Context, userID uint64, cl
 		return ErrDuplicateName
 	}
 
-	if err := s.cruiseLineRepo.Update(ctx, cl); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCruiseLine, domain.DomainEventActionUpdated, cl.ID, userID, cl)
+	if err := s.cruiseLineRepo.Update(ctx, cl, event); err != nil {
 		return fmt.Errorf("failed to update cruise line: %w", err)
 	}
 
@@ -120,7 +140,8 @@ func (s *CatalogService) DeleteCruiseLine(ctx context.Context, userID uint64, id
 		return ErrCruiseLineNotFound
 	}
 
-	if err := s.cruiseLineRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCruiseLine, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.cruiseLineRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete cruise line: %w", err)
 	}
 
@@ -155,11 +176,13 @@ func (s *CatalogService) CreateShip(ctx context.Context, userID uint64, ship *do
 	createdBy := userID
 	ship.CreatedBy = &createdBy
 
-	if err := s.shipRepo.Create(ctx, ship); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeShip, domain.DomainEventActionCreated, 0, userID, ship)
+	if err := s.shipRepo.Create(ctx, ship, event); err != nil {
 		return fmt.Errorf("failed to create ship: %w", err)
 	}
 
 	_ = s.audit.LogCreate(ctx, userID, nil, domain.EntityTypeShip, ship.ID, ship)
+	s.refreshEmbedding(ctx, domain.EntityTypeShip, ship.ID, s.embeddings.IndexShip(ctx, ship))
 	return nil
 }
 
@@ -180,11 +203,13 @@ func (s *CatalogService) UpdateShip(ctx context.Context, userID uint64, ship *do
 		return ErrDuplicateName
 	}
 
-	if err := s.shipRepo.Update(ctx, ship); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeShip, domain.DomainEventActionUpdated, ship.ID, userID, ship)
+	if err := s.shipRepo.Update(ctx, ship, event); err != nil {
 		return fmt.Errorf("failed to update ship: %w", err)
 	}
 
 	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeShip, ship.ID, old, ship)
+	s.refreshEmbedding(ctx, domain.EntityTypeShip, ship.ID, s.embeddings.IndexShip(ctx, ship))
 	return nil
 }
 
@@ -197,11 +222,13 @@ func (s *CatalogService) DeleteShip(ctx context.Context, userID uint64, id uint6
 		return ErrShipNotFound
 	}
 
-	if err := s.shipRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeShip, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.shipRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete ship: %w", err)
 	}
 
 	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeShip, id, old)
+	s.refreshEmbedding(ctx, domain.EntityTypeShip, id, s.embeddings.Remove(ctx, domain.EntityTypeShip, id))
 	return nil
 }
 
@@ -224,11 +251,13 @@ func (s *CatalogService) CreateCabinCategory(ctx context.Context, userID uint64,
 		return ErrDuplicateName
 	}
 
-	if err := s.cabinCategoryRepo.Create(ctx, cc); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinCategory, domain.DomainEventActionCreated, 0, userID, cc)
+	if err := s.cabinCategoryRepo.Create(ctx, cc, event); err != nil {
 		return fmt.Errorf("failed to create cabin category: %w", err)
 	}
 
 	_ = s.audit.LogCreate(ctx, userID, nil, domain.EntityTypeCabinCategory, cc.ID, cc)
+	s.refreshEmbedding(ctx, domain.EntityTypeCabinCategory, cc.ID, s.embeddings.IndexCabinCategory(ctx, cc))
 	return nil
 }
 
@@ -241,11 +270,13 @@ func (s *CatalogService) UpdateCabinCategory(ctx context.Context, userID uint64,
 		return ErrCabinCategoryNotFound
 	}
 
-	if err := s.cabinCategoryRepo.Update(ctx, cc); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinCategory, domain.DomainEventActionUpdated, cc.ID, userID, cc)
+	if err := s.cabinCategoryRepo.Update(ctx, cc, event); err != nil {
 		return fmt.Errorf("failed to update cabin category: %w", err)
 	}
 
 	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeCabinCategory, cc.ID, old, cc)
+	s.refreshEmbedding(ctx, domain.EntityTypeCabinCategory, cc.ID, s.embeddings.IndexCabinCategory(ctx, cc))
 	return nil
 }
 
@@ -258,11 +289,13 @@ func (s *CatalogService) DeleteCabinCategory(ctx context.Context, userID uint64,
 		return ErrCabinCategoryNotFound
 	}
 
-	if err := s.cabinCategoryRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinCategory, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.cabinCategoryRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete cabin category: %w", err)
 	}
 
 	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeCabinCategory, id, old)
+	s.refreshEmbedding(ctx, domain.EntityTypeCabinCategory, id, s.embeddings.Remove(ctx, domain.EntityTypeCabinCategory, id))
 	return nil
 }
 
@@ -283,11 +316,13 @@ func (s *CatalogService) ListCabinTypesByShip(ctx context.Context, shipID uint64
 func (s *CatalogService) CreateCabinType(ctx context.Context, userID uint64, ct *domain.CabinType) error {
 	ct.IsEnabled = true
 
-	if err := s.cabinTypeRepo.Create(ctx, ct); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinType, domain.DomainEventActionCreated, 0, userID, ct)
+	if err := s.cabinTypeRepo.Create(ctx, ct, event); err != nil {
 		return fmt.Errorf("failed to create cabin type: %w", err)
 	}
 
 	_ = s.audit.LogCreate(ctx, userID, nil, domain.EntityTypeCabinType, ct.ID, ct)
+	s.refreshEmbedding(ctx, domain.EntityTypeCabinType, ct.ID, s.embeddings.IndexCabinType(ctx, ct))
 	return nil
 }
 
@@ -300,11 +335,17 @@ func (s *CatalogService) UpdateCabinType(ctx context.Context, userID uint64, ct
 		return ErrCabinTypeNotFound
 	}
 
-	if err := s.cabinTypeRepo.Update(ctx, ct); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinType, domain.DomainEventActionUpdated, ct.ID, userID, ct)
+	if err := s.cabinTypeRepo.Update(ctx, ct, event); err != nil {
 		return fmt.Errorf("failed to update cabin type: %w", err)
 	}
 
 	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeCabinType, ct.ID, old, ct)
+	if ct.IsEnabled {
+		s.refreshEmbedding(ctx, domain.EntityTypeCabinType, ct.ID, s.embeddings.IndexCabinType(ctx, ct))
+	} else {
+		s.refreshEmbedding(ctx, domain.EntityTypeCabinType, ct.ID, s.embeddings.Remove(ctx, domain.EntityTypeCabinType, ct.ID))
+	}
 	return nil
 }
 
@@ -317,30 +358,300 @@ func (s *CatalogService) DeleteCabinType(ctx context.Context, userID uint64, id
 		return ErrCabinTypeNotFound
 	}
 
-	if err := s.cabinTypeRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeCabinType, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.cabinTypeRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete cabin type: %w", err)
 	}
 
 	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeCabinType, id, old)
+	s.refreshEmbedding(ctx, domain.EntityTypeCabinType, id, s.embeddings.Remove(ctx, domain.EntityTypeCabinType, id))
 	return nil
 }
 
 // Sailing operations
 
+// GetSailing retrieves a sailing with its itinerary
 func (s *CatalogService) GetSailing(ctx context.Context, id uint64) (*domain.Sailing, error) {
-	return s.sailingRepo.GetByID(ctx, id)
+	sailing, err := s.sailingRepo.GetByID(ctx, id)
+	if err != nil || sailing == nil {
+		return sailing, err
+	}
+
+	sailing.Itinerary, err = s.loadItinerary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sailing, nil
 }
 
 func (s *CatalogService) ListSailings(ctx context.Context, pagination repo.Pagination, shipID *uint64, status *domain.SailingStatus) (repo.PaginatedResult[domain.Sailing], error) {
 	return s.sailingRepo.List(ctx, pagination, shipID, status, nil, nil)
 }
 
+// SearchCurrencyDefault is the currency sailing search prices are compared in when none is requested
+const SearchCurrencyDefault = "CNY"
+
+// SailingSearchInput represents the input for a sailing search
+type SailingSearchInput struct {
+	Pagination      repo.Pagination
+	Filter          repo.SailingSearchFilter
+	Currency        string  // Currency prices are compared in, defaults to SearchCurrencyDefault
+	PriceCategoryID *uint64 // Optional, cabin category the lowest price sort compares
+	UserRole        domain.UserRole
+	UserSupplier    uint64 // From auth context (if vendor)
+}
+
+// CategoryLowestPrice is the lowest latest current price of a cabin category on a sailing,
+// compared per person at base occupancy
+type CategoryLowestPrice struct {
+	CabinCategoryID   uint64             `json:"cabin_category_id"`
+	CabinCategoryName string             `json:"cabin_category_name"`
+	CabinTypeID       uint64             `json:"cabin_type_id"`
+	SupplierID        uint64             `json:"supplier_id"`
+	QuoteID           uint64             `json:"quote_id"`
+	Price             decimal.Decimal    `json:"price"`
+	Currency          string             `json:"currency"`
+	PricingUnit       domain.PricingUnit `json:"pricing_unit"`
+	PricePerPerson    decimal.Decimal    `json:"price_per_person"` // In DisplayCurrency
+	DisplayCurrency   string             `json:"display_currency"`
+	Rate              decimal.Decimal    `json:"rate"` // Currency to DisplayCurrency at the quote date
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// SailingSearchItem is a sailing search hit with its lowest price per cabin category
+type SailingSearchItem struct {
+	domain.Sailing
+	LowestPrices []CategoryLowestPrice `json:"lowest_prices"`
+	Warnings     []string              `json:"warnings,omitempty"` // Prices left out for lack of an FX rate
+}
+
+// SearchSailings searches sailings and embeds the lowest latest price of each cabin category.
+// Prices are converted to the search currency at the rate of their quote date; vendors only
+// see their own prices and may only filter by their own or public suppliers.
+func (s *CatalogService) SearchSailings(ctx context.Context, input SailingSearchInput) (repo.PaginatedResult[SailingSearchItem], error) {
+	filter := input.Filter
+	currency, err := NormalizeCurrency(input.Currency)
+	if err != nil {
+		return repo.PaginatedResult[SailingSearchItem]{}, err
+	}
+	if currency == "" {
+		currency = SearchCurrencyDefault
+	}
+
+	var priceSupplierID *uint64
+	if input.UserRole == domain.UserRoleVendor {
+		priceSupplierID = &input.UserSupplier
+	}
+
+	if quotedBy := filter.QuotedBySupplierID; quotedBy != nil {
+		visible, err := visibleSupplierIDs(ctx, s.supplierRepo, []uint64{*quotedBy}, nil, input.UserRole, input.UserSupplier)
+		if err != nil {
+			return repo.PaginatedResult[SailingSearchItem]{}, err
+		}
+		if visible[*quotedBy] == nil {
+			return repo.PaginatedResult[SailingSearchItem]{}, ErrSupplierNotFound
+		}
+	}
+
+	// The lowest price sort needs the converted prices of every matching sailing
+	var sailings []domain.Sailing
+	var total int64
+	if filter.Sort == repo.SailingSortLowestPrice {
+		sailings, err = s.sailingRepo.ListMatching(ctx, filter)
+		if err != nil {
+			return repo.PaginatedResult[SailingSearchItem]{}, err
+		}
+		total = int64(len(sailings))
+	} else {
+		page, err := s.sailingRepo.Search(ctx, input.Pagination, filter)
+		if err != nil {
+			return repo.PaginatedResult[SailingSearchItem]{}, err
+		}
+		sailings, total = page.Items, page.Total
+	}
+
+	sailingIDs := make([]uint64, len(sailings))
+	for i := range sailings {
+		sailingIDs[i] = sailings[i].ID
+	}
+
+	prices, err := s.sailingRepo.ListLatestCategoryPrices(ctx, sailingIDs, priceSupplierID)
+	if err != nil {
+		return repo.PaginatedResult[SailingSearchItem]{}, err
+	}
+
+	categories, err := s.cabinCategoryRepo.List(ctx)
+	if err != nil {
+		return repo.PaginatedResult[SailingSearchItem]{}, err
+	}
+	categoryNames := make(map[uint64]string, len(categories))
+	categoryOrder := make(map[uint64]int, len(categories))
+	for _, cc := range categories {
+		categoryNames[cc.ID] = cc.Name
+		categoryOrder[cc.ID] = cc.SortOrder
+	}
+
+	converter := s.fx.NewConverter(currency)
+	warnings := make(map[uint64]*warningSet)
+	converted := make([]sailingPrice, 0, len(prices))
+	for _, p := range prices {
+		pricedAt := p.QuotedAt
+		if pricedAt.IsZero() {
+			pricedAt = p.CreatedAt
+		}
+		display, err := converter.Convert(ctx, p.PerPersonPrice, p.Currency, pricedAt)
+		if errors.Is(err, ErrFXRateNotFound) {
+			if warnings[p.SailingID] == nil {
+				warnings[p.SailingID] = newWarningSet()
+			}
+			warnings[p.SailingID].add(err.Error())
+			continue
+		}
+		if err != nil {
+			return repo.PaginatedResult[SailingSearchItem]{}, err
+		}
+
+		converted = append(converted, sailingPrice{SailingID: p.SailingID, Price: CategoryLowestPrice{
+			CabinCategoryID:   p.CabinCategoryID,
+			CabinCategoryName: categoryNames[p.CabinCategoryID],
+			CabinTypeID:       p.CabinTypeID,
+			SupplierID:        p.SupplierID,
+			QuoteID:           p.QuoteID,
+			Price:             p.Price,
+			Currency:          p.Currency,
+			PricingUnit:       domain.PricingUnit(p.PricingUnit),
+			PricePerPerson:    display.Amount,
+			DisplayCurrency:   display.Currency,
+			Rate:              display.Rate,
+			UpdatedAt:         p.QuotedAt,
+		}})
+	}
+	lowest := lowestCategoryPrices(converted, categoryOrder)
+
+	if filter.Sort == repo.SailingSortLowestPrice {
+		sortByLowestPrice(sailings, lowest, input.PriceCategoryID, filter.SortDesc)
+		sailings = paginate(sailings, input.Pagination)
+	}
+
+	items := make([]SailingSearchItem, len(sailings))
+	ships := make(map[uint64]*domain.Ship)
+	for i, sailing := range sailings {
+		ship, ok := ships[sailing.ShipID]
+		if !ok {
+			ship, err = s.shipRepo.GetByID(ctx, sailing.ShipID)
+			if err != nil {
+				return repo.PaginatedResult[SailingSearchItem]{}, err
+			}
+			ships[sailing.ShipID] = ship
+		}
+		sailing.Ship = ship
+
+		sailingPrices := lowest[sailing.ID]
+		if sailingPrices == nil {
+			sailingPrices = []CategoryLowestPrice{}
+		}
+		items[i] = SailingSearchItem{Sailing: sailing, LowestPrices: sailingPrices}
+		if w := warnings[sailing.ID]; w != nil {
+			items[i].Warnings = w.list()
+		}
+	}
+
+	return repo.NewPaginatedResult(items, total, input.Pagination), nil
+}
+
+// sailingPrice is a price of a sailing converted to the search currency
+type sailingPrice struct {
+	SailingID uint64
+	Price     CategoryLowestPrice
+}
+
+// lowestCategoryPrices keeps the cheapest per person of the prices of each sailing + cabin
+// category, ties going to the lower quote ID, and orders each sailing's prices by category
+func lowestCategoryPrices(prices []sailingPrice, categoryOrder map[uint64]int) map[uint64][]CategoryLowestPrice {
+	type priceKey struct{ sailingID, categoryID uint64 }
+	index := make(map[priceKey]int)
+	lowest := make(map[uint64][]CategoryLowestPrice)
+	for _, sp := range prices {
+		p := sp.Price
+		key := priceKey{sp.SailingID, p.CabinCategoryID}
+		j, ok := index[key]
+		if !ok {
+			index[key] = len(lowest[key.sailingID])
+			lowest[key.sailingID] = append(lowest[key.sailingID], p)
+			continue
+		}
+		current := &lowest[key.sailingID][j]
+		if p.PricePerPerson.LessThan(current.PricePerPerson) ||
+			(p.PricePerPerson.Equal(current.PricePerPerson) && p.QuoteID < current.QuoteID) {
+			*current = p
+		}
+	}
+
+	for _, sailingPrices := range lowest {
+		sort.SliceStable(sailingPrices, func(a, b int) bool {
+			return categoryOrder[sailingPrices[a].CabinCategoryID] < categoryOrder[sailingPrices[b].CabinCategoryID]
+		})
+	}
+	return lowest
+}
+
+// sortByLowestPrice sorts sailings by their lowest price per person, of categoryID when set.
+// Sailings without a price come last; ties keep departure order.
+func sortByLowestPrice(sailings []domain.Sailing, lowest map[uint64][]CategoryLowestPrice, categoryID *uint64, desc bool) {
+	lowestOf := func(sailingID uint64) (decimal.Decimal, bool) {
+		var cheapest decimal.Decimal
+		found := false
+		for _, p := range lowest[sailingID] {
+			if categoryID != nil && p.CabinCategoryID != *categoryID {
+				continue
+			}
+			if !found || p.PricePerPerson.LessThan(cheapest) {
+				cheapest, found = p.PricePerPerson, true
+			}
+		}
+		return cheapest, found
+	}
+
+	sort.SliceStable(sailings, func(a, b int) bool {
+		pa, okA := lowestOf(sailings[a].ID)
+		pb, okB := lowestOf(sailings[b].ID)
+		if okA != okB {
+			return okA
+		}
+		if okA && !pa.Equal(pb) {
+			if desc {
+				return pa.GreaterThan(pb)
+			}
+			return pa.LessThan(pb)
+		}
+		if !sailings[a].DepartureDate.Equal(sailings[b].DepartureDate) {
+			return sailings[a].DepartureDate.Before(sailings[b].DepartureDate)
+		}
+		return sailings[a].ID < sailings[b].ID
+	})
+}
+
+// paginate returns the page of items
+func paginate[T any](items []T, pagination repo.Pagination) []T {
+	offset := pagination.Offset()
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + pagination.Limit()
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
 func (s *CatalogService) CreateSailing(ctx context.Context, userID uint64, sailing *domain.Sailing) error {
 	sailing.Status = domain.SailingStatusActive
 	createdBy := userID
 	sailing.CreatedBy = &createdBy
 
-	if err := s.sailingRepo.Create(ctx, sailing); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSailing, domain.DomainEventActionCreated, 0, userID, sailing)
+	if err := s.sailingRepo.Create(ctx, sailing, event); err != nil {
 		return fmt.Errorf("failed to create sailing: %w", err)
 	}
 
@@ -357,7 +668,8 @@ func (s *CatalogService) UpdateSailing(ctx context.Context, userID uint64, saili
 		return ErrSailingNotFound
 	}
 
-	if err := s.sailingRepo.Update(ctx, sailing); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSailing, domain.DomainEventActionUpdated, sailing.ID, userID, sailing)
+	if err := s.sailingRepo.Update(ctx, sailing, event); err != nil {
 		return fmt.Errorf("failed to update sailing: %w", err)
 	}
 
@@ -374,7 +686,8 @@ func (s *CatalogService) DeleteSailing(ctx context.Context, userID uint64, id ui
 		return ErrSailingNotFound
 	}
 
-	if err := s.sailingRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSailing, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.sailingRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete sailing: %w", err)
 	}
 
@@ -382,6 +695,213 @@ func (s *CatalogService) DeleteSailing(ctx context.Context, userID uint64, id ui
 	return nil
 }
 
+// GetSailingItinerary retrieves the itinerary of a sailing with its ports
+func (s *CatalogService) GetSailingItinerary(ctx context.Context, sailingID uint64) ([]domain.ItineraryStop, error) {
+	sailing, err := s.sailingRepo.GetByID(ctx, sailingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sailing: %w", err)
+	}
+	if sailing == nil {
+		return nil, ErrSailingNotFound
+	}
+
+	return s.loadItinerary(ctx, sailingID)
+}
+
+// SetSailingItinerary replaces the itinerary of a sailing. The ports of the sailing are
+// rewritten from the itinerary so the plain port list agrees with it.
+func (s *CatalogService) SetSailingItinerary(ctx context.Context, userID uint64, sailingID uint64, stops []domain.ItineraryStop) ([]domain.ItineraryStop, error) {
+	sailing, err := s.sailingRepo.GetByID(ctx, sailingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sailing: %w", err)
+	}
+	if sailing == nil {
+		return nil, ErrSailingNotFound
+	}
+
+	if errs := domain.ValidateItinerary(stops, sailing.Nights); errs.HasErrors() {
+		return nil, errs
+	}
+
+	ports, err := s.portRepo.ListByIDs(ctx, domain.ItineraryPortIDs(stops))
+	if err != nil {
+		return nil, err
+	}
+	portsByID := make(map[uint64]*domain.Port, len(ports))
+	for i := range ports {
+		portsByID[ports[i].ID] = &ports[i]
+	}
+
+	portNames := []string{}
+	for i := range stops {
+		if stops[i].PortID == nil {
+			continue
+		}
+		port, ok := portsByID[*stops[i].PortID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrPortNotFound, *stops[i].PortID)
+		}
+		stops[i].Port = port
+		if len(portNames) == 0 || portNames[len(portNames)-1] != port.Name {
+			portNames = append(portNames, port.Name)
+		}
+	}
+
+	old, err := s.sailingRepo.ListItinerary(ctx, sailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	sailing.Itinerary = stops
+	sailing.Ports = portNames
+	event := catalogEvent(ctx, domain.EntityTypeSailing, domain.DomainEventActionUpdated, sailingID, userID, sailing)
+	if err := s.sailingRepo.ReplaceItinerary(ctx, sailingID, stops, portNames, event); err != nil {
+		return nil, fmt.Errorf("failed to replace itinerary: %w", err)
+	}
+
+	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeSailing, sailingID,
+		map[string]interface{}{"itinerary": old}, map[string]interface{}{"itinerary": stops})
+	return stops, nil
+}
+
+// loadItinerary retrieves the itinerary of a sailing and attaches the port of each stop
+func (s *CatalogService) loadItinerary(ctx context.Context, sailingID uint64) ([]domain.ItineraryStop, error) {
+	stops, err := s.sailingRepo.ListItinerary(ctx, sailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := s.portRepo.ListByIDs(ctx, domain.ItineraryPortIDs(stops))
+	if err != nil {
+		return nil, err
+	}
+	portsByID := make(map[uint64]*domain.Port, len(ports))
+	for i := range ports {
+		portsByID[ports[i].ID] = &ports[i]
+	}
+	for i := range stops {
+		if stops[i].PortID != nil {
+			stops[i].Port = portsByID[*stops[i].PortID]
+		}
+	}
+
+	if stops == nil {
+		stops = []domain.ItineraryStop{}
+	}
+	return stops, nil
+}
+
+// Port operations
+
+func (s *CatalogService) GetPort(ctx context.Context, id uint64) (*domain.Port, error) {
+	return s.portRepo.GetByID(ctx, id)
+}
+
+func (s *CatalogService) ListPorts(ctx context.Context, pagination repo.Pagination, country string, status *domain.EntityStatus) (repo.PaginatedResult[domain.Port], error) {
+	return s.portRepo.List(ctx, pagination, country, status)
+}
+
+func (s *CatalogService) CreatePort(ctx context.Context, userID uint64, port *domain.Port) error {
+	if err := s.checkPortUnique(ctx, port, nil); err != nil {
+		return err
+	}
+
+	port.Status = domain.EntityStatusActive
+	createdBy := userID
+	port.CreatedBy = &createdBy
+
+	event := catalogEvent(ctx, domain.EntityTypePort, domain.DomainEventActionCreated, 0, userID, port)
+	if err := s.portRepo.Create(ctx, port, event); err != nil {
+		return fmt.Errorf("failed to create port: %w", err)
+	}
+
+	_ = s.audit.LogCreate(ctx, userID, nil, domain.EntityTypePort, port.ID, port)
+	return nil
+}
+
+func (s *CatalogService) UpdatePort(ctx context.Context, userID uint64, port *domain.Port) error {
+	old, err := s.portRepo.GetByID(ctx, port.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get port: %w", err)
+	}
+	if old == nil {
+		return ErrPortNotFound
+	}
+
+	if err := s.checkPortUnique(ctx, port, &port.ID); err != nil {
+		return err
+	}
+	if port.Status == "" {
+		port.Status = old.Status
+	}
+
+	event := catalogEvent(ctx, domain.EntityTypePort, domain.DomainEventActionUpdated, port.ID, userID, port)
+	if err := s.portRepo.Update(ctx, port, event); err != nil {
+		return fmt.Errorf("failed to update port: %w", err)
+	}
+
+	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypePort, port.ID, old, port)
+	return nil
+}
+
+// DeletePort deletes a port no itinerary calls at; ports in use can only be deactivated
+func (s *CatalogService) DeletePort(ctx context.Context, userID uint64, id uint64) error {
+	old, err := s.portRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get port: %w", err)
+	}
+	if old == nil {
+		return ErrPortNotFound
+	}
+
+	stops, err := s.portRepo.CountItineraryStops(ctx, id)
+	if err != nil {
+		return err
+	}
+	if stops > 0 {
+		return ErrPortInUse
+	}
+
+	event := catalogEvent(ctx, domain.EntityTypePort, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.portRepo.Delete(ctx, id, event); err != nil {
+		return fmt.Errorf("failed to delete port: %w", err)
+	}
+
+	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypePort, id, old)
+	return nil
+}
+
+// checkPortUnique checks that no other port has the name or UN/LOCODE of port
+func (s *CatalogService) checkPortUnique(ctx context.Context, port *domain.Port, excludeID *uint64) error {
+	exists, err := s.portRepo.ExistsByName(ctx, port.Name, excludeID)
+	if err != nil {
+		return fmt.Errorf("failed to check port exists: %w", err)
+	}
+	if exists {
+		return ErrDuplicateName
+	}
+
+	exists, err = s.portRepo.ExistsByUNLocode(ctx, port.UNLocode, excludeID)
+	if err != nil {
+		return fmt.Errorf("failed to check port exists: %w", err)
+	}
+	if exists {
+		return ErrDuplicateUNLocode
+	}
+
+	return nil
+}
+
+// ResolvePort finds the active port a name refers to, by name, English name, alias or
+// UN/LOCODE after normalization; nil when no port matches
+func (s *CatalogService) ResolvePort(ctx context.Context, name string) (*domain.Port, error) {
+	ports, err := s.portRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findPortByName(s.normalizer, ports, name), nil
+}
+
 // Supplier operations
 
 func (s *CatalogService) GetSupplier(ctx context.Context, id uint64) (*domain.Supplier, error) {
@@ -405,7 +925,8 @@ func (s *CatalogService) CreateSupplier(ctx context.Context, userID uint64, supp
 	createdBy := userID
 	supplier.CreatedBy = &createdBy
 
-	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSupplier, domain.DomainEventActionCreated, 0, userID, supplier)
+	if err := s.supplierRepo.Create(ctx, supplier, event); err != nil {
 		return fmt.Errorf("failed to create supplier: %w", err)
 	}
 
@@ -430,7 +951,8 @@ func (s *CatalogService) UpdateSupplier(ctx context.Context, userID uint64, supp
 		return ErrDuplicateName
 	}
 
-	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSupplier, domain.DomainEventActionUpdated, supplier.ID, userID, supplier)
+	if err := s.supplierRepo.Update(ctx, supplier, event); err != nil {
 		return fmt.Errorf("failed to update supplier: %w", err)
 	}
 
@@ -447,10 +969,98 @@ func (s *CatalogService) DeleteSupplier(ctx context.Context, userID uint64, id u
 		return ErrSupplierNotFound
 	}
 
-	if err := s.supplierRepo.Delete(ctx, id); err != nil {
+	event := catalogEvent(ctx, domain.EntityTypeSupplier, domain.DomainEventActionDeleted, id, userID, old)
+	if err := s.supplierRepo.Delete(ctx, id, event); err != nil {
 		return fmt.Errorf("failed to delete supplier: %w", err)
 	}
 
 	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeSupplier, id, old)
 	return nil
 }
+
+// refreshEmbedding reports a failed embedding index refresh. Catalog writes do not
+// fail on it: the entity stays matchable lexically until the next index rebuild.
+func (s *CatalogService) refreshEmbedding(ctx context.Context, entityType string, entityID uint64, err error) {
+	if err == nil {
+		return
+	}
+	s.logger.WithContext(ctx).WithError(err).WithFields(map[string]any{
+		"entity_type": entityType,
+		"entity_id":   entityID,
+	}).Warn("Failed to refresh catalog embedding")
+}
+
+// Search operations
+
+// CatalogSearchHit is a single catalog entity matched by Search
+type CatalogSearchHit struct {
+	EntityType string  `json:"entity_type"`
+	ID         uint64  `json:"id"`
+	Name       string  `json:"name"`
+	NameEN     string  `json:"name_en,omitempty"`
+	ParentID   *uint64 `json:"parent_id,omitempty"` // cruise line of a ship
+	Score      float64 `json:"score"`
+}
+
+// catalogSearchMinScore is the lowest similarity returned by Search
+const catalogSearchMinScore = 0.6
+
+// Search finds cruise lines, ships and cabin categories by name.
+// Names are compared after full-width, traditional/simplified and pinyin
+// normalization, against both the Chinese name, the English name and aliases.
+func (s *CatalogService) Search(ctx context.Context, keyword string, limit int) ([]CatalogSearchHit, error) {
+	if s.normalizer.Normalize(keyword) == "" {
+		return []CatalogSearchHit{}, nil
+	}
+
+	hits := []CatalogSearchHit{}
+	add := func(hit CatalogSearchHit, candidates ...string) {
+		hit.Score = s.normalizer.BestSimilarity(keyword, candidates...)
+		if hit.Score >= catalogSearchMinScore {
+			hits = append(hits, hit)
+		}
+	}
+
+	cruiseLines, err := s.cruiseLineRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cruise lines: %w", err)
+	}
+	for _, cl := range cruiseLines {
+		add(CatalogSearchHit{EntityType: domain.EntityTypeCruiseLine, ID: cl.ID, Name: cl.Name, NameEN: cl.NameEN},
+			append([]string{cl.Name, cl.NameEN}, cl.Aliases...)...)
+	}
+
+	ships, err := s.shipRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ships: %w", err)
+	}
+	for _, ship := range ships {
+		cruiseLineID := ship.CruiseLineID
+		add(CatalogSearchHit{EntityType: domain.EntityTypeShip, ID: ship.ID, Name: ship.Name, NameEN: ship.NameEN, ParentID: &cruiseLineID},
+			append([]string{ship.Name, ship.NameEN}, ship.Aliases...)...)
+	}
+
+	categories, err := s.cabinCategoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cabin categories: %w", err)
+	}
+	for _, cc := range categories {
+		add(CatalogSearchHit{EntityType: domain.EntityTypeCabinCategory, ID: cc.ID, Name: cc.Name, NameEN: cc.NameEN},
+			cc.Name, cc.NameEN)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+// catalogEvent creates the outbox event of a catalog entity change; aggregateID is zero for
+// an entity being created
+func catalogEvent(ctx context.Context, entityType, action string, aggregateID uint64, userID uint64, data interface{}) *domain.DomainEvent {
+	return newDomainEvent(ctx, domain.EntityEventType(entityType, action), entityType, aggregateID, userID, nil, data)
+}
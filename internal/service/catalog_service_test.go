@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLowestCategoryPrices(t *testing.T) {
+	price := func(sailingID, categoryID, quoteID uint64, perPerson string) sailingPrice {
+		return sailingPrice{SailingID: sailingID, Price: CategoryLowestPrice{
+			CabinCategoryID: categoryID,
+			QuoteID:         quoteID,
+			PricePerPerson:  decimal.RequireFromString(perPerson),
+		}}
+	}
+	categoryOrder := map[uint64]int{1: 2, 2: 1}
+
+	lowest := lowestCategoryPrices([]sailingPrice{
+		price(10, 1, 1, "5000"),
+		price(10, 1, 2, "4200"), // converted from another currency, cheaper
+		price(10, 2, 3, "8000"),
+		price(10, 2, 4, "8000"), // tie keeps the lower quote ID
+		price(11, 1, 5, "3000"),
+	}, categoryOrder)
+
+	got := lowest[10]
+	if len(got) != 2 {
+		t.Fatalf("sailing 10 has %d prices, want 2", len(got))
+	}
+	if got[0].CabinCategoryID != 2 || got[0].QuoteID != 3 {
+		t.Errorf("first price = category %d quote %d, want category 2 quote 3", got[0].CabinCategoryID, got[0].QuoteID)
+	}
+	if got[1].CabinCategoryID != 1 || got[1].QuoteID != 2 {
+		t.Errorf("second price = category %d quote %d, want category 1 quote 2", got[1].CabinCategoryID, got[1].QuoteID)
+	}
+	if len(lowest[11]) != 1 || lowest[11][0].QuoteID != 5 {
+		t.Errorf("sailing 11 prices = %+v, want quote 5", lowest[11])
+	}
+}
+
+func TestSortByLowestPrice(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.UTC) }
+	sailings := func() []domain.Sailing {
+		return []domain.Sailing{
+			{ID: 1, DepartureDate: day(1)},
+			{ID: 2, DepartureDate: day(2)},
+			{ID: 3, DepartureDate: day(3)},
+			{ID: 4, DepartureDate: day(4)},
+		}
+	}
+	price := func(categoryID uint64, perPerson string) CategoryLowestPrice {
+		return CategoryLowestPrice{CabinCategoryID: categoryID, PricePerPerson: decimal.RequireFromString(perPerson)}
+	}
+	lowest := map[uint64][]CategoryLowestPrice{
+		1: {price(1, "6000")},
+		2: {price(1, "4000"), price(2, "9000")},
+		4: {price(1, "6000"), price(2, "7000")},
+	}
+	category2 := uint64(2)
+
+	tests := []struct {
+		name       string
+		categoryID *uint64
+		desc       bool
+		want       []uint64
+	}{
+		{"ascending", nil, false, []uint64{2, 1, 4, 3}},
+		{"descending", nil, true, []uint64{1, 4, 2, 3}},
+		{"one category", &category2, false, []uint64{4, 2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sailings()
+			sortByLowestPrice(got, lowest, tt.categoryID, tt.desc)
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Fatalf("position %d = sailing %d, want %d", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name       string
+		pagination repo.Pagination
+		want       []int
+	}{
+		{"first page", repo.Pagination{Page: 1, PageSize: 2}, []int{1, 2}},
+		{"last page", repo.Pagination{Page: 3, PageSize: 2}, []int{5}},
+		{"past the end", repo.Pagination{Page: 4, PageSize: 2}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := paginate(items, tt.pagination)
+			if len(got) != len(tt.want) {
+				t.Fatalf("paginate() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("paginate() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package service
+
+// The traditional-to-simplified character table, traditionalToSimplified, is generated
+// from the OpenCC dictionary; regenerate it with an OpenCC checkout in OPENCC_DIR:
+//go:generate go run gen_chinese_variants.go -src ${OPENCC_DIR}/data/dictionary/TSCharacters.txt
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidDiffWindow is returned when a comparison diff does not go forward in time
+var ErrInvalidDiffWindow = errors.New("comparison diff must end after it starts")
+
+// ComparisonChangeType classifies how a comparison cell changed between two times
+type ComparisonChangeType string
+
+const (
+	ComparisonChangeAdded     ComparisonChangeType = "ADDED"     // no price before, a price after
+	ComparisonChangeWithdrawn ComparisonChangeType = "WITHDRAWN" // a price before, none after
+	ComparisonChangeIncreased ComparisonChangeType = "INCREASED"
+	ComparisonChangeDecreased ComparisonChangeType = "DECREASED"
+	ComparisonChangeRequoted  ComparisonChangeType = "REQUOTED" // new quote, same or incomparable price
+)
+
+// ComparisonDiffInput represents the input for the diff of a sailing comparison between two
+// times; the other fields apply to both sides
+type ComparisonDiffInput struct {
+	ComparisonInput
+	From time.Time
+	To   time.Time
+}
+
+// ComparisonCellChange is a cabin type + supplier cell that differs between the two times.
+// Change is in the quote currency when both quotes share currency and pricing unit;
+// DisplayChange is in the display currency when both quotes share the pricing unit.
+type ComparisonCellChange struct {
+	CabinTypeID       uint64               `json:"cabin_type_id"`
+	CabinTypeName     string               `json:"cabin_type_name"`
+	CabinCategoryName string               `json:"cabin_category_name"`
+	SupplierID        uint64               `json:"supplier_id"`
+	SupplierName      string               `json:"supplier_name"`
+	Type              ComparisonChangeType `json:"type"`
+	Before            *CabinPriceCell      `json:"before,omitempty"`
+	After             *CabinPriceCell      `json:"after,omitempty"`
+	Change            *decimal.Decimal     `json:"change,omitempty"`
+	DisplayChange     *decimal.Decimal     `json:"display_change,omitempty"`
+
+	// Withdrawn cells only: why the earlier price no longer shows (VOIDED, CORRECTED or EXPIRED),
+	// empty when it was superseded by a quote the comparison filters out
+	Reason domain.QuoteStatus `json:"reason,omitempty"`
+}
+
+// ComparisonDiff is the difference between a sailing comparison at two times
+type ComparisonDiff struct {
+	Sailing         SailingInfo            `json:"sailing"`
+	From            time.Time              `json:"from"`
+	To              time.Time              `json:"to"`
+	DisplayCurrency string                 `json:"display_currency,omitempty"`
+	NewSuppliers    []SupplierColumn       `json:"new_suppliers"`  // no price at From, some at To
+	GoneSuppliers   []SupplierColumn       `json:"gone_suppliers"` // some price at From, none at To
+	Changes         []ComparisonCellChange `json:"changes"`
+	Warnings        []string               `json:"warnings,omitempty"`
+}
+
+// GetComparisonDiff compares the comparison of a sailing as it stood at two times: suppliers
+// that started or stopped pricing the sailing, and per cabin type + supplier the prices that
+// appeared, moved or were withdrawn. Cells with the same quote at both times are left out.
+func (s *ComparisonService) GetComparisonDiff(ctx context.Context, input ComparisonDiffInput) (*ComparisonDiff, error) {
+	if !input.To.After(input.From) {
+		return nil, ErrInvalidDiffWindow
+	}
+
+	beforeInput := input.ComparisonInput
+	beforeInput.AsOf = &input.From
+	before, err := s.GetSailingComparison(ctx, beforeInput)
+	if err != nil {
+		return nil, err
+	}
+
+	afterInput := input.ComparisonInput
+	afterInput.AsOf = &input.To
+	after, err := s.GetSailingComparison(ctx, afterInput)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ComparisonDiff{
+		Sailing:         after.Sailing,
+		From:            input.From,
+		To:              input.To,
+		DisplayCurrency: after.DisplayCurrency,
+		NewSuppliers:    []SupplierColumn{},
+		GoneSuppliers:   []SupplierColumn{},
+		Changes:         []ComparisonCellChange{},
+	}
+
+	warnings := newWarningSet()
+	for _, w := range before.Warnings {
+		warnings.add(w)
+	}
+	for _, w := range after.Warnings {
+		warnings.add(w)
+	}
+	result.Warnings = warnings.list()
+
+	beforeCells := pricedCells(before)
+	afterCells := pricedCells(after)
+
+	supplierNames := make(map[uint64]string)
+	for _, columns := range [][]SupplierColumn{before.Suppliers, after.Suppliers} {
+		for _, column := range columns {
+			supplierNames[column.ID] = column.Name
+		}
+	}
+
+	pricedBefore := make(map[uint64]bool)
+	for key := range beforeCells {
+		pricedBefore[key.supplierID] = true
+	}
+	pricedAfter := make(map[uint64]bool)
+	for key := range afterCells {
+		pricedAfter[key.supplierID] = true
+	}
+	for id := range pricedAfter {
+		if !pricedBefore[id] {
+			result.NewSuppliers = append(result.NewSuppliers, SupplierColumn{ID: id, Name: supplierNames[id]})
+		}
+	}
+	for id := range pricedBefore {
+		if !pricedAfter[id] {
+			result.GoneSuppliers = append(result.GoneSuppliers, SupplierColumn{ID: id, Name: supplierNames[id]})
+		}
+	}
+	sortSupplierColumns(result.NewSuppliers)
+	sortSupplierColumns(result.GoneSuppliers)
+
+	// Rows follow the cabin type order of the later comparison, then of the earlier one
+	// for cabin types no longer shown
+	supplierIDs := sortedSupplierIDs(supplierNames)
+	seenRows := make(map[uint64]bool)
+	for _, rows := range [][]CabinTypeRow{after.CabinTypes, before.CabinTypes} {
+		for _, row := range rows {
+			if seenRows[row.CabinTypeID] {
+				continue
+			}
+			seenRows[row.CabinTypeID] = true
+
+			for _, supplierID := range supplierIDs {
+				key := cellKey{row.CabinTypeID, supplierID}
+				change, ok := diffCell(beforeCells[key], afterCells[key], input.To)
+				if !ok {
+					continue
+				}
+				change.CabinTypeID = row.CabinTypeID
+				change.CabinTypeName = row.CabinTypeName
+				change.CabinCategoryName = row.CabinCategoryName
+				change.SupplierID = supplierID
+				change.SupplierName = supplierNames[supplierID]
+				result.Changes = append(result.Changes, change)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// pricedCells indexes the cells of a comparison that hold a price
+func pricedCells(comparison *SailingComparison) map[cellKey]*CabinPriceCell {
+	cells := make(map[cellKey]*CabinPriceCell)
+	for i := range comparison.CabinTypes {
+		row := &comparison.CabinTypes[i]
+		for j := range row.Prices {
+			cell := &row.Prices[j]
+			if cell.QuoteID != nil {
+				cells[cellKey{row.CabinTypeID, cell.SupplierID}] = cell
+			}
+		}
+	}
+	return cells
+}
+
+// diffCell classifies the change of a cell between two comparisons; false when it did not change
+func diffCell(before, after *CabinPriceCell, to time.Time) (ComparisonCellChange, bool) {
+	change := ComparisonCellChange{Before: before, After: after}
+
+	switch {
+	case before == nil && after == nil:
+		return change, false
+	case before == nil:
+		change.Type = ComparisonChangeAdded
+		return change, true
+	case after == nil:
+		change.Type = ComparisonChangeWithdrawn
+		expiry := domain.PriceQuote{ValidUntil: before.ValidUntil}
+		switch {
+		case before.WithdrawnAt != nil && !before.WithdrawnAt.After(to):
+			change.Reason = before.WithdrawnAs
+		case expiry.IsPastValidUntil(to):
+			change.Reason = domain.QuoteStatusExpired
+		}
+		return change, true
+	case *before.QuoteID == *after.QuoteID:
+		return change, false
+	}
+
+	if before.Currency == after.Currency && before.PricingUnit == after.PricingUnit {
+		diff := after.LatestPrice.Sub(*before.LatestPrice)
+		change.Change = &diff
+	}
+	if before.Display != nil && after.Display != nil && before.PricingUnit == after.PricingUnit {
+		diff := after.Display.Amount.Sub(before.Display.Amount)
+		change.DisplayChange = &diff
+	}
+
+	delta := change.DisplayChange
+	if delta == nil {
+		delta = change.Change
+	}
+	switch {
+	case delta == nil || delta.IsZero():
+		change.Type = ComparisonChangeRequoted
+	case delta.IsPositive():
+		change.Type = ComparisonChangeIncreased
+	default:
+		change.Type = ComparisonChangeDecreased
+	}
+
+	return change, true
+}
+
+// sortSupplierColumns sorts supplier columns by name
+func sortSupplierColumns(columns []SupplierColumn) {
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+}
+
+// sortedSupplierIDs returns the supplier IDs of names ordered by name
+func sortedSupplierIDs(names map[uint64]string) []uint64 {
+	ids := make([]uint64, 0, len(names))
+	for id := range names {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if names[ids[i]] != names[ids[j]] {
+			return names[ids[i]] < names[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// ComparisonService builds the per-sailing supplier price comparison
+type ComparisonService struct {
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinTypeRepo  *repo.CabinTypeRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	supplierRepo   *repo.SupplierRepository
+	fx             *FXService
+}
+
+// NewComparisonService creates a new comparison service
+func NewComparisonService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *ComparisonService {
+	return &ComparisonService{
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinTypeRepo:  cabinTypeRepo,
+		categoryRepo:   categoryRepo,
+		supplierRepo:   supplierRepo,
+		fx:             fx,
+	}
+}
+
+// SailingInfo summarizes a sailing for comparison and trend views
+type SailingInfo struct {
+	ID             uint64    `json:"id"`
+	SailingCode    string    `json:"sailing_code,omitempty"`
+	ShipName       string    `json:"ship_name"`
+	CruiseLineName string    `json:"cruise_line_name"`
+	DepartureDate  time.Time `json:"departure_date"`
+	ReturnDate     time.Time `json:"return_date"`
+	Nights         int       `json:"nights"`
+	Route          string    `json:"route"`
+}
+
+// SupplierColumn is a supplier column of the comparison table
+type SupplierColumn struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+// CabinPriceCell is the latest price of one supplier for one cabin type
+type CabinPriceCell struct {
+	SupplierID    uint64             `json:"supplier_id"`
+	QuoteID       *uint64            `json:"quote_id,omitempty"`
+	LatestPrice   *decimal.Decimal   `json:"latest_price"` // nil if no quote
+	Currency      string             `json:"currency,omitempty"`
+	PricingUnit   domain.PricingUnit `json:"pricing_unit,omitempty"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"`
+	QuotedAt      *time.Time         `json:"quoted_at,omitempty"`    // when the supplier issued the latest quote
+	PriceChange   *decimal.Decimal   `json:"price_change,omitempty"` // vs previous quote in the same currency and unit
+	QuoteCount    int                `json:"quote_count"`
+	Status        domain.QuoteStatus `json:"status,omitempty"`
+	ValidFrom     *time.Time         `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time         `json:"valid_until,omitempty"`
+	Expired       bool               `json:"expired"`                  // latest quote is past its validity date
+	CabinQuantity *int               `json:"cabin_quantity,omitempty"` // cabins held by the supplier, if stated
+	Display       *ConvertedAmount   `json:"display,omitempty"`
+	DisplayDiff   *decimal.Decimal   `json:"display_price_change,omitempty"` // vs previous quote, both in display currency
+
+	// As-of comparisons only: the quote was voided or corrected after the as-of time
+	WithdrawnAs domain.QuoteStatus `json:"withdrawn_as,omitempty"`
+	WithdrawnAt *time.Time         `json:"withdrawn_at,omitempty"`
+
+	// Latest price plus the charges the quote lists as payable on top of it, in the same
+	// pricing unit; equals the latest price when the quote lists no extra charges
+	AllInPrice   *decimal.Decimal `json:"all_in_price,omitempty"`
+	DisplayAllIn *decimal.Decimal `json:"display_all_in_price,omitempty"`
+
+	// Latest price after the promotions anyone booking today gets; equals the latest price
+	// when no promotion applies. AppliedPromotions lists the IDs of the promotions taken off.
+	EffectivePrice    *decimal.Decimal   `json:"effective_price,omitempty"`
+	DisplayEffective  *decimal.Decimal   `json:"display_effective_price,omitempty"`
+	Promotions        []domain.Promotion `json:"promotions,omitempty"`
+	AppliedPromotions []uint64           `json:"applied_promotions,omitempty"`
+
+	// Latest price normalized for the requested occupancy, in the quote and display currency
+	Normalized        *NormalizedPrice `json:"normalized,omitempty"`
+	DisplayNormalized *NormalizedPrice `json:"display_normalized,omitempty"`
+}
+
+// cellKey identifies a comparison cell
+type cellKey struct{ cabinTypeID, supplierID uint64 }
+
+// quoteHistory holds the latest and previous active quote of a cabin type + supplier
+type quoteHistory struct {
+	latest   *domain.PriceQuote
+	previous *domain.PriceQuote
+	count    int
+}
+
+// CabinTypeRow is a cabin type row of the comparison table
+type CabinTypeRow struct {
+	CabinTypeID       uint64           `json:"cabin_type_id"`
+	CabinTypeName     string           `json:"cabin_type_name"`
+	CabinCategoryID   uint64           `json:"cabin_category_id"`
+	CabinCategoryName string           `json:"cabin_category_name"`
+	Prices            []CabinPriceCell `json:"prices"`
+}
+
+// SailingComparison is the supplier price comparison of a sailing
+type SailingComparison struct {
+	Sailing         SailingInfo      `json:"sailing"`
+	AsOf            *time.Time       `json:"as_of,omitempty"`
+	Suppliers       []SupplierColumn `json:"suppliers"`
+	CabinTypes      []CabinTypeRow   `json:"cabin_types"`
+	DisplayCurrency string           `json:"display_currency,omitempty"`
+	Warnings        []string         `json:"warnings,omitempty"`
+}
+
+// ComparisonInput represents the input for a sailing comparison
+type ComparisonInput struct {
+	SailingID       uint64
+	SupplierIDs     []uint64 // Optional, defaults to every visible supplier
+	CabinCategoryID *uint64
+	DisplayCurrency string                // Optional, converts prices into this currency
+	Occupancy       *Occupancy            // Optional, normalizes prices for this party
+	IncludeExpired  bool                  // Also consider quotes past their validity date
+	IncludePending  bool                  // Admins only: also consider quotes waiting for approval
+	PromotionType   *domain.PromotionType // Optional, only shows latest prices carrying this promotion
+	AsOf            *time.Time            // Optional, the comparison as it stood at this time
+	UserRole        domain.UserRole
+	UserSupplier    uint64
+}
+
+// GetSailingComparison returns the latest current price of each visible supplier
+// for each cabin type of a sailing; expired prices, and for admins prices waiting for approval,
+// only count when requested. With AsOf,
+// it returns the comparison as it stood at that time, from the append-only quote history by
+// quote date and valid-from date: quotes voided or corrected since still show, marked as
+// withdrawn, and quotes issued later do not.
+func (s *ComparisonService) GetSailingComparison(ctx context.Context, input ComparisonInput) (*SailingComparison, error) {
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if input.Occupancy != nil && (input.Occupancy.Adults < 1 || input.Occupancy.Children < 0) {
+		return nil, ErrInvalidOccupancy
+	}
+
+	sailing, info, err := loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var quotes []domain.PriceQuote
+	if input.AsOf != nil {
+		now = *input.AsOf
+		quotes, err = s.quoteRepo.ListBySailingAsOf(ctx, sailing.ID, now, input.IncludeExpired)
+	} else {
+		includePending := input.IncludePending && input.UserRole == domain.UserRoleAdmin
+		quotes, err = s.quoteRepo.ListBySailing(ctx, sailing.ID, input.IncludeExpired, includePending)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := visibleSuppliers(ctx, s.supplierRepo, quotes, input.SupplierIDs, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	cabinTypes, err := s.cabinTypeRepo.ListByShip(ctx, sailing.ShipID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uint64]string, len(categories))
+	categoryOrder := make(map[uint64]int, len(categories))
+	for _, cc := range categories {
+		categoryNames[cc.ID] = cc.Name
+		categoryOrder[cc.ID] = cc.SortOrder
+	}
+
+	// Quotes are newest first: the first per cabin type + supplier is the latest,
+	// the second the previous one
+	histories := make(map[cellKey]*quoteHistory)
+	for i := range quotes {
+		q := &quotes[i]
+		if _, ok := suppliers[q.SupplierID]; !ok {
+			continue
+		}
+
+		key := cellKey{q.CabinTypeID, q.SupplierID}
+		history, ok := histories[key]
+		if !ok {
+			history = &quoteHistory{latest: q}
+			histories[key] = history
+		} else if history.previous == nil {
+			history.previous = q
+		}
+		history.count++
+	}
+
+	result := &SailingComparison{
+		Sailing:         *info,
+		Suppliers:       make([]SupplierColumn, 0, len(suppliers)),
+		CabinTypes:      []CabinTypeRow{},
+		DisplayCurrency: displayCurrency,
+		AsOf:            input.AsOf,
+	}
+
+	for _, supplier := range suppliers {
+		result.Suppliers = append(result.Suppliers, SupplierColumn{ID: supplier.ID, Name: supplier.Name})
+	}
+	sort.Slice(result.Suppliers, func(i, j int) bool {
+		return result.Suppliers[i].Name < result.Suppliers[j].Name
+	})
+
+	if err := s.loadLatestDetails(ctx, histories, input.Occupancy != nil); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(cabinTypes, func(i, j int) bool {
+		return categoryOrder[cabinTypes[i].CategoryID] < categoryOrder[cabinTypes[j].CategoryID]
+	})
+
+	converter := s.fx.NewConverter(displayCurrency)
+	warnings := newWarningSet()
+
+	for _, ct := range cabinTypes {
+		if input.CabinCategoryID != nil && ct.CategoryID != *input.CabinCategoryID {
+			continue
+		}
+
+		row := CabinTypeRow{
+			CabinTypeID:       ct.ID,
+			CabinTypeName:     ct.Name,
+			CabinCategoryID:   ct.CategoryID,
+			CabinCategoryName: categoryNames[ct.CategoryID],
+			Prices:            make([]CabinPriceCell, 0, len(result.Suppliers)),
+		}
+
+		for _, column := range result.Suppliers {
+			cell := CabinPriceCell{SupplierID: column.ID}
+			history, ok := histories[cellKey{ct.ID, column.ID}]
+			if ok && input.PromotionType != nil && !history.latest.HasPromotion(*input.PromotionType) {
+				ok = false
+			}
+			if ok {
+				if err := fillPriceCell(ctx, &cell, history, converter, warnings); err != nil {
+					return nil, err
+				}
+				if input.AsOf != nil {
+					fillStatusAsOf(&cell, history.latest, now)
+				}
+				fillAllInPrice(&cell, history.latest, info.Nights, warnings)
+				fillEffectivePrice(&cell, history.latest, now)
+				if input.Occupancy != nil {
+					normalizePriceCell(&cell, history.latest, *input.Occupancy, info.Nights, warnings)
+				}
+			}
+			row.Prices = append(row.Prices, cell)
+		}
+
+		result.CabinTypes = append(result.CabinTypes, row)
+	}
+
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// fillPriceCell sets the latest price, price change and display values of a cell
+func fillPriceCell(ctx context.Context, cell *CabinPriceCell, history *quoteHistory, converter *CurrencyConverter, warnings *warningSet) error {
+	latest := history.latest
+	price := latest.Price
+	updatedAt := latest.CreatedAt
+	quotedAt := latest.QuotedAt
+	quoteID := latest.ID
+
+	cell.QuoteID = &quoteID
+	cell.LatestPrice = &price
+	cell.Currency = latest.Currency
+	cell.PricingUnit = latest.PricingUnit
+	cell.UpdatedAt = &updatedAt
+	cell.QuotedAt = &quotedAt
+	cell.QuoteCount = history.count
+	cell.Status = latest.Status
+	cell.ValidFrom = latest.ValidFrom
+	cell.ValidUntil = latest.ValidUntil
+	cell.Expired = latest.IsExpired(time.Now())
+	cell.CabinQuantity = latest.CabinQuantity
+
+	previous := history.previous
+	if previous != nil && previous.Currency == latest.Currency && previous.PricingUnit == latest.PricingUnit {
+		change := latest.Price.Sub(previous.Price)
+		cell.PriceChange = &change
+	}
+
+	display, err := convertForDisplay(ctx, converter, latest, warnings)
+	if err != nil || display == nil {
+		return err
+	}
+	cell.Display = display
+
+	if previous != nil && previous.PricingUnit == latest.PricingUnit {
+		previousDisplay, err := convertForDisplay(ctx, converter, previous, warnings)
+		if err != nil {
+			return err
+		}
+		if previousDisplay != nil {
+			change := display.Amount.Sub(previousDisplay.Amount)
+			cell.DisplayDiff = &change
+		}
+	}
+
+	return nil
+}
+
+// fillStatusAsOf sets the status a cell's quote had at t, and when it was withdrawn since
+func fillStatusAsOf(cell *CabinPriceCell, latest *domain.PriceQuote, t time.Time) {
+	cell.Status = latest.StatusAt(t)
+	cell.Expired = cell.Status == domain.QuoteStatusExpired
+	if latest.IsWithdrawn() {
+		cell.WithdrawnAs = latest.Status
+		cell.WithdrawnAt = latest.StatusChangedAt
+	}
+}
+
+// fillAllInPrice sets the all-in price of a cell; per-night charges that cannot be
+// priced are reported as warnings
+func fillAllInPrice(cell *CabinPriceCell, latest *domain.PriceQuote, nights int, warnings *warningSet) {
+	allIn, complete := latest.AllInPrice(nights)
+	if !complete {
+		warnings.add(fmt.Sprintf("quote %d: nights unknown; per-night charges left out of the all-in price", latest.ID))
+	}
+
+	cell.AllInPrice = &allIn
+	if cell.Display != nil {
+		displayAllIn := allIn.Mul(cell.Display.Rate).Round(fxAmountPrecision)
+		cell.DisplayAllIn = &displayAllIn
+	}
+}
+
+// fillEffectivePrice sets the price after promotions of a cell for a booking made at t
+func fillEffectivePrice(cell *CabinPriceCell, latest *domain.PriceQuote, t time.Time) {
+	effective, applied := latest.EffectivePrice(t)
+
+	cell.EffectivePrice = &effective
+	cell.Promotions = latest.Promotions
+	for _, p := range applied {
+		cell.AppliedPromotions = append(cell.AppliedPromotions, p.ID)
+	}
+	if cell.Display != nil {
+		displayEffective := effective.Mul(cell.Display.Rate).Round(fxAmountPrecision)
+		cell.DisplayEffective = &displayEffective
+	}
+}
+
+// loadLatestDetails loads the price components and promotions, and optionally the occupancy rates,
+// of the latest quote of each cell
+func (s *ComparisonService) loadLatestDetails(ctx context.Context, histories map[cellKey]*quoteHistory, withOccupancyRates bool) error {
+	latest := make([]domain.PriceQuote, 0, len(histories))
+	keys := make([]cellKey, 0, len(histories))
+	for key, history := range histories {
+		latest = append(latest, *history.latest)
+		keys = append(keys, key)
+	}
+
+	if err := s.quoteRepo.LoadComponents(ctx, latest); err != nil {
+		return err
+	}
+	if err := s.quoteRepo.LoadPromotions(ctx, latest); err != nil {
+		return err
+	}
+	if withOccupancyRates {
+		if err := s.quoteRepo.LoadOccupancyRates(ctx, latest); err != nil {
+			return err
+		}
+	}
+
+	for i, key := range keys {
+		histories[key].latest = &latest[i]
+	}
+
+	return nil
+}
+
+// normalizePriceCell sets the occupancy-normalized figures of a cell; quotes that
+// cannot host the party are reported as warnings
+func normalizePriceCell(cell *CabinPriceCell, latest *domain.PriceQuote, occupancy Occupancy, nights int, warnings *warningSet) {
+	normalized, err := NormalizeQuotePrice(latest, occupancy, nights)
+	if err != nil {
+		warnings.add(fmt.Sprintf("quote %d: %v", latest.ID, err))
+		return
+	}
+
+	cell.Normalized = normalized
+	if cell.Display != nil {
+		cell.DisplayNormalized = normalized.Convert(cell.Display.Rate, cell.Display.Currency)
+	}
+}
+
+// convertForDisplay converts a quote price at the rate in effect when the supplier
+// issued the quote (see domain.PriceQuote.PricedAt). A missing rate is reported as a warning and yields nil.
+func convertForDisplay(ctx context.Context, converter *CurrencyConverter, quote *domain.PriceQuote, warnings *warningSet) (*ConvertedAmount, error) {
+	converted, err := converter.Convert(ctx, quote.Price, quote.Currency, quote.PricedAt())
+	if errors.Is(err, ErrFXRateNotFound) {
+		warnings.add(err.Error())
+		return nil, nil
+	}
+	return converted, err
+}
+
+// loadSailingInfo loads a sailing with its ship and cruise line names
+func loadSailingInfo(ctx context.Context, sailingRepo *repo.SailingRepository, shipRepo *repo.ShipRepository, cruiseLineRepo *repo.CruiseLineRepository, sailingID uint64) (*domain.Sailing, *SailingInfo, error) {
+	sailing, err := sailingRepo.GetByID(ctx, sailingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sailing == nil {
+		return nil, nil, ErrSailingNotFound
+	}
+
+	info := &SailingInfo{
+		ID:            sailing.ID,
+		SailingCode:   sailing.SailingCode,
+		DepartureDate: sailing.DepartureDate,
+		ReturnDate:    sailing.ReturnDate,
+		Nights:        sailing.Nights,
+		Route:         sailing.Route,
+	}
+	if info.Nights == 0 {
+		info.Nights = sailing.CalculateNights()
+	}
+
+	ship, err := shipRepo.GetByID(ctx, sailing.ShipID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ship != nil {
+		info.ShipName = ship.Name
+
+		cruiseLine, err := cruiseLineRepo.GetByID(ctx, ship.CruiseLineID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cruiseLine != nil {
+			info.CruiseLineName = cruiseLine.Name
+		}
+	}
+
+	return sailing, info, nil
+}
+
+// visibleSuppliers returns the suppliers of the quotes that the user may compare:
+// admins see every supplier, vendors their own and public ones. A non-empty
+// requested list narrows the result further.
+func visibleSuppliers(ctx context.Context, supplierRepo *repo.SupplierRepository, quotes []domain.PriceQuote, requested []uint64, userRole domain.UserRole, userSupplier uint64) (map[uint64]*domain.Supplier, error) {
+	supplierIDs := make([]uint64, len(quotes))
+	for i := range quotes {
+		supplierIDs[i] = quotes[i].SupplierID
+	}
+	return visibleSupplierIDs(ctx, supplierRepo, supplierIDs, requested, userRole, userSupplier)
+}
+
+// visibleSupplierIDs is visibleSuppliers for a list of supplier IDs, which may repeat
+func visibleSupplierIDs(ctx context.Context, supplierRepo *repo.SupplierRepository, supplierIDs []uint64, requested []uint64, userRole domain.UserRole, userSupplier uint64) (map[uint64]*domain.Supplier, error) {
+	wanted := make(map[uint64]bool, len(requested))
+	for _, id := range requested {
+		wanted[id] = true
+	}
+
+	seen := make(map[uint64]bool)
+	ids := make([]uint64, 0)
+	for _, id := range supplierIDs {
+		if seen[id] || (len(wanted) > 0 && !wanted[id]) {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	suppliers, err := supplierRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppliers: %w", err)
+	}
+
+	visible := make(map[uint64]*domain.Supplier, len(suppliers))
+	for i := range suppliers {
+		supplier := &suppliers[i]
+		if userRole == domain.UserRoleVendor && supplier.ID != userSupplier && !supplier.IsPublic() {
+			continue
+		}
+		visible[supplier.ID] = supplier
+	}
+
+	return visible, nil
+}
+
+// warningSet collects distinct warnings in insertion order
+type warningSet struct {
+	seen  map[string]bool
+	items []string
+}
+
+func newWarningSet() *warningSet {
+	return &warningSet{seen: make(map[string]bool)}
+}
+
+func (w *warningSet) add(warning string) {
+	if !w.seen[warning] {
+		w.seen[warning] = true
+		w.items = append(w.items, warning)
+	}
+}
+
+func (w *warningSet) list() []string {
+	return w.items
+}
@@ -3,19 +3,37 @@ package service
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"cruise-price-compare/internal/domain"
 	"cruise-price-compare/internal/repo"
 )
 
-// DataMatcher handles matching of parsed data to existing database records
+const (
+	// semanticWeight is the share of the semantic score in the combined match score
+	semanticWeight = 0.7
+
+	// semanticFloor is the cosine similarity that counts as "unrelated"; embedding
+	// models rarely go below it even for unrelated short names
+	semanticFloor = 0.4
+
+	// semanticCategoryThreshold is the rescaled semantic score above which parsed
+	// wording is taken to belong to a cabin category
+	semanticCategoryThreshold = 0.6
+)
+
+// DataMatcher handles matching of parsed data to existing database records.
+// Names are compared lexically after normalization and, when an embedding index
+// is configured, semantically; the two scores are combined.
 type DataMatcher struct {
 	shipRepo       *repo.ShipRepository
 	sailingRepo    *repo.SailingRepository
 	cabinTypeRepo  *repo.CabinTypeRepository
 	cruiseLineRepo *repo.CruiseLineRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	portRepo       *repo.PortRepository
+	embeddings     *EmbeddingIndexService
+	normalizer     *NameNormalizer
 }
 
 // NewDataMatcher creates a new data matcher
@@ -24,12 +42,19 @@ func NewDataMatcher(
 	sailingRepo *repo.SailingRepository,
 	cabinTypeRepo *repo.CabinTypeRepository,
 	cruiseLineRepo *repo.CruiseLineRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	portRepo *repo.PortRepository,
+	embeddings *EmbeddingIndexService,
 ) *DataMatcher {
 	return &DataMatcher{
 		shipRepo:       shipRepo,
 		sailingRepo:    sailingRepo,
 		cabinTypeRepo:  cabinTypeRepo,
 		cruiseLineRepo: cruiseLineRepo,
+		categoryRepo:   categoryRepo,
+		portRepo:       portRepo,
+		embeddings:     embeddings,
+		normalizer:     NewNameNormalizer(),
 	}
 }
 
@@ -41,8 +66,10 @@ type MatchResult struct {
 	Issues     []string                     // Any issues encountered
 }
 
-// MatchSailingData matches parsed sailing data to database records
-func (m *DataMatcher) MatchSailingData(ctx context.Context, sailingCode, shipName string, departureDate time.Time, nights int) (*MatchResult, error) {
+// MatchSailingData matches parsed sailing data to database records. ports are the parsed
+// ports of call; when several sailings of the ship fit the date and nights, the one whose
+// itinerary calls at most of them is chosen.
+func (m *DataMatcher) MatchSailingData(ctx context.Context, sailingCode, shipName string, departureDate time.Time, nights int, ports []string) (*MatchResult, error) {
 	result := &MatchResult{
 		CabinTypes: make(map[string]*domain.CabinType),
 		Confidence: 1.0,
@@ -76,19 +103,27 @@ func (m *DataMatcher) MatchSailingData(ctx context.Context, sailingCode, shipNam
 	// Query sailings for this ship
 	sailings, err := m.sailingRepo.ListByShip(ctx, ship.ID)
 	if err == nil {
+		var candidates []domain.Sailing
 		for _, s := range sailings {
 			// Check if dates match (within 1 day tolerance)
 			dateDiff := s.DepartureDate.Sub(departureDate).Hours() / 24
 			if dateDiff >= -1 && dateDiff <= 1 && s.Nights == nights {
-				sailing = &s
-				result.Sailing = sailing
-				if s.SailingCode != sailingCode {
-					result.Issues = append(result.Issues, fmt.Sprintf("Sailing code mismatch: expected '%s', found '%s'", sailingCode, s.SailingCode))
-					result.Confidence -= 0.2
-				}
-				return result, nil
+				candidates = append(candidates, s)
 			}
 		}
+
+		if len(candidates) > 0 {
+			s := candidates[0]
+			if len(candidates) > 1 {
+				s = m.disambiguateByItinerary(ctx, candidates, ports, result)
+			}
+			result.Sailing = &s
+			if s.SailingCode != sailingCode {
+				result.Issues = append(result.Issues, fmt.Sprintf("Sailing code mismatch: expected '%s', found '%s'", sailingCode, s.SailingCode))
+				result.Confidence -= 0.2
+			}
+			return result, nil
+		}
 	}
 
 	// Step 4: No exact match found
@@ -109,16 +144,17 @@ func (m *DataMatcher) MatchCabinType(ctx context.Context, shipID uint64, cabinTy
 	if len(cabinTypes) == 0 {
 		return nil, 0, fmt.Errorf("no cabin types found for ship ID %d", shipID)
 	}
+	m.attachCategories(ctx, cabinTypes)
 
 	// Step 2: Try exact name match first
-	for _, ct := range cabinTypes {
-		if strings.EqualFold(ct.Name, cabinTypeName) {
-			return &ct, 1.0, nil
+	for i := range cabinTypes {
+		if m.normalizer.Equal(cabinTypes[i].Name, cabinTypeName) {
+			return &cabinTypes[i], 1.0, nil
 		}
 	}
 
 	// Step 3: Try fuzzy matching
-	bestMatch, bestScore := m.findBestCabinTypeMatch(cabinTypes, cabinTypeName, cabinCategory)
+	bestMatch, bestScore := m.findBestCabinTypeMatch(ctx, cabinTypes, cabinTypeName, cabinCategory)
 	if bestMatch != nil && bestScore >= 0.6 {
 		return bestMatch, bestScore, nil
 	}
@@ -137,6 +173,7 @@ func (m *DataMatcher) MatchMultipleCabinTypes(ctx context.Context, shipID uint64
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get cabin types: %w", err)
 	}
+	m.attachCategories(ctx, cabinTypes)
 
 	// Match each cabin type
 	for _, name := range cabinTypeNames {
@@ -145,7 +182,7 @@ func (m *DataMatcher) MatchMultipleCabinTypes(ctx context.Context, shipID uint64
 		// Try exact match first
 		var exactMatch *domain.CabinType
 		for i := range cabinTypes {
-			if strings.EqualFold(cabinTypes[i].Name, name) {
+			if m.normalizer.Equal(cabinTypes[i].Name, name) {
 				exactMatch = &cabinTypes[i]
 				break
 			}
@@ -157,7 +194,7 @@ func (m *DataMatcher) MatchMultipleCabinTypes(ctx context.Context, shipID uint64
 		}
 
 		// Try fuzzy match
-		bestMatch, bestScore := m.findBestCabinTypeMatch(cabinTypes, name, category)
+		bestMatch, bestScore := m.findBestCabinTypeMatch(ctx, cabinTypes, name, category)
 		if bestMatch != nil && bestScore >= 0.6 {
 			matched[name] = bestMatch
 		} else {
@@ -187,22 +224,85 @@ func (m *DataMatcher) validateSailingMatch(sailing *domain.Sailing, shipName str
 	return true
 }
 
+// disambiguateByItinerary picks, among sailings that fit the parsed date and nights, the one
+// whose itinerary calls at most of the parsed ports. Without a decisive overlap the first
+// candidate is kept and the ambiguity is reported.
+func (m *DataMatcher) disambiguateByItinerary(ctx context.Context, candidates []domain.Sailing, ports []string, result *MatchResult) domain.Sailing {
+	ambiguous := func() domain.Sailing {
+		result.Issues = append(result.Issues, fmt.Sprintf("%d sailings match the ship, date and nights; itinerary did not tell them apart", len(candidates)))
+		result.Confidence -= 0.3
+		return candidates[0]
+	}
+
+	portIDs := m.resolvePorts(ctx, ports)
+	if len(portIDs) == 0 {
+		return ambiguous()
+	}
+
+	ids := make([]uint64, len(candidates))
+	for i := range candidates {
+		ids[i] = candidates[i].ID
+	}
+	itineraries, err := m.sailingRepo.ListItineraries(ctx, ids)
+	if err != nil {
+		return ambiguous()
+	}
+
+	best, bestOverlap, tie := -1, 0, false
+	for i := range candidates {
+		overlap := 0
+		for _, id := range domain.ItineraryPortIDs(itineraries[candidates[i].ID]) {
+			if portIDs[id] {
+				overlap++
+			}
+		}
+		switch {
+		case overlap > bestOverlap:
+			best, bestOverlap, tie = i, overlap, false
+		case overlap == bestOverlap && overlap > 0:
+			tie = true
+		}
+	}
+	if best < 0 || tie {
+		return ambiguous()
+	}
+
+	return candidates[best]
+}
+
+// resolvePorts maps parsed port names to catalog port IDs; unknown names are skipped
+func (m *DataMatcher) resolvePorts(ctx context.Context, names []string) map[uint64]bool {
+	if len(names) == 0 {
+		return nil
+	}
+
+	ports, err := m.portRepo.ListAll(ctx)
+	if err != nil {
+		return nil
+	}
+
+	ids := make(map[uint64]bool, len(names))
+	for _, name := range names {
+		if port := findPortByName(m.normalizer, ports, name); port != nil {
+			ids[port.ID] = true
+		}
+	}
+	return ids
+}
+
 // findShipByName finds a ship by name with fuzzy matching
 func (m *DataMatcher) findShipByName(ctx context.Context, shipName string) (*domain.Ship, error) {
-	// Try exact match first
-	pagination := repo.Pagination{Page: 1, PageSize: 100}
-	activestatus := domain.EntityStatusActive
-	ships, err := m.shipRepo.List(ctx, pagination, nil, &activestatus)
+	ships, err := m.shipRepo.ListAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	normalizedSearch := m.normalizeName(shipName)
-
-	// Exact match
-	for _, ship := range ships.Items {
-		if strings.EqualFold(ship.Name, shipName) {
-			return &ship, nil
+	// Exact match on name, English name or any alias
+	for i := range ships {
+		for _, candidate := range shipNameCandidates(&ships[i]) {
+			if m.normalizer.Equal(candidate, shipName) {
+				return &ships[i], nil
+			}
 		}
 	}
 
@@ -210,11 +310,13 @@ func (m *DataMatcher) findShipByName(ctx context.Context, shipName string) (*dom
 	var bestMatch *domain.Ship
 	bestScore := 0.0
 
-	for i := range ships.Items {
-		score := m.calculateNameSimilarity(normalizedSearch, m.normalizeName(ships.Items[i].Name))
+	semantic := m.semanticScores(ctx, domain.EntityTypeShip, shipName, nil)
+	for i := range ships {
+		lexical := m.normalizer.BestSimilarity(shipName, shipNameCandidates(&ships[i])...)
+		score := combineMatchScores(lexical, semantic, ships[i].ID)
 		if score > bestScore && score >= 0.7 {
 			bestScore = score
-			bestMatch = &ships.Items[i]
+			bestMatch = &ships[i]
 		}
 	}
 
@@ -222,17 +324,23 @@ func (m *DataMatcher) findShipByName(ctx context.Context, shipName string) (*dom
 }
 
 // findBestCabinTypeMatch finds the best matching cabin type using fuzzy matching
-func (m *DataMatcher) findBestCabinTypeMatch(cabinTypes []domain.CabinType, targetName, targetCategory string) (*domain.CabinType, float64) {
+func (m *DataMatcher) findBestCabinTypeMatch(ctx context.Context, cabinTypes []domain.CabinType, targetName, targetCategory string) (*domain.CabinType, float64) {
 	var bestMatch *domain.CabinType
 	bestScore := 0.0
 
-	normalizedTarget := m.normalizeName(targetName)
+	ids := make([]uint64, len(cabinTypes))
+	for i := range cabinTypes {
+		ids[i] = cabinTypes[i].ID
+	}
+	semantic := m.semanticScores(ctx, domain.EntityTypeCabinType, targetName, ids)
+	semanticCategoryID := m.semanticCategory(ctx, targetName, targetCategory)
 
 	for i := range cabinTypes {
-		score := m.calculateNameSimilarity(normalizedTarget, m.normalizeName(cabinTypes[i].Name))
+		lexical := m.normalizer.Similarity(targetName, cabinTypes[i].Name)
+		score := combineMatchScores(lexical, semantic, cabinTypes[i].ID)
 
-		// Boost score if category matches
-		if targetCategory != "" && cabinTypes[i].Category != nil && cabinTypes[i].Category.Name == targetCategory {
+		// Boost score if category matches (Chinese or English category name, or by meaning)
+		if m.categoryMatches(&cabinTypes[i], targetCategory, semanticCategoryID) {
 			score += 0.2
 			if score > 1.0 {
 				score = 1.0
@@ -248,105 +356,115 @@ func (m *DataMatcher) findBestCabinTypeMatch(cabinTypes []domain.CabinType, targ
 	return bestMatch, bestScore
 }
 
-// normalizeName normalizes a name for comparison
-func (m *DataMatcher) normalizeName(name string) string {
-	// Convert to lowercase
-	normalized := strings.ToLower(name)
+// categoryMatches checks whether a cabin type belongs to the parsed category
+func (m *DataMatcher) categoryMatches(ct *domain.CabinType, targetCategory string, semanticCategoryID uint64) bool {
+	if semanticCategoryID != 0 && ct.CategoryID == semanticCategoryID {
+		return true
+	}
+	if targetCategory == "" || ct.Category == nil {
+		return false
+	}
+	return m.normalizer.Equal(ct.Category.Name, targetCategory) ||
+		m.normalizer.Equal(ct.Category.NameEN, targetCategory)
+}
 
-	// Remove extra whitespace
-	normalized = strings.Join(strings.Fields(normalized), " ")
+// semanticCategory returns the cabin category the parsed wording means, or 0.
+// The category hint is used when present, otherwise the cabin type name itself,
+// so "Grand Suite" is recognized as a 套房 even without a hint.
+func (m *DataMatcher) semanticCategory(ctx context.Context, targetName, targetCategory string) uint64 {
+	text := targetCategory
+	if text == "" {
+		text = targetName
+	}
 
-	// Remove common prefixes/suffixes
-	normalized = strings.TrimSpace(normalized)
+	scores := m.semanticScores(ctx, domain.EntityTypeCabinCategory, text, nil)
+	var bestID uint64
+	bestScore := semanticCategoryThreshold
+	for id, cosine := range scores {
+		if score := rescaleSemantic(cosine); score >= bestScore {
+			bestScore = score
+			bestID = id
+		}
+	}
 
-	return normalized
+	return bestID
 }
 
-// calculateNameSimilarity calculates similarity between two names (0.0 to 1.0)
-// Uses a simple Levenshtein-like approach
-func (m *DataMatcher) calculateNameSimilarity(name1, name2 string) float64 {
-	// Quick checks
-	if name1 == name2 {
-		return 1.0
-	}
-	if name1 == "" || name2 == "" {
-		return 0.0
+// semanticScores returns cosine similarities from the embedding index.
+// Semantic matching is best effort: without an index, or when the embedding
+// backend fails, matching falls back to lexical scores only.
+func (m *DataMatcher) semanticScores(ctx context.Context, entityType, text string, ids []uint64) map[uint64]float64 {
+	scores, err := m.embeddings.Similarities(ctx, entityType, text, ids)
+	if err != nil {
+		return nil
 	}
+	return scores
+}
 
-	// Check if one contains the other
-	if strings.Contains(name1, name2) || strings.Contains(name2, name1) {
-		shorter := len(name1)
-		if len(name2) < shorter {
-			shorter = len(name2)
-		}
-		longer := len(name1)
-		if len(name2) > longer {
-			longer = len(name2)
-		}
-		return float64(shorter) / float64(longer)
+// combineMatchScores combines a lexical score with the semantic score of an entity (0.0 to 1.0).
+// The semantic score can only raise the lexical one, never lower it.
+func combineMatchScores(lexical float64, semantic map[uint64]float64, id uint64) float64 {
+	cosine, ok := semantic[id]
+	if !ok {
+		return lexical
 	}
 
-	// Calculate Levenshtein distance
-	distance := m.levenshteinDistance(name1, name2)
-	maxLen := len(name1)
-	if len(name2) > maxLen {
-		maxLen = len(name2)
-	}
+	combined := (1-semanticWeight)*lexical + semanticWeight*rescaleSemantic(cosine)
+	return max(lexical, combined)
+}
 
-	return 1.0 - (float64(distance) / float64(maxLen))
+// rescaleSemantic maps a cosine similarity onto 0.0 to 1.0, treating everything
+// at or below semanticFloor as unrelated
+func rescaleSemantic(cosine float64) float64 {
+	score := (cosine - semanticFloor) / (1 - semanticFloor)
+	return min(max(score, 0.0), 1.0)
 }
 
-// levenshteinDistance calculates the Levenshtein distance between two strings
-func (m *DataMatcher) levenshteinDistance(s1, s2 string) int {
-	if len(s1) == 0 {
-		return len(s2)
-	}
-	if len(s2) == 0 {
-		return len(s1)
+// attachCategories loads the category of each cabin type so category hints can be used
+func (m *DataMatcher) attachCategories(ctx context.Context, cabinTypes []domain.CabinType) {
+	categories, err := m.categoryRepo.List(ctx)
+	if err != nil {
+		return
 	}
 
-	// Create a 2D matrix
-	matrix := make([][]int, len(s1)+1)
-	for i := range matrix {
-		matrix[i] = make([]int, len(s2)+1)
+	byID := make(map[uint64]*domain.CabinCategory, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
 	}
-
-	// Initialize first row and column
-	for i := 0; i <= len(s1); i++ {
-		matrix[i][0] = i
+	for i := range cabinTypes {
+		if cabinTypes[i].Category == nil {
+			cabinTypes[i].Category = byID[cabinTypes[i].CategoryID]
+		}
 	}
-	for j := 0; j <= len(s2); j++ {
-		matrix[0][j] = j
+}
+
+// shipNameCandidates returns every name a ship may be referred to by
+func shipNameCandidates(ship *domain.Ship) []string {
+	candidates := make([]string, 0, len(ship.Aliases)+2)
+	candidates = append(candidates, ship.Name)
+	if ship.NameEN != "" {
+		candidates = append(candidates, ship.NameEN)
 	}
+	return append(candidates, ship.Aliases...)
+}
 
-	// Fill in the rest of the matrix
-	for i := 1; i <= len(s1); i++ {
-		for j := 1; j <= len(s2); j++ {
-			cost := 1
-			if s1[i-1] == s2[j-1] {
-				cost = 0
+// findPortByName returns the port a name refers to: an exact match, after normalization,
+// on any name of the port. Port names are too short for fuzzy matching to be safe.
+func findPortByName(normalizer *NameNormalizer, ports []domain.Port, name string) *domain.Port {
+	if code := domain.NormalizeUNLocode(name); len(code) == 5 {
+		for i := range ports {
+			if ports[i].UNLocode == code {
+				return &ports[i]
 			}
-
-			matrix[i][j] = min(
-				matrix[i-1][j]+1,      // deletion
-				matrix[i][j-1]+1,      // insertion
-				matrix[i-1][j-1]+cost, // substitution
-			)
 		}
 	}
 
-	return matrix[len(s1)][len(s2)]
-}
-
-func min(a, b, c int) int {
-	if a < b {
-		if a < c {
-			return a
+	for i := range ports {
+		for _, candidate := range ports[i].Names() {
+			if normalizer.Equal(candidate, name) {
+				return &ports[i]
+			}
 		}
-		return c
-	}
-	if b < c {
-		return b
 	}
-	return c
+	return nil
 }
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/llm"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+)
+
+const (
+	// embeddingRefreshTimeout bounds a single embedding call made on a catalog write,
+	// so an unavailable embedding backend does not stall catalog maintenance
+	embeddingRefreshTimeout = 10 * time.Second
+
+	// embeddingQueryCacheSize caps the number of cached query vectors
+	embeddingQueryCacheSize = 2000
+)
+
+// EmbeddingIndexService maintains the embedding index of cabin types, ships and
+// cabin categories and answers semantic similarity lookups against it.
+// A nil *EmbeddingIndexService is valid and behaves as an empty index.
+type EmbeddingIndexService struct {
+	embeddingRepo *repo.CatalogEmbeddingRepository
+	cabinTypeRepo *repo.CabinTypeRepository
+	shipRepo      *repo.ShipRepository
+	categoryRepo  *repo.CabinCategoryRepository
+	embedder      llm.Embedder
+	logger        *obs.Logger
+
+	mu         sync.Mutex
+	queryCache map[string][]float32
+}
+
+// NewEmbeddingIndexService creates a new embedding index service
+func NewEmbeddingIndexService(
+	embeddingRepo *repo.CatalogEmbeddingRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	shipRepo *repo.ShipRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	embedder llm.Embedder,
+	logger *obs.Logger,
+) *EmbeddingIndexService {
+	return &EmbeddingIndexService{
+		embeddingRepo: embeddingRepo,
+		cabinTypeRepo: cabinTypeRepo,
+		shipRepo:      shipRepo,
+		categoryRepo:  categoryRepo,
+		embedder:      embedder,
+		logger:        logger,
+		queryCache:    make(map[string][]float32),
+	}
+}
+
+// EmbeddingRebuildResult summarizes an index rebuild
+type EmbeddingRebuildResult struct {
+	Model      string   `json:"model"`
+	Indexed    int      `json:"indexed"`
+	Unchanged  int      `json:"unchanged"`
+	Removed    int64    `json:"removed"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// EmbeddingIndexStatus describes the current index contents
+type EmbeddingIndexStatus struct {
+	Model  string           `json:"model"`
+	Counts map[string]int64 `json:"counts"` // Key: entity type
+}
+
+// Model returns the embedding model of the index
+func (s *EmbeddingIndexService) Model() string {
+	if s == nil {
+		return ""
+	}
+	return s.embedder.Model()
+}
+
+// IndexCabinType refreshes the embedding of a cabin type
+func (s *EmbeddingIndexService) IndexCabinType(ctx context.Context, ct *domain.CabinType) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.index(ctx, domain.EntityTypeCabinType, ct.ID, cabinTypeEmbeddingContent(ct), false)
+	return err
+}
+
+// IndexShip refreshes the embedding of a ship
+func (s *EmbeddingIndexService) IndexShip(ctx context.Context, ship *domain.Ship) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.index(ctx, domain.EntityTypeShip, ship.ID, shipEmbeddingContent(ship), false)
+	return err
+}
+
+// IndexCabinCategory refreshes the embedding of a cabin category
+func (s *EmbeddingIndexService) IndexCabinCategory(ctx context.Context, cc *domain.CabinCategory) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.index(ctx, domain.EntityTypeCabinCategory, cc.ID, categoryEmbeddingContent(cc), false)
+	return err
+}
+
+// Remove deletes the embeddings of a deleted entity
+func (s *EmbeddingIndexService) Remove(ctx context.Context, entityType string, entityID uint64) error {
+	if s == nil {
+		return nil
+	}
+	return s.embeddingRepo.DeleteByEntity(ctx, entityType, entityID)
+}
+
+// Rebuild re-embeds every cabin type, ship and cabin category and removes
+// embeddings of entities that no longer exist. Unless force is set, entities
+// whose content is unchanged since the last run are skipped.
+func (s *EmbeddingIndexService) Rebuild(ctx context.Context, force bool) (*EmbeddingRebuildResult, error) {
+	if s == nil {
+		return nil, fmt.Errorf("embedding index is not configured")
+	}
+
+	start := time.Now()
+	result := &EmbeddingRebuildResult{Model: s.embedder.Model(), Errors: []string{}}
+
+	type item struct {
+		id      uint64
+		content string
+	}
+	entities := make(map[string][]item, 3)
+
+	cabinTypes, err := s.cabinTypeRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cabin types: %w", err)
+	}
+	for i := range cabinTypes {
+		entities[domain.EntityTypeCabinType] = append(entities[domain.EntityTypeCabinType],
+			item{cabinTypes[i].ID, cabinTypeEmbeddingContent(&cabinTypes[i])})
+	}
+
+	ships, err := s.shipRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ships: %w", err)
+	}
+	for i := range ships {
+		entities[domain.EntityTypeShip] = append(entities[domain.EntityTypeShip],
+			item{ships[i].ID, shipEmbeddingContent(&ships[i])})
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cabin categories: %w", err)
+	}
+	for i := range categories {
+		entities[domain.EntityTypeCabinCategory] = append(entities[domain.EntityTypeCabinCategory],
+			item{categories[i].ID, categoryEmbeddingContent(&categories[i])})
+	}
+
+	for _, entityType := range domain.EmbeddableEntityTypes() {
+		keepIDs := make([]uint64, 0, len(entities[entityType]))
+		for _, it := range entities[entityType] {
+			keepIDs = append(keepIDs, it.id)
+
+			indexed, err := s.index(ctx, entityType, it.id, it.content, force)
+			switch {
+			case err != nil:
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s %d: %v", entityType, it.id, err))
+			case indexed:
+				result.Indexed++
+			default:
+				result.Unchanged++
+			}
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+
+		removed, err := s.embeddingRepo.DeleteExcept(ctx, entityType, result.Model, keepIDs)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed += removed
+	}
+
+	s.mu.Lock()
+	s.queryCache = make(map[string][]float32)
+	s.mu.Unlock()
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	s.logger.WithContext(ctx).WithFields(map[string]any{
+		"model":     result.Model,
+		"indexed":   result.Indexed,
+		"unchanged": result.Unchanged,
+		"removed":   result.Removed,
+		"failed":    result.Failed,
+	}).Info("Embedding index rebuilt")
+
+	return result, nil
+}
+
+// Status returns the number of indexed entities per type for the current model
+func (s *EmbeddingIndexService) Status(ctx context.Context) (*EmbeddingIndexStatus, error) {
+	if s == nil {
+		return nil, fmt.Errorf("embedding index is not configured")
+	}
+
+	counts, err := s.embeddingRepo.CountByModel(ctx, s.embedder.Model())
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmbeddingIndexStatus{Model: s.embedder.Model(), Counts: counts}, nil
+}
+
+// Similarities returns the cosine similarity between text and each of the given
+// entities that has an embedding. Entities without an embedding are absent from
+// the result; a nil entityIDs compares against every entity of the type.
+func (s *EmbeddingIndexService) Similarities(ctx context.Context, entityType, text string, entityIDs []uint64) (map[uint64]float64, error) {
+	if s == nil || strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	embeddings, err := s.embeddingRepo.ListByEntities(ctx, entityType, s.embedder.Model(), entityIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, nil
+	}
+
+	query, err := s.embedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint64]float64, len(embeddings))
+	for _, e := range embeddings {
+		scores[e.EntityID] = llm.CosineSimilarity(query, e.Vector)
+	}
+
+	return scores, nil
+}
+
+// index embeds content and stores it, returning false when the stored embedding was already current
+func (s *EmbeddingIndexService) index(ctx context.Context, entityType string, entityID uint64, content string, force bool) (bool, error) {
+	model := s.embedder.Model()
+	hash := sha256.Sum256([]byte(content))
+	contentHash := hex.EncodeToString(hash[:])
+
+	if !force {
+		existing, err := s.embeddingRepo.GetByEntity(ctx, entityType, entityID, model)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil && existing.ContentHash == contentHash {
+			return false, nil
+		}
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, embeddingRefreshTimeout)
+	defer cancel()
+
+	vector, err := s.embedder.Embed(embedCtx, content)
+	if err != nil {
+		return false, fmt.Errorf("failed to embed %s %d: %w", entityType, entityID, err)
+	}
+
+	err = s.embeddingRepo.Upsert(ctx, &domain.CatalogEmbedding{
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Model:       model,
+		Content:     content,
+		ContentHash: contentHash,
+		Dimensions:  len(vector),
+		Vector:      vector,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// embedQuery embeds matcher input, caching vectors since suppliers repeat the same wording
+func (s *EmbeddingIndexService) embedQuery(ctx context.Context, text string) ([]float32, error) {
+	s.mu.Lock()
+	cached, ok := s.queryCache[text]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, embeddingRefreshTimeout)
+	defer cancel()
+
+	vector, err := s.embedder.Embed(embedCtx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	s.mu.Lock()
+	if len(s.queryCache) >= embeddingQueryCacheSize {
+		s.queryCache = make(map[string][]float32)
+	}
+	s.queryCache[text] = vector
+	s.mu.Unlock()
+
+	return vector, nil
+}
+
+// cabinTypeEmbeddingContent returns the text embedded for a cabin type
+func cabinTypeEmbeddingContent(ct *domain.CabinType) string {
+	return ct.Name
+}
+
+// shipEmbeddingContent returns the text embedded for a ship
+func shipEmbeddingContent(ship *domain.Ship) string {
+	return joinEmbeddingNames(append([]string{ship.Name, ship.NameEN}, ship.Aliases...))
+}
+
+// categoryEmbeddingContent returns the text embedded for a cabin category
+func categoryEmbeddingContent(cc *domain.CabinCategory) string {
+	return joinEmbeddingNames([]string{cc.Name, cc.NameEN})
+}
+
+// joinEmbeddingNames joins the non-empty names of an entity
+func joinEmbeddingNames(names []string) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
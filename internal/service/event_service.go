@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+)
+
+// Event errors
+var (
+	ErrInvalidSubscriber  = errors.New("invalid event subscriber")
+	ErrInvalidEventCursor = errors.New("invalid event cursor")
+)
+
+const (
+	// eventDispatchBatchSize is the number of events read per subscriber and round
+	eventDispatchBatchSize = 100
+
+	// eventListMaxLimit caps the page size of the event feed
+	eventListMaxLimit = 500
+
+	// eventGapTimeout is how long a missing outbox position holds back the events after
+	// it. Positions are assigned at insert but become visible at commit, so a gap is
+	// usually a transaction still in flight; once the event after it is older than this,
+	// the gap is taken to be a rolled back insert and skipped.
+	eventGapTimeout = 5 * time.Minute
+)
+
+// EventHandler handles one domain event. Events are delivered at least once, so handlers
+// must tolerate seeing the same event (same EventID) again.
+type EventHandler func(ctx context.Context, event *domain.DomainEvent) error
+
+// eventSubscription is an in-process consumer of the outbox with its own cursor
+type eventSubscription struct {
+	name       string
+	eventTypes []string
+	handler    EventHandler
+}
+
+// EventService reads the domain event outbox: it dispatches events to in-process
+// subscribers and serves the event feed and subscriber cursors to admins
+type EventService struct {
+	eventRepo     *repo.DomainEventRepository
+	logger        *obs.Logger
+	subscriptions []eventSubscription
+}
+
+// NewEventService creates a new event service
+func NewEventService(eventRepo *repo.DomainEventRepository, logger *obs.Logger) *EventService {
+	return &EventService{
+		eventRepo: eventRepo,
+		logger:    logger,
+	}
+}
+
+// newDomainEvent creates an outbox event carrying the trace ID of the request
+func newDomainEvent(ctx context.Context, eventType, aggregateType string, aggregateID uint64, userID uint64, supplierID *uint64, data interface{}) *domain.DomainEvent {
+	event := domain.NewDomainEvent(eventType, aggregateType, aggregateID, userID, supplierID, data)
+	event.TraceID = obs.GetTraceIDFromContext(ctx)
+	return event
+}
+
+// Subscribe registers a handler under a unique name. eventTypes optionally restricts the
+// events it receives. A subscriber without a stored cursor starts at the beginning of the
+// outbox, so a new subscriber replays the whole history before it sees new events.
+func (s *EventService) Subscribe(name string, eventTypes []string, handler EventHandler) {
+	s.subscriptions = append(s.subscriptions, eventSubscription{
+		name:       name,
+		eventTypes: eventTypes,
+		handler:    handler,
+	})
+}
+
+// Dispatch hands the events after each subscriber's cursor to it, in outbox order, and
+// returns how many events the cursors moved past. The cursor only moves past an event once
+// its handler succeeded; a failing handler stops its subscriber for this round and the
+// event is retried on the next one, while the other subscribers carry on. Events are only
+// read up to the first gap in the outbox, so an event committed after ones positioned
+// behind it is not skipped (see eventGapTimeout).
+func (s *EventService) Dispatch(ctx context.Context) (int, error) {
+	total := 0
+	var firstErr error
+	for _, sub := range s.subscriptions {
+		moved, err := s.dispatchTo(ctx, sub)
+		total += moved
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("subscriber %s: %w", sub.name, err)
+		}
+	}
+	return total, firstErr
+}
+
+func (s *EventService) dispatchTo(ctx context.Context, sub eventSubscription) (int, error) {
+	var position uint64
+	cursor, err := s.eventRepo.GetCursor(ctx, sub.name)
+	if err != nil {
+		return 0, err
+	}
+	if cursor != nil {
+		position = cursor.LastEventID
+	}
+
+	horizon, err := s.visibleHorizon(ctx, position, eventDispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if horizon == position {
+		return 0, nil
+	}
+
+	events, err := s.eventRepo.ListAfter(ctx, position, horizon, sub.eventTypes, eventDispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for i := range events {
+		event := &events[i]
+		if err := sub.handler(ctx, event); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+				"subscriber": sub.name,
+				"event_id":   event.ID,
+				"event_type": event.EventType,
+			}).Warn("Event subscriber failed, will retry")
+			return moved, nil
+		}
+
+		if err := s.eventRepo.SaveCursor(ctx, sub.name, event.ID); err != nil {
+			return moved, err
+		}
+		moved += int(event.ID - position)
+		position = event.ID
+	}
+
+	// Every event up to the horizon is handled, or not of a subscribed type
+	if len(events) < eventDispatchBatchSize && horizon > position {
+		if err := s.eventRepo.SaveCursor(ctx, sub.name, horizon); err != nil {
+			return moved, err
+		}
+		moved += int(horizon - position)
+	}
+
+	return moved, nil
+}
+
+// visibleHorizon returns the position up to which the events after afterID can be read
+// without skipping one still to be committed, looking at up to limit positions
+func (s *EventService) visibleHorizon(ctx context.Context, afterID uint64, limit int) (uint64, error) {
+	positions, err := s.eventRepo.ListPositionsAfter(ctx, afterID, limit)
+	if err != nil {
+		return 0, err
+	}
+	return eventHorizon(afterID, positions, time.Now()), nil
+}
+
+// eventHorizon returns the last of the positions that follows afterID without a gap, where
+// gaps before an event older than eventGapTimeout at now do not count
+func eventHorizon(afterID uint64, positions []repo.EventPosition, now time.Time) uint64 {
+	horizon := afterID
+	for _, p := range positions {
+		if p.ID != horizon+1 && now.Sub(p.OccurredAt) < eventGapTimeout {
+			break
+		}
+		horizon = p.ID
+	}
+	return horizon
+}
+
+// EventPage is a page of the event feed. NextAfter is the cursor to pass to fetch the next page.
+type EventPage struct {
+	Items     []domain.DomainEvent `json:"items"`
+	NextAfter uint64               `json:"next_after"`
+	HasMore   bool                 `json:"has_more"`
+}
+
+// ListEvents returns up to limit events positioned after afterID, oldest first. Like
+// Dispatch, it stops at the first gap in the outbox, so paging with NextAfter does not
+// skip events committed late.
+func (s *EventService) ListEvents(ctx context.Context, afterID uint64, eventTypes []string, limit int) (*EventPage, error) {
+	if limit < 1 {
+		limit = eventDispatchBatchSize
+	}
+	if limit > eventListMaxLimit {
+		limit = eventListMaxLimit
+	}
+
+	// Look at one more position than requested to know whether another page follows
+	positions, err := s.eventRepo.ListPositionsAfter(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	horizon := eventHorizon(afterID, positions, time.Now())
+
+	events, err := s.eventRepo.ListAfter(ctx, afterID, horizon, eventTypes, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &EventPage{Items: events, NextAfter: horizon}
+	if len(events) > limit {
+		page.Items = events[:limit]
+		page.NextAfter = page.Items[limit-1].ID
+		page.HasMore = true
+	} else if n := len(positions); n > limit && horizon == positions[n-1].ID {
+		// The page ends at the position window, not at a gap or the end of the outbox
+		page.HasMore = true
+	}
+	if page.Items == nil {
+		page.Items = []domain.DomainEvent{}
+	}
+
+	return page, nil
+}
+
+// SubscriberStatus is the stored position of a subscriber and how far it lags behind the outbox
+type SubscriberStatus struct {
+	domain.EventCursor
+	LatestEventID uint64 `json:"latest_event_id"`
+	Lag           uint64 `json:"lag"`
+}
+
+// ListSubscribers returns the cursors of every subscriber that has consumed events
+func (s *EventService) ListSubscribers(ctx context.Context) ([]SubscriberStatus, error) {
+	cursors, err := s.eventRepo.ListCursors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.eventRepo.LatestID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]SubscriberStatus, len(cursors))
+	for i, cursor := range cursors {
+		statuses[i] = SubscriberStatus{EventCursor: cursor, LatestEventID: latest}
+		if latest > cursor.LastEventID {
+			statuses[i].Lag = latest - cursor.LastEventID
+		}
+	}
+
+	return statuses, nil
+}
+
+// ResetSubscriber moves the cursor of a subscriber to lastEventID, e.g. 0 to replay the
+// whole history. The subscriber picks it up on its next dispatch round.
+func (s *EventService) ResetSubscriber(ctx context.Context, name string, lastEventID uint64, userID uint64) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidSubscriber
+	}
+
+	latest, err := s.eventRepo.LatestID(ctx)
+	if err != nil {
+		return err
+	}
+	if lastEventID > latest {
+		return fmt.Errorf("%w: %d is past the latest event %d", ErrInvalidEventCursor, lastEventID, latest)
+	}
+
+	if err := s.eventRepo.SaveCursor(ctx, name, lastEventID); err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"subscriber":    name,
+		"last_event_id": lastEventID,
+		"user_id":       userID,
+	}).Info("Event subscriber cursor reset")
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"cruise-price-compare/internal/repo"
+)
+
+func TestEventHorizon(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Second)
+	old := now.Add(-eventGapTimeout - time.Second)
+
+	positions := func(ps ...repo.EventPosition) []repo.EventPosition { return ps }
+
+	tests := []struct {
+		name      string
+		afterID   uint64
+		positions []repo.EventPosition
+		want      uint64
+	}{
+		{"empty outbox", 0, nil, 0},
+		{"contiguous", 4, positions(repo.EventPosition{ID: 5, OccurredAt: recent}, repo.EventPosition{ID: 6, OccurredAt: recent}), 6},
+		{"stops at an uncommitted position", 4, positions(repo.EventPosition{ID: 5, OccurredAt: recent}, repo.EventPosition{ID: 7, OccurredAt: recent}), 5},
+		{"gap right after the cursor", 4, positions(repo.EventPosition{ID: 6, OccurredAt: recent}), 4},
+		{"skips a rolled back position", 4, positions(repo.EventPosition{ID: 5, OccurredAt: old}, repo.EventPosition{ID: 7, OccurredAt: old}, repo.EventPosition{ID: 8, OccurredAt: recent}), 8},
+		{"old gap then new gap", 4, positions(repo.EventPosition{ID: 6, OccurredAt: old}, repo.EventPosition{ID: 9, OccurredAt: recent}), 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventHorizon(tt.afterID, tt.positions, now); got != tt.want {
+				t.Errorf("eventHorizon() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -36,8 +38,13 @@ func (s *FileStorageService) UploadFile(ctx context.Context, filename string, co
 	uniqueFilename := fmt.Sprintf("%s_%s%s", baseName, timestamp, ext)
 	filePath := filepath.Join(s.uploadDir, uniqueFilename)
 
-	// Create file
-	file, err := os.Create(filePath)
+	// Create file; files of the same name stored within the same second get a counter,
+	// e.g. when a backfill stores many archived files in a row
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	for n := 2; errors.Is(err, fs.ErrExist); n++ {
+		filePath = filepath.Join(s.uploadDir, fmt.Sprintf("%s_%s_%d%s", baseName, timestamp, n, ext))
+		file, err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	}
 	if err != nil {
 		return "", "", 0, fmt.Errorf("failed to create file: %w", err)
 	}
@@ -58,6 +65,12 @@ func (s *FileStorageService) UploadFile(ctx context.Context, filename string, co
 	return filePath, hash, size, nil
 }
 
+// FileHash returns the SHA-256 hash of file content as stored in import jobs
+func FileHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
 // GetFilePath returns the full path for a stored file
 func (s *FileStorageService) GetFilePath(filename string) string {
 	return filepath.Join(s.uploadDir, filename)
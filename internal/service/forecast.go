@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// Forecast errors
+var (
+	ErrInvalidForecastWeeks = errors.New("forecast weeks must be between 1 and 26")
+)
+
+const (
+	// defaultForecastWeeks is the number of weekly price bands of a forecast
+	defaultForecastWeeks = 8
+	// maxForecastWeeks is the most weekly price bands of a forecast
+	maxForecastWeeks = 26
+	// forecastSignalDays is the horizon of the drop or rise signal of a forecast
+	forecastSignalDays = 28
+	// forecastPeerYearsBack and forecastPeerYearsAhead bound the departures of the sailings
+	// whose histories make up the curve of a route and season
+	forecastPeerYearsBack  = 3
+	forecastPeerYearsAhead = 1
+)
+
+// ForecastSignal tells whether the price of a supplier is likely to drop or rise
+type ForecastSignal string
+
+// Forecast signals
+const (
+	ForecastLikelyDrop       ForecastSignal = "LIKELY_DROP"
+	ForecastLikelyRise       ForecastSignal = "LIKELY_RISE"
+	ForecastStable           ForecastSignal = "STABLE"
+	ForecastInsufficientData ForecastSignal = "INSUFFICIENT_DATA"
+)
+
+// ForecastConfidence grades the history a forecast is based on
+type ForecastConfidence string
+
+// Forecast confidences
+const (
+	ForecastConfidenceLow    ForecastConfidence = "LOW"
+	ForecastConfidenceMedium ForecastConfidence = "MEDIUM"
+	ForecastConfidenceHigh   ForecastConfidence = "HIGH"
+)
+
+// ForecastBand is the expected price of a supplier on a date, with the band 80% of prices
+// are expected to fall into
+type ForecastBand struct {
+	Date            time.Time       `json:"date"`
+	DaysToDeparture int             `json:"days_to_departure"`
+	Expected        decimal.Decimal `json:"expected"`
+	Low             decimal.Decimal `json:"low"`
+	High            decimal.Decimal `json:"high"`
+}
+
+// ForecastCurvePoint is the average weekly price change of a route and season within a
+// range of days to departure
+type ForecastCurvePoint struct {
+	FromDays        int              `json:"from_days"`
+	ToDays          *int             `json:"to_days,omitempty"` // nil = no upper bound
+	WeeklyChangePct *decimal.Decimal `json:"weekly_change_pct,omitempty"`
+}
+
+// ForecastMarket describes the histories of the route and season a forecast is blended with:
+// the same cabin category on other sailings of the route departing in the same season
+type ForecastMarket struct {
+	Route    string               `json:"route"`
+	Season   Season               `json:"season"`
+	Sailings int                  `json:"sailings"`
+	Series   int                  `json:"series"`
+	Curve    []ForecastCurvePoint `json:"curve,omitempty"`
+}
+
+// PriceForecast is the expected price of a supplier in the coming weeks, in the currency and
+// pricing unit of its latest quote. Bands are weekly from the forecast date up to departure.
+type PriceForecast struct {
+	Signal            ForecastSignal     `json:"signal"`
+	Confidence        ForecastConfidence `json:"confidence"`
+	SignalHorizonDays int                `json:"signal_horizon_days,omitempty"`
+	ExpectedChangePct *decimal.Decimal   `json:"expected_change_pct,omitempty"`
+	AsOf              time.Time          `json:"as_of"`
+	CurrentPrice      decimal.Decimal    `json:"current_price"`
+	Currency          string             `json:"currency"`
+	PricingUnit       domain.PricingUnit `json:"pricing_unit"`
+	LastQuotedAt      time.Time          `json:"last_quoted_at"`
+	Observations      int                `json:"observations"`
+	Bands             []ForecastBand     `json:"bands"`
+
+	// Date with the lowest expected price, the forecast date unless a later one is expected
+	// to be lower by more than the signal threshold
+	BestBookingDate   *time.Time       `json:"best_booking_date,omitempty"`
+	BestExpectedPrice *decimal.Decimal `json:"best_expected_price,omitempty"`
+
+	Market *ForecastMarket `json:"market,omitempty"`
+}
+
+// forecastSeriesKey identifies the price history of a supplier for a sailing cabin type
+type forecastSeriesKey struct {
+	sailingID   uint64
+	cabinTypeID uint64
+	supplierID  uint64
+}
+
+// forecastSeries is a price history in the currency and pricing unit of its latest quote
+type forecastSeries struct {
+	key          forecastSeriesKey
+	currency     string
+	pricingUnit  domain.PricingUnit
+	observations []forecastObservation
+}
+
+// forecastDay returns the UTC date of a time
+func forecastDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// daysBetween returns the days from one date to another
+func daysBetween(from, to time.Time) float64 {
+	return forecastDay(to).Sub(forecastDay(from)).Hours() / 24
+}
+
+// buildForecastSeries groups quotes, oldest quote date first, into the price histories of
+// their supplier, sailing and cabin type as of a date. Quotes after the date or the departure
+// and in another currency or pricing unit than the latest one are left out; of several quotes
+// on one date the last counts.
+func buildForecastSeries(quotes []domain.PriceQuote, departures map[uint64]time.Time, asOf time.Time) []forecastSeries {
+	grouped := make(map[forecastSeriesKey][]*domain.PriceQuote)
+	var keys []forecastSeriesKey
+	for i := range quotes {
+		q := &quotes[i]
+		departure, ok := departures[q.SailingID]
+		if !ok || q.QuotedAt.After(asOf) || forecastDay(q.QuotedAt).After(forecastDay(departure)) {
+			continue
+		}
+		key := forecastSeriesKey{sailingID: q.SailingID, cabinTypeID: q.CabinTypeID, supplierID: q.SupplierID}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], q)
+	}
+
+	series := make([]forecastSeries, 0, len(keys))
+	for _, key := range keys {
+		group := grouped[key]
+		latest := group[len(group)-1]
+		s := forecastSeries{key: key, currency: latest.Currency, pricingUnit: latest.PricingUnit}
+		departure := departures[key.sailingID]
+
+		for _, q := range group {
+			if q.Currency != s.currency || q.PricingUnit != s.pricingUnit {
+				continue
+			}
+			price, _ := q.Price.Float64()
+			observation := forecastObservation{
+				QuotedAt: forecastDay(q.QuotedAt),
+				Days:     daysBetween(q.QuotedAt, departure),
+				Price:    price,
+			}
+			if n := len(s.observations); n > 0 && s.observations[n-1].QuotedAt.Equal(observation.QuotedAt) {
+				s.observations[n-1] = observation
+				continue
+			}
+			s.observations = append(s.observations, observation)
+		}
+		series = append(series, s)
+	}
+
+	return series
+}
+
+// observationsUntil returns the observations of a history quoted on or before a date
+func observationsUntil(history []forecastObservation, until time.Time) []forecastObservation {
+	n := 0
+	for n < len(history) && !history[n].QuotedAt.After(until) {
+		n++
+	}
+	return history[:n]
+}
+
+// peerCurve builds the market curve of histories other than those of a sailing, as known on
+// a date, and returns it with the number of sailings it is built from
+func peerCurve(series []forecastSeries, sailingID uint64, until time.Time) (*marketCurve, int) {
+	var histories [][]forecastObservation
+	sailings := make(map[uint64]bool)
+	for _, s := range series {
+		if s.key.sailingID == sailingID {
+			continue
+		}
+		history := observationsUntil(s.observations, until)
+		if len(history) >= 2 {
+			histories = append(histories, history)
+			sailings[s.key.sailingID] = true
+		}
+	}
+	return buildMarketCurve(histories), len(sailings)
+}
+
+// sameRoute reports whether two sailings sail the same route
+func sameRoute(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// forecastPeers returns the other sailings of the route of a sailing departing in the same
+// season, from a few years back to a year ahead
+func (s *TrendService) forecastPeers(ctx context.Context, sailing *domain.Sailing) ([]domain.Sailing, error) {
+	route := strings.TrimSpace(sailing.Route)
+	if route == "" {
+		return nil, nil
+	}
+
+	from := sailing.DepartureDate.AddDate(-forecastPeerYearsBack, 0, 0)
+	to := sailing.DepartureDate.AddDate(forecastPeerYearsAhead, 0, 0)
+	candidates, err := s.sailingRepo.ListMatching(ctx, repo.SailingSearchFilter{FromDate: &from, ToDate: &to, RouteKeyword: route})
+	if err != nil {
+		return nil, err
+	}
+
+	season := SeasonOf(sailing.DepartureDate)
+	var peers []domain.Sailing
+	for _, candidate := range candidates {
+		if candidate.ID != sailing.ID && sameRoute(candidate.Route, route) && SeasonOf(candidate.DepartureDate) == season {
+			peers = append(peers, candidate)
+		}
+	}
+	return peers, nil
+}
+
+// addForecasts adds the forecast of each supplier trend. Forecasts learn from the whole price
+// history of the cabin type, regardless of the trend time range, and from the histories of
+// the cabin category on the other sailings of the route and season.
+func (s *TrendService) addForecasts(ctx context.Context, sailing *domain.Sailing, cabinType *domain.CabinType, weeks int, trends map[uint64]*SupplierTrend) error {
+	peers, err := s.forecastPeers(ctx, sailing)
+	if err != nil {
+		return err
+	}
+
+	departures := map[uint64]time.Time{sailing.ID: sailing.DepartureDate}
+	ids := []uint64{sailing.ID}
+	for _, peer := range peers {
+		departures[peer.ID] = peer.DepartureDate
+		ids = append(ids, peer.ID)
+	}
+
+	quotes, err := s.quoteRepo.ListHistory(ctx, ids, cabinType.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	series := buildForecastSeries(quotes, departures, now)
+
+	var market *ForecastMarket
+	curve, sailings := peerCurve(series, sailing.ID, now)
+	if route := strings.TrimSpace(sailing.Route); route != "" {
+		market = &ForecastMarket{Route: route, Season: SeasonOf(sailing.DepartureDate), Sailings: sailings}
+		if curve != nil {
+			market.Series = curve.Series
+			market.Curve = curvePoints(curve)
+		}
+	}
+
+	for _, history := range series {
+		if history.key.sailingID != sailing.ID || history.key.cabinTypeID != cabinType.ID {
+			continue
+		}
+		trend, ok := trends[history.key.supplierID]
+		if !ok || len(history.observations) == 0 {
+			continue
+		}
+		trend.Forecast = forecastPrice(history, curve, market, sailing.DepartureDate, now, weeks)
+	}
+
+	return nil
+}
+
+// curvePoints lists the weekly price changes of a market curve by days to departure, most
+// days first
+func curvePoints(curve *marketCurve) []ForecastCurvePoint {
+	points := make([]ForecastCurvePoint, 0, forecastCurveBuckets)
+	for b := forecastCurveBuckets - 1; b >= 0; b-- {
+		point := ForecastCurvePoint{}
+		if b > 0 {
+			point.FromDays = int(forecastCurveEdges[b-1])
+		}
+		if b < len(forecastCurveEdges) {
+			to := int(forecastCurveEdges[b])
+			point.ToDays = &to
+		}
+		if curve.Covered[b] > 0 {
+			pct := decimal.NewFromFloat((math.Exp(curve.Drift[b]*7) - 1) * 100).Round(2)
+			point.WeeklyChangePct = &pct
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// forecastPrice forecasts a price history as of a date. It returns nil once the sailing has
+// departed.
+func forecastPrice(history forecastSeries, curve *marketCurve, market *ForecastMarket, departure, asOf time.Time, weeks int) *PriceForecast {
+	daysLeft := int(daysBetween(asOf, departure))
+	if daysLeft <= 0 {
+		return nil
+	}
+
+	observations := history.observations
+	last := observations[len(observations)-1]
+	today := forecastDay(asOf)
+	forecast := &PriceForecast{
+		Signal:       ForecastInsufficientData,
+		Confidence:   ForecastConfidenceLow,
+		AsOf:         today,
+		CurrentPrice: decimal.NewFromFloat(last.Price).Round(2),
+		Currency:     history.currency,
+		PricingUnit:  history.pricingUnit,
+		LastQuotedAt: last.QuotedAt,
+		Observations: len(observations),
+		Bands:        []ForecastBand{},
+		Market:       market,
+	}
+
+	// Without a curve to lean on, a trend needs at least two price changes
+	if len(observations) < 3 && curve == nil {
+		return forecast
+	}
+
+	model := newForecastModel(observations, curve)
+
+	horizon := min(forecastSignalDays, daysLeft)
+	expected := model.predict(float64(daysLeft - horizon)).Expected
+	change := decimal.NewFromFloat((expected/last.Price - 1) * 100).Round(2)
+	forecast.Signal = forecastSignalOf(last.Price, expected)
+	forecast.SignalHorizonDays = horizon
+	forecast.ExpectedChangePct = &change
+
+	bestDate, bestPrice := today, last.Price
+	for week := 1; week <= weeks && week*7 <= daysLeft; week++ {
+		days := daysLeft - week*7
+		point := model.predict(float64(days))
+		date := today.AddDate(0, 0, week*7)
+		forecast.Bands = append(forecast.Bands, ForecastBand{
+			Date:            date,
+			DaysToDeparture: days,
+			Expected:        decimal.NewFromFloat(point.Expected).Round(2),
+			Low:             decimal.NewFromFloat(point.Low).Round(2),
+			High:            decimal.NewFromFloat(point.High).Round(2),
+		})
+		if point.Expected < bestPrice && point.Expected <= last.Price*(1-forecastSignalThreshold) {
+			bestDate, bestPrice = date, point.Expected
+		}
+	}
+	best := decimal.NewFromFloat(bestPrice).Round(2)
+	forecast.BestBookingDate = &bestDate
+	forecast.BestExpectedPrice = &best
+
+	peerSeries := 0
+	if curve != nil {
+		peerSeries = curve.Series
+	}
+	switch {
+	case len(observations) >= 6 && peerSeries >= 3:
+		forecast.Confidence = ForecastConfidenceHigh
+	case len(observations) >= 3 || peerSeries >= 3:
+		forecast.Confidence = ForecastConfidenceMedium
+	}
+
+	return forecast
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// Forecast model parameters
+const (
+	// forecastAlpha and forecastBeta are the smoothing factors of the price level and trend
+	forecastAlpha = 0.5
+	forecastBeta  = 0.3
+	// forecastDamping damps the trend per day, so far horizons do not extrapolate it linearly
+	forecastDamping = 0.99
+	// forecastPriorWeight is the weight, in price changes, of the route and season curve
+	// against the own trend of a price history
+	forecastPriorWeight = 4.0
+	// forecastDefaultVolatility is the relative price volatility per square-root day assumed
+	// without history to estimate it from
+	forecastDefaultVolatility = 0.01
+	// forecastBandZ is the z-score of the expected price bands, which cover 80% of outcomes
+	forecastBandZ = 1.2816
+	// forecastSignalThreshold is the relative expected change that signals a drop or rise
+	forecastSignalThreshold = 0.02
+)
+
+// forecastCurveEdges are the days to departure bounding the buckets of a market curve:
+// under 30, 30 to 90, 90 to 180 and over 180 days
+var forecastCurveEdges = [...]float64{30, 90, 180}
+
+// forecastCurveBuckets is the number of buckets of a market curve
+const forecastCurveBuckets = len(forecastCurveEdges) + 1
+
+// forecastObservation is a quoted price of a price history, the last one of its quote date
+type forecastObservation struct {
+	QuotedAt time.Time // quote date
+	Days     float64   // days to departure at the quote date
+	Price    float64
+}
+
+// forecastPoint is an expected price with its band
+type forecastPoint struct {
+	Expected float64
+	Low      float64
+	High     float64
+}
+
+// curveBucket returns the bucket of a market curve that days to departure fall into
+func curveBucket(days float64) int {
+	for i, edge := range forecastCurveEdges {
+		if days < edge {
+			return i
+		}
+	}
+	return len(forecastCurveEdges)
+}
+
+// marketCurve is the average price drift of the histories of a route and season against
+// days to departure
+type marketCurve struct {
+	Drift      [forecastCurveBuckets]float64 // daily log price change per bucket as departure nears
+	Covered    [forecastCurveBuckets]float64 // days of history per bucket
+	Overall    float64                       // daily log price change over all buckets
+	Volatility float64                       // relative volatility per square-root day, 0 if unknown
+	Series     int                           // histories with at least one price change
+}
+
+// buildMarketCurve averages the price changes between consecutive observations of histories,
+// weighted by the days between them. It returns nil when no history has two observations.
+func buildMarketCurve(histories [][]forecastObservation) *marketCurve {
+	type change struct {
+		bucket int
+		days   float64
+		log    float64
+	}
+
+	var changes []change
+	curve := &marketCurve{}
+	var sums [forecastCurveBuckets]float64
+	var totalLog, totalDays float64
+
+	for _, history := range histories {
+		contributed := false
+		for i := 1; i < len(history); i++ {
+			prev, next := history[i-1], history[i]
+			days := prev.Days - next.Days
+			if days <= 0 || prev.Price <= 0 || next.Price <= 0 {
+				continue
+			}
+			c := change{bucket: curveBucket((prev.Days + next.Days) / 2), days: days, log: math.Log(next.Price / prev.Price)}
+			changes = append(changes, c)
+			sums[c.bucket] += c.log
+			curve.Covered[c.bucket] += c.days
+			totalLog += c.log
+			totalDays += c.days
+			contributed = true
+		}
+		if contributed {
+			curve.Series++
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	curve.Overall = totalLog / totalDays
+	for b := range curve.Drift {
+		if curve.Covered[b] > 0 {
+			curve.Drift[b] = sums[b] / curve.Covered[b]
+		} else {
+			curve.Drift[b] = curve.Overall
+		}
+	}
+
+	if len(changes) >= 2 {
+		var sumSq float64
+		for _, c := range changes {
+			residual := c.log - curve.Drift[c.bucket]*c.days
+			sumSq += residual * residual / c.days
+		}
+		curve.Volatility = math.Sqrt(sumSq / float64(len(changes)))
+	}
+
+	return curve
+}
+
+// integrate returns the log price change the curve expects from fromDays to toDays before
+// departure, fromDays not less than toDays
+func (c *marketCurve) integrate(fromDays, toDays float64) float64 {
+	total := 0.0
+	for days := fromDays; days > toDays; {
+		bucket := curveBucket(math.Nextafter(days, 0))
+		lower := 0.0
+		if bucket > 0 {
+			lower = forecastCurveEdges[bucket-1]
+		}
+		step := days - math.Max(lower, toDays)
+		if step <= 0 {
+			break
+		}
+		total += c.Drift[bucket] * step
+		days -= step
+	}
+	return total
+}
+
+// holtFit is the price level and daily trend of a history by linear exponential smoothing
+// over the days between its observations
+type holtFit struct {
+	Level      float64
+	Trend      float64 // price change per day
+	Volatility float64 // relative one-step error per square-root day, 0 if unknown
+}
+
+// dampedDays returns the days of trend a damped trend adds up to over horizon days
+func dampedDays(horizon float64) float64 {
+	if horizon <= 0 {
+		return 0
+	}
+	return forecastDamping * (1 - math.Pow(forecastDamping, horizon)) / (1 - forecastDamping)
+}
+
+// fitHolt smooths a history, oldest observation first
+func fitHolt(history []forecastObservation) holtFit {
+	fit := holtFit{Level: history[0].Price}
+	var sumSq float64
+	errors := 0
+
+	for i := 1; i < len(history); i++ {
+		days := math.Max(history[i-1].Days-history[i].Days, 0)
+		predicted := fit.Level + fit.Trend*dampedDays(days)
+		if predicted > 0 && days > 0 {
+			relative := (history[i].Price - predicted) / predicted
+			sumSq += relative * relative / days
+			errors++
+		}
+
+		level := forecastAlpha*history[i].Price + (1-forecastAlpha)*predicted
+		if days > 0 {
+			fit.Trend = forecastBeta*(level-fit.Level)/days + (1-forecastBeta)*fit.Trend
+		}
+		fit.Level = level
+	}
+
+	if errors >= 2 {
+		fit.Volatility = math.Sqrt(sumSq / float64(errors))
+	}
+	return fit
+}
+
+// forecastModel forecasts a price history by its own smoothed trend, blended with the curve
+// of its route and season the more the fewer price changes the history has
+type forecastModel struct {
+	fit        holtFit
+	market     *marketCurve
+	weight     float64 // weight of the own trend
+	volatility float64
+	lastDays   float64
+}
+
+// newForecastModel fits a model to a history, oldest observation first; market may be nil
+func newForecastModel(history []forecastObservation, market *marketCurve) *forecastModel {
+	m := &forecastModel{
+		fit:      fitHolt(history),
+		market:   market,
+		weight:   1,
+		lastDays: history[len(history)-1].Days,
+	}
+
+	if market != nil {
+		changes := float64(len(history) - 1)
+		m.weight = changes / (changes + forecastPriorWeight)
+	}
+
+	switch {
+	case m.fit.Volatility > 0 && market != nil && market.Volatility > 0:
+		m.volatility = m.weight*m.fit.Volatility + (1-m.weight)*market.Volatility
+	case m.fit.Volatility > 0:
+		m.volatility = m.fit.Volatility
+	case market != nil && market.Volatility > 0:
+		m.volatility = market.Volatility
+	default:
+		m.volatility = forecastDefaultVolatility
+	}
+
+	return m
+}
+
+// predict returns the expected price and its band at days before departure
+func (m *forecastModel) predict(days float64) forecastPoint {
+	horizon := math.Max(m.lastDays-days, 0)
+
+	expected := m.fit.Level + m.fit.Trend*dampedDays(horizon)
+	if m.market != nil {
+		marketExpected := m.fit.Level * math.Exp(m.market.integrate(m.lastDays, math.Min(days, m.lastDays)))
+		expected = m.weight*expected + (1-m.weight)*marketExpected
+	}
+	expected = math.Max(expected, 0)
+
+	spread := forecastBandZ * m.volatility * math.Sqrt(math.Max(horizon, 1))
+	return forecastPoint{
+		Expected: expected,
+		Low:      expected * math.Exp(-spread),
+		High:     expected * math.Exp(spread),
+	}
+}
+
+// forecastSignalOf classifies the change from a current to an expected price
+func forecastSignalOf(current, expected float64) ForecastSignal {
+	if current <= 0 {
+		return ForecastInsufficientData
+	}
+	change := expected/current - 1
+	switch {
+	case change <= -forecastSignalThreshold:
+		return ForecastLikelyDrop
+	case change >= forecastSignalThreshold:
+		return ForecastLikelyRise
+	default:
+		return ForecastStable
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// backtestHoldoutDays is the history held out of each forecast in the backtests
+const backtestHoldoutDays = 28
+
+// backtestResult sums the errors of forecasts of held-out prices
+type backtestResult struct {
+	series     int
+	prices     int
+	absError   float64 // relative, summed over prices
+	naiveError float64 // of expecting the last known price to hold
+	covered    int     // prices within the 80% band
+	signals    int
+	correct    int
+}
+
+func (r backtestResult) meanAbsError() float64  { return r.absError / float64(r.prices) }
+func (r backtestResult) naiveAbsError() float64 { return r.naiveError / float64(r.prices) }
+func (r backtestResult) coverage() float64      { return float64(r.covered) / float64(r.prices) }
+
+// backtest cuts each price history holdout days before its last quote, forecasts it from what
+// was known at the cut, its own history and the curve of the other histories, and compares the
+// forecasts with the prices quoted after the cut
+func backtest(series []forecastSeries, holdout int) backtestResult {
+	var result backtestResult
+	for _, history := range series {
+		observations := history.observations
+		cut := observations[len(observations)-1].QuotedAt.AddDate(0, 0, -holdout)
+		train := observationsUntil(observations, cut)
+		test := observations[len(train):]
+		if len(train) < 3 || len(test) == 0 {
+			continue
+		}
+
+		curve, _ := peerCurve(series, history.key.sailingID, cut)
+		model := newForecastModel(train, curve)
+		known := train[len(train)-1].Price
+		result.series++
+		for _, actual := range test {
+			point := model.predict(actual.Days)
+			result.prices++
+			result.absError += math.Abs(point.Expected-actual.Price) / actual.Price
+			result.naiveError += math.Abs(known-actual.Price) / actual.Price
+			if actual.Price >= point.Low && actual.Price <= point.High {
+				result.covered++
+			}
+		}
+
+		final := test[len(test)-1]
+		result.signals++
+		if forecastSignalOf(known, model.predict(final.Days).Expected) == forecastSignalOf(known, final.Price) {
+			result.correct++
+		}
+	}
+	return result
+}
+
+// syntheticHistory quotes sailings departing two weeks apart weekly from 180 to 7 days before
+// departure. The price moves by dailyDrift in log terms per day as departure nears, with
+// relative noise drawn from a fixed seed.
+func syntheticHistory(sailings int, dailyDrift, noise float64) ([]domain.PriceQuote, map[uint64]time.Time) {
+	rng := rand.New(rand.NewSource(47))
+	firstDeparture := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	departures := make(map[uint64]time.Time, sailings)
+	var quotes []domain.PriceQuote
+
+	for i := 0; i < sailings; i++ {
+		sailingID := uint64(i + 1)
+		departure := firstDeparture.AddDate(0, 0, 14*i)
+		departures[sailingID] = departure
+		base := 6000 + 200*float64(i%3)
+
+		for days := 180; days >= 7; days -= 7 {
+			price := base * math.Exp(dailyDrift*float64(180-days)) * (1 + noise*rng.NormFloat64())
+			quotes = append(quotes, domain.PriceQuote{
+				ID:          uint64(len(quotes) + 1),
+				SailingID:   sailingID,
+				CabinTypeID: 1,
+				SupplierID:  1,
+				Price:       decimal.NewFromFloat(price).Round(2),
+				Currency:    "CNY",
+				PricingUnit: domain.PricingUnitPerPerson,
+				QuotedAt:    departure.AddDate(0, 0, -days),
+			})
+		}
+	}
+	return quotes, departures
+}
+
+func TestForecastBacktest(t *testing.T) {
+	tests := []struct {
+		name       string
+		dailyDrift float64
+		noise      float64
+		maxError   float64 // mean absolute error bound of the forecasts
+		beatNaive  bool
+	}{
+		// Prices fall about 4.5% over the held-out four weeks
+		{"steady drop", -0.0016, 0.005, 0.015, true},
+		{"steady rise", 0.0016, 0.005, 0.015, true},
+		{"flat with noise", 0, 0.01, 0.02, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, departures := syntheticHistory(12, tt.dailyDrift, tt.noise)
+			series := buildForecastSeries(quotes, departures, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			result := backtest(series, backtestHoldoutDays)
+			if result.series != 12 || result.prices == 0 {
+				t.Fatalf("backtested %d series with %d prices, want 12 series", result.series, result.prices)
+			}
+			if got := result.meanAbsError(); got > tt.maxError {
+				t.Errorf("mean absolute error = %.4f, want at most %.4f", got, tt.maxError)
+			}
+			if tt.beatNaive && result.meanAbsError() >= result.naiveAbsError() {
+				t.Errorf("mean absolute error %.4f does not beat the naive error %.4f", result.meanAbsError(), result.naiveAbsError())
+			}
+			if got := result.coverage(); got < 0.7 {
+				t.Errorf("band coverage = %.2f, want at least 0.70", got)
+			}
+			if tt.beatNaive && result.correct < result.signals*3/4 {
+				t.Errorf("%d of %d signals correct, want at least three quarters", result.correct, result.signals)
+			}
+		})
+	}
+}
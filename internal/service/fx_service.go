@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/parsers"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// FX errors
+var (
+	ErrFXRateNotFound     = errors.New("exchange rate not found")
+	ErrInvalidCurrency    = errors.New("currency must be a 3-letter code")
+	ErrUnsupportedFXInput = errors.New("unsupported rate file format")
+)
+
+// FX rate file formats
+const (
+	FXFileFormatCSV    = "csv"
+	FXFileFormatECBXML = "ecb_xml"
+)
+
+const (
+	// fxRatePrecision is the number of decimal places kept for derived (inverse/cross) rates
+	fxRatePrecision = 10
+
+	// fxAmountPrecision is the number of decimal places of converted amounts
+	fxAmountPrecision = 2
+)
+
+// fxPivotCurrencies are tried, in order, to build a cross rate when a pair has no direct rate
+var fxPivotCurrencies = []string{"EUR", "USD", "CNY"}
+
+// FXService manages exchange rates and converts amounts between currencies
+type FXService struct {
+	fxRepo *repo.FXRateRepository
+	audit  *obs.AuditService
+	logger *obs.Logger
+}
+
+// NewFXService creates a new FX service
+func NewFXService(fxRepo *repo.FXRateRepository, audit *obs.AuditService, logger *obs.Logger) *FXService {
+	return &FXService{
+		fxRepo: fxRepo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// ConvertedAmount is an amount converted into a display currency, together with
+// the original amount and the rate that was used
+type ConvertedAmount struct {
+	Amount           decimal.Decimal `json:"amount"`
+	Currency         string          `json:"currency"`
+	OriginalAmount   decimal.Decimal `json:"original_amount"`
+	OriginalCurrency string          `json:"original_currency"`
+	Rate             decimal.Decimal `json:"rate"`                // 1 original = rate display
+	RateDate         *time.Time      `json:"rate_date,omitempty"` // nil when no conversion was needed
+	Via              string          `json:"via,omitempty"`       // pivot currency of a cross rate
+}
+
+// FXImportResult summarizes a rate file import
+type FXImportResult struct {
+	Format     string     `json:"format"`
+	Imported   int        `json:"imported"`
+	FromDate   *time.Time `json:"from_date,omitempty"`
+	ToDate     *time.Time `json:"to_date,omitempty"`
+	Currencies []string   `json:"currencies"`
+}
+
+// CreateFXRateInput represents input for entering a rate manually
+type CreateFXRateInput struct {
+	RateDate      time.Time
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          string
+}
+
+// NormalizeCurrency upper-cases and validates a currency code; an empty code stays empty
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return "", nil
+	}
+	if len(currency) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return currency, nil
+}
+
+// ListRates lists exchange rates
+func (s *FXService) ListRates(ctx context.Context, pagination repo.Pagination, baseCurrency, quoteCurrency *string, fromDate, toDate *time.Time) (repo.PaginatedResult[domain.FXRate], error) {
+	return s.fxRepo.List(ctx, pagination, baseCurrency, quoteCurrency, fromDate, toDate)
+}
+
+// CreateRate creates or replaces the rate of a currency pair on a date
+func (s *FXService) CreateRate(ctx context.Context, input CreateFXRateInput, userID uint64) (*domain.FXRate, error) {
+	rate := &domain.FXRate{
+		RateDate:      input.RateDate,
+		BaseCurrency:  strings.ToUpper(strings.TrimSpace(input.BaseCurrency)),
+		QuoteCurrency: strings.ToUpper(strings.TrimSpace(input.QuoteCurrency)),
+		Source:        domain.FXRateSourceManual,
+		CreatedBy:     &userID,
+	}
+
+	value, err := decimal.NewFromString(strings.TrimSpace(input.Rate))
+	if err != nil {
+		return nil, domain.ValidationErrors{domain.NewValidationError("rate", domain.ErrFieldInvalidFormat)}
+	}
+	rate.Rate = value
+
+	if errs := domain.ValidateFXRate(rate); errs.HasErrors() {
+		return nil, errs
+	}
+
+	if err := s.fxRepo.Upsert(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	_ = s.audit.LogCreate(ctx, userID, nil, domain.EntityTypeFXRate, rate.ID, rate)
+
+	return rate, nil
+}
+
+// DeleteRate deletes an exchange rate
+func (s *FXService) DeleteRate(ctx context.Context, id uint64, userID uint64) error {
+	rate, err := s.fxRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rate == nil {
+		return ErrFXRateNotFound
+	}
+
+	if err := s.fxRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = s.audit.LogDelete(ctx, userID, nil, domain.EntityTypeFXRate, id, rate)
+
+	return nil
+}
+
+// ImportFile loads rates from a local CSV (date,base,quote,rate) or ECB-style XML file.
+// Rates for a pair and date that already exist are replaced.
+func (s *FXService) ImportFile(ctx context.Context, filePath, format string, userID *uint64) (*FXImportResult, error) {
+	var rows []parsers.FXRateRowData
+	var source domain.FXRateSource
+	var err error
+
+	switch format {
+	case FXFileFormatCSV:
+		rows, err = parsers.ParseFXRateCSV(filePath)
+		source = domain.FXRateSourceCSV
+	case FXFileFormatECBXML:
+		rows, err = parsers.ParseECBRatesXML(filePath)
+		source = domain.FXRateSourceECBXML
+	default:
+		return nil, ErrUnsupportedFXInput
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rate file: %w", err)
+	}
+
+	result := &FXImportResult{Format: format, Currencies: []string{}}
+	currencies := make(map[string]bool)
+	rates := make([]domain.FXRate, 0, len(rows))
+
+	for _, row := range rows {
+		rate := domain.FXRate{
+			RateDate:      row.RateDate,
+			BaseCurrency:  row.BaseCurrency,
+			QuoteCurrency: row.QuoteCurrency,
+			Rate:          row.Rate,
+			Source:        source,
+			CreatedBy:     userID,
+		}
+		if errs := domain.ValidateFXRate(&rate); errs.HasErrors() {
+			return nil, fmt.Errorf("row %d: %w", row.RowNumber, errs)
+		}
+		rates = append(rates, rate)
+
+		rateDate := row.RateDate
+		if result.FromDate == nil || rateDate.Before(*result.FromDate) {
+			result.FromDate = &rateDate
+		}
+		if result.ToDate == nil || rateDate.After(*result.ToDate) {
+			result.ToDate = &rateDate
+		}
+		currencies[row.BaseCurrency] = true
+		currencies[row.QuoteCurrency] = true
+	}
+
+	if err := s.fxRepo.BulkUpsert(ctx, rates); err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(rates)
+	for currency := range currencies {
+		result.Currencies = append(result.Currencies, currency)
+	}
+	sort.Strings(result.Currencies)
+
+	if userID != nil {
+		_ = s.audit.LogCreate(ctx, *userID, nil, domain.EntityTypeFXRate, 0, result)
+	}
+
+	s.logger.WithContext(ctx).WithFields(map[string]any{
+		"format":   format,
+		"imported": result.Imported,
+	}).Info("Exchange rates imported")
+
+	return result, nil
+}
+
+// Convert converts amount from one currency into another using the rate in effect at the given time
+func (s *FXService) Convert(ctx context.Context, amount decimal.Decimal, from, to string, at time.Time) (*ConvertedAmount, error) {
+	return s.NewConverter(to).Convert(ctx, amount, from, at)
+}
+
+// NewConverter returns a converter into the display currency that caches rate
+// lookups; it is meant to live for a single request. An empty display currency
+// yields a nil converter, which converts nothing.
+func (s *FXService) NewConverter(displayCurrency string) *CurrencyConverter {
+	if displayCurrency == "" {
+		return nil
+	}
+	return &CurrencyConverter{
+		fx:       s,
+		currency: displayCurrency,
+		rates:    make(map[string]*domain.FXRate),
+	}
+}
+
+// CurrencyConverter converts amounts into a single display currency
+type CurrencyConverter struct {
+	fx       *FXService
+	currency string
+	rates    map[string]*domain.FXRate // Key: base|quote|date, nil when no rate exists
+}
+
+// Currency returns the display currency; empty for a nil converter
+func (c *CurrencyConverter) Currency() string {
+	if c == nil {
+		return ""
+	}
+	return c.currency
+}
+
+// Convert converts amount in the given currency into the display currency using
+// the rate in effect at the given time. It returns ErrFXRateNotFound when no
+// direct, inverse or cross rate is available on or before that date.
+func (c *CurrencyConverter) Convert(ctx context.Context, amount decimal.Decimal, currency string, at time.Time) (*ConvertedAmount, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	converted := &ConvertedAmount{
+		Amount:           amount,
+		Currency:         c.currency,
+		OriginalAmount:   amount,
+		OriginalCurrency: currency,
+		Rate:             decimal.NewFromInt(1),
+	}
+	if currency == c.currency {
+		return converted, nil
+	}
+
+	rate, rateDate, err := c.lookup(ctx, currency, c.currency, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if rate == nil {
+		for _, pivot := range fxPivotCurrencies {
+			if pivot == currency || pivot == c.currency {
+				continue
+			}
+
+			first, firstDate, err := c.lookup(ctx, currency, pivot, at)
+			if err != nil {
+				return nil, err
+			}
+			if first == nil {
+				continue
+			}
+
+			second, secondDate, err := c.lookup(ctx, pivot, c.currency, at)
+			if err != nil {
+				return nil, err
+			}
+			if second == nil {
+				continue
+			}
+
+			cross := first.Mul(*second).Round(fxRatePrecision)
+			rate = &cross
+			// A cross rate is only as recent as its older leg
+			rateDate = firstDate
+			if secondDate.Before(rateDate) {
+				rateDate = secondDate
+			}
+			converted.Via = pivot
+			break
+		}
+	}
+
+	if rate == nil {
+		return nil, fmt.Errorf("%w: %s to %s on %s", ErrFXRateNotFound, currency, c.currency, at.Format("2006-01-02"))
+	}
+
+	converted.Amount = amount.Mul(*rate).Round(fxAmountPrecision)
+	converted.Rate = *rate
+	converted.RateDate = &rateDate
+
+	return converted, nil
+}
+
+// lookup returns the rate in effect at the given time for from→to, using the
+// inverse of a to→from rate when no direct rate is stored
+func (c *CurrencyConverter) lookup(ctx context.Context, from, to string, at time.Time) (*decimal.Decimal, time.Time, error) {
+	direct, err := c.effective(ctx, from, to, at)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if direct != nil {
+		return &direct.Rate, direct.RateDate, nil
+	}
+
+	inverse, err := c.effective(ctx, to, from, at)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if inverse != nil {
+		rate := decimal.NewFromInt(1).DivRound(inverse.Rate, fxRatePrecision)
+		return &rate, inverse.RateDate, nil
+	}
+
+	return nil, time.Time{}, nil
+}
+
+// effective loads the stored rate of a pair in effect at the given time, caching misses too
+func (c *CurrencyConverter) effective(ctx context.Context, base, quote string, at time.Time) (*domain.FXRate, error) {
+	key := base + "|" + quote + "|" + at.Format("2006-01-02")
+	if rate, ok := c.rates[key]; ok {
+		return rate, nil
+	}
+
+	rate, err := c.fx.fxRepo.GetEffective(ctx, base, quote, at)
+	if err != nil {
+		return nil, err
+	}
+	c.rates[key] = rate
+
+	return rate, nil
+}
@@ -0,0 +1,98 @@
+//go:build ignore
+
+// gen_chinese_variants generates chinese_variants_table.go from the OpenCC
+// traditional-to-simplified character dictionary (data/dictionary/TSCharacters.txt).
+// Each dictionary line holds a traditional character, a tab and its simplified
+// candidates separated by spaces; the first candidate is the standard form.
+//
+// Usage: go run gen_chinese_variants.go -src path/to/TSCharacters.txt
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+func main() {
+	src := flag.String("src", "", "path of the OpenCC TSCharacters.txt dictionary")
+	out := flag.String("out", "chinese_variants_table.go", "output file")
+	flag.Parse()
+	if *src == "" {
+		log.Fatal("-src is required")
+	}
+
+	table, err := readDictionary(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keys := make([]rune, 0, len(table))
+	for t := range table {
+		keys = append(keys, t)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_chinese_variants.go from OpenCC TSCharacters.txt; DO NOT EDIT.\n\n")
+	b.WriteString("package service\n\n")
+	fmt.Fprintf(&b, "// traditionalToSimplified maps each of the %d traditional characters of OpenCC\n", len(keys))
+	b.WriteString("// to its standard simplified form\n")
+	b.WriteString("var traditionalToSimplified = map[rune]rune{\n")
+	for i, t := range keys {
+		fmt.Fprintf(&b, "%q: %q,", t, table[t])
+		if i%8 == 7 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("\n}\n")
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format table: %v", err)
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readDictionary reads the single-character mappings of the dictionary, skipping
+// characters that map to themselves
+func readDictionary(path string) (map[rune]rune, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	table := make(map[rune]rune)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if utf8.RuneCountInString(fields[0]) != 1 || utf8.RuneCountInString(fields[1]) != 1 {
+			return nil, fmt.Errorf("%s:%d: expected single characters", path, line)
+		}
+		t, _ := utf8.DecodeRuneInString(fields[0])
+		s, _ := utf8.DecodeRuneInString(fields[1])
+		if t == s {
+			continue
+		}
+		if existing, ok := table[t]; ok && existing != s {
+			return nil, fmt.Errorf("%s:%d: %q mapped twice", path, line, t)
+		}
+		table[t] = s
+	}
+	return table, scanner.Err()
+}
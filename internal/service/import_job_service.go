@@ -17,46 +17,73 @@ import (
 
 // ImportJobService handles import job operations
 type ImportJobService struct {
-	jobRepo        *repo.ImportJobRepository
-	fileStorage    *FileStorageService
-	pdfExtractor   *llm.PDFExtractor
-	wordExtractor  *llm.WordExtractor
-	ollamaClient   *llm.OllamaClient
-	responseParser *llm.ResponseParser
-	dataMatcher    *DataMatcher
-	quoteService   *QuoteService
-	auditService   *obs.AuditService
+	jobRepo              *repo.ImportJobRepository
+	fileStorage          *FileStorageService
+	pdfExtractor         *llm.PDFExtractor
+	wordExtractor        *llm.WordExtractor
+	spreadsheetExtractor *llm.SpreadsheetExtractor
+	generator            llm.Generator
+	responseParser       *llm.ResponseParser
+	dataMatcher          *DataMatcher
+	quoteService         *QuoteService
+	auditService         *obs.AuditService
 }
 
 // NewImportJobService creates a new import job service
 func NewImportJobService(
 	jobRepo *repo.ImportJobRepository,
 	fileStorage *FileStorageService,
-	ollamaClient *llm.OllamaClient,
+	generator llm.Generator,
 	dataMatcher *DataMatcher,
 	quoteService *QuoteService,
 	auditService *obs.AuditService,
 ) *ImportJobService {
 	return &ImportJobService{
-		jobRepo:        jobRepo,
-		fileStorage:    fileStorage,
-		pdfExtractor:   llm.NewPDFExtractor(),
-		wordExtractor:  llm.NewWordExtractor(),
-		ollamaClient:   ollamaClient,
-		responseParser: llm.NewResponseParser(),
-		dataMatcher:    dataMatcher,
-		quoteService:   quoteService,
-		auditService:   auditService,
+		jobRepo:              jobRepo,
+		fileStorage:          fileStorage,
+		pdfExtractor:         llm.NewPDFExtractor(),
+		wordExtractor:        llm.NewWordExtractor(),
+		spreadsheetExtractor: llm.NewSpreadsheetExtractor(),
+		generator:            generator,
+		responseParser:       llm.NewResponseParser(),
+		dataMatcher:          dataMatcher,
+		quoteService:         quoteService,
+		auditService:         auditService,
 	}
 }
 
+// IsSupportedImportFile reports whether import jobs can parse a file, by its extension
+func IsSupportedImportFile(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".pdf", ".docx", ".doc", ".xlsx":
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportJobFinishedEvent is the event payload of an import job that succeeded or failed.
+// SupplierID is the supplier of the job; for jobs without one it is resolved from the
+// uploading user by the consumers that need it.
+type ImportJobFinishedEvent struct {
+	JobID        uint64                      `json:"job_id"`
+	Status       domain.ImportJobStatus      `json:"status"`
+	FileName     string                      `json:"file_name,omitempty"`
+	Summary      *domain.ImportResultSummary `json:"summary,omitempty"`
+	ErrorMessage string                      `json:"error_message,omitempty"`
+	SupplierID   *uint64                     `json:"supplier_id,omitempty"`
+	CreatedBy    uint64                      `json:"created_by"`
+}
+
 // CreateImportJobInput represents input for creating an import job
 type CreateImportJobInput struct {
 	FileName       string
 	FileContent    []byte
 	UserID         uint64
-	SupplierID     uint64
-	IdempotencyKey string // Optional, for duplicate detection
+	SupplierID     uint64     // Optional for admins, the supplier that issued the file
+	QuotedAt       *time.Time // Optional, quote date of the file when the document states none
+	Backfill       bool       // Archived file: its quotes are history and raise no alerts or webhooks
+	IdempotencyKey string     // Optional, for duplicate detection
 }
 
 // CreateImportJob creates a new import job from uploaded file
@@ -79,14 +106,10 @@ func (s *ImportJobService) CreateImportJob(ctx context.Context, input CreateImpo
 	}
 
 	// Determine job type from file extension
-	ext := strings.ToLower(filepath.Ext(input.FileName))
-	var jobType domain.ImportJobType
-	switch ext {
-	case ".pdf", ".docx", ".doc":
-		jobType = domain.ImportJobTypeFileUpload
-	default:
-		return nil, fmt.Errorf("unsupported file type: %s", ext)
+	if !IsSupportedImportFile(input.FileName) {
+		return nil, fmt.Errorf("unsupported file type: %s", strings.ToLower(filepath.Ext(input.FileName)))
 	}
+	jobType := domain.ImportJobTypeFileUpload
 
 	// Create the import job
 	job := &domain.ImportJob{
@@ -96,11 +119,17 @@ func (s *ImportJobService) CreateImportJob(ctx context.Context, input CreateImpo
 		FileHash:       fileHash,
 		FileSize:       fileSize,
 		FilePath:       filePath,
+		QuotedAt:       input.QuotedAt,
+		Backfill:       input.Backfill,
 		IdempotencyKey: input.IdempotencyKey,
 		CreatedBy:      input.UserID,
 	}
+	if input.SupplierID != 0 {
+		job.SupplierID = &input.SupplierID
+	}
 
-	if err := s.jobRepo.Create(ctx, job); err != nil {
+	event := newDomainEvent(ctx, domain.DomainEventImportJobCreated, domain.EntityTypeImportJob, 0, input.UserID, &input.SupplierID, job)
+	if err := s.jobRepo.Create(ctx, job, event); err != nil {
 		return nil, fmt.Errorf("failed to create import job: %w", err)
 	}
 
@@ -129,19 +158,8 @@ func (s *ImportJobService) ProcessImportJob(ctx context.Context, jobID uint64) e
 		return fmt.Errorf("failed to mark job as started: %w", err)
 	}
 
-	// Process based on type
-	var processErr error
-	var summary *domain.ImportResultSummary
-
-	// Determine file type from extension
-	ext := strings.ToLower(filepath.Ext(job.FileName))
-	if ext == ".pdf" {
-		summary, processErr = s.processPDFJob(ctx, job)
-	} else if ext == ".docx" || ext == ".doc" {
-		summary, processErr = s.processWordJob(ctx, job)
-	} else {
-		processErr = fmt.Errorf("unsupported file type: %s", ext)
-	}
+	// Process the file
+	summary, processErr := s.processFileJob(ctx, job)
 
 	// Update job status
 	var status domain.ImportJobStatus
@@ -154,35 +172,31 @@ func (s *ImportJobService) ProcessImportJob(ctx context.Context, jobID uint64) e
 		status = domain.ImportJobStatusSucceeded
 	}
 
-	if err := s.jobRepo.UpdateCompleted(ctx, jobID, status, summary, errorMsg); err != nil {
+	event := newDomainEvent(ctx, domain.DomainEventImportJobFinished, domain.EntityTypeImportJob, jobID, domain.SystemUserID, nil,
+		ImportJobFinishedEvent{
+			JobID:        jobID,
+			Status:       status,
+			FileName:     job.FileName,
+			Summary:      summary,
+			ErrorMessage: errorMsg,
+			SupplierID:   job.SupplierID,
+			CreatedBy:    job.CreatedBy,
+		})
+	if err := s.jobRepo.UpdateCompleted(ctx, jobID, status, summary, errorMsg, event); err != nil {
 		return fmt.Errorf("failed to update job completion: %w", err)
 	}
 
 	return processErr
 }
 
-// processPDFJob processes a PDF import job
-func (s *ImportJobService) processPDFJob(ctx context.Context, job *domain.ImportJob) (*domain.ImportResultSummary, error) {
-	// Step 1: Extract text from PDF
-	text, err := s.pdfExtractor.ExtractText(job.FilePath)
+// processFileJob parses the file of an import job and creates its quotes
+func (s *ImportJobService) processFileJob(ctx context.Context, job *domain.ImportJob) (*domain.ImportResultSummary, error) {
+	parseResult, err := s.ParseFile(ctx, job.FileName, job.FilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract PDF text: %w", err)
-	}
-
-	// Step 2: Send to LLM for parsing
-	prompt := prompts.QuoteParsePrompt(text)
-	llmResponse, err := s.ollamaClient.Generate(ctx, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate LLM response: %w", err)
-	}
-
-	// Step 3: Parse LLM response
-	parseResult, err := s.responseParser.ParseQuoteResponse(llmResponse)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
+		return nil, err
 	}
 
-	// Step 4: Match sailing and cabin types
+	// Match sailing and cabin types
 	summary, err := s.matchAndCreateQuotes(ctx, job, parseResult)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create quotes: %w", err)
@@ -191,17 +205,35 @@ func (s *ImportJobService) processPDFJob(ctx context.Context, job *domain.Import
 	return summary, nil
 }
 
-// processWordJob processes a Word document import job
-func (s *ImportJobService) processWordJob(ctx context.Context, job *domain.ImportJob) (*domain.ImportResultSummary, error) {
-	// Step 1: Extract text from Word document
-	text, err := s.wordExtractor.ExtractText(job.FilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract Word text: %w", err)
+// ParseFile extracts the text of a PDF, Word or Excel file and has the LLM parse it into
+// quote data, without storing anything; fileName determines the file type
+func (s *ImportJobService) ParseFile(ctx context.Context, fileName, filePath string) (*llm.QuoteParseResult, error) {
+	// Step 1: Extract text
+	var text string
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(fileName)); ext {
+	case ".pdf":
+		text, err = s.pdfExtractor.ExtractText(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract PDF text: %w", err)
+		}
+	case ".docx", ".doc":
+		text, err = s.wordExtractor.ExtractText(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract Word text: %w", err)
+		}
+	case ".xlsx":
+		text, err = s.spreadsheetExtractor.ExtractText(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract spreadsheet text: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", ext)
 	}
 
 	// Step 2: Send to LLM for parsing
 	prompt := prompts.QuoteParsePrompt(text)
-	llmResponse, err := s.ollamaClient.Generate(ctx, prompt)
+	llmResponse, err := s.generator.Generate(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate LLM response: %w", err)
 	}
@@ -212,13 +244,7 @@ func (s *ImportJobService) processWordJob(ctx context.Context, job *domain.Impor
 		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
 
-	// Step 4: Match sailing and cabin types
-	summary, err := s.matchAndCreateQuotes(ctx, job, parseResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create quotes: %w", err)
-	}
-
-	return summary, nil
+	return parseResult, nil
 }
 
 // matchAndCreateQuotes matches parsed data and creates quotes
@@ -238,7 +264,7 @@ func (s *ImportJobService) matchAndCreateQuotes(ctx context.Context, job *domain
 	}
 
 	// Match sailing
-	matchResult, err := s.dataMatcher.MatchSailingData(ctx, parseResult.SailingCode, parseResult.ShipName, departureDate, parseResult.Nights)
+	matchResult, err := s.dataMatcher.MatchSailingData(ctx, parseResult.SailingCode, parseResult.ShipName, departureDate, parseResult.Nights, parseResult.Ports)
 	if err != nil {
 		summary.Warnings = append(summary.Warnings, fmt.Sprintf("Sailing match error: %v", err))
 		return summary, fmt.Errorf("sailing match failed: %w", err)
@@ -249,6 +275,25 @@ func (s *ImportJobService) matchAndCreateQuotes(ctx context.Context, job *domain
 		return summary, fmt.Errorf("sailing not found")
 	}
 
+	// Quote dates stated in the document, else the quote date of the job; a future quote date
+	// is a misread and falls back to the job's
+	quotedAt := job.QuotedAt
+	var validFrom *time.Time
+	if parseResult.QuotedAt != "" {
+		if t, err := time.Parse("2006-01-02", parseResult.QuotedAt); err != nil || t.After(time.Now()) {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("Ignored invalid quote date: %s", parseResult.QuotedAt))
+		} else {
+			quotedAt = &t
+		}
+	}
+	if parseResult.ValidFrom != "" {
+		if t, err := time.Parse("2006-01-02", parseResult.ValidFrom); err != nil {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("Ignored invalid valid-from date: %s", parseResult.ValidFrom))
+		} else {
+			validFrom = &t
+		}
+	}
+
 	// Process each quote
 	for _, parsedQuote := range parseResult.Quotes {
 		// Match cabin type
@@ -261,7 +306,7 @@ func (s *ImportJobService) matchAndCreateQuotes(ctx context.Context, job *domain
 
 		// Create quote
 		quoteInput := CreateQuoteInput{
-			SailingID:   matchResult.Sailing.ShipID,
+			SailingID:   matchResult.Sailing.ID,
 			CabinTypeID: cabinType.ID,
 			Price:       fmt.Sprintf("%.2f", parsedQuote.Price),
 			Currency:    parsedQuote.Currency,
@@ -269,13 +314,42 @@ func (s *ImportJobService) matchAndCreateQuotes(ctx context.Context, job *domain
 			Conditions:  parsedQuote.Conditions,
 			Promotion:   parsedQuote.Promotion,
 			Notes:       parsedQuote.Notes,
+			ValidFrom:   validFrom,
+			QuotedAt:    quotedAt,
+			ImportJobID: &job.ID,
 			UserID:      job.CreatedBy,
 		}
+		if job.SupplierID != nil {
+			quoteInput.SupplierID = *job.SupplierID
+		}
+		if job.Backfill {
+			quoteInput.Source = domain.QuoteSourceBackfill
+		}
+		for _, component := range parsedQuote.Components {
+			var pricingUnit domain.PricingUnit // empty: same as the quote
+			if component.PricingUnit != "" {
+				pricingUnit = s.responseParser.ConvertPricingUnit(component.PricingUnit)
+			}
+			quoteInput.Components = append(quoteInput.Components, PriceComponentInput{
+				ComponentType: s.responseParser.ConvertComponentType(component.Type),
+				Amount:        fmt.Sprintf("%.2f", component.Amount),
+				PricingUnit:   pricingUnit,
+				PerNight:      component.PerNight,
+				Inclusive:     s.responseParser.ComponentInclusive(component),
+				Description:   component.Description,
+			})
+		}
+		for _, promotion := range parsedQuote.Promotions {
+			quoteInput.Promotions = append(quoteInput.Promotions, parsedPromotionInput(s.responseParser, promotion))
+		}
 
-		_, err = s.quoteService.CreateQuote(ctx, quoteInput)
+		_, confirmed, err := s.quoteService.SubmitQuote(ctx, quoteInput)
 		if err != nil {
 			summary.Warnings = append(summary.Warnings, fmt.Sprintf("Failed to create quote for cabin '%s': %v", parsedQuote.CabinTypeName, err))
 			summary.SkippedRows++
+		} else if confirmed {
+			summary.SuccessRows++
+			summary.ConfirmedQuotes++
 		} else {
 			summary.SuccessRows++
 			summary.CreatedQuotes++
@@ -285,6 +359,34 @@ func (s *ImportJobService) matchAndCreateQuotes(ctx context.Context, job *domain
 	return summary, nil
 }
 
+// parsedPromotionInput maps a promotion extracted by the LLM to quote input; the parser has
+// already validated its amounts and dates
+func parsedPromotionInput(parser *llm.ResponseParser, promotion llm.ParsedPromotion) PromotionInput {
+	input := PromotionInput{
+		PromotionType: parser.ConvertPromotionType(promotion.Type),
+		GuestSlots:    promotion.GuestSlots,
+		Stackable:     promotion.Stackable,
+		Description:   promotion.Description,
+	}
+	if promotion.PricingUnit != "" {
+		input.PricingUnit = parser.ConvertPricingUnit(promotion.PricingUnit)
+	}
+	// A zero discount is how the model states that the text gives none
+	if promotion.DiscountPct != nil && *promotion.DiscountPct > 0 {
+		input.DiscountPct = fmt.Sprintf("%.2f", *promotion.DiscountPct)
+	}
+	if promotion.DiscountAmount != nil && *promotion.DiscountAmount > 0 {
+		input.DiscountAmount = fmt.Sprintf("%.2f", *promotion.DiscountAmount)
+	}
+	if t, err := time.Parse("2006-01-02", promotion.BookingFrom); err == nil {
+		input.BookingFrom = &t
+	}
+	if t, err := time.Parse("2006-01-02", promotion.BookingUntil); err == nil {
+		input.BookingUntil = &t
+	}
+	return input
+}
+
 // GetJob retrieves an import job by ID
 func (s *ImportJobService) GetJob(ctx context.Context, id uint64, userID uint64, userRole domain.UserRole, supplierID uint64) (*domain.ImportJob, error) {
 	job, err := s.jobRepo.GetByID(ctx, id)
@@ -314,6 +416,12 @@ func (s *ImportJobService) ListJobs(ctx context.Context, pagination repo.Paginat
 	return s.jobRepo.List(ctx, pagination, userIDToUse, status, jobType)
 }
 
+// GetLatestJobByFileHash retrieves the most recent import job of a file by its SHA-256 hash,
+// nil when the file was never imported
+func (s *ImportJobService) GetLatestJobByFileHash(ctx context.Context, hash string) (*domain.ImportJob, error) {
+	return s.jobRepo.GetLatestByFileHash(ctx, hash)
+}
+
 // GetNextPendingJob gets the next pending job for processing
 func (s *ImportJobService) GetNextPendingJob(ctx context.Context) (*domain.ImportJob, error) {
 	jobs, err := s.jobRepo.ListPending(ctx, 1)
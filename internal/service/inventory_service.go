@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+)
+
+// Inventory errors
+var (
+	ErrInvalidInventoryState = errors.New("inventory state must be SOLD_OUT or NO_ALLOCATION")
+)
+
+// inventoryNoteMaxLength is the size of the cabin_inventory_report.note column
+const inventoryNoteMaxLength = 500
+
+// InventoryService tracks how many cabins suppliers hold, from the cabin quantity of their
+// quotes and from explicit sold out / no allocation reports
+type InventoryService struct {
+	quoteRepo      *repo.PriceQuoteRepository
+	reportRepo     *repo.CabinInventoryReportRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinTypeRepo  *repo.CabinTypeRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	supplierRepo   *repo.SupplierRepository
+	audit          *obs.AuditService
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(
+	quoteRepo *repo.PriceQuoteRepository,
+	reportRepo *repo.CabinInventoryReportRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	audit *obs.AuditService,
+) *InventoryService {
+	return &InventoryService{
+		quoteRepo:      quoteRepo,
+		reportRepo:     reportRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinTypeRepo:  cabinTypeRepo,
+		categoryRepo:   categoryRepo,
+		supplierRepo:   supplierRepo,
+		audit:          audit,
+	}
+}
+
+// ReportInventoryInput represents the input for an inventory report
+type ReportInventoryInput struct {
+	SailingID   uint64
+	CabinTypeID uint64
+	SupplierID  uint64
+	State       domain.InventoryState
+	Note        string
+	UserID      uint64
+}
+
+// ReportInventory records that a supplier has no cabins of a cabin type to offer on a sailing.
+// The state holds until the supplier quotes the cabin again.
+func (s *InventoryService) ReportInventory(ctx context.Context, input ReportInventoryInput) (*domain.CabinInventoryReport, error) {
+	if !input.State.IsReportable() {
+		return nil, ErrInvalidInventoryState
+	}
+
+	sailing, err := s.sailingRepo.GetByID(ctx, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+	if sailing == nil {
+		return nil, ErrSailingNotFound
+	}
+
+	cabinType, err := s.cabinTypeRepo.GetByID(ctx, input.CabinTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if cabinType == nil || cabinType.ShipID != sailing.ShipID {
+		return nil, ErrCabinTypeNotFound
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, input.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, ErrSupplierNotFound
+	}
+
+	report := &domain.CabinInventoryReport{
+		SailingID:   sailing.ID,
+		CabinTypeID: cabinType.ID,
+		SupplierID:  supplier.ID,
+		State:       input.State,
+		Note:        truncateRunes(strings.TrimSpace(input.Note), inventoryNoteMaxLength),
+		ReportedAt:  time.Now(),
+		CreatedBy:   input.UserID,
+	}
+
+	event := newDomainEvent(ctx, domain.DomainEventInventoryReported, domain.EntityTypeInventory, 0, input.UserID, &supplier.ID, report)
+	if err := s.reportRepo.Create(ctx, report, event); err != nil {
+		return nil, err
+	}
+
+	if s.audit != nil {
+		_ = s.audit.LogCreate(ctx, input.UserID, &supplier.ID, domain.EntityTypeInventory, report.ID, report)
+	}
+
+	return report, nil
+}
+
+// SupplierInventory is the latest inventory of one supplier for a cabin type
+type SupplierInventory struct {
+	SupplierID   uint64 `json:"supplier_id"`
+	SupplierName string `json:"supplier_name"`
+	domain.InventoryObservation
+}
+
+// CabinInventory is the latest inventory of every supplier for a cabin type and the market total
+type CabinInventory struct {
+	CabinTypeID       uint64              `json:"cabin_type_id"`
+	CabinTypeName     string              `json:"cabin_type_name"`
+	CabinCategoryID   uint64              `json:"cabin_category_id"`
+	CabinCategoryName string              `json:"cabin_category_name"`
+	Suppliers         []SupplierInventory `json:"suppliers"`
+	MarketInventory
+}
+
+// MarketInventory sums the latest inventory of the suppliers. Suppliers that offer the cabin
+// without stating a quantity count as available but add nothing to the total.
+type MarketInventory struct {
+	TotalQuantity      int  `json:"total_quantity"`
+	AvailableSuppliers int  `json:"available_suppliers"`
+	UnknownQuantity    int  `json:"unknown_quantity"` // Available suppliers that did not state a quantity
+	SoldOut            bool `json:"sold_out"`         // No supplier has the cabin available
+}
+
+// add adds the inventory of one supplier
+func (m *MarketInventory) add(o domain.InventoryObservation) {
+	if o.State != domain.InventoryAvailable {
+		return
+	}
+	m.AvailableSuppliers++
+	if o.Quantity == nil {
+		m.UnknownQuantity++
+		return
+	}
+	m.TotalQuantity += *o.Quantity
+}
+
+// SailingInventory is the inventory of a sailing by cabin type
+type SailingInventory struct {
+	Sailing       SailingInfo      `json:"sailing"`
+	CabinTypes    []CabinInventory `json:"cabin_types"`
+	TotalQuantity int              `json:"total_quantity"`
+}
+
+// InventoryInput represents the input for the inventory of a sailing
+type InventoryInput struct {
+	SailingID    uint64
+	SupplierIDs  []uint64 // Optional, defaults to every visible supplier
+	UserRole     domain.UserRole
+	UserSupplier uint64
+}
+
+// GetSailingInventory returns the latest inventory of each visible supplier for each cabin type
+// of a sailing, taken from the newest of its current quote and its inventory reports
+func (s *InventoryService) GetSailingInventory(ctx context.Context, input InventoryInput) (*SailingInventory, error) {
+	sailing, info, err := loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.quoteRepo.ListBySailing(ctx, sailing.ID, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.reportRepo.ListBySailing(ctx, sailing.ID, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := s.visibleSuppliers(ctx, quotes, reports, input.SupplierIDs, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quotes are newest first, reports oldest first
+	latest := make(map[cellKey]domain.InventoryObservation)
+	for i := range quotes {
+		q := &quotes[i]
+		key := cellKey{q.CabinTypeID, q.SupplierID}
+		if _, ok := latest[key]; !ok {
+			latest[key] = quoteObservation(q)
+		}
+	}
+	for i := range reports {
+		r := &reports[i]
+		key := cellKey{r.CabinTypeID, r.SupplierID}
+		if current, ok := latest[key]; !ok || !r.ReportedAt.Before(current.ObservedAt) {
+			latest[key] = domain.ReportInventory(r)
+		}
+	}
+
+	cabinTypes, err := s.cabinTypeRepo.ListByShip(ctx, sailing.ShipID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uint64]string, len(categories))
+	categoryOrder := make(map[uint64]int, len(categories))
+	for _, cc := range categories {
+		categoryNames[cc.ID] = cc.Name
+		categoryOrder[cc.ID] = cc.SortOrder
+	}
+	sort.SliceStable(cabinTypes, func(i, j int) bool {
+		return categoryOrder[cabinTypes[i].CategoryID] < categoryOrder[cabinTypes[j].CategoryID]
+	})
+
+	supplierList := sortedSuppliers(suppliers)
+
+	result := &SailingInventory{Sailing: *info, CabinTypes: []CabinInventory{}}
+	for _, ct := range cabinTypes {
+		cabin := CabinInventory{
+			CabinTypeID:       ct.ID,
+			CabinTypeName:     ct.Name,
+			CabinCategoryID:   ct.CategoryID,
+			CabinCategoryName: categoryNames[ct.CategoryID],
+			Suppliers:         []SupplierInventory{},
+		}
+
+		for _, supplier := range supplierList {
+			observation, ok := latest[cellKey{ct.ID, supplier.ID}]
+			if !ok {
+				continue
+			}
+			cabin.Suppliers = append(cabin.Suppliers, SupplierInventory{
+				SupplierID:           supplier.ID,
+				SupplierName:         supplier.Name,
+				InventoryObservation: observation,
+			})
+			cabin.add(observation)
+		}
+
+		// Only cabin types some supplier has said something about
+		if len(cabin.Suppliers) == 0 {
+			continue
+		}
+		cabin.SoldOut = cabin.AvailableSuppliers == 0
+		result.TotalQuantity += cabin.TotalQuantity
+		result.CabinTypes = append(result.CabinTypes, cabin)
+	}
+
+	return result, nil
+}
+
+// SupplierInventoryTrend is the inventory history of one supplier
+type SupplierInventoryTrend struct {
+	SupplierID   uint64                        `json:"supplier_id"`
+	SupplierName string                        `json:"supplier_name"`
+	Points       []domain.InventoryObservation `json:"points"`
+}
+
+// MarketInventoryPoint is the market inventory right after an observation of any supplier
+type MarketInventoryPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	MarketInventory
+}
+
+// InventoryTrend is the inventory history of a sailing cabin type across suppliers
+type InventoryTrend struct {
+	Sailing   SailingInfo              `json:"sailing"`
+	CabinType CabinTypeInfo            `json:"cabin_type"`
+	Suppliers []SupplierInventoryTrend `json:"suppliers"`
+	Market    []MarketInventoryPoint   `json:"market"`
+}
+
+// InventoryTrendInput represents the input for an inventory trend
+type InventoryTrendInput struct {
+	SailingID    uint64
+	CabinTypeID  uint64
+	SupplierIDs  []uint64 // Optional, defaults to every visible supplier
+	From         *time.Time
+	To           *time.Time
+	UserRole     domain.UserRole
+	UserSupplier uint64
+}
+
+// GetInventoryTrend returns the inventory observations of each visible supplier for a sailing
+// cabin type within the time range, oldest first: the cabin quantity of its quotes and their
+// confirmations, including expired quotes, and its reports. The market series sums the latest
+// observation of every supplier after each observation.
+func (s *InventoryService) GetInventoryTrend(ctx context.Context, input InventoryTrendInput) (*InventoryTrend, error) {
+	sailing, info, err := loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	cabinType, err := s.cabinTypeRepo.GetByID(ctx, input.CabinTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if cabinType == nil || cabinType.ShipID != sailing.ShipID {
+		return nil, ErrCabinTypeNotFound
+	}
+
+	cabinInfo := CabinTypeInfo{ID: cabinType.ID, Name: cabinType.Name}
+	category, err := s.categoryRepo.GetByID(ctx, cabinType.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category != nil {
+		cabinInfo.CategoryName = category.Name
+	}
+
+	quotes, err := s.quoteRepo.ListForTrend(ctx, sailing.ID, cabinType.ID, input.SupplierIDs, input.From, input.To, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.reportRepo.ListBySailing(ctx, sailing.ID, &cabinType.ID, input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := s.visibleSuppliers(ctx, quotes, reports, input.SupplierIDs, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	type supplierObservation struct {
+		supplierID uint64
+		domain.InventoryObservation
+	}
+	var observations []supplierObservation
+
+	confirmed := make(map[uint64]*domain.PriceQuote)
+	var confirmedIDs []uint64
+	for i := range quotes {
+		q := &quotes[i]
+		if _, ok := suppliers[q.SupplierID]; !ok {
+			continue
+		}
+		// A quote created before the range only counts through its confirmations in it
+		if input.From == nil || !q.CreatedAt.Before(*input.From) {
+			observations = append(observations, supplierObservation{q.SupplierID, domain.QuoteInventory(q, domain.InventoryFromQuote, q.CreatedAt)})
+		}
+		if q.ConfirmationCount > 0 {
+			confirmed[q.ID] = q
+			confirmedIDs = append(confirmedIDs, q.ID)
+		}
+	}
+
+	confirmations, err := s.quoteRepo.ListConfirmations(ctx, confirmedIDs, input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range confirmations {
+		q := confirmed[c.QuoteID]
+		observations = append(observations, supplierObservation{q.SupplierID, domain.QuoteInventory(q, domain.InventoryFromConfirmation, c.ConfirmedAt)})
+	}
+
+	for i := range reports {
+		r := &reports[i]
+		if _, ok := suppliers[r.SupplierID]; !ok {
+			continue
+		}
+		observations = append(observations, supplierObservation{r.SupplierID, domain.ReportInventory(r)})
+	}
+
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].ObservedAt.Before(observations[j].ObservedAt)
+	})
+
+	result := &InventoryTrend{
+		Sailing:   *info,
+		CabinType: cabinInfo,
+		Suppliers: []SupplierInventoryTrend{},
+		Market:    []MarketInventoryPoint{},
+	}
+
+	trends := make(map[uint64]*SupplierInventoryTrend, len(suppliers))
+	latest := make(map[uint64]domain.InventoryObservation, len(suppliers))
+	for _, o := range observations {
+		trend, ok := trends[o.supplierID]
+		if !ok {
+			supplier := suppliers[o.supplierID]
+			trend = &SupplierInventoryTrend{SupplierID: supplier.ID, SupplierName: supplier.Name}
+			trends[o.supplierID] = trend
+		}
+		trend.Points = append(trend.Points, o.InventoryObservation)
+		latest[o.supplierID] = o.InventoryObservation
+
+		point := MarketInventoryPoint{Timestamp: o.ObservedAt}
+		for _, l := range latest {
+			point.add(l)
+		}
+		point.SoldOut = point.AvailableSuppliers == 0
+
+		// Observations at the same instant make up one market point
+		if n := len(result.Market); n > 0 && result.Market[n-1].Timestamp.Equal(point.Timestamp) {
+			result.Market[n-1] = point
+		} else {
+			result.Market = append(result.Market, point)
+		}
+	}
+
+	for _, trend := range trends {
+		result.Suppliers = append(result.Suppliers, *trend)
+	}
+	sort.Slice(result.Suppliers, func(i, j int) bool {
+		return result.Suppliers[i].SupplierName < result.Suppliers[j].SupplierName
+	})
+
+	return result, nil
+}
+
+// visibleSuppliers returns the suppliers of the quotes and reports the user may see
+func (s *InventoryService) visibleSuppliers(ctx context.Context, quotes []domain.PriceQuote, reports []domain.CabinInventoryReport, requested []uint64, userRole domain.UserRole, userSupplier uint64) (map[uint64]*domain.Supplier, error) {
+	ids := make([]uint64, 0, len(quotes)+len(reports))
+	for i := range quotes {
+		ids = append(ids, quotes[i].SupplierID)
+	}
+	for i := range reports {
+		ids = append(ids, reports[i].SupplierID)
+	}
+
+	return visibleSupplierIDs(ctx, s.supplierRepo, ids, requested, userRole, userSupplier)
+}
+
+// quoteObservation returns the inventory stated by a current quote as of its last confirmation
+func quoteObservation(q *domain.PriceQuote) domain.InventoryObservation {
+	if q.LastConfirmedAt != nil {
+		return domain.QuoteInventory(q, domain.InventoryFromConfirmation, *q.LastConfirmedAt)
+	}
+	return domain.QuoteInventory(q, domain.InventoryFromQuote, q.CreatedAt)
+}
+
+// sortedSuppliers returns the suppliers ordered by name
+func sortedSuppliers(suppliers map[uint64]*domain.Supplier) []*domain.Supplier {
+	list := make([]*domain.Supplier, 0, len(suppliers))
+	for _, supplier := range suppliers {
+		list = append(list, supplier)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/width"
+)
+
+// NameNormalizer folds catalog names into comparable keys.
+// Full-width characters are narrowed, traditional Chinese is mapped to simplified and
+// case and punctuation are dropped, so "海洋光谱号" and "海洋光譜號" compare equal.
+// Han characters can be romanized to pinyin, but since different names can sound the
+// same, "Haiyang Guangpu Hao" only scores as similar to "海洋光谱号", never as equal.
+type NameNormalizer struct {
+	pinyinArgs pinyin.Args
+}
+
+// NewNameNormalizer creates a new name normalizer
+func NewNameNormalizer() *NameNormalizer {
+	args := pinyin.NewArgs()
+	args.Style = pinyin.Normal
+	return &NameNormalizer{pinyinArgs: args}
+}
+
+// Normalize returns the comparison form of a name
+func (n *NameNormalizer) Normalize(name string) string {
+	folded := width.Fold.String(name)
+
+	var b strings.Builder
+	b.Grow(len(folded))
+	for _, r := range folded {
+		if s, ok := traditionalToSimplified[r]; ok {
+			r = s
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			// Punctuation, symbols and all kinds of spaces become separators
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// PinyinKey returns the normalized name with Han characters romanized and all
+// separators removed, e.g. "海洋 光谱号" -> "haiyangguangpuhao"
+func (n *NameNormalizer) PinyinKey(name string) string {
+	normalized := n.Normalize(name)
+
+	var b strings.Builder
+	b.Grow(len(normalized) * 2)
+	for _, r := range normalized {
+		if r == ' ' {
+			continue
+		}
+		if unicode.Is(unicode.Han, r) {
+			if syllables := pinyin.SinglePinyin(r, n.pinyinArgs); len(syllables) > 0 {
+				b.WriteString(syllables[0])
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Equal reports whether two names are the same after normalization. Names that
+// only share their pinyin romanization are not equal, see Similarity.
+func (n *NameNormalizer) Equal(a, b string) bool {
+	na, nb := n.Normalize(a), n.Normalize(b)
+	return na != "" && na == nb
+}
+
+// pinyinSimilarityWeight scales the similarity of names compared by pinyin, so that
+// a romanization or homophone never scores as an exact match
+const pinyinSimilarityWeight = 0.9
+
+// Similarity calculates similarity between two names (0.0 to 1.0).
+// Names written in the same script are compared on their normalized form; when
+// only one side contains Han characters both sides are compared as pinyin, scaled
+// by pinyinSimilarityWeight.
+func (n *NameNormalizer) Similarity(a, b string) float64 {
+	na, nb := n.Normalize(a), n.Normalize(b)
+	if na == "" || nb == "" {
+		return 0.0
+	}
+	if na == nb {
+		return 1.0
+	}
+
+	if containsHan(na) != containsHan(nb) {
+		return pinyinSimilarityWeight * stringSimilarity(n.PinyinKey(a), n.PinyinKey(b))
+	}
+	return stringSimilarity(na, nb)
+}
+
+// BestSimilarity returns the highest similarity between name and any of the candidates
+func (n *NameNormalizer) BestSimilarity(name string, candidates ...string) float64 {
+	best := 0.0
+	for _, candidate := range candidates {
+		if score := n.Similarity(name, candidate); score > best {
+			best = score
+			if best == 1.0 {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// containsHan checks whether s contains at least one Han character
+func containsHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringSimilarity calculates similarity between two strings (0.0 to 1.0)
+// Uses a simple Levenshtein-like approach over runes
+func stringSimilarity(s1, s2 string) float64 {
+	// Quick checks
+	if s1 == s2 {
+		return 1.0
+	}
+	if s1 == "" || s2 == "" {
+		return 0.0
+	}
+
+	r1, r2 := []rune(s1), []rune(s2)
+	shorter, longer := len(r1), len(r2)
+	if shorter > longer {
+		shorter, longer = longer, shorter
+	}
+
+	// Check if one contains the other
+	if strings.Contains(s1, s2) || strings.Contains(s2, s1) {
+		return float64(shorter) / float64(longer)
+	}
+
+	// Calculate Levenshtein distance
+	distance := levenshteinDistance(r1, r2)
+	return 1.0 - (float64(distance) / float64(longer))
+}
+
+// levenshteinDistance calculates the Levenshtein distance between two rune slices
+func levenshteinDistance(s1, s2 []rune) int {
+	if len(s1) == 0 {
+		return len(s2)
+	}
+	if len(s2) == 0 {
+		return len(s1)
+	}
+
+	// Two rolling rows are enough
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+			)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(s2)]
+}
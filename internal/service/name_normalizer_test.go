@@ -0,0 +1,77 @@
+package service
+
+import "testing"
+
+func TestNameNormalizerNormalize(t *testing.T) {
+	n := NewNameNormalizer()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"traditional to simplified", "海洋光譜號", "海洋光谱号"},
+		{"traditional variants", "臺灣麗星郵輪", "台湾丽星邮轮"},
+		{"full-width letters and digits", "ＭＳＣ　Ｍｅｒａｖｉｇｌｉａ　２", "msc meraviglia 2"},
+		{"full-width punctuation", "阳台房（Ａ）", "阳台房 a"},
+		{"case and separators", "  Spectrum-of  the_Seas ", "spectrum of the seas"},
+		{"empty after folding", "——", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameNormalizerEqual(t *testing.T) {
+	n := NewNameNormalizer()
+
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"traditional and simplified", "海洋光譜號", "海洋光谱号", true},
+		{"full-width and half-width", "ＭＳＣ Ｂｅｌｌｉｓｓｉｍａ", "msc bellissima", true},
+		{"pinyin romanization", "Haiyang Guangpu Hao", "海洋光谱号", false},
+		{"homophones", "海洋光谱号", "海洋光普号", false},
+		{"homophone ship names", "星梦号", "新梦号", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameNormalizerSimilarity(t *testing.T) {
+	n := NewNameNormalizer()
+
+	if got := n.Similarity("海洋光譜號", "海洋光谱号"); got != 1.0 {
+		t.Errorf("variant similarity = %v, want 1.0", got)
+	}
+
+	// A romanization matches as pinyin, but below an exact match
+	romanized := n.Similarity("Haiyang Guangpu Hao", "海洋光谱号")
+	if romanized >= 1.0 || romanized < 0.8 {
+		t.Errorf("romanized similarity = %v, want in [0.8, 1.0)", romanized)
+	}
+
+	// Homophones in Han script are compared character by character, not by sound
+	homophone := n.Similarity("星梦号", "新梦号")
+	if homophone >= romanized {
+		t.Errorf("homophone similarity = %v, want below the romanized similarity %v", homophone, romanized)
+	}
+
+	if got := n.BestSimilarity("Haiyang Guangpu Hao", "星梦号", "海洋光谱号"); got != romanized {
+		t.Errorf("BestSimilarity = %v, want %v", got, romanized)
+	}
+}
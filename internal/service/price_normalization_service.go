@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// Occupancy errors
+var (
+	ErrInvalidOccupancy  = errors.New("occupancy needs at least one adult and no negative counts")
+	ErrOccupancyExceeded = errors.New("occupancy exceeds the cabin's maximum occupancy")
+)
+
+// Guest price basis: how the price of a guest was derived
+const (
+	PriceBasisBaseFare         = "BASE_FARE"         // per-person fare at base occupancy
+	PriceBasisOccupancyRate    = "OCCUPANCY_RATE"    // explicit guest slot / child rate
+	PriceBasisSingleSupplement = "SINGLE_SUPPLEMENT" // per-person fare plus the single supplement
+	PriceBasisAssumed          = "ASSUMED"           // the quote does not price this guest; a fallback was used
+)
+
+// Occupancy is the party a cabin price is normalized for
+type Occupancy struct {
+	Adults   int `json:"adults"`
+	Children int `json:"children"`
+}
+
+// Guests returns the party size
+func (o Occupancy) Guests() int {
+	return o.Adults + o.Children
+}
+
+// GuestPrice is the price of one guest in a normalized cabin price
+type GuestPrice struct {
+	GuestSlot int              `json:"guest_slot"`
+	GuestType domain.GuestType `json:"guest_type"`
+	Price     decimal.Decimal  `json:"price"`
+	Basis     string           `json:"basis"`
+}
+
+// NormalizedPrice is a quote priced for a given occupancy, expressed per cabin,
+// per person and per night so quotes with different pricing units compare like for like
+type NormalizedPrice struct {
+	Occupancy         Occupancy        `json:"occupancy"`
+	Currency          string           `json:"currency"`
+	PerCabin          decimal.Decimal  `json:"per_cabin"` // what the party pays for the cabin
+	PerPerson         decimal.Decimal  `json:"per_person"`
+	PerNight          *decimal.Decimal `json:"per_night,omitempty"` // per cabin; nil when nights are unknown
+	PerPersonPerNight *decimal.Decimal `json:"per_person_per_night,omitempty"`
+	Nights            int              `json:"nights"`
+	Guests            []GuestPrice     `json:"guests"`
+	Estimated         bool             `json:"estimated"` // true if any guest price was assumed
+	Notes             []string         `json:"notes,omitempty"`
+
+	// Including the charges the quote lists as payable on top of the price
+	AllInPerCabin  decimal.Decimal `json:"all_in_per_cabin"`
+	AllInPerPerson decimal.Decimal `json:"all_in_per_person"`
+}
+
+// Convert returns the normalized price converted at the given rate
+func (n *NormalizedPrice) Convert(rate decimal.Decimal, currency string) *NormalizedPrice {
+	converted := *n
+	converted.Currency = currency
+	converted.PerCabin = n.PerCabin.Mul(rate).Round(fxAmountPrecision)
+	converted.PerPerson = n.PerPerson.Mul(rate).Round(fxAmountPrecision)
+	if n.PerNight != nil {
+		perNight := n.PerNight.Mul(rate).Round(fxAmountPrecision)
+		converted.PerNight = &perNight
+	}
+	if n.PerPersonPerNight != nil {
+		perPersonPerNight := n.PerPersonPerNight.Mul(rate).Round(fxAmountPrecision)
+		converted.PerPersonPerNight = &perPersonPerNight
+	}
+	converted.AllInPerCabin = n.AllInPerCabin.Mul(rate).Round(fxAmountPrecision)
+	converted.AllInPerPerson = n.AllInPerPerson.Mul(rate).Round(fxAmountPrecision)
+	converted.Guests = make([]GuestPrice, len(n.Guests))
+	for i, g := range n.Guests {
+		g.Price = g.Price.Mul(rate).Round(fxAmountPrecision)
+		converted.Guests[i] = g
+	}
+	return &converted
+}
+
+// NormalizeQuotePrice prices a quote for the given occupancy. Adults take the first
+// berths and children the following ones. Each berth uses, in order: an explicit
+// rate for the slot and guest type, the adult rate of the slot for a child, the
+// per-person fare within base occupancy, the rate of the nearest lower extra berth,
+// and finally the per-person fare, which marks the result as estimated.
+// A single adult pays the per-person fare plus the single supplement; without a
+// stated supplement, and for any party below base occupancy of a cabin-priced
+// quote, the cabin price is assumed to cover the party.
+// The all-in figures add the exclusive price components: per-person charges for
+// every guest, per-cabin charges once.
+func NormalizeQuotePrice(quote *domain.PriceQuote, occupancy Occupancy, nights int) (*NormalizedPrice, error) {
+	if occupancy.Adults < 1 || occupancy.Children < 0 {
+		return nil, ErrInvalidOccupancy
+	}
+	guests := occupancy.Guests()
+	if quote.MaxOccupancy != nil && guests > *quote.MaxOccupancy {
+		return nil, fmt.Errorf("%w: %d guests, cabin sleeps %d", ErrOccupancyExceeded, guests, *quote.MaxOccupancy)
+	}
+
+	base := quote.BaseOccupancy(0)
+	perPerson := quote.PricePerPerson(0)
+	cabinPriced := quote.PricingUnit == domain.PricingUnitPerCabin || quote.PricingUnit == domain.PricingUnitTotal
+
+	result := &NormalizedPrice{
+		Occupancy: occupancy,
+		Currency:  quote.Currency,
+		Nights:    nights,
+		Guests:    make([]GuestPrice, 0, guests),
+	}
+
+	switch {
+	case guests == 1 && quote.HasSingleSupplement():
+		price := perPerson
+		if rate := quote.OccupancyRate(1, domain.GuestTypeAdult); rate != nil {
+			price = rate.Price
+		}
+		if quote.SingleSupplementPct != nil {
+			price = price.Add(perPerson.Mul(*quote.SingleSupplementPct).Div(decimal.NewFromInt(100)))
+		}
+		if quote.SingleSupplementAmount != nil {
+			price = price.Add(*quote.SingleSupplementAmount)
+		}
+		result.Guests = append(result.Guests, GuestPrice{
+			GuestSlot: 1,
+			GuestType: domain.GuestTypeAdult,
+			Price:     price.Round(fxAmountPrecision),
+			Basis:     PriceBasisSingleSupplement,
+		})
+
+	case guests < base && (guests == 1 || cabinPriced):
+		// The cabin is sold as a unit: the party pays the base occupancy price
+		cabinPrice := perPerson.Mul(decimal.NewFromInt(int64(base)))
+		share := cabinPrice.Div(decimal.NewFromInt(int64(guests))).Round(fxAmountPrecision)
+		for slot := 1; slot <= guests; slot++ {
+			result.Guests = append(result.Guests, GuestPrice{
+				GuestSlot: slot,
+				GuestType: guestTypeOfSlot(slot, occupancy),
+				Price:     share,
+				Basis:     PriceBasisAssumed,
+			})
+		}
+		if guests == 1 {
+			result.Notes = append(result.Notes, "no single supplement quoted; assumed the full cabin fare")
+		} else {
+			result.Notes = append(result.Notes, fmt.Sprintf("party below base occupancy of %d; assumed the full cabin fare", base))
+		}
+
+	default:
+		for slot := 1; slot <= guests; slot++ {
+			guestType := guestTypeOfSlot(slot, occupancy)
+			price, basis, note := slotPrice(quote, slot, guestType, base, perPerson)
+			result.Guests = append(result.Guests, GuestPrice{
+				GuestSlot: slot,
+				GuestType: guestType,
+				Price:     price.Round(fxAmountPrecision),
+				Basis:     basis,
+			})
+			if note != "" {
+				result.Notes = append(result.Notes, note)
+			}
+		}
+	}
+
+	total := decimal.Zero
+	for _, g := range result.Guests {
+		total = total.Add(g.Price)
+		if g.Basis == PriceBasisAssumed {
+			result.Estimated = true
+		}
+	}
+
+	result.PerCabin = total.Round(fxAmountPrecision)
+	result.PerPerson = total.Div(decimal.NewFromInt(int64(guests))).Round(fxAmountPrecision)
+	if nights > 0 {
+		n := decimal.NewFromInt(int64(nights))
+		perNight := total.Div(n).Round(fxAmountPrecision)
+		perPersonPerNight := total.Div(n).Div(decimal.NewFromInt(int64(guests))).Round(fxAmountPrecision)
+		result.PerNight = &perNight
+		result.PerPersonPerNight = &perPersonPerNight
+	}
+
+	extras, complete := quote.ExtraCharges(guests, nights)
+	if !complete {
+		result.Notes = append(result.Notes, "nights unknown; per-night charges left out of the all-in price")
+	}
+	allIn := total.Add(extras)
+	result.AllInPerCabin = allIn.Round(fxAmountPrecision)
+	result.AllInPerPerson = allIn.Div(decimal.NewFromInt(int64(guests))).Round(fxAmountPrecision)
+
+	return result, nil
+}
+
+// guestTypeOfSlot returns who occupies a berth: adults fill the first berths
+func guestTypeOfSlot(slot int, occupancy Occupancy) domain.GuestType {
+	if slot <= occupancy.Adults {
+		return domain.GuestTypeAdult
+	}
+	return domain.GuestTypeChild
+}
+
+// slotPrice returns the price of one berth, how it was derived and an optional note
+func slotPrice(quote *domain.PriceQuote, slot int, guestType domain.GuestType, base int, perPerson decimal.Decimal) (decimal.Decimal, string, string) {
+	if rate := quote.OccupancyRate(slot, guestType); rate != nil {
+		return rate.Price, PriceBasisOccupancyRate, ""
+	}
+	if guestType == domain.GuestTypeChild {
+		if rate := quote.OccupancyRate(slot, domain.GuestTypeAdult); rate != nil {
+			return rate.Price, PriceBasisOccupancyRate, ""
+		}
+	}
+	if slot <= base {
+		return perPerson, PriceBasisBaseFare, ""
+	}
+
+	// Extra berths without their own rate share the rate of the nearest lower extra berth
+	for lower := slot - 1; lower > base; lower-- {
+		if rate := quote.OccupancyRate(lower, guestType); rate != nil {
+			return rate.Price, PriceBasisOccupancyRate, ""
+		}
+		if rate := quote.OccupancyRate(lower, domain.GuestTypeAdult); rate != nil {
+			return rate.Price, PriceBasisOccupancyRate, ""
+		}
+	}
+
+	return perPerson, PriceBasisAssumed, fmt.Sprintf("no rate quoted for guest %d; assumed the per-person fare", slot)
+}
+
+// PriceNormalizationService normalizes stored quotes for a chosen occupancy
+type PriceNormalizationService struct {
+	quoteRepo   *repo.PriceQuoteRepository
+	sailingRepo *repo.SailingRepository
+	fx          *FXService
+}
+
+// NewPriceNormalizationService creates a new price normalization service
+func NewPriceNormalizationService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	fx *FXService,
+) *PriceNormalizationService {
+	return &PriceNormalizationService{
+		quoteRepo:   quoteRepo,
+		sailingRepo: sailingRepo,
+		fx:          fx,
+	}
+}
+
+// QuoteNormalization is a quote normalized for an occupancy, optionally also in a display currency
+type QuoteNormalization struct {
+	QuoteID    uint64           `json:"quote_id"`
+	Normalized *NormalizedPrice `json:"normalized"`
+	Display    *NormalizedPrice `json:"display,omitempty"`
+	Conversion *ConvertedAmount `json:"conversion,omitempty"` // rate used for Display
+}
+
+// NormalizeQuote normalizes a stored quote for the given occupancy
+func (s *PriceNormalizationService) NormalizeQuote(ctx context.Context, id uint64, occupancy Occupancy, displayCurrency string, userRole domain.UserRole, userSupplier uint64) (*QuoteNormalization, error) {
+	displayCurrency, err := NormalizeCurrency(displayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.quoteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if quote == nil {
+		return nil, errors.New("quote not found")
+	}
+
+	// Vendor can only see their own supplier's quotes
+	if userRole == domain.UserRoleVendor && quote.SupplierID != userSupplier {
+		return nil, errors.New("forbidden: cannot access other supplier's quotes")
+	}
+
+	sailing, err := s.sailingRepo.GetByID(ctx, quote.SailingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sailing: %w", err)
+	}
+	nights := 0
+	if sailing != nil {
+		nights = sailing.Nights
+		if nights == 0 {
+			nights = sailing.CalculateNights()
+		}
+	}
+
+	normalized, err := NormalizeQuotePrice(quote, occupancy, nights)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QuoteNormalization{QuoteID: quote.ID, Normalized: normalized}
+
+	conversion, err := s.fx.NewConverter(displayCurrency).Convert(ctx, quote.Price, quote.Currency, quote.PricedAt())
+	if err != nil {
+		return nil, err
+	}
+	if conversion != nil {
+		result.Conversion = conversion
+		result.Display = normalized.Convert(conversion.Rate, conversion.Currency)
+	}
+
+	return result, nil
+}
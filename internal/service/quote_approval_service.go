@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+)
+
+// Quote approval errors
+var (
+	ErrReviewReasonRequired = errors.New("a reason is required to reject quotes")
+)
+
+const (
+	// reviewReasonMaxLength is the size of the price_quote.review_reason column
+	reviewReasonMaxLength = 500
+
+	// reviewMaxQuotes bounds the quotes approved or rejected in one request
+	reviewMaxQuotes = 500
+)
+
+// QuoteApprovalService manages the approval of quotes from suppliers whose quotes must be
+// reviewed before publication: their new quotes wait as PENDING_REVIEW, hidden from
+// comparisons and trends, until an admin approves or rejects them
+type QuoteApprovalService struct {
+	quoteRepo    *repo.PriceQuoteRepository
+	supplierRepo *repo.SupplierRepository
+	alertService *AlertService
+	audit        *obs.AuditService
+}
+
+// NewQuoteApprovalService creates a new quote approval service
+func NewQuoteApprovalService(
+	quoteRepo *repo.PriceQuoteRepository,
+	supplierRepo *repo.SupplierRepository,
+	alertService *AlertService,
+	audit *obs.AuditService,
+) *QuoteApprovalService {
+	return &QuoteApprovalService{
+		quoteRepo:    quoteRepo,
+		supplierRepo: supplierRepo,
+		alertService: alertService,
+		audit:        audit,
+	}
+}
+
+// SetApprovalPolicy sets whether new quotes of a supplier wait for approval. Quotes already
+// stored keep their status.
+func (s *QuoteApprovalService) SetApprovalPolicy(ctx context.Context, supplierID uint64, requiresApproval bool, userID uint64) (*domain.Supplier, error) {
+	old, err := s.supplierRepo.GetByID(ctx, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier: %w", err)
+	}
+	if old == nil {
+		return nil, ErrSupplierNotFound
+	}
+
+	supplier := *old
+	supplier.RequiresApproval = requiresApproval
+
+	event := catalogEvent(ctx, domain.EntityTypeSupplier, domain.DomainEventActionUpdated, supplierID, userID, &supplier)
+	if err := s.supplierRepo.SetRequiresApproval(ctx, supplierID, requiresApproval, event); err != nil {
+		return nil, fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	if s.audit != nil {
+		_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeSupplier, supplierID, old, &supplier)
+	}
+
+	return &supplier, nil
+}
+
+// ListPendingInput represents the input for the approval queue
+type ListPendingInput struct {
+	Pagination repo.Pagination
+	SupplierID *uint64 // Optional
+	SailingID  *uint64 // Optional
+}
+
+// ListPending returns the quotes waiting for approval, oldest first
+func (s *QuoteApprovalService) ListPending(ctx context.Context, input ListPendingInput) (repo.PaginatedResult[domain.PriceQuote], error) {
+	return s.quoteRepo.ListPendingReview(ctx, input.Pagination, input.SupplierID, input.SailingID)
+}
+
+// ReviewQuotesInput represents the input for approving or rejecting quotes in bulk
+type ReviewQuotesInput struct {
+	QuoteIDs []uint64
+	Reason   string // Required to reject
+	UserID   uint64
+}
+
+// ReviewResult lists the quotes a review changed and the ones it skipped because they
+// were not waiting for approval
+type ReviewResult struct {
+	Quotes  []domain.PriceQuote `json:"quotes"`
+	Skipped []uint64            `json:"skipped,omitempty"`
+}
+
+// QuoteReviewedEvent is the event payload of an approved or rejected quote
+type QuoteReviewedEvent struct {
+	Quote  *domain.PriceQuote `json:"quote"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+// ApproveQuotes publishes quotes waiting for approval: they become ACTIVE and count in
+// comparisons, trends and price alerts, and are announced with the quote.created event held
+// back when they were submitted
+func (s *QuoteApprovalService) ApproveQuotes(ctx context.Context, input ReviewQuotesInput) (*ReviewResult, error) {
+	return s.review(ctx, input, domain.QuoteStatusActive, domain.DomainEventQuoteApproved)
+}
+
+// RejectQuotes rejects quotes waiting for approval; they are kept as REJECTED and never published
+func (s *QuoteApprovalService) RejectQuotes(ctx context.Context, input ReviewQuotesInput) (*ReviewResult, error) {
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, ErrReviewReasonRequired
+	}
+	return s.review(ctx, input, domain.QuoteStatusRejected, domain.DomainEventQuoteRejected)
+}
+
+// review moves the quotes of the input that wait for approval to status
+func (s *QuoteApprovalService) review(ctx context.Context, input ReviewQuotesInput, status domain.QuoteStatus, eventType string) (*ReviewResult, error) {
+	ids, err := validateReview(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewedAt := time.Now()
+	reviewedBy := input.UserID
+	toReviewed := func(q domain.PriceQuote) domain.PriceQuote {
+		q.Status = status
+		q.StatusChangedAt = &reviewedAt
+		q.ReviewedBy = &reviewedBy
+		q.ReviewedAt = &reviewedAt
+		q.ReviewReason = input.Reason
+		return q
+	}
+
+	before, err := s.quoteRepo.ReviewQuotes(ctx, ids, status, input.UserID, input.Reason, reviewedAt, func(q *domain.PriceQuote) []*domain.DomainEvent {
+		reviewed := toReviewed(*q)
+		return reviewEvents(ctx, &reviewed, eventType, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReviewResult{Quotes: make([]domain.PriceQuote, 0, len(before))}
+	reviewed := make(map[uint64]bool, len(before))
+	for i := range before {
+		after := toReviewed(before[i])
+		reviewed[after.ID] = true
+		result.Quotes = append(result.Quotes, after)
+
+		if s.audit != nil {
+			_ = s.audit.LogUpdate(ctx, input.UserID, &after.SupplierID, "PriceQuote", after.ID, before[i], after)
+		}
+
+		// Price alerts were held back while the quote waited for approval
+		if status == domain.QuoteStatusActive && s.alertService != nil && !after.IsBackfill() {
+			s.alertService.EvaluateQuote(ctx, &after)
+		}
+	}
+	for _, id := range ids {
+		if !reviewed[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+
+	return result, nil
+}
+
+// reviewEvents builds the domain events of a reviewed quote: the review itself and, for an
+// approved quote, the quote.created event held back while it waited for approval. Reviews of
+// backfilled quotes get their own event type, which webhooks do not subscribe to.
+func reviewEvents(ctx context.Context, reviewed *domain.PriceQuote, eventType string, input ReviewQuotesInput) []*domain.DomainEvent {
+	if reviewed.IsBackfill() {
+		eventType = domain.DomainEventQuoteBackfillReviewed
+	}
+	events := []*domain.DomainEvent{newDomainEvent(ctx, eventType, domain.EntityTypePriceQuote, reviewed.ID, input.UserID, &reviewed.SupplierID,
+		QuoteReviewedEvent{Quote: reviewed, Reason: input.Reason})}
+	if reviewed.Status == domain.QuoteStatusActive {
+		events = append(events, quoteCreatedEvent(ctx, reviewed, reviewed.CreatedBy))
+	}
+	return events
+}
+
+// validateReview checks and normalizes the review input and returns the distinct quote IDs
+func validateReview(input *ReviewQuotesInput) ([]uint64, error) {
+	var errs domain.ValidationErrors
+
+	input.Reason = strings.TrimSpace(input.Reason)
+	if len(input.Reason) > reviewReasonMaxLength {
+		errs.Add("reason", domain.ErrFieldTooLong)
+	}
+
+	seen := make(map[uint64]bool, len(input.QuoteIDs))
+	ids := make([]uint64, 0, len(input.QuoteIDs))
+	for _, id := range input.QuoteIDs {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		errs.Add("quote_ids", domain.ErrFieldRequired)
+	} else if len(ids) > reviewMaxQuotes {
+		errs.AddMsg("quote_ids", fmt.Sprintf("at most %d quotes", reviewMaxQuotes))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return ids, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cruise-price-compare/internal/domain"
+)
+
+func TestValidateReview(t *testing.T) {
+	input := ReviewQuotesInput{QuoteIDs: []uint64{3, 0, 1, 3}, Reason: "  checked  "}
+	ids, err := validateReview(&input)
+	if err != nil {
+		t.Fatalf("validateReview() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 1 {
+		t.Errorf("validateReview() ids = %v, want [3 1]", ids)
+	}
+	if input.Reason != "checked" {
+		t.Errorf("reason = %q, want it trimmed", input.Reason)
+	}
+
+	tests := []struct {
+		name  string
+		input ReviewQuotesInput
+		field string
+	}{
+		{"no quotes", ReviewQuotesInput{QuoteIDs: []uint64{0}}, "quote_ids"},
+		{"too many quotes", ReviewQuotesInput{QuoteIDs: sequence(reviewMaxQuotes + 1)}, "quote_ids"},
+		{"reason too long", ReviewQuotesInput{QuoteIDs: []uint64{1}, Reason: strings.Repeat("x", reviewReasonMaxLength+1)}, "reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateReview(&tt.input)
+			var errs domain.ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != tt.field {
+				t.Errorf("validateReview() error = %v, want a %s validation error", err, tt.field)
+			}
+		})
+	}
+}
+
+func sequence(n int) []uint64 {
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = uint64(i + 1)
+	}
+	return ids
+}
+
+func TestReviewEvents(t *testing.T) {
+	ctx := context.Background()
+	quote := func(status domain.QuoteStatus, source domain.QuoteSource) *domain.PriceQuote {
+		return &domain.PriceQuote{ID: 7, SupplierID: 2, Status: status, Source: source, CreatedBy: 5}
+	}
+	input := ReviewQuotesInput{UserID: 1, Reason: "ok"}
+
+	tests := []struct {
+		name  string
+		quote *domain.PriceQuote
+		event string
+		want  []string
+	}{
+		{"approved", quote(domain.QuoteStatusActive, domain.QuoteSourceManual), domain.DomainEventQuoteApproved,
+			[]string{domain.DomainEventQuoteApproved, domain.DomainEventQuoteCreated}},
+		{"approved backfill", quote(domain.QuoteStatusActive, domain.QuoteSourceBackfill), domain.DomainEventQuoteApproved,
+			[]string{domain.DomainEventQuoteBackfillReviewed, domain.DomainEventQuoteBackfilled}},
+		{"rejected", quote(domain.QuoteStatusRejected, domain.QuoteSourceManual), domain.DomainEventQuoteRejected,
+			[]string{domain.DomainEventQuoteRejected}},
+		{"rejected backfill", quote(domain.QuoteStatusRejected, domain.QuoteSourceBackfill), domain.DomainEventQuoteRejected,
+			[]string{domain.DomainEventQuoteBackfillReviewed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := reviewEvents(ctx, tt.quote, tt.event, input)
+			if len(events) != len(tt.want) {
+				t.Fatalf("reviewEvents() returned %d events, want %d", len(events), len(tt.want))
+			}
+			for i, event := range events {
+				if event.EventType != tt.want[i] {
+					t.Errorf("event %d type = %s, want %s", i, event.EventType, tt.want[i])
+				}
+				if event.AggregateID != 7 {
+					t.Errorf("event %d aggregate = %d, want 7", i, event.AggregateID)
+				}
+			}
+			// The quote.created event is attributed to whoever submitted the quote
+			if len(events) == 2 && (events[1].UserID == nil || *events[1].UserID != 5) {
+				t.Errorf("quote.created user = %v, want 5", events[1].UserID)
+			}
+		})
+	}
+}
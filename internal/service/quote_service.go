@@ -15,11 +15,13 @@ import (
 
 // QuoteService handles quote business logic
 type QuoteService struct {
-	quoteRepo    *repo.PriceQuoteRepository
-	sailingRepo  *repo.SailingRepository
-	cabinRepo    *repo.CabinTypeRepository
-	supplierRepo *repo.SupplierRepository
-	auditService *obs.AuditService
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	cabinRepo      *repo.CabinTypeRepository
+	supplierRepo   *repo.SupplierRepository
+	alertService   *AlertService
+	anomalyService *AnomalyService
+	auditService   *obs.AuditService
 }
 
 // NewQuoteService creates a new quote service
@@ -28,14 +30,18 @@ func NewQuoteService(
 	sailingRepo *repo.SailingRepository,
 	cabinRepo *repo.CabinTypeRepository,
 	supplierRepo *repo.SupplierRepository,
+	alertService *AlertService,
+	anomalyService *AnomalyService,
 	auditService *obs.AuditService,
 ) *QuoteService {
 	return &QuoteService{
-		quoteRepo:    quoteRepo,
-		sailingRepo:  sailingRepo,
-		cabinRepo:    cabinRepo,
-		supplierRepo: supplierRepo,
-		auditService: auditService,
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		cabinRepo:      cabinRepo,
+		supplierRepo:   supplierRepo,
+		alertService:   alertService,
+		anomalyService: anomalyService,
+		auditService:   auditService,
 	}
 }
 
@@ -48,17 +54,220 @@ type CreateQuoteInput struct {
 	PricingUnit    domain.PricingUnit
 	Conditions     string
 	GuestCount     *int
+	MaxOccupancy   *int
 	Promotion      string
 	CabinQuantity  *int
+	ValidFrom      *time.Time // Optional, first day the price applies
 	ValidUntil     *time.Time
+	QuotedAt       *time.Time // Optional, when the supplier issued the quote; defaults to now
 	Notes          string
 	IdempotencyKey string
-	SupplierID     uint64 // From auth context
-	UserID         uint64 // From auth context
+	ImportJobID    *uint64
+	RFQID          *uint64            // RFQ the quote answers, checked by RFQService
+	Source         domain.QuoteSource // Optional, defaults to MANUAL
+	SupplierID     uint64             // From auth context
+	UserID         uint64             // From auth context
+
+	// Occupancy pricing, optional
+	SingleSupplementPct    string
+	SingleSupplementAmount string
+	OccupancyRates         []OccupancyRateInput
+
+	// Price breakdown, optional
+	Components []PriceComponentInput
+
+	// Structured promotions, optional; Promotion keeps the free text
+	Promotions []PromotionInput
 }
 
-// CreateQuote creates a new quote (manual entry)
+// PromotionInput represents a structured promotion of the quote
+type PromotionInput struct {
+	PromotionType  domain.PromotionType
+	DiscountPct    string
+	DiscountAmount string
+	PricingUnit    domain.PricingUnit // Optional, defaults to the pricing unit of the quote
+	GuestSlots     []int
+	BookingFrom    *time.Time
+	BookingUntil   *time.Time
+	Stackable      bool
+	Description    string
+}
+
+// OccupancyRateInput represents the price of one guest slot of the quoted cabin
+type OccupancyRateInput struct {
+	GuestSlot   int
+	GuestType   domain.GuestType
+	Price       string
+	MaxChildAge *int
+}
+
+// PriceComponentInput represents one charge line of the quoted price
+type PriceComponentInput struct {
+	ComponentType domain.PriceComponentType
+	Amount        string
+	PricingUnit   domain.PricingUnit // Optional, defaults to the pricing unit of the quote
+	PerNight      bool
+	Inclusive     bool
+	Description   string
+}
+
+// CreateQuote creates a new quote (manual entry), or confirms the latest quote when the input
+// is an unchanged resubmission of it, see SubmitQuote
 func (s *QuoteService) CreateQuote(ctx context.Context, input CreateQuoteInput) (*domain.PriceQuote, error) {
+	quote, _, err := s.SubmitQuote(ctx, input)
+	return quote, err
+}
+
+// SubmitQuote creates a new quote unless the input is an unchanged resubmission of the latest
+// current quote of the supplier for the cabin, issued no earlier than it (see
+// domain.PriceQuote.IsConfirmedBy). In that case no row is added: the latest quote is confirmed
+// as still valid on the quote date of the input and returned with confirmed set. An older
+// document with the same terms is stored as history.
+func (s *QuoteService) SubmitQuote(ctx context.Context, input CreateQuoteInput) (*domain.PriceQuote, bool, error) {
+	quote, err := s.newQuote(ctx, input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	latest, err := s.quoteRepo.GetLatestPrice(ctx, quote.SailingID, quote.CabinTypeID, quote.SupplierID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get latest quote: %w", err)
+	}
+	if latest != nil {
+		latests := []domain.PriceQuote{*latest}
+		if err := s.quoteRepo.LoadOccupancyRates(ctx, latests); err != nil {
+			return nil, false, fmt.Errorf("failed to load occupancy rates: %w", err)
+		}
+		if err := s.quoteRepo.LoadComponents(ctx, latests); err != nil {
+			return nil, false, fmt.Errorf("failed to load price components: %w", err)
+		}
+		if err := s.quoteRepo.LoadPromotions(ctx, latests); err != nil {
+			return nil, false, fmt.Errorf("failed to load promotions: %w", err)
+		}
+		latest = &latests[0]
+	}
+	if latest != nil && latest.IsConfirmedBy(quote) {
+		confirmed, err := s.confirmQuote(ctx, latest, input)
+		if err != nil {
+			return nil, false, err
+		}
+		if confirmed != nil {
+			return confirmed, true, nil
+		}
+		// The latest quote changed status meanwhile, store the submission as a new quote
+	}
+
+	if err := s.createQuote(ctx, quote, input); err != nil {
+		return nil, false, err
+	}
+
+	return quote, false, nil
+}
+
+// createQuote stores a new quote and runs the checks that follow a price change
+func (s *QuoteService) createQuote(ctx context.Context, quote *domain.PriceQuote, input CreateQuoteInput) error {
+	// Quotes waiting for approval are announced once approved, see QuoteApprovalService
+	var events []*domain.DomainEvent
+	if !quote.IsPendingReview() {
+		events = append(events, quoteCreatedEvent(ctx, quote, input.UserID))
+	}
+	if err := s.quoteRepo.Create(ctx, quote, events...); err != nil {
+		return fmt.Errorf("failed to create quote: %w", err)
+	}
+
+	// Audit log
+	if s.auditService != nil {
+		supplierIDPtr := &input.SupplierID
+		s.auditService.LogCreate(ctx, input.UserID, supplierIDPtr, "PriceQuote", quote.ID, quote)
+	}
+
+	// Backfilled quotes are history, not news
+	if quote.IsBackfill() {
+		return nil
+	}
+
+	// Anomaly checks
+	if s.anomalyService != nil {
+		s.anomalyService.EvaluateQuote(ctx, quote)
+	}
+
+	// Price alerts; quotes waiting for approval are evaluated once approved
+	if s.alertService != nil && !quote.IsPendingReview() {
+		s.alertService.EvaluateQuote(ctx, quote)
+	}
+
+	return nil
+}
+
+// quoteCreatedEvent builds the domain event announcing a published quote; backfilled quotes
+// get their own event type, which webhooks do not subscribe to
+func quoteCreatedEvent(ctx context.Context, quote *domain.PriceQuote, userID uint64) *domain.DomainEvent {
+	eventType := domain.DomainEventQuoteCreated
+	if quote.IsBackfill() {
+		eventType = domain.DomainEventQuoteBackfilled
+	}
+	return newDomainEvent(ctx, eventType, domain.EntityTypePriceQuote, quote.ID, userID, &quote.SupplierID, quote)
+}
+
+// QuoteConfirmedEvent is the event payload of a quote resubmitted unchanged
+type QuoteConfirmedEvent struct {
+	Quote        *domain.PriceQuote        `json:"quote"`
+	Confirmation *domain.QuoteConfirmation `json:"confirmation"`
+}
+
+// confirmQuote records an unchanged resubmission of an active quote and returns the quote as
+// confirmed. It returns nil when the quote is no longer active.
+func (s *QuoteService) confirmQuote(ctx context.Context, quote *domain.PriceQuote, input CreateQuoteInput) (*domain.PriceQuote, error) {
+	confirmation := newConfirmation(quote, input, time.Now())
+	confirmed := quote.WithConfirmation(confirmation.ConfirmedAt)
+
+	event := newDomainEvent(ctx, domain.DomainEventQuoteConfirmed, domain.EntityTypePriceQuote, quote.ID, input.UserID, &quote.SupplierID,
+		QuoteConfirmedEvent{Quote: &confirmed, Confirmation: confirmation})
+	ok, err := s.quoteRepo.Confirm(ctx, confirmation, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm quote: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	// Audit log
+	if s.auditService != nil {
+		supplierIDPtr := &quote.SupplierID
+		s.auditService.LogConfirm(ctx, input.UserID, supplierIDPtr, "PriceQuote", quote.ID, confirmation)
+	}
+
+	// Reload to get the occupancy rates and price components
+	reloaded, err := s.quoteRepo.GetByID(ctx, quote.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload quote: %w", err)
+	}
+
+	return reloaded, nil
+}
+
+// newConfirmation builds the confirmation of a quote by an unchanged resubmission. The quote is
+// confirmed on the date the supplier issued the resubmission, or now when it states none.
+func newConfirmation(quote *domain.PriceQuote, input CreateQuoteInput, now time.Time) *domain.QuoteConfirmation {
+	confirmation := &domain.QuoteConfirmation{
+		QuoteID:     quote.ID,
+		Source:      input.Source,
+		SourceRef:   input.IdempotencyKey,
+		ImportJobID: input.ImportJobID,
+		ConfirmedAt: now,
+		CreatedBy:   input.UserID,
+	}
+	if input.QuotedAt != nil {
+		confirmation.ConfirmedAt = *input.QuotedAt
+	}
+	if confirmation.Source == "" {
+		confirmation.Source = domain.QuoteSourceManual
+	}
+	return confirmation
+}
+
+// newQuote validates the input and builds the quote to store
+func (s *QuoteService) newQuote(ctx context.Context, input CreateQuoteInput) (*domain.PriceQuote, error) {
 	// Validate price
 	price, err := decimal.NewFromString(input.Price)
 	if err != nil {
@@ -105,6 +314,22 @@ func (s *QuoteService) CreateQuote(ctx context.Context, input CreateQuoteInput)
 		return nil, errors.New("pricing unit is required")
 	}
 
+	if input.Source == "" {
+		input.Source = domain.QuoteSourceManual
+	}
+
+	// Validate quote dates
+	quotedAt := time.Now()
+	if input.QuotedAt != nil {
+		if input.QuotedAt.After(quotedAt) {
+			return nil, errors.New("quoted_at must not be in the future")
+		}
+		quotedAt = *input.QuotedAt
+	}
+	if input.ValidFrom != nil && input.ValidUntil != nil && input.ValidFrom.After(*input.ValidUntil) {
+		return nil, errors.New("valid_from must not be after valid_until")
+	}
+
 	// Create quote
 	quote := &domain.PriceQuote{
 		SailingID:     input.SailingID,
@@ -115,24 +340,32 @@ func (s *QuoteService) CreateQuote(ctx context.Context, input CreateQuoteInput)
 		PricingUnit:   input.PricingUnit,
 		Conditions:    input.Conditions,
 		GuestCount:    input.GuestCount,
+		MaxOccupancy:  input.MaxOccupancy,
 		Promotion:     input.Promotion,
 		CabinQuantity: input.CabinQuantity,
+		ValidFrom:     input.ValidFrom,
 		ValidUntil:    input.ValidUntil,
+		QuotedAt:      quotedAt,
 		Notes:         input.Notes,
-		Source:        domain.QuoteSourceManual,
+		Source:        input.Source,
 		SourceRef:     input.IdempotencyKey,
+		ImportJobID:   input.ImportJobID,
+		RFQID:         input.RFQID,
 		Status:        domain.QuoteStatusActive,
 		CreatedBy:     input.UserID,
 	}
-
-	if err := s.quoteRepo.Create(ctx, quote); err != nil {
-		return nil, fmt.Errorf("failed to create quote: %w", err)
+	if supplier.RequiresApproval {
+		quote.Status = domain.QuoteStatusPendingReview
 	}
 
-	// Audit log
-	if s.auditService != nil {
-		supplierIDPtr := &input.SupplierID
-		s.auditService.LogCreate(ctx, input.UserID, supplierIDPtr, "PriceQuote", quote.ID, quote)
+	if err := applyOccupancyPricing(quote, input); err != nil {
+		return nil, err
+	}
+	if err := applyPriceComponents(quote, input); err != nil {
+		return nil, err
+	}
+	if err := applyPromotions(quote, input); err != nil {
+		return nil, err
 	}
 
 	return quote, nil
@@ -140,14 +373,139 @@ func (s *QuoteService) CreateQuote(ctx context.Context, input CreateQuoteInput)
 
 // ListQuotesInput represents the input for listing quotes
 type ListQuotesInput struct {
-	Pagination   repo.Pagination
-	SailingID    *uint64
-	CabinTypeID  *uint64
-	SupplierID   *uint64
-	Status       *domain.QuoteStatus
-	UserID       uint64 // From auth context
-	UserRole     domain.UserRole
-	UserSupplier uint64 // From auth context (if vendor)
+	Pagination    repo.Pagination
+	SailingID     *uint64
+	CabinTypeID   *uint64
+	SupplierID    *uint64
+	Status        *domain.QuoteStatus
+	PromotionType *domain.PromotionType
+	UserID        uint64 // From auth context
+	UserRole      domain.UserRole
+	UserSupplier  uint64 // From auth context (if vendor)
+}
+
+// applyOccupancyPricing parses the occupancy pricing of the input onto the quote and validates it
+func applyOccupancyPricing(quote *domain.PriceQuote, input CreateQuoteInput) error {
+	var errs domain.ValidationErrors
+
+	parseOptional := func(field, value string) *decimal.Decimal {
+		if value == "" {
+			return nil
+		}
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			errs.Add(field, domain.ErrFieldInvalidFormat)
+			return nil
+		}
+		return &d
+	}
+
+	quote.SingleSupplementPct = parseOptional("single_supplement_pct", input.SingleSupplementPct)
+	quote.SingleSupplementAmount = parseOptional("single_supplement_amount", input.SingleSupplementAmount)
+
+	for i, r := range input.OccupancyRates {
+		price, err := decimal.NewFromString(r.Price)
+		if err != nil {
+			errs.Add(fmt.Sprintf("occupancy_rates[%d].price", i), domain.ErrFieldInvalidFormat)
+			continue
+		}
+		guestType := r.GuestType
+		if guestType == "" {
+			guestType = domain.GuestTypeAdult
+		}
+		quote.OccupancyRates = append(quote.OccupancyRates, domain.OccupancyRate{
+			GuestSlot:   r.GuestSlot,
+			GuestType:   guestType,
+			Price:       price,
+			MaxChildAge: r.MaxChildAge,
+		})
+	}
+
+	errs = append(errs, domain.ValidateOccupancyPricing(quote)...)
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// applyPriceComponents parses the price components of the input onto the quote and validates them
+func applyPriceComponents(quote *domain.PriceQuote, input CreateQuoteInput) error {
+	var errs domain.ValidationErrors
+
+	for i, c := range input.Components {
+		amount, err := decimal.NewFromString(c.Amount)
+		if err != nil {
+			errs.Add(fmt.Sprintf("components[%d].amount", i), domain.ErrFieldInvalidFormat)
+			continue
+		}
+		pricingUnit := c.PricingUnit
+		if pricingUnit == "" {
+			pricingUnit = quote.PricingUnit
+		}
+		quote.Components = append(quote.Components, domain.PriceComponent{
+			ComponentType: c.ComponentType,
+			Amount:        amount,
+			PricingUnit:   pricingUnit,
+			PerNight:      c.PerNight,
+			Inclusive:     c.Inclusive,
+			Description:   c.Description,
+		})
+	}
+
+	errs = append(errs, domain.ValidatePriceComponents(quote)...)
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// applyPromotions parses the structured promotions of the input onto the quote and validates them
+func applyPromotions(quote *domain.PriceQuote, input CreateQuoteInput) error {
+	var errs domain.ValidationErrors
+
+	parseOptional := func(field, value string) *decimal.Decimal {
+		if value == "" {
+			return nil
+		}
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			errs.Add(field, domain.ErrFieldInvalidFormat)
+			return nil
+		}
+		return &d
+	}
+
+	for i, p := range input.Promotions {
+		field := fmt.Sprintf("promotions[%d]", i)
+		pricingUnit := p.PricingUnit
+		if pricingUnit == "" {
+			pricingUnit = quote.PricingUnit
+		}
+		promotionType := p.PromotionType
+		if promotionType == "" {
+			promotionType = domain.PromotionOther
+		}
+		quote.Promotions = append(quote.Promotions, domain.Promotion{
+			PromotionType:  promotionType,
+			DiscountPct:    parseOptional(field+".discount_pct", p.DiscountPct),
+			DiscountAmount: parseOptional(field+".discount_amount", p.DiscountAmount),
+			PricingUnit:    pricingUnit,
+			GuestSlots:     p.GuestSlots,
+			BookingFrom:    p.BookingFrom,
+			BookingUntil:   p.BookingUntil,
+			Stackable:      p.Stackable,
+			Description:    p.Description,
+		})
+	}
+
+	errs = append(errs, domain.ValidatePromotions(quote)...)
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
 }
 
 // ListQuotes retrieves quotes with filters
@@ -158,7 +516,7 @@ func (s *QuoteService) ListQuotes(ctx context.Context, input ListQuotesInput) (r
 		supplierID = &input.UserSupplier
 	}
 
-	result, err := s.quoteRepo.List(ctx, input.Pagination, input.SailingID, input.CabinTypeID, supplierID, input.Status)
+	result, err := s.quoteRepo.List(ctx, input.Pagination, input.SailingID, input.CabinTypeID, supplierID, input.Status, input.PromotionType)
 	if err != nil {
 		return repo.PaginatedResult[domain.PriceQuote]{}, fmt.Errorf("failed to list quotes: %w", err)
 	}
@@ -200,13 +558,17 @@ func (s *QuoteService) VoidQuote(ctx context.Context, id uint64, reason string,
 		return nil, errors.New("forbidden: cannot void other supplier's quotes")
 	}
 
-	// Cannot void if already voided
-	if quote.Status != domain.QuoteStatusActive {
+	// Cannot void if already voided; expired quotes can still be voided
+	if quote.Status != domain.QuoteStatusActive && quote.Status != domain.QuoteStatusExpired {
 		return nil, errors.New("quote is not active")
 	}
 
 	// Void the quote
-	if err := s.quoteRepo.VoidQuote(ctx, id); err != nil {
+	voided := *quote
+	voided.Status = domain.QuoteStatusVoided
+	event := newDomainEvent(ctx, domain.DomainEventQuoteVoided, domain.EntityTypePriceQuote, id, userID, &quote.SupplierID,
+		QuoteVoidedEvent{Quote: &voided, Reason: reason})
+	if err := s.quoteRepo.VoidQuote(ctx, id, event); err != nil {
 		return nil, fmt.Errorf("failed to void quote: %w", err)
 	}
 
@@ -225,6 +587,124 @@ func (s *QuoteService) VoidQuote(ctx context.Context, id uint64, reason string,
 	return quote, nil
 }
 
+// expiryBatchSize is the number of quotes expired per transaction
+const expiryBatchSize = 500
+
+// QuoteVoidedEvent is the event payload of a voided quote
+type QuoteVoidedEvent struct {
+	Quote  *domain.PriceQuote `json:"quote"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+// QuoteCorrectedEvent is the event payload of a corrected quote
+type QuoteCorrectedEvent struct {
+	CorrectedQuoteID uint64             `json:"corrected_quote_id"`
+	Quote            *domain.PriceQuote `json:"quote,omitempty"` // The replacement, left out while it waits for approval
+}
+
+// quoteCorrectedEvent builds the domain event announcing a correction. A replacement waiting
+// for approval is announced by quote.created once approved, see QuoteApprovalService.
+func quoteCorrectedEvent(ctx context.Context, original, replacement *domain.PriceQuote, userID uint64) *domain.DomainEvent {
+	data := QuoteCorrectedEvent{CorrectedQuoteID: original.ID}
+	if !replacement.IsPendingReview() {
+		data.Quote = replacement
+	}
+	return newDomainEvent(ctx, domain.DomainEventQuoteCorrected, domain.EntityTypePriceQuote, 0, userID, &replacement.SupplierID, data)
+}
+
+// CorrectQuote replaces an active or expired quote with a corrected one. The original is kept
+// with status CORRECTED and the replacement is created for the same supplier in one transaction.
+func (s *QuoteService) CorrectQuote(ctx context.Context, id uint64, input CreateQuoteInput, userRole domain.UserRole, userSupplier uint64) (*domain.PriceQuote, error) {
+	original, err := s.quoteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if original == nil {
+		return nil, errors.New("quote not found")
+	}
+
+	// Vendor can only correct their own supplier's quotes
+	if userRole == domain.UserRoleVendor && original.SupplierID != userSupplier {
+		return nil, errors.New("forbidden: cannot correct other supplier's quotes")
+	}
+
+	if original.Status != domain.QuoteStatusActive && original.Status != domain.QuoteStatusExpired {
+		return nil, errors.New("quote is not active")
+	}
+
+	input.SupplierID = original.SupplierID
+	input.RFQID = original.RFQID
+	if input.Source == "" {
+		input.Source = original.Source
+	}
+
+	quote, err := s.newQuote(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	event := quoteCorrectedEvent(ctx, original, quote, input.UserID)
+	if err := s.quoteRepo.CorrectQuote(ctx, id, quote, event); err != nil {
+		return nil, fmt.Errorf("failed to correct quote: %w", err)
+	}
+
+	// Audit log
+	if s.auditService != nil {
+		supplierIDPtr := &original.SupplierID
+		corrected := *original
+		corrected.Status = domain.QuoteStatusCorrected
+		s.auditService.LogUpdate(ctx, input.UserID, supplierIDPtr, "PriceQuote", original.ID, original, &corrected)
+		s.auditService.LogCreate(ctx, input.UserID, supplierIDPtr, "PriceQuote", quote.ID, quote)
+	}
+
+	// Backfilled quotes are history, not news
+	if quote.IsBackfill() {
+		return quote, nil
+	}
+
+	// Anomaly checks
+	if s.anomalyService != nil {
+		s.anomalyService.EvaluateQuote(ctx, quote)
+	}
+
+	// Price alerts; quotes waiting for approval are evaluated once approved
+	if s.alertService != nil && !quote.IsPendingReview() {
+		s.alertService.EvaluateQuote(ctx, quote)
+	}
+
+	return quote, nil
+}
+
+// ExpireQuotes moves active quotes whose valid_until lies before the day of now to
+// EXPIRED, recording an audit entry per quote, and returns how many were expired
+func (s *QuoteService) ExpireQuotes(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		quotes, err := s.quoteRepo.ExpireQuotes(ctx, now, expiryBatchSize, func(q *domain.PriceQuote) *domain.DomainEvent {
+			expired := *q
+			expired.Status = domain.QuoteStatusExpired
+			return newDomainEvent(ctx, domain.DomainEventQuoteExpired, domain.EntityTypePriceQuote, q.ID, domain.SystemUserID, &q.SupplierID, &expired)
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to expire quotes: %w", err)
+		}
+
+		for i := range quotes {
+			before := quotes[i]
+			after := before
+			after.Status = domain.QuoteStatusExpired
+			if s.auditService != nil {
+				s.auditService.LogExpire(ctx, domain.SystemUserID, &before.SupplierID, "PriceQuote", before.ID, before, after)
+			}
+		}
+
+		total += len(quotes)
+		if len(quotes) < expiryBatchSize {
+			return total, nil
+		}
+	}
+}
+
 // BatchCreateQuotesInput represents input for batch quote creation
 type BatchCreateQuotesInput struct {
 	Quotes      []CreateQuoteInput
@@ -241,6 +721,9 @@ func (s *QuoteService) BatchCreateQuotes(ctx context.Context, input BatchCreateQ
 	for _, q := range input.Quotes {
 		q.SupplierID = input.SupplierID
 		q.UserID = input.UserID
+		if q.ImportJobID == nil {
+			q.ImportJobID = input.ImportJobID
+		}
 
 		quote, err := s.CreateQuote(ctx, q)
 		if err != nil {
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBackfillConfirmsOnFileDate(t *testing.T) {
+	firstFile := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	secondFile := firstFile.AddDate(0, 0, 7)
+	importJobID := uint64(12)
+
+	// The quote stored from the first archived list
+	stored := &domain.PriceQuote{
+		ID:          1,
+		SailingID:   1,
+		CabinTypeID: 2,
+		SupplierID:  3,
+		Price:       decimal.RequireFromString("8999"),
+		Currency:    "CNY",
+		PricingUnit: domain.PricingUnitPerPerson,
+		Source:      domain.QuoteSourceBackfill,
+		QuotedAt:    firstFile,
+	}
+
+	// The second list repeats it a week later; its import job carries the inferred file date
+	input := CreateQuoteInput{
+		SailingID:   1,
+		CabinTypeID: 2,
+		Price:       "8999.00",
+		Currency:    "CNY",
+		PricingUnit: domain.PricingUnitPerPerson,
+		QuotedAt:    &secondFile,
+		ImportJobID: &importJobID,
+		Source:      domain.QuoteSourceBackfill,
+		SupplierID:  3,
+	}
+	submission := *stored
+	submission.ID = 0
+	submission.QuotedAt = *input.QuotedAt
+
+	if !stored.IsConfirmedBy(&submission) {
+		t.Fatal("the second list does not confirm the first")
+	}
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	confirmation := newConfirmation(stored, input, now)
+	if !confirmation.ConfirmedAt.Equal(secondFile) {
+		t.Errorf("ConfirmedAt = %v, want the second file's date %v", confirmation.ConfirmedAt, secondFile)
+	}
+	if confirmation.Source != domain.QuoteSourceBackfill {
+		t.Errorf("Source = %s, want %s", confirmation.Source, domain.QuoteSourceBackfill)
+	}
+
+	confirmed := stored.WithConfirmation(confirmation.ConfirmedAt)
+	if confirmed.LastConfirmedAt == nil || !confirmed.LastConfirmedAt.Equal(secondFile) {
+		t.Errorf("LastConfirmedAt = %v, want the second file's date %v", confirmed.LastConfirmedAt, secondFile)
+	}
+
+	// A submission stating no date is confirmed now
+	input.QuotedAt = nil
+	if got := newConfirmation(stored, input, now).ConfirmedAt; !got.Equal(now) {
+		t.Errorf("ConfirmedAt without a quote date = %v, want %v", got, now)
+	}
+}
+
+func TestQuoteCorrectedEvent(t *testing.T) {
+	original := &domain.PriceQuote{ID: 7, SupplierID: 2, Status: domain.QuoteStatusActive}
+
+	tests := []struct {
+		name      string
+		status    domain.QuoteStatus
+		withQuote bool
+	}{
+		{"active replacement", domain.QuoteStatusActive, true},
+		{"replacement waiting for approval", domain.QuoteStatusPendingReview, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacement := &domain.PriceQuote{SupplierID: 2, Status: tt.status}
+			event := quoteCorrectedEvent(context.Background(), original, replacement, 1)
+			if event.EventType != domain.DomainEventQuoteCorrected {
+				t.Errorf("event type = %s, want %s", event.EventType, domain.DomainEventQuoteCorrected)
+			}
+			data, ok := event.Data.(QuoteCorrectedEvent)
+			if !ok {
+				t.Fatalf("event data = %T, want QuoteCorrectedEvent", event.Data)
+			}
+			if data.CorrectedQuoteID != 7 {
+				t.Errorf("corrected quote = %d, want 7", data.CorrectedQuoteID)
+			}
+			if got := data.Quote != nil; got != tt.withQuote {
+				t.Errorf("event carries the replacement = %v, want %v", got, tt.withQuote)
+			}
+		})
+	}
+}
@@ -0,0 +1,794 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/obs"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// RFQ errors
+var (
+	ErrRFQNotFound         = errors.New("rfq not found")
+	ErrRFQNotOpen          = errors.New("rfq is no longer open")
+	ErrRFQDeclined         = errors.New("supplier declined the rfq")
+	ErrRFQItemNotRequested = errors.New("rfq does not request this sailing cabin type")
+)
+
+const (
+	// rfqTitleMaxLength is the size of the rfq.title column
+	rfqTitleMaxLength = 200
+
+	// rfqDeclineReasonMaxLength is the size of the rfq_supplier.decline_reason column
+	rfqDeclineReasonMaxLength = 500
+
+	// rfqMaxItems and rfqMaxSuppliers bound the size of an RFQ
+	rfqMaxItems     = 200
+	rfqMaxSuppliers = 50
+)
+
+// rfqReminderLeadTimes are the times before the deadline at which suppliers that have not
+// answered every requested cabin are reminded, longest first
+var rfqReminderLeadTimes = []time.Duration{48 * time.Hour, 24 * time.Hour, 4 * time.Hour}
+
+// RFQService manages requests for quote and the quotes suppliers answer them with
+type RFQService struct {
+	rfqRepo        *repo.RFQRepository
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinRepo      *repo.CabinTypeRepository
+	supplierRepo   *repo.SupplierRepository
+	quoteService   *QuoteService
+	fx             *FXService
+	audit          *obs.AuditService
+}
+
+// NewRFQService creates a new RFQ service
+func NewRFQService(
+	rfqRepo *repo.RFQRepository,
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinRepo *repo.CabinTypeRepository,
+	supplierRepo *repo.SupplierRepository,
+	quoteService *QuoteService,
+	fx *FXService,
+	audit *obs.AuditService,
+) *RFQService {
+	return &RFQService{
+		rfqRepo:        rfqRepo,
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinRepo:      cabinRepo,
+		supplierRepo:   supplierRepo,
+		quoteService:   quoteService,
+		fx:             fx,
+		audit:          audit,
+	}
+}
+
+// RFQInvitationEvent is the event payload of an RFQ issued to a supplier and of its deadline
+// reminders. It is delivered to the supplier's webhooks, so the RFQ leaves out the other
+// suppliers it was issued to.
+type RFQInvitationEvent struct {
+	RFQ         *domain.RFQ      `json:"rfq"`
+	SupplierID  uint64           `json:"supplier_id"`
+	Reminder    int              `json:"reminder,omitempty"`          // reminders only: 1 for the first one
+	Outstanding []domain.RFQItem `json:"outstanding_items,omitempty"` // reminders only: cabins not yet quoted
+}
+
+// MarshalJSON encodes the event with the supplier's view of the RFQ. The view is taken when
+// the event is written, once the RFQ has its ID.
+func (e RFQInvitationEvent) MarshalJSON() ([]byte, error) {
+	type plain RFQInvitationEvent
+	view := plain(e)
+	if view.RFQ != nil {
+		view.RFQ = view.RFQ.ForSupplier(e.SupplierID)
+	}
+	return json.Marshal(view)
+}
+
+// RFQDeclinedEvent is the event payload of a supplier declining an RFQ
+type RFQDeclinedEvent struct {
+	RFQID      uint64              `json:"rfq_id"`
+	Invitation *domain.RFQSupplier `json:"invitation"`
+}
+
+// RFQItemInput is a sailing cabin type requested by an RFQ
+type RFQItemInput struct {
+	SailingID   uint64
+	CabinTypeID uint64
+}
+
+// CreateRFQInput represents the input for issuing an RFQ
+type CreateRFQInput struct {
+	Title         string
+	Notes         string
+	CabinQuantity int
+	Deadline      time.Time
+	Items         []RFQItemInput
+	SupplierIDs   []uint64
+	UserID        uint64 // From auth context
+}
+
+// CreateRFQ issues an RFQ to the chosen suppliers. Repeated items and suppliers are kept once.
+func (s *RFQService) CreateRFQ(ctx context.Context, input CreateRFQInput) (*domain.RFQ, error) {
+	rfq := &domain.RFQ{
+		Title:         strings.TrimSpace(input.Title),
+		Notes:         strings.TrimSpace(input.Notes),
+		CabinQuantity: input.CabinQuantity,
+		Deadline:      input.Deadline,
+		Status:        domain.RFQStatusOpen,
+		CreatedBy:     input.UserID,
+	}
+
+	seenItems := make(map[RFQItemInput]bool, len(input.Items))
+	for _, item := range input.Items {
+		if seenItems[item] {
+			continue
+		}
+		seenItems[item] = true
+		rfq.Items = append(rfq.Items, domain.RFQItem{SailingID: item.SailingID, CabinTypeID: item.CabinTypeID})
+	}
+
+	seenSuppliers := make(map[uint64]bool, len(input.SupplierIDs))
+	supplierIDs := make([]uint64, 0, len(input.SupplierIDs))
+	for _, id := range input.SupplierIDs {
+		if seenSuppliers[id] {
+			continue
+		}
+		seenSuppliers[id] = true
+		supplierIDs = append(supplierIDs, id)
+		rfq.Suppliers = append(rfq.Suppliers, domain.RFQSupplier{SupplierID: id})
+	}
+
+	if errs := validateRFQ(rfq, time.Now()); errs.HasErrors() {
+		return nil, errs
+	}
+
+	if err := s.checkRFQItems(ctx, rfq.Items); err != nil {
+		return nil, err
+	}
+
+	suppliers, err := s.supplierRepo.ListByIDs(ctx, supplierIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppliers: %w", err)
+	}
+	active := 0
+	for i := range suppliers {
+		if suppliers[i].IsActive() {
+			active++
+		}
+	}
+	if active != len(supplierIDs) {
+		return nil, ErrSupplierNotFound
+	}
+
+	events := make([]*domain.DomainEvent, 0, len(rfq.Suppliers))
+	for _, invitation := range rfq.Suppliers {
+		supplierID := invitation.SupplierID
+		events = append(events, newDomainEvent(ctx, domain.DomainEventRFQIssued, domain.EntityTypeRFQ, 0, input.UserID, &supplierID,
+			RFQInvitationEvent{RFQ: rfq, SupplierID: supplierID}))
+	}
+
+	if err := s.rfqRepo.Create(ctx, rfq, events...); err != nil {
+		return nil, err
+	}
+
+	_ = s.audit.LogCreate(ctx, input.UserID, nil, domain.EntityTypeRFQ, rfq.ID, rfq)
+
+	return rfq, nil
+}
+
+// validateRFQ checks the fields of a new RFQ
+func validateRFQ(rfq *domain.RFQ, now time.Time) domain.ValidationErrors {
+	var errs domain.ValidationErrors
+
+	if rfq.Title == "" {
+		errs.Add("title", domain.ErrFieldRequired)
+	} else if len(rfq.Title) > rfqTitleMaxLength {
+		errs.Add("title", domain.ErrFieldTooLong)
+	}
+	if rfq.CabinQuantity < 1 {
+		errs.Add("cabin_quantity", domain.ErrFieldMustBePositive)
+	}
+	if rfq.Deadline.IsZero() {
+		errs.Add("deadline", domain.ErrFieldRequired)
+	} else if !rfq.Deadline.After(now) {
+		errs.AddMsg("deadline", "deadline must be in the future")
+	}
+	if len(rfq.Items) == 0 {
+		errs.Add("items", domain.ErrFieldRequired)
+	} else if len(rfq.Items) > rfqMaxItems {
+		errs.AddMsg("items", fmt.Sprintf("at most %d items", rfqMaxItems))
+	}
+	if len(rfq.Suppliers) == 0 {
+		errs.Add("supplier_ids", domain.ErrFieldRequired)
+	} else if len(rfq.Suppliers) > rfqMaxSuppliers {
+		errs.AddMsg("supplier_ids", fmt.Sprintf("at most %d suppliers", rfqMaxSuppliers))
+	}
+
+	return errs
+}
+
+// checkRFQItems verifies that the requested sailings exist and that each cabin type belongs
+// to the ship of its sailing
+func (s *RFQService) checkRFQItems(ctx context.Context, items []domain.RFQItem) error {
+	shipOf := make(map[uint64]uint64)
+	for _, item := range items {
+		shipID, ok := shipOf[item.SailingID]
+		if !ok {
+			sailing, err := s.sailingRepo.GetByID(ctx, item.SailingID)
+			if err != nil {
+				return err
+			}
+			if sailing == nil {
+				return ErrSailingNotFound
+			}
+			shipID = sailing.ShipID
+			shipOf[item.SailingID] = shipID
+		}
+
+		cabinType, err := s.cabinRepo.GetByID(ctx, item.CabinTypeID)
+		if err != nil {
+			return err
+		}
+		if cabinType == nil || cabinType.ShipID != shipID {
+			return ErrCabinTypeNotFound
+		}
+	}
+	return nil
+}
+
+// ListRFQsInput represents the input for listing RFQs
+type ListRFQsInput struct {
+	Pagination   repo.Pagination
+	Status       *domain.RFQStatus // Vendors: defaults to the RFQs still open for answers
+	UserRole     domain.UserRole
+	UserSupplier uint64
+}
+
+// ListRFQs lists RFQs, newest first. Vendors only see the RFQs issued to their supplier.
+func (s *RFQService) ListRFQs(ctx context.Context, input ListRFQsInput) (repo.PaginatedResult[domain.RFQ], error) {
+	if input.UserRole != domain.UserRoleVendor {
+		return s.rfqRepo.List(ctx, input.Pagination, input.Status, nil, nil)
+	}
+
+	status := input.Status
+	var deadlineAfter *time.Time
+	if status == nil {
+		open := domain.RFQStatusOpen
+		now := time.Now()
+		status, deadlineAfter = &open, &now
+	}
+
+	result, err := s.rfqRepo.List(ctx, input.Pagination, status, &input.UserSupplier, deadlineAfter)
+	if err != nil {
+		return result, err
+	}
+	for i := range result.Items {
+		result.Items[i] = *result.Items[i].ForSupplier(input.UserSupplier)
+	}
+	return result, nil
+}
+
+// GetRFQ returns an RFQ. Vendors only see the RFQs issued to their supplier, without the
+// other suppliers.
+func (s *RFQService) GetRFQ(ctx context.Context, id uint64, userRole domain.UserRole, userSupplier uint64) (*domain.RFQ, error) {
+	rfq, err := s.rfqRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq == nil {
+		return nil, ErrRFQNotFound
+	}
+	if userRole == domain.UserRoleVendor {
+		if rfq.Invitation(userSupplier) == nil {
+			return nil, ErrRFQNotFound
+		}
+		return rfq.ForSupplier(userSupplier), nil
+	}
+	return rfq, nil
+}
+
+// getInvitedRFQ returns an RFQ open for answers from a supplier it was issued to; RFQs
+// issued to other suppliers are reported as not found
+func (s *RFQService) getInvitedRFQ(ctx context.Context, id, supplierID uint64) (*domain.RFQ, *domain.RFQSupplier, error) {
+	rfq, err := s.rfqRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rfq == nil {
+		return nil, nil, ErrRFQNotFound
+	}
+	invitation := rfq.Invitation(supplierID)
+	if invitation == nil {
+		return nil, nil, ErrRFQNotFound
+	}
+	if !rfq.IsOpen(time.Now()) {
+		return nil, nil, ErrRFQNotOpen
+	}
+	if invitation.DeclinedAt != nil {
+		return nil, nil, ErrRFQDeclined
+	}
+	return rfq, invitation, nil
+}
+
+// RespondWithQuote submits a quote of the supplier in answer to an RFQ it was issued to.
+// The quote must price a requested sailing cabin type; its cabin quantity defaults to the
+// group size of the RFQ. Like SubmitQuote, it reports whether an unchanged answer only
+// confirmed the previous one.
+func (s *RFQService) RespondWithQuote(ctx context.Context, rfqID uint64, input CreateQuoteInput) (*domain.PriceQuote, bool, error) {
+	rfq, _, err := s.getInvitedRFQ(ctx, rfqID, input.SupplierID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !rfq.HasItem(input.SailingID, input.CabinTypeID) {
+		return nil, false, ErrRFQItemNotRequested
+	}
+
+	input.RFQID = &rfq.ID
+	if input.CabinQuantity == nil {
+		quantity := rfq.CabinQuantity
+		input.CabinQuantity = &quantity
+	}
+
+	return s.quoteService.SubmitQuote(ctx, input)
+}
+
+// DeclineRFQ records that the supplier declines to quote an RFQ it was issued to
+func (s *RFQService) DeclineRFQ(ctx context.Context, rfqID uint64, reason string, userID, supplierID uint64) (*domain.RFQSupplier, error) {
+	rfq, invitation, err := s.getInvitedRFQ(ctx, rfqID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len(reason) > rfqDeclineReasonMaxLength {
+		return nil, domain.ValidationErrors{domain.NewValidationError("reason", domain.ErrFieldTooLong)}
+	}
+
+	now := time.Now()
+	declined := *invitation
+	declined.DeclinedAt = &now
+	declined.DeclineReason = reason
+
+	event := newDomainEvent(ctx, domain.DomainEventRFQDeclined, domain.EntityTypeRFQ, rfq.ID, userID, &supplierID,
+		RFQDeclinedEvent{RFQID: rfq.ID, Invitation: &declined})
+	ok, err := s.rfqRepo.Decline(ctx, rfq.ID, supplierID, reason, event)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRFQDeclined
+	}
+
+	_ = s.audit.LogUpdate(ctx, userID, &supplierID, domain.EntityTypeRFQ, rfq.ID, invitation, &declined)
+
+	return &declined, nil
+}
+
+// CloseRFQ closes an open RFQ; the answers received are kept
+func (s *RFQService) CloseRFQ(ctx context.Context, id, userID uint64) (*domain.RFQ, error) {
+	return s.finishRFQ(ctx, id, domain.RFQStatusClosed, userID)
+}
+
+// CancelRFQ withdraws an open RFQ
+func (s *RFQService) CancelRFQ(ctx context.Context, id, userID uint64) (*domain.RFQ, error) {
+	return s.finishRFQ(ctx, id, domain.RFQStatusCancelled, userID)
+}
+
+// finishRFQ moves an open RFQ to a final status
+func (s *RFQService) finishRFQ(ctx context.Context, id uint64, status domain.RFQStatus, userID uint64) (*domain.RFQ, error) {
+	rfq, err := s.rfqRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq == nil {
+		return nil, ErrRFQNotFound
+	}
+	if rfq.Status != domain.RFQStatusOpen {
+		return nil, ErrRFQNotOpen
+	}
+
+	now := time.Now()
+	finished := *rfq
+	finished.Status = status
+	finished.ClosedAt = &now
+
+	event := newDomainEvent(ctx, domain.DomainEventRFQClosed, domain.EntityTypeRFQ, rfq.ID, userID, nil, &finished)
+	ok, err := s.rfqRepo.UpdateStatus(ctx, rfq.ID, status, event)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRFQNotOpen
+	}
+
+	_ = s.audit.LogUpdate(ctx, userID, nil, domain.EntityTypeRFQ, rfq.ID, rfq, &finished)
+
+	return &finished, nil
+}
+
+// RFQComparisonInput represents the input for an RFQ comparison
+type RFQComparisonInput struct {
+	RFQID           uint64
+	DisplayCurrency string     // Optional, defaults to SearchCurrencyDefault
+	Occupancy       *Occupancy // Optional, defaults to two adults
+}
+
+// RFQOffer is the latest answer of a supplier for a requested cabin
+type RFQOffer struct {
+	SupplierID        uint64             `json:"supplier_id"`
+	SupplierName      string             `json:"supplier_name"`
+	QuoteID           uint64             `json:"quote_id"`
+	Price             decimal.Decimal    `json:"price"`
+	Currency          string             `json:"currency"`
+	PricingUnit       domain.PricingUnit `json:"pricing_unit"`
+	CabinQuantity     *int               `json:"cabin_quantity,omitempty"`
+	QuotedAt          time.Time          `json:"quoted_at"`
+	ValidUntil        *time.Time         `json:"valid_until,omitempty"`
+	Status            domain.QuoteStatus `json:"status"`
+	Normalized        *NormalizedPrice   `json:"normalized,omitempty"`
+	DisplayNormalized *NormalizedPrice   `json:"display_normalized,omitempty"`
+	Rank              int                `json:"rank,omitempty"` // by display per-person price, 1 for the lowest; 0 if it could not be priced
+}
+
+// RFQComparisonItem compares the answers for one requested sailing cabin type
+type RFQComparisonItem struct {
+	Sailing       SailingInfo `json:"sailing"`
+	CabinTypeID   uint64      `json:"cabin_type_id"`
+	CabinTypeName string      `json:"cabin_type_name"`
+	Offers        []RFQOffer  `json:"offers"` // ranked offers first, lowest price first
+}
+
+// RFQSupplierResponse is how far an invited supplier has answered an RFQ
+type RFQSupplierResponse struct {
+	SupplierID     uint64                   `json:"supplier_id"`
+	SupplierName   string                   `json:"supplier_name"`
+	Status         domain.RFQResponseStatus `json:"status"`
+	QuotedItems    int                      `json:"quoted_items"`
+	TotalItems     int                      `json:"total_items"`
+	LowestItems    int                      `json:"lowest_items"` // requested cabins where the supplier ranks first
+	LastResponseAt *time.Time               `json:"last_response_at,omitempty"`
+	DeclinedAt     *time.Time               `json:"declined_at,omitempty"`
+	DeclineReason  string                   `json:"decline_reason,omitempty"`
+	RemindedAt     *time.Time               `json:"reminded_at,omitempty"`
+	ReminderCount  int                      `json:"reminder_count"`
+}
+
+// RFQComparison compares the answers to an RFQ and tracks the response of each supplier
+type RFQComparison struct {
+	RFQ             *domain.RFQ           `json:"rfq"`
+	Open            bool                  `json:"open"` // suppliers may still answer
+	DisplayCurrency string                `json:"display_currency"`
+	Occupancy       Occupancy             `json:"occupancy"`
+	Items           []RFQComparisonItem   `json:"items"`
+	Suppliers       []RFQSupplierResponse `json:"suppliers"`
+	Warnings        []string              `json:"warnings,omitempty"`
+}
+
+// rfqOfferKey identifies the answer of a supplier for a requested cabin
+type rfqOfferKey struct{ sailingID, cabinTypeID, supplierID uint64 }
+
+// GetRFQComparison compares the latest answer of each invited supplier for each requested
+// cabin, priced per person for the occupancy in the display currency, and reports the
+// response status of each supplier
+func (s *RFQService) GetRFQComparison(ctx context.Context, input RFQComparisonInput) (*RFQComparison, error) {
+	displayCurrency := input.DisplayCurrency
+	if displayCurrency == "" {
+		displayCurrency = SearchCurrencyDefault
+	}
+	displayCurrency, err := NormalizeCurrency(displayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancy := Occupancy{Adults: domain.DefaultBaseOccupancy}
+	if input.Occupancy != nil {
+		occupancy = *input.Occupancy
+	}
+	if occupancy.Adults < 1 || occupancy.Children < 0 {
+		return nil, ErrInvalidOccupancy
+	}
+
+	rfq, err := s.rfqRepo.GetByID(ctx, input.RFQID)
+	if err != nil {
+		return nil, err
+	}
+	if rfq == nil {
+		return nil, ErrRFQNotFound
+	}
+
+	quotes, err := s.quoteRepo.ListByRFQ(ctx, rfq.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quotes are newest first: the first per cabin and supplier is the latest answer
+	latest := make([]domain.PriceQuote, 0, len(quotes))
+	seen := make(map[rfqOfferKey]bool, len(quotes))
+	for _, q := range quotes {
+		key := rfqOfferKey{q.SailingID, q.CabinTypeID, q.SupplierID}
+		if seen[key] || rfq.Invitation(q.SupplierID) == nil || !rfq.HasItem(q.SailingID, q.CabinTypeID) {
+			continue
+		}
+		seen[key] = true
+		latest = append(latest, q)
+	}
+	if err := s.quoteRepo.LoadOccupancyRates(ctx, latest); err != nil {
+		return nil, err
+	}
+	if err := s.quoteRepo.LoadComponents(ctx, latest); err != nil {
+		return nil, err
+	}
+	offers := make(map[rfqOfferKey]*domain.PriceQuote, len(latest))
+	for i := range latest {
+		q := &latest[i]
+		offers[rfqOfferKey{q.SailingID, q.CabinTypeID, q.SupplierID}] = q
+	}
+
+	supplierIDs := make([]uint64, len(rfq.Suppliers))
+	for i, invitation := range rfq.Suppliers {
+		supplierIDs[i] = invitation.SupplierID
+	}
+	suppliers, err := s.supplierRepo.ListByIDs(ctx, supplierIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppliers: %w", err)
+	}
+	supplierNames := make(map[uint64]string, len(suppliers))
+	for _, supplier := range suppliers {
+		supplierNames[supplier.ID] = supplier.Name
+	}
+
+	result := &RFQComparison{
+		RFQ:             rfq,
+		Open:            rfq.IsOpen(time.Now()),
+		DisplayCurrency: displayCurrency,
+		Occupancy:       occupancy,
+		Items:           make([]RFQComparisonItem, 0, len(rfq.Items)),
+		Suppliers:       make([]RFQSupplierResponse, 0, len(rfq.Suppliers)),
+	}
+
+	responses := make(map[uint64]*RFQSupplierResponse, len(rfq.Suppliers))
+	for _, invitation := range rfq.Suppliers {
+		result.Suppliers = append(result.Suppliers, RFQSupplierResponse{
+			SupplierID:    invitation.SupplierID,
+			SupplierName:  supplierNames[invitation.SupplierID],
+			TotalItems:    len(rfq.Items),
+			DeclinedAt:    invitation.DeclinedAt,
+			DeclineReason: invitation.DeclineReason,
+			RemindedAt:    invitation.RemindedAt,
+			ReminderCount: invitation.ReminderCount,
+		})
+	}
+	for i := range result.Suppliers {
+		responses[result.Suppliers[i].SupplierID] = &result.Suppliers[i]
+	}
+
+	converter := s.fx.NewConverter(displayCurrency)
+	warnings := newWarningSet()
+	sailings := make(map[uint64]*SailingInfo)
+	cabinNames := make(map[uint64]string)
+
+	for _, rfqItem := range rfq.Items {
+		info, ok := sailings[rfqItem.SailingID]
+		if !ok {
+			_, info, err = loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, rfqItem.SailingID)
+			if err != nil {
+				return nil, err
+			}
+			sailings[rfqItem.SailingID] = info
+		}
+
+		cabinName, ok := cabinNames[rfqItem.CabinTypeID]
+		if !ok {
+			cabinType, err := s.cabinRepo.GetByID(ctx, rfqItem.CabinTypeID)
+			if err != nil {
+				return nil, err
+			}
+			if cabinType != nil {
+				cabinName = cabinType.Name
+			}
+			cabinNames[rfqItem.CabinTypeID] = cabinName
+		}
+
+		item := RFQComparisonItem{
+			Sailing:       *info,
+			CabinTypeID:   rfqItem.CabinTypeID,
+			CabinTypeName: cabinName,
+			Offers:        []RFQOffer{},
+		}
+
+		for _, invitation := range rfq.Suppliers {
+			q, ok := offers[rfqOfferKey{rfqItem.SailingID, rfqItem.CabinTypeID, invitation.SupplierID}]
+			if !ok {
+				continue
+			}
+
+			offer := RFQOffer{
+				SupplierID:    q.SupplierID,
+				SupplierName:  supplierNames[q.SupplierID],
+				QuoteID:       q.ID,
+				Price:         q.Price,
+				Currency:      q.Currency,
+				PricingUnit:   q.PricingUnit,
+				CabinQuantity: q.CabinQuantity,
+				QuotedAt:      q.QuotedAt,
+				ValidUntil:    q.ValidUntil,
+				Status:        q.Status,
+			}
+			if err := priceRFQOffer(ctx, &offer, q, converter, occupancy, info.Nights, warnings); err != nil {
+				return nil, err
+			}
+			item.Offers = append(item.Offers, offer)
+
+			response := responses[q.SupplierID]
+			response.QuotedItems++
+			if response.LastResponseAt == nil || q.CreatedAt.After(*response.LastResponseAt) {
+				createdAt := q.CreatedAt
+				response.LastResponseAt = &createdAt
+			}
+		}
+
+		rankRFQOffers(item.Offers)
+		for _, offer := range item.Offers {
+			if offer.Rank == 1 {
+				responses[offer.SupplierID].LowestItems++
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	for i := range result.Suppliers {
+		response := &result.Suppliers[i]
+		response.Status = rfqResponseStatus(response.DeclinedAt != nil, response.QuotedItems, response.TotalItems)
+	}
+
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// priceRFQOffer normalizes the price of an offer for the occupancy and converts it for
+// display. Quotes that cannot host the party or lack an exchange rate are left unpriced
+// and reported as warnings.
+func priceRFQOffer(ctx context.Context, offer *RFQOffer, quote *domain.PriceQuote, converter *CurrencyConverter, occupancy Occupancy, nights int, warnings *warningSet) error {
+	normalized, err := NormalizeQuotePrice(quote, occupancy, nights)
+	if err != nil {
+		warnings.add(fmt.Sprintf("quote %d: %v", quote.ID, err))
+		return nil
+	}
+	offer.Normalized = normalized
+
+	display, err := convertForDisplay(ctx, converter, quote, warnings)
+	if err != nil || display == nil {
+		return err
+	}
+	offer.DisplayNormalized = normalized.Convert(display.Rate, display.Currency)
+	return nil
+}
+
+// rankRFQOffers sorts offers by display per-person price and ranks them, equal prices
+// sharing a rank; offers without a display price come last, unranked
+func rankRFQOffers(offers []RFQOffer) {
+	sort.SliceStable(offers, func(i, j int) bool {
+		a, b := offers[i].DisplayNormalized, offers[j].DisplayNormalized
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.PerPerson.LessThan(b.PerPerson)
+	})
+
+	for i := range offers {
+		if offers[i].DisplayNormalized == nil {
+			break
+		}
+		if i > 0 && offers[i].DisplayNormalized.PerPerson.Equal(offers[i-1].DisplayNormalized.PerPerson) {
+			offers[i].Rank = offers[i-1].Rank
+		} else {
+			offers[i].Rank = i + 1
+		}
+	}
+}
+
+// rfqResponseStatus classifies the answer of a supplier to an RFQ
+func rfqResponseStatus(declined bool, quoted, total int) domain.RFQResponseStatus {
+	switch {
+	case declined:
+		return domain.RFQResponseDeclined
+	case quoted >= total:
+		return domain.RFQResponseResponded
+	case quoted > 0:
+		return domain.RFQResponsePartial
+	default:
+		return domain.RFQResponsePending
+	}
+}
+
+// rfqRemindersDue returns how many reminders are due with remaining time to the deadline
+func rfqRemindersDue(remaining time.Duration) int {
+	due := 0
+	for _, lead := range rfqReminderLeadTimes {
+		if remaining <= lead {
+			due++
+		}
+	}
+	return due
+}
+
+// SendReminders reminds the suppliers of open RFQs nearing their deadline that have neither
+// declined nor answered every requested cabin, and returns how many reminders were sent.
+// A supplier gets one reminder per lead time it has reached; lead times passed while the
+// worker was down are covered by a single reminder. Answers waiting for approval count.
+func (s *RFQService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	rfqs, err := s.rfqRepo.ListOpenDeadlineBetween(ctx, now, now.Add(rfqReminderLeadTimes[0]))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range rfqs {
+		rfq := &rfqs[i]
+		due := rfqRemindersDue(rfq.Deadline.Sub(now))
+
+		var pending []domain.RFQSupplier
+		for _, invitation := range rfq.Suppliers {
+			if invitation.DeclinedAt == nil && invitation.ReminderCount < due {
+				pending = append(pending, invitation)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		quotes, err := s.quoteRepo.ListByRFQ(ctx, rfq.ID, true)
+		if err != nil {
+			return sent, err
+		}
+		quoted := make(map[rfqOfferKey]bool, len(quotes))
+		for _, q := range quotes {
+			quoted[rfqOfferKey{q.SailingID, q.CabinTypeID, q.SupplierID}] = true
+		}
+
+		for _, invitation := range pending {
+			supplierID := invitation.SupplierID
+			var outstanding []domain.RFQItem
+			for _, item := range rfq.Items {
+				if !quoted[rfqOfferKey{item.SailingID, item.CabinTypeID, supplierID}] {
+					outstanding = append(outstanding, item)
+				}
+			}
+			if len(outstanding) == 0 {
+				continue
+			}
+
+			event := newDomainEvent(ctx, domain.DomainEventRFQReminder, domain.EntityTypeRFQ, rfq.ID, domain.SystemUserID, &supplierID,
+				RFQInvitationEvent{RFQ: rfq, SupplierID: supplierID, Reminder: due, Outstanding: outstanding})
+			ok, err := s.rfqRepo.MarkReminded(ctx, rfq.ID, supplierID, due, now, event)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
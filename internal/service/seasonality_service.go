@@ -0,0 +1,664 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// Seasonality errors
+var (
+	ErrYoYScopeRequired     = errors.New("route or ship_id is required")
+	ErrInvalidYoYAlignment  = errors.New("align_by must be SEASON, HOLIDAY or WEEK")
+	ErrInvalidSeason        = errors.New("season must be SPRING, SUMMER, AUTUMN or WINTER")
+	ErrInvalidHoliday       = errors.New("holiday must be CHINESE_NEW_YEAR, NEW_YEAR, LABOR_DAY, MID_AUTUMN or NATIONAL_DAY")
+	ErrHolidayDateUnknown   = errors.New("holiday date is unknown for the year")
+	ErrInvalidHolidayWindow = errors.New("holiday window must be between 0 and 30 days")
+	ErrInvalidYoYWeek       = errors.New("week must be a week of the ISO year")
+	ErrInvalidYoYYears      = errors.New("compare year must differ from year")
+)
+
+const (
+	// defaultHolidayWindowDays is how many days before and after a holiday departures count
+	defaultHolidayWindowDays = 7
+	// maxHolidayWindowDays is the widest holiday window
+	maxHolidayWindowDays = 30
+)
+
+// yoyCurveEdges are the days before departure bounding the buckets of a YoY price curve
+var yoyCurveEdges = [...]int{14, 30, 60, 90, 120, 180}
+
+// Season is the season of a departure date
+type Season string
+
+// Seasons, by month of departure
+const (
+	SeasonSpring Season = "SPRING" // March to May
+	SeasonSummer Season = "SUMMER" // June to August
+	SeasonAutumn Season = "AUTUMN" // September to November
+	SeasonWinter Season = "WINTER" // December to February
+)
+
+// SeasonOf returns the season of a date
+func SeasonOf(date time.Time) Season {
+	switch date.Month() {
+	case time.March, time.April, time.May:
+		return SeasonSpring
+	case time.June, time.July, time.August:
+		return SeasonSummer
+	case time.September, time.October, time.November:
+		return SeasonAutumn
+	default:
+		return SeasonWinter
+	}
+}
+
+// seasonWindow returns the departure dates of a season of a year. The winter of a year runs
+// from the December before to the end of February.
+func seasonWindow(season Season, year int) (time.Time, time.Time, error) {
+	start := map[Season]time.Month{
+		SeasonSpring: time.March,
+		SeasonSummer: time.June,
+		SeasonAutumn: time.September,
+	}
+	from := time.Date(year-1, time.December, 1, 0, 0, 0, 0, time.UTC)
+	if season != SeasonWinter {
+		month, ok := start[season]
+		if !ok {
+			return time.Time{}, time.Time{}, ErrInvalidSeason
+		}
+		from = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return from, from.AddDate(0, 3, -1), nil
+}
+
+// Holiday is a holiday departures are aligned on across years
+type Holiday string
+
+// Holidays
+const (
+	HolidayChineseNewYear Holiday = "CHINESE_NEW_YEAR" // 春节
+	HolidayNewYear        Holiday = "NEW_YEAR"         // 元旦
+	HolidayLaborDay       Holiday = "LABOR_DAY"        // 劳动节
+	HolidayMidAutumn      Holiday = "MID_AUTUMN"       // 中秋节
+	HolidayNationalDay    Holiday = "NATIONAL_DAY"     // 国庆节
+)
+
+// lunarHolidays are the Gregorian dates of the holidays of the lunar calendar
+var lunarHolidays = map[Holiday]map[int]string{
+	HolidayChineseNewYear: {
+		2020: "2020-01-25", 2021: "2021-02-12", 2022: "2022-02-01", 2023: "2023-01-22",
+		2024: "2024-02-10", 2025: "2025-01-29", 2026: "2026-02-17", 2027: "2027-02-06",
+		2028: "2028-01-26", 2029: "2029-02-13", 2030: "2030-02-03", 2031: "2031-01-23",
+		2032: "2032-02-11",
+	},
+	HolidayMidAutumn: {
+		2020: "2020-10-01", 2021: "2021-09-21", 2022: "2022-09-10", 2023: "2023-09-29",
+		2024: "2024-09-17", 2025: "2025-10-06", 2026: "2026-09-25", 2027: "2027-09-15",
+		2028: "2028-10-03", 2029: "2029-09-22", 2030: "2030-09-12", 2031: "2031-10-01",
+		2032: "2032-09-19",
+	},
+}
+
+// holidayDate returns the date of a holiday in a year
+func holidayDate(holiday Holiday, year int) (time.Time, error) {
+	switch holiday {
+	case HolidayNewYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	case HolidayLaborDay:
+		return time.Date(year, time.May, 1, 0, 0, 0, 0, time.UTC), nil
+	case HolidayNationalDay:
+		return time.Date(year, time.October, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	dates, ok := lunarHolidays[holiday]
+	if !ok {
+		return time.Time{}, ErrInvalidHoliday
+	}
+	date, ok := dates[year]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s %d", ErrHolidayDateUnknown, holiday, year)
+	}
+	return time.Parse("2006-01-02", date)
+}
+
+// isoWeekWindow returns the Monday to Sunday of an ISO week
+func isoWeekWindow(year, week int) (time.Time, time.Time, error) {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, time.Time{}, ErrInvalidYoYWeek
+	}
+	return monday, monday.AddDate(0, 0, 6), nil
+}
+
+// YoYAlignment is how sailings of different years are aligned
+type YoYAlignment string
+
+// YoY alignments
+const (
+	YoYAlignSeason  YoYAlignment = "SEASON"  // departures in the same season
+	YoYAlignHoliday YoYAlignment = "HOLIDAY" // departures around the same holiday
+	YoYAlignWeek    YoYAlignment = "WEEK"    // departures in the same ISO week
+)
+
+// SeasonalityService compares the prices of sailings of a route or ship across years. Sailings
+// are aligned by season, holiday or week of departure and grouped by nights; per cabin category
+// it compares their price curves by days before departure. Prices are per person, normalized
+// for an occupancy and converted into a display currency.
+type SeasonalityService struct {
+	quoteRepo    *repo.PriceQuoteRepository
+	sailingRepo  *repo.SailingRepository
+	shipRepo     *repo.ShipRepository
+	categoryRepo *repo.CabinCategoryRepository
+	supplierRepo *repo.SupplierRepository
+	fx           *FXService
+}
+
+// NewSeasonalityService creates a new seasonality service
+func NewSeasonalityService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *SeasonalityService {
+	return &SeasonalityService{
+		quoteRepo:    quoteRepo,
+		sailingRepo:  sailingRepo,
+		shipRepo:     shipRepo,
+		categoryRepo: categoryRepo,
+		supplierRepo: supplierRepo,
+		fx:           fx,
+	}
+}
+
+// YoYInput represents the input for a year-over-year comparison
+type YoYInput struct {
+	RouteKeyword      string  // Optional, sailings of matching routes
+	ShipID            *uint64 // Optional, sailings of this ship; one of route and ship is required
+	Nights            *int    // Optional, only sailings of this length
+	CabinCategoryID   *uint64 // Optional, only this category
+	AlignBy           YoYAlignment
+	Season            Season  // With AlignBy SEASON
+	Holiday           Holiday // With AlignBy HOLIDAY
+	HolidayWindowDays *int    // Days around the holiday, defaults to 7
+	Week              int     // ISO week, with AlignBy WEEK
+	Year              int     // Defaults to the current year
+	CompareYear       int     // Defaults to the year before Year
+	DisplayCurrency   string  // Optional, defaults to SearchCurrencyDefault
+	Occupancy         *Occupancy
+	UserRole          domain.UserRole
+	UserSupplier      uint64
+}
+
+// YoYPeriod is the departure window of a year
+type YoYPeriod struct {
+	Year     int       `json:"year"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Sailings int       `json:"sailings"`
+}
+
+// YoYDelta compares the mean of a price over the sailings of each year. A sailing's price is
+// its lowest over the suppliers and cabin types of the category.
+type YoYDelta struct {
+	Current          *decimal.Decimal `json:"current,omitempty"`
+	CurrentSailings  int              `json:"current_sailings"`
+	Previous         *decimal.Decimal `json:"previous,omitempty"`
+	PreviousSailings int              `json:"previous_sailings"`
+	Delta            *decimal.Decimal `json:"delta,omitempty"`
+	DeltaPct         *decimal.Decimal `json:"delta_pct,omitempty"`
+}
+
+// YoYCurvePoint compares the prices quoted within a range of days before departure
+type YoYCurvePoint struct {
+	FromDays int  `json:"from_days"`
+	ToDays   *int `json:"to_days,omitempty"` // nil = no upper bound
+	YoYDelta
+}
+
+// YoYCategory compares a cabin category: the lowest price each sailing was ever quoted at and
+// its curve by days before departure
+type YoYCategory struct {
+	CabinCategoryID   uint64          `json:"cabin_category_id"`
+	CabinCategoryName string          `json:"cabin_category_name"`
+	Lowest            YoYDelta        `json:"lowest"`
+	Curve             []YoYCurvePoint `json:"curve"`
+}
+
+// YoYNightsGroup compares the sailings of one length
+type YoYNightsGroup struct {
+	Nights     int           `json:"nights"`
+	Categories []YoYCategory `json:"categories"`
+}
+
+// YoYComparison compares the prices of aligned sailings of two years
+type YoYComparison struct {
+	RouteKeyword    string           `json:"route,omitempty"`
+	ShipID          *uint64          `json:"ship_id,omitempty"`
+	ShipName        string           `json:"ship_name,omitempty"`
+	AlignBy         YoYAlignment     `json:"align_by"`
+	Season          Season           `json:"season,omitempty"`
+	Holiday         Holiday          `json:"holiday,omitempty"`
+	Week            int              `json:"week,omitempty"`
+	Current         YoYPeriod        `json:"current"`
+	Previous        YoYPeriod        `json:"previous"`
+	DisplayCurrency string           `json:"display_currency"`
+	Occupancy       Occupancy        `json:"occupancy"`
+	Groups          []YoYNightsGroup `json:"groups"`
+	Warnings        []string         `json:"warnings,omitempty"`
+}
+
+// yoyWindow returns the departure window of a year for the alignment of the input
+func yoyWindow(input YoYInput, year int) (time.Time, time.Time, error) {
+	switch input.AlignBy {
+	case YoYAlignSeason:
+		return seasonWindow(input.Season, year)
+	case YoYAlignHoliday:
+		date, err := holidayDate(input.Holiday, year)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		days := defaultHolidayWindowDays
+		if input.HolidayWindowDays != nil {
+			days = *input.HolidayWindowDays
+		}
+		if days < 0 || days > maxHolidayWindowDays {
+			return time.Time{}, time.Time{}, ErrInvalidHolidayWindow
+		}
+		return date.AddDate(0, 0, -days), date.AddDate(0, 0, days), nil
+	case YoYAlignWeek:
+		return isoWeekWindow(year, input.Week)
+	default:
+		return time.Time{}, time.Time{}, ErrInvalidYoYAlignment
+	}
+}
+
+// yoyKey identifies the prices of a year, length and cabin category
+type yoyKey struct {
+	period     int // 0 = current year, 1 = compare year
+	nights     int
+	categoryID uint64
+}
+
+// yoyPrices collects the lowest price per sailing, overall and per curve bucket
+type yoyPrices struct {
+	lowest map[yoyKey]map[uint64]decimal.Decimal
+	curve  map[yoyKey][]map[uint64]decimal.Decimal
+}
+
+// add records a price of a sailing quoted days before departure
+func (p *yoyPrices) add(key yoyKey, sailingID uint64, days int, price decimal.Decimal) {
+	lowerPrice(p.lowest, key, sailingID, price)
+
+	buckets, ok := p.curve[key]
+	if !ok {
+		buckets = make([]map[uint64]decimal.Decimal, len(yoyCurveEdges)+1)
+		for i := range buckets {
+			buckets[i] = make(map[uint64]decimal.Decimal)
+		}
+		p.curve[key] = buckets
+	}
+	bucket := len(yoyCurveEdges)
+	for i, edge := range yoyCurveEdges {
+		if days < edge {
+			bucket = i
+			break
+		}
+	}
+	if current, ok := buckets[bucket][sailingID]; !ok || price.LessThan(current) {
+		buckets[bucket][sailingID] = price
+	}
+}
+
+// lowerPrice keeps the lowest price of a sailing under a key
+func lowerPrice(prices map[yoyKey]map[uint64]decimal.Decimal, key yoyKey, sailingID uint64, price decimal.Decimal) {
+	bySailing, ok := prices[key]
+	if !ok {
+		bySailing = make(map[uint64]decimal.Decimal)
+		prices[key] = bySailing
+	}
+	if current, ok := bySailing[sailingID]; !ok || price.LessThan(current) {
+		bySailing[sailingID] = price
+	}
+}
+
+// meanPrice returns the mean of prices by sailing, nil when there are none
+func meanPrice(prices map[uint64]decimal.Decimal) *decimal.Decimal {
+	if len(prices) == 0 {
+		return nil
+	}
+	sum := decimal.Zero
+	for _, price := range prices {
+		sum = sum.Add(price)
+	}
+	mean := sum.Div(decimal.NewFromInt(int64(len(prices)))).Round(fxAmountPrecision)
+	return &mean
+}
+
+// newYoYDelta compares the prices by sailing of the current and the compare year
+func newYoYDelta(current, previous map[uint64]decimal.Decimal) YoYDelta {
+	delta := YoYDelta{
+		Current:          meanPrice(current),
+		CurrentSailings:  len(current),
+		Previous:         meanPrice(previous),
+		PreviousSailings: len(previous),
+	}
+	if delta.Current != nil && delta.Previous != nil {
+		d := delta.Current.Sub(*delta.Previous)
+		pct := percentOf(d, *delta.Previous)
+		delta.Delta = &d
+		delta.DeltaPct = &pct
+	}
+	return delta
+}
+
+// GetYoYComparison compares the prices of the sailings of a route or ship departing in the
+// aligned windows of two years, per length and cabin category. Every active and expired quote
+// of a visible supplier counts at the days before departure it was quoted.
+func (s *SeasonalityService) GetYoYComparison(ctx context.Context, input YoYInput) (*YoYComparison, error) {
+	input.RouteKeyword = strings.TrimSpace(input.RouteKeyword)
+	if input.RouteKeyword == "" && input.ShipID == nil {
+		return nil, ErrYoYScopeRequired
+	}
+
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if displayCurrency == "" {
+		displayCurrency = SearchCurrencyDefault
+	}
+
+	occupancy := Occupancy{Adults: domain.DefaultBaseOccupancy}
+	if input.Occupancy != nil {
+		occupancy = *input.Occupancy
+	}
+	if occupancy.Adults < 1 || occupancy.Children < 0 {
+		return nil, ErrInvalidOccupancy
+	}
+
+	if input.Year == 0 {
+		input.Year = time.Now().Year()
+	}
+	if input.CompareYear == 0 {
+		input.CompareYear = input.Year - 1
+	}
+	if input.CompareYear == input.Year {
+		return nil, ErrInvalidYoYYears
+	}
+
+	result := &YoYComparison{
+		RouteKeyword:    input.RouteKeyword,
+		ShipID:          input.ShipID,
+		AlignBy:         input.AlignBy,
+		DisplayCurrency: displayCurrency,
+		Occupancy:       occupancy,
+		Groups:          []YoYNightsGroup{},
+	}
+	switch input.AlignBy {
+	case YoYAlignSeason:
+		result.Season = input.Season
+	case YoYAlignHoliday:
+		result.Holiday = input.Holiday
+	case YoYAlignWeek:
+		result.Week = input.Week
+	}
+
+	if input.ShipID != nil {
+		ship, err := s.shipRepo.GetByID(ctx, *input.ShipID)
+		if err != nil {
+			return nil, err
+		}
+		if ship == nil {
+			return nil, ErrShipNotFound
+		}
+		result.ShipName = ship.Name
+	}
+
+	// Sailings of each year by ID, with the year they belong to
+	sailings := make(map[uint64]*domain.Sailing)
+	periods := make(map[uint64]int)
+	var ids []uint64
+	for period, year := range []int{input.Year, input.CompareYear} {
+		from, to, err := yoyWindow(input, year)
+		if err != nil {
+			return nil, err
+		}
+		matched, err := s.sailingRepo.ListMatching(ctx, repo.SailingSearchFilter{
+			FromDate:     &from,
+			ToDate:       &to,
+			MinNights:    input.Nights,
+			MaxNights:    input.Nights,
+			ShipID:       input.ShipID,
+			RouteKeyword: input.RouteKeyword,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		yp := YoYPeriod{Year: year, From: from, To: to, Sailings: len(matched)}
+		if period == 0 {
+			result.Current = yp
+		} else {
+			result.Previous = yp
+		}
+		for i := range matched {
+			sailings[matched[i].ID] = &matched[i]
+			periods[matched[i].ID] = period
+			ids = append(ids, matched[i].ID)
+		}
+	}
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := s.fx.NewConverter(displayCurrency)
+	warnings := newWarningSet()
+	prices := &yoyPrices{
+		lowest: make(map[yoyKey]map[uint64]decimal.Decimal),
+		curve:  make(map[yoyKey][]map[uint64]decimal.Decimal),
+	}
+
+	for _, category := range categories {
+		if len(ids) == 0 || (input.CabinCategoryID != nil && category.ID != *input.CabinCategoryID) {
+			continue
+		}
+
+		quotes, err := s.quoteRepo.ListHistory(ctx, ids, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		suppliers, err := visibleSuppliers(ctx, s.supplierRepo, quotes, nil, input.UserRole, input.UserSupplier)
+		if err != nil {
+			return nil, err
+		}
+		kept := quotes[:0]
+		for _, q := range quotes {
+			if _, ok := suppliers[q.SupplierID]; ok {
+				kept = append(kept, q)
+			}
+		}
+		if err := s.quoteRepo.LoadOccupancyRates(ctx, kept); err != nil {
+			return nil, err
+		}
+
+		for i := range kept {
+			q := &kept[i]
+			sailing := sailings[q.SailingID]
+			days := int(daysBetween(q.QuotedAt, sailing.DepartureDate))
+			if days < 0 {
+				continue
+			}
+
+			normalized, err := NormalizeQuotePrice(q, occupancy, sailing.Nights)
+			if err != nil {
+				warnings.add(fmt.Sprintf("quote %d: %v", q.ID, err))
+				continue
+			}
+			display, err := convertForDisplay(ctx, converter, q, warnings)
+			if err != nil {
+				return nil, err
+			}
+			if display == nil {
+				continue
+			}
+
+			price := normalized.PerPerson.Mul(display.Rate).Round(fxAmountPrecision)
+			key := yoyKey{period: periods[q.SailingID], nights: sailing.Nights, categoryID: category.ID}
+			prices.add(key, sailing.ID, days, price)
+		}
+	}
+
+	// Group by nights, categories in their sort order
+	byNights := make(map[int]bool)
+	for key := range prices.lowest {
+		byNights[key.nights] = true
+	}
+	nights := make([]int, 0, len(byNights))
+	for n := range byNights {
+		nights = append(nights, n)
+	}
+	sort.Ints(nights)
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].SortOrder < categories[j].SortOrder
+	})
+
+	for _, n := range nights {
+		group := YoYNightsGroup{Nights: n, Categories: []YoYCategory{}}
+		for _, category := range categories {
+			current := yoyKey{period: 0, nights: n, categoryID: category.ID}
+			previous := yoyKey{period: 1, nights: n, categoryID: category.ID}
+			if len(prices.lowest[current]) == 0 && len(prices.lowest[previous]) == 0 {
+				continue
+			}
+
+			yc := YoYCategory{
+				CabinCategoryID:   category.ID,
+				CabinCategoryName: category.Name,
+				Lowest:            newYoYDelta(prices.lowest[current], prices.lowest[previous]),
+				Curve:             []YoYCurvePoint{},
+			}
+			for b := len(yoyCurveEdges); b >= 0; b-- {
+				point := YoYCurvePoint{}
+				if b > 0 {
+					point.FromDays = yoyCurveEdges[b-1]
+				}
+				if b < len(yoyCurveEdges) {
+					to := yoyCurveEdges[b]
+					point.ToDays = &to
+				}
+				var currentPrices, previousPrices map[uint64]decimal.Decimal
+				if buckets, ok := prices.curve[current]; ok {
+					currentPrices = buckets[b]
+				}
+				if buckets, ok := prices.curve[previous]; ok {
+					previousPrices = buckets[b]
+				}
+				if len(currentPrices) == 0 && len(previousPrices) == 0 {
+					continue
+				}
+				point.YoYDelta = newYoYDelta(currentPrices, previousPrices)
+				yc.Curve = append(yc.Curve, point)
+			}
+			group.Categories = append(group.Categories, yc)
+		}
+		result.Groups = append(result.Groups, group)
+	}
+
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// ExportYoYComparison writes a year-over-year comparison into a workbook with the lowest
+// prices and the price curves on a sheet each
+func (s *SeasonalityService) ExportYoYComparison(ctx context.Context, input YoYInput) (*excelize.File, error) {
+	comparison, err := s.GetYoYComparison(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	w := &statsWorkbook{f: f}
+	if err := w.init(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	deltaCells := func(d YoYDelta) []interface{} {
+		return []interface{}{
+			optionalDecimalCell(d.Current), d.CurrentSailings,
+			optionalDecimalCell(d.Previous), d.PreviousSailings,
+			optionalDecimalCell(d.Delta), optionalDecimalCell(d.DeltaPct),
+		}
+	}
+
+	lowest := [][]interface{}{}
+	curves := [][]interface{}{}
+	for _, group := range comparison.Groups {
+		for _, category := range group.Categories {
+			lowest = append(lowest, append([]interface{}{group.Nights, category.CabinCategoryName}, deltaCells(category.Lowest)...))
+			for _, point := range category.Curve {
+				days := fmt.Sprintf("%d+", point.FromDays)
+				if point.ToDays != nil {
+					days = fmt.Sprintf("%d-%d", point.FromDays, *point.ToDays)
+				}
+				curves = append(curves, append([]interface{}{group.Nights, category.CabinCategoryName, days}, deltaCells(point.YoYDelta)...))
+			}
+		}
+	}
+
+	currency := comparison.DisplayCurrency
+	current, previous := comparison.Current.Year, comparison.Previous.Year
+	yearHeaders := []string{
+		fmt.Sprintf("%d年平均价/人 (%s)", current, currency), fmt.Sprintf("%d年航次数", current),
+		fmt.Sprintf("%d年平均价/人 (%s)", previous, currency), fmt.Sprintf("%d年航次数", previous),
+		"同比差额", "同比%",
+	}
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"同比最低价", append([]string{"晚数", "舱房类别"}, yearHeaders...), lowest},
+		{"价格曲线", append([]string{"晚数", "舱房类别", "离港前天数"}, yearHeaders...), curves},
+	}
+	for i, sheet := range sheets {
+		if err := w.writeSheet(i == 0, sheet.name, sheet.headers, sheet.rows); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	scope := strings.TrimSpace(comparison.ShipName + " " + comparison.RouteKeyword)
+	title := fmt.Sprintf("%s %d vs %d %s", scope, current, previous, comparison.AlignBy)
+	if err := f.SetDocProps(&excelize.DocProperties{Title: title}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to set workbook properties: %w", err)
+	}
+
+	return f, nil
+}
+
+// optionalDecimalCell converts an optional amount for a spreadsheet cell, blank when nil
+func optionalDecimalCell(d *decimal.Decimal) interface{} {
+	if d == nil {
+		return ""
+	}
+	return decimalCell(*d)
+}
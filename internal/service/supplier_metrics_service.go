@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// maxSupplierMetricsDays is the longest period of the supplier metrics
+	maxSupplierMetricsDays = 366
+	// defaultSupplierMetricsDays is the period of the supplier metrics without a start date
+	defaultSupplierMetricsDays = 30
+)
+
+// SupplierMetricsService computes how competitive each supplier was over a period and how
+// well it covered and maintained its quotes: what it quoted, how often it was the cheapest,
+// how far it was from the best price, how often it updated, how many of its quotes were later
+// voided or corrected and how its imports fared. Sources are price_quote and import_job.
+type SupplierMetricsService struct {
+	quoteRepo    *repo.PriceQuoteRepository
+	jobRepo      *repo.ImportJobRepository
+	sailingRepo  *repo.SailingRepository
+	supplierRepo *repo.SupplierRepository
+	fx           *FXService
+}
+
+// NewSupplierMetricsService creates a new supplier metrics service
+func NewSupplierMetricsService(
+	quoteRepo *repo.PriceQuoteRepository,
+	jobRepo *repo.ImportJobRepository,
+	sailingRepo *repo.SailingRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *SupplierMetricsService {
+	return &SupplierMetricsService{
+		quoteRepo:    quoteRepo,
+		jobRepo:      jobRepo,
+		sailingRepo:  sailingRepo,
+		supplierRepo: supplierRepo,
+		fx:           fx,
+	}
+}
+
+// SupplierMetricsInput represents the period of the supplier metrics; both dates are inclusive
+type SupplierMetricsInput struct {
+	From            *time.Time // Defaults to 30 days before To
+	To              *time.Time // Defaults to today
+	DisplayCurrency string     // Optional, defaults to SearchCurrencyDefault
+}
+
+// SupplierMetrics are the competitiveness and coverage metrics of one supplier over a period.
+//
+// A cell is a sailing + cabin type. The supplier's price in a cell is its latest quote of the
+// period that was not voided or corrected, per person for two adults in the display currency.
+// A cell is contested when at least two suppliers priced it; only contested cells count towards
+// the cheapest rate and the gap to the best price. Ties for the cheapest count for everyone tied.
+type SupplierMetrics struct {
+	SupplierID   uint64 `json:"supplier_id"`
+	SupplierName string `json:"supplier_name"`
+
+	QuoteCount       int `json:"quote_count"`
+	SailingsQuoted   int `json:"sailings_quoted"`
+	CabinTypesQuoted int `json:"cabin_types_quoted"` // distinct sailing + cabin type cells
+
+	ContestedCells  int              `json:"contested_cells"`
+	CheapestCount   int              `json:"cheapest_count"`
+	CheapestRate    *decimal.Decimal `json:"cheapest_rate,omitempty"`   // percent of contested cells
+	AvgGapToBest    *decimal.Decimal `json:"avg_gap_to_best,omitempty"` // per person, display currency
+	AvgGapToBestPct *decimal.Decimal `json:"avg_gap_to_best_pct,omitempty"`
+
+	// Updates are new quotes and unchanged resubmissions (confirmations). The interval is the
+	// mean time between consecutive updates of the same cell.
+	UpdateCount           int              `json:"update_count"`
+	UpdatesPerCell        decimal.Decimal  `json:"updates_per_cell"`
+	AvgUpdateIntervalDays *decimal.Decimal `json:"avg_update_interval_days,omitempty"`
+
+	VoidedCount          int             `json:"voided_count"`
+	CorrectedCount       int             `json:"corrected_count"`
+	VoidedOrCorrectedPct decimal.Decimal `json:"voided_or_corrected_pct"`
+
+	// Import jobs of the supplier's files or created by its users. The success rate is over finished jobs:
+	// succeeded and failed ones, leaving out those still pending, running or awaiting confirmation.
+	ImportJobs        int              `json:"import_jobs"`
+	ImportSucceeded   int              `json:"import_succeeded"`
+	ImportFailed      int              `json:"import_failed"`
+	ImportPending     int              `json:"import_pending"`
+	ImportSuccessRate *decimal.Decimal `json:"import_success_rate,omitempty"`
+}
+
+// SupplierMetricsReport is the supplier metrics of a period, suppliers by name
+type SupplierMetricsReport struct {
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	DisplayCurrency string            `json:"display_currency"`
+	Suppliers       []SupplierMetrics `json:"suppliers"`
+	Warnings        []string          `json:"warnings,omitempty"`
+}
+
+// supplierCell is a sailing + cabin type quoted by a supplier
+type supplierCell struct {
+	sailingID, cabinTypeID, supplierID uint64
+}
+
+// GetSupplierMetrics returns the metrics of every supplier that quoted or imported in the period
+func (s *SupplierMetricsService) GetSupplierMetrics(ctx context.Context, input SupplierMetricsInput) (*SupplierMetricsReport, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	if input.To != nil {
+		to = input.To.UTC().Truncate(24 * time.Hour)
+	}
+	from := to.AddDate(0, 0, -(defaultSupplierMetricsDays - 1))
+	if input.From != nil {
+		from = input.From.UTC().Truncate(24 * time.Hour)
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidAnalyticsWindow
+	}
+	if to.Sub(from) > maxSupplierMetricsDays*24*time.Hour {
+		return nil, ErrAnalyticsWindowTooLarge
+	}
+
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if displayCurrency == "" {
+		displayCurrency = SearchCurrencyDefault
+	}
+
+	end := to.AddDate(0, 0, 1)
+	quotes, err := s.quoteRepo.ListCreatedBetween(ctx, from, end)
+	if err != nil {
+		return nil, err
+	}
+	jobStats, err := s.jobRepo.CountBySupplier(ctx, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[uint64]*SupplierMetrics)
+	metricsFor := func(supplierID uint64) *SupplierMetrics {
+		m, ok := metrics[supplierID]
+		if !ok {
+			m = &SupplierMetrics{SupplierID: supplierID}
+			metrics[supplierID] = m
+		}
+		return m
+	}
+
+	// Coverage, voided/corrected share and the latest priced quote per cell
+	sailings := make(map[uint64]map[uint64]bool)
+	cells := make(map[supplierCell]*domain.PriceQuote)
+	updates := make(map[supplierCell][]time.Time)
+	quoteCells := make(map[uint64]supplierCell, len(quotes))
+	for i := range quotes {
+		q := &quotes[i]
+		m := metricsFor(q.SupplierID)
+		m.QuoteCount++
+		switch q.Status {
+		case domain.QuoteStatusVoided:
+			m.VoidedCount++
+		case domain.QuoteStatusCorrected:
+			m.CorrectedCount++
+		}
+
+		if sailings[q.SupplierID] == nil {
+			sailings[q.SupplierID] = make(map[uint64]bool)
+		}
+		sailings[q.SupplierID][q.SailingID] = true
+
+		cell := supplierCell{q.SailingID, q.CabinTypeID, q.SupplierID}
+		quoteCells[q.ID] = cell
+		updates[cell] = append(updates[cell], q.CreatedAt)
+		switch q.Status {
+		case domain.QuoteStatusVoided, domain.QuoteStatusCorrected, domain.QuoteStatusPendingReview, domain.QuoteStatusRejected:
+			// Withdrawn and unpublished quotes do not price the cell
+		default:
+			// Quotes are oldest first, so the last one wins
+			cells[cell] = q
+		}
+	}
+
+	quoteIDs := make([]uint64, 0, len(quotes))
+	for i := range quotes {
+		quoteIDs = append(quoteIDs, quotes[i].ID)
+	}
+	confirmations, err := s.quoteRepo.ListConfirmations(ctx, quoteIDs, &from, &end)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range confirmations {
+		if !c.ConfirmedAt.Before(end) {
+			continue
+		}
+		cell := quoteCells[c.QuoteID]
+		updates[cell] = append(updates[cell], c.ConfirmedAt)
+	}
+
+	warnings := newWarningSet()
+	prices, err := s.cellPrices(ctx, cells, displayCurrency, warnings)
+	if err != nil {
+		return nil, err
+	}
+	s.scoreCompetitiveness(prices, metricsFor)
+
+	// Update frequency per supplier
+	type intervalSum struct {
+		total time.Duration
+		count int
+	}
+	intervals := make(map[uint64]*intervalSum)
+	cellCounts := make(map[uint64]int)
+	for cell, times := range updates {
+		m := metricsFor(cell.supplierID)
+		m.UpdateCount += len(times)
+		cellCounts[cell.supplierID]++
+		if len(times) < 2 {
+			continue
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		sum, ok := intervals[cell.supplierID]
+		if !ok {
+			sum = &intervalSum{}
+			intervals[cell.supplierID] = sum
+		}
+		sum.total += times[len(times)-1].Sub(times[0])
+		sum.count += len(times) - 1
+	}
+
+	for _, stats := range jobStats {
+		m := metricsFor(stats.SupplierID)
+		m.ImportJobs = stats.Total
+		m.ImportSucceeded = stats.Succeeded
+		m.ImportFailed = stats.Failed
+		m.ImportPending = stats.Total - stats.Succeeded - stats.Failed
+	}
+
+	supplierIDs := make([]uint64, 0, len(metrics))
+	for id := range metrics {
+		supplierIDs = append(supplierIDs, id)
+	}
+	suppliers, err := s.supplierRepo.ListByIDs(ctx, supplierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SupplierMetricsReport{
+		From:            from,
+		To:              to,
+		DisplayCurrency: displayCurrency,
+		Suppliers:       make([]SupplierMetrics, 0, len(suppliers)),
+	}
+	day := decimal.NewFromInt(int64(24 * time.Hour))
+	// Suppliers come sorted by name
+	for _, supplier := range suppliers {
+		m := metrics[supplier.ID]
+		m.SupplierName = supplier.Name
+		m.SailingsQuoted = len(sailings[supplier.ID])
+		m.CabinTypesQuoted = cellCounts[supplier.ID]
+
+		if m.CabinTypesQuoted > 0 {
+			m.UpdatesPerCell = decimal.NewFromInt(int64(m.UpdateCount)).
+				Div(decimal.NewFromInt(int64(m.CabinTypesQuoted))).Round(2)
+		}
+		if sum, ok := intervals[supplier.ID]; ok {
+			avg := decimal.NewFromInt(int64(sum.total)).Div(decimal.NewFromInt(int64(sum.count))).Div(day).Round(1)
+			m.AvgUpdateIntervalDays = &avg
+		}
+		m.VoidedOrCorrectedPct = percentOf(decimal.NewFromInt(int64(m.VoidedCount+m.CorrectedCount)), decimal.NewFromInt(int64(m.QuoteCount)))
+		if finished := m.ImportSucceeded + m.ImportFailed; finished > 0 {
+			rate := percentOf(decimal.NewFromInt(int64(m.ImportSucceeded)), decimal.NewFromInt(int64(finished)))
+			m.ImportSuccessRate = &rate
+		}
+
+		result.Suppliers = append(result.Suppliers, *m)
+	}
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// cellPrices returns the per-person price for two adults of each priced cell in the display
+// currency, at the rate of the quote date. Cells without a usable quote are left out; quotes
+// that cannot be normalized or converted are reported as warnings.
+func (s *SupplierMetricsService) cellPrices(ctx context.Context, cells map[supplierCell]*domain.PriceQuote, displayCurrency string, warnings *warningSet) (map[supplierCell]decimal.Decimal, error) {
+	latest := make([]domain.PriceQuote, 0, len(cells))
+	sailingIDs := make(map[uint64]bool)
+	for _, q := range cells {
+		latest = append(latest, *q)
+		sailingIDs[q.SailingID] = true
+	}
+	if err := s.quoteRepo.LoadOccupancyRates(ctx, latest); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(sailingIDs))
+	for id := range sailingIDs {
+		ids = append(ids, id)
+	}
+	sailings, err := s.sailingRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	nights := make(map[uint64]int, len(sailings))
+	for i := range sailings {
+		nights[sailings[i].ID] = sailings[i].Nights
+		if nights[sailings[i].ID] == 0 {
+			nights[sailings[i].ID] = sailings[i].CalculateNights()
+		}
+	}
+
+	occupancy := Occupancy{Adults: domain.DefaultBaseOccupancy}
+	converter := s.fx.NewConverter(displayCurrency)
+	prices := make(map[supplierCell]decimal.Decimal, len(latest))
+	for i := range latest {
+		q := &latest[i]
+		normalized, err := NormalizeQuotePrice(q, occupancy, nights[q.SailingID])
+		if err != nil {
+			warnings.add(fmt.Sprintf("quote %d: %v", q.ID, err))
+			continue
+		}
+		display, err := convertForDisplay(ctx, converter, q, warnings)
+		if err != nil {
+			return nil, err
+		}
+		if display == nil {
+			continue
+		}
+		prices[supplierCell{q.SailingID, q.CabinTypeID, q.SupplierID}] = normalized.PerPerson.Mul(display.Rate).Round(fxAmountPrecision)
+	}
+
+	return prices, nil
+}
+
+// scoreCompetitiveness compares the suppliers' prices per sailing + cabin type and records, per
+// supplier, the contested cells, how often it was the cheapest and its average gap to the best
+func (s *SupplierMetricsService) scoreCompetitiveness(prices map[supplierCell]decimal.Decimal, metricsFor func(uint64) *SupplierMetrics) {
+	type offer struct {
+		supplierID uint64
+		price      decimal.Decimal
+	}
+	type marketCell struct{ sailingID, cabinTypeID uint64 }
+	byCell := make(map[marketCell][]offer)
+	for cell, price := range prices {
+		key := marketCell{cell.sailingID, cell.cabinTypeID}
+		byCell[key] = append(byCell[key], offer{cell.supplierID, price})
+	}
+
+	type gapSum struct {
+		amount, pct decimal.Decimal
+	}
+	gaps := make(map[uint64]*gapSum)
+	for _, offers := range byCell {
+		if len(offers) < 2 {
+			continue
+		}
+		best := offers[0].price
+		for _, o := range offers[1:] {
+			if o.price.LessThan(best) {
+				best = o.price
+			}
+		}
+		for _, o := range offers {
+			m := metricsFor(o.supplierID)
+			m.ContestedCells++
+			if o.price.Equal(best) {
+				m.CheapestCount++
+			}
+			sum, ok := gaps[o.supplierID]
+			if !ok {
+				sum = &gapSum{}
+				gaps[o.supplierID] = sum
+			}
+			gap := o.price.Sub(best)
+			sum.amount = sum.amount.Add(gap)
+			sum.pct = sum.pct.Add(percentOf(gap, best))
+		}
+	}
+
+	for supplierID, sum := range gaps {
+		m := metricsFor(supplierID)
+		contested := decimal.NewFromInt(int64(m.ContestedCells))
+		rate := percentOf(decimal.NewFromInt(int64(m.CheapestCount)), contested)
+		amount := sum.amount.Div(contested).Round(fxAmountPrecision)
+		pct := sum.pct.Div(contested).Round(2)
+		m.CheapestRate = &rate
+		m.AvgGapToBest = &amount
+		m.AvgGapToBestPct = &pct
+	}
+}
+
+// ExportSupplierMetrics writes the supplier metrics of a period into a workbook
+func (s *SupplierMetricsService) ExportSupplierMetrics(ctx context.Context, input SupplierMetricsInput) (*excelize.File, error) {
+	report, err := s.GetSupplierMetrics(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	w := &statsWorkbook{f: f}
+	if err := w.init(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	optional := func(d *decimal.Decimal) interface{} {
+		if d == nil {
+			return ""
+		}
+		return decimalCell(*d)
+	}
+
+	rows := [][]interface{}{}
+	for _, m := range report.Suppliers {
+		rows = append(rows, []interface{}{
+			m.SupplierName, m.QuoteCount, m.SailingsQuoted, m.CabinTypesQuoted,
+			m.ContestedCells, m.CheapestCount, optional(m.CheapestRate), optional(m.AvgGapToBest), optional(m.AvgGapToBestPct),
+			m.UpdateCount, decimalCell(m.UpdatesPerCell), optional(m.AvgUpdateIntervalDays),
+			m.VoidedCount, m.CorrectedCount, decimalCell(m.VoidedOrCorrectedPct),
+			m.ImportJobs, m.ImportSucceeded, m.ImportFailed, optional(m.ImportSuccessRate),
+		})
+	}
+
+	currency := report.DisplayCurrency
+	headers := []string{
+		"供应商", "报价数", "航次数", "航次舱型数",
+		"竞争舱型数", "最低价次数", "最低价占比%", "与最低价平均差额/人 (" + currency + ")", "平均差额%",
+		"更新次数", "每舱型更新次数", "平均更新间隔(天)",
+		"作废数", "更正数", "作废/更正占比%",
+		"导入任务数", "导入成功", "导入失败", "导入成功率%",
+	}
+	if err := w.writeSheet(true, "供应商指标", headers, rows); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	title := fmt.Sprintf("供应商指标 %s ~ %s", report.From.Format("2006-01-02"), report.To.Format("2006-01-02"))
+	if err := f.SetDocProps(&excelize.DocProperties{Title: title}); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to set workbook properties: %w", err)
+	}
+
+	return f, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +23,11 @@ type TemplateImportService struct {
 	cabinCategoryRepo *repo.CabinCategoryRepository
 	cabinTypeRepo     *repo.CabinTypeRepository
 	sailingRepo       *repo.SailingRepository
+	supplierRepo      *repo.SupplierRepository
+	portRepo          *repo.PortRepository
+	quoteService      *QuoteService
+	catalogService    *CatalogService
+	embeddings        *EmbeddingIndexService
 	auditService      *obs.AuditService
 	logger            obs.Logger
 }
@@ -32,6 +39,11 @@ func NewTemplateImportService(
 	cabinCategoryRepo *repo.CabinCategoryRepository,
 	cabinTypeRepo *repo.CabinTypeRepository,
 	sailingRepo *repo.SailingRepository,
+	supplierRepo *repo.SupplierRepository,
+	portRepo *repo.PortRepository,
+	quoteService *QuoteService,
+	catalogService *CatalogService,
+	embeddings *EmbeddingIndexService,
 	auditService *obs.AuditService,
 	logger obs.Logger,
 ) *TemplateImportService {
@@ -41,6 +53,11 @@ func NewTemplateImportService(
 		cabinCategoryRepo: cabinCategoryRepo,
 		cabinTypeRepo:     cabinTypeRepo,
 		sailingRepo:       sailingRepo,
+		supplierRepo:      supplierRepo,
+		portRepo:          portRepo,
+		quoteService:      quoteService,
+		catalogService:    catalogService,
+		embeddings:        embeddings,
 		auditService:      auditService,
 		logger:            logger,
 	}
@@ -73,6 +90,18 @@ func (s *TemplateImportService) GenerateCabinTypeTemplate(ctx context.Context) (
 	return generator.GenerateCabinTypeTemplate()
 }
 
+// GenerateQuoteTemplate 生成报价模板
+func (s *TemplateImportService) GenerateQuoteTemplate(ctx context.Context) (*excelize.File, error) {
+	generator := parsers.NewExcelTemplateGenerator()
+	return generator.GenerateQuoteTemplate()
+}
+
+// GenerateItineraryTemplate 生成行程模板
+func (s *TemplateImportService) GenerateItineraryTemplate(ctx context.Context) (*excelize.File, error) {
+	generator := parsers.NewExcelTemplateGenerator()
+	return generator.GenerateItineraryTemplate()
+}
+
 // ImportSailingTemplate 导入航次模板
 func (s *TemplateImportService) ImportSailingTemplate(ctx context.Context, filePath string, userID uint64) (*ImportResult, error) {
 	// 解析 Excel 文件
@@ -165,6 +194,362 @@ func (s *TemplateImportService) ImportCabinTypeTemplate(ctx context.Context, fil
 	return result, nil
 }
 
+// ImportQuoteTemplate 导入报价模板
+func (s *TemplateImportService) ImportQuoteTemplate(ctx context.Context, filePath string, userID uint64) (*ImportResult, error) {
+	// 解析 Excel 文件
+	rows, err := parsers.ParseQuoteExcel(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse excel: %w", err)
+	}
+
+	result := &ImportResult{
+		TotalRows:   len(rows),
+		SuccessRows: 0,
+		ErrorRows:   0,
+		Errors:      []ImportRowError{},
+		CreatedIDs:  []uint64{},
+	}
+
+	lookup, err := s.newQuoteLookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		// 验证行数据
+		validationErrors := parsers.ValidateQuoteRow(row)
+		if len(validationErrors) > 0 {
+			result.ErrorRows++
+			result.Errors = append(result.Errors, ImportRowError{
+				RowNumber: row.RowNumber,
+				Errors:    validationErrors,
+			})
+			continue
+		}
+
+		// 创建报价
+		quoteID, err := s.createQuote(ctx, row, lookup, userID)
+		if err != nil {
+			result.ErrorRows++
+			result.Errors = append(result.Errors, ImportRowError{
+				RowNumber: row.RowNumber,
+				Errors:    []string{err.Error()},
+			})
+			continue
+		}
+
+		result.SuccessRows++
+		result.CreatedIDs = append(result.CreatedIDs, quoteID)
+	}
+
+	return result, nil
+}
+
+// ImportItineraryTemplate 导入行程模板。行按航次分组，每个航次的行程整体替换；
+// 同一航次任一行有错误时，该航次的行程不导入。CreatedIDs 为已更新行程的航次 ID。
+func (s *TemplateImportService) ImportItineraryTemplate(ctx context.Context, filePath string, userID uint64) (*ImportResult, error) {
+	// 解析 Excel 文件
+	rows, err := parsers.ParseItineraryExcel(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse excel: %w", err)
+	}
+
+	result := &ImportResult{
+		TotalRows:   len(rows),
+		SuccessRows: 0,
+		ErrorRows:   0,
+		Errors:      []ImportRowError{},
+		CreatedIDs:  []uint64{},
+	}
+
+	lookup, err := s.newQuoteLookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := s.portRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+	normalizer := NewNameNormalizer()
+
+	// 按邮轮与出发日期分组，保持文件中的顺序
+	type itineraryGroup struct {
+		rows []parsers.ItineraryRowData
+	}
+	var keys []string
+	groups := make(map[string]*itineraryGroup)
+	for _, row := range rows {
+		key := row.ShipName + "|" + row.DepartureDate
+		group, ok := groups[key]
+		if !ok {
+			group = &itineraryGroup{}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.rows = append(group.rows, row)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+
+		// 验证行数据并解析港口
+		rowErrors := make(map[int][]string)
+		stops := make([]domain.ItineraryStop, 0, len(group.rows))
+		for _, row := range group.rows {
+			if errs := parsers.ValidateItineraryRow(row); len(errs) > 0 {
+				rowErrors[row.RowNumber] = errs
+				continue
+			}
+
+			dayNumber, _ := strconv.Atoi(row.DayNumber)
+			seaDay, _ := parsers.ParseIncluded(row.SeaDay)
+			stop := domain.ItineraryStop{DayNumber: dayNumber, IsSeaDay: seaDay, Note: row.Note}
+			if !seaDay {
+				port := findPortByName(normalizer, ports, row.PortName)
+				if port == nil {
+					rowErrors[row.RowNumber] = []string{fmt.Sprintf("港口“%s”不存在", row.PortName)}
+					continue
+				}
+				stop.PortID = &port.ID
+			}
+			if t, ok := parsers.ParseItineraryTime(row.ArriveTime); ok {
+				stop.ArriveTime = &t
+			}
+			if t, ok := parsers.ParseItineraryTime(row.DepartTime); ok {
+				stop.DepartTime = &t
+			}
+			stops = append(stops, stop)
+		}
+
+		var sailingID uint64
+		var groupErr error
+		if len(rowErrors) == 0 {
+			_, sailingID, groupErr = s.findSailing(ctx, lookup, group.rows[0].ShipName, group.rows[0].DepartureDate)
+		}
+		if len(rowErrors) == 0 && groupErr == nil {
+			sort.SliceStable(stops, func(i, j int) bool { return stops[i].DayNumber < stops[j].DayNumber })
+			_, groupErr = s.catalogService.SetSailingItinerary(ctx, userID, sailingID, stops)
+		}
+
+		if len(rowErrors) == 0 && groupErr == nil {
+			result.SuccessRows += len(group.rows)
+			result.CreatedIDs = append(result.CreatedIDs, sailingID)
+			continue
+		}
+
+		// 整个航次不导入：出错的行给出原因，其余行标记为未导入
+		for _, row := range group.rows {
+			errs, ok := rowErrors[row.RowNumber]
+			switch {
+			case ok:
+			case groupErr != nil:
+				errs = []string{groupErr.Error()}
+			default:
+				errs = []string{"同一航次存在错误行，该航次行程未导入"}
+			}
+			result.ErrorRows++
+			result.Errors = append(result.Errors, ImportRowError{RowNumber: row.RowNumber, Errors: errs})
+		}
+	}
+
+	return result, nil
+}
+
+// quoteLookup 报价导入时按名称查找目录数据，航次与房型按邮轮缓存
+type quoteLookup struct {
+	suppliers  map[string]uint64
+	ships      map[string][]uint64
+	sailings   map[uint64][]domain.Sailing
+	cabinTypes map[uint64][]domain.CabinType
+}
+
+// newQuoteLookup 加载供应商与邮轮名称
+func (s *TemplateImportService) newQuoteLookup(ctx context.Context) (*quoteLookup, error) {
+	suppliers, err := s.supplierRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppliers: %w", err)
+	}
+
+	ships, err := s.shipRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ships: %w", err)
+	}
+
+	lookup := &quoteLookup{
+		suppliers:  make(map[string]uint64, len(suppliers)),
+		ships:      make(map[string][]uint64, len(ships)),
+		sailings:   make(map[uint64][]domain.Sailing),
+		cabinTypes: make(map[uint64][]domain.CabinType),
+	}
+	for _, supplier := range suppliers {
+		lookup.suppliers[supplier.Name] = supplier.ID
+	}
+	for _, ship := range ships {
+		lookup.ships[ship.Name] = append(lookup.ships[ship.Name], ship.ID)
+	}
+
+	return lookup, nil
+}
+
+// findSailing 按邮轮名称与出发日期查找航次，同名邮轮无法区分时报错
+func (s *TemplateImportService) findSailing(ctx context.Context, lookup *quoteLookup, shipName, departureDate string) (uint64, uint64, error) {
+	shipIDs := lookup.ships[shipName]
+	if len(shipIDs) == 0 {
+		return 0, 0, fmt.Errorf("ship '%s' not found", shipName)
+	}
+	if len(shipIDs) > 1 {
+		return 0, 0, fmt.Errorf("ship name '%s' matches %d ships", shipName, len(shipIDs))
+	}
+	shipID := shipIDs[0]
+
+	sailings, ok := lookup.sailings[shipID]
+	if !ok {
+		var err error
+		sailings, err = s.sailingRepo.ListByShip(ctx, shipID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to list sailings: %w", err)
+		}
+		lookup.sailings[shipID] = sailings
+	}
+
+	for _, sailing := range sailings {
+		if sailing.DepartureDate.Format("2006-01-02") == departureDate {
+			return shipID, sailing.ID, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("sailing of ship '%s' on %s not found", shipName, departureDate)
+}
+
+// createQuote 创建报价
+func (s *TemplateImportService) createQuote(ctx context.Context, row parsers.QuoteRowData, lookup *quoteLookup, userID uint64) (uint64, error) {
+	// 查找供应商
+	supplierID, ok := lookup.suppliers[row.SupplierName]
+	if !ok {
+		return 0, fmt.Errorf("supplier '%s' not found", row.SupplierName)
+	}
+
+	// 查找邮轮与航次
+	shipID, sailingID, err := s.findSailing(ctx, lookup, row.ShipName, row.DepartureDate)
+	if err != nil {
+		return 0, err
+	}
+
+	// 查找房型
+	cabinTypes, ok := lookup.cabinTypes[shipID]
+	if !ok {
+		var err error
+		cabinTypes, err = s.cabinTypeRepo.ListByShip(ctx, shipID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list cabin types: %w", err)
+		}
+		lookup.cabinTypes[shipID] = cabinTypes
+	}
+
+	var cabinTypeID uint64
+	for _, ct := range cabinTypes {
+		if ct.Name == row.CabinTypeName {
+			cabinTypeID = ct.ID
+			break
+		}
+	}
+	if cabinTypeID == 0 {
+		return 0, fmt.Errorf("cabin type '%s' not found for ship '%s'", row.CabinTypeName, row.ShipName)
+	}
+
+	// 行数据已通过校验，以下解析不会失败
+	pricingUnit, _ := parsers.ParseQuotePricingUnit(row.PricingUnit)
+
+	var guestCount *int
+	if row.GuestCount != "" {
+		n, _ := strconv.Atoi(row.GuestCount)
+		guestCount = &n
+	}
+
+	var validUntil *time.Time
+	if row.ValidUntil != "" {
+		t, _ := time.Parse("2006-01-02", row.ValidUntil)
+		validUntil = &t
+	}
+
+	var validFrom, quotedAt *time.Time
+	if row.ValidFrom != "" {
+		t, _ := time.Parse("2006-01-02", row.ValidFrom)
+		validFrom = &t
+	}
+	if row.QuotedAt != "" {
+		t, _ := time.Parse("2006-01-02", row.QuotedAt)
+		quotedAt = &t
+	}
+
+	// 价格构成
+	var components []PriceComponentInput
+	if row.BaseFare != "" {
+		components = append(components, PriceComponentInput{
+			ComponentType: domain.PriceComponentBaseFare,
+			Amount:        row.BaseFare,
+			Inclusive:     true,
+		})
+	}
+	for _, charge := range row.Charges {
+		included, _ := parsers.ParseIncluded(charge.Included)
+		components = append(components, PriceComponentInput{
+			ComponentType: domain.PriceComponentType(charge.Type),
+			Amount:        charge.Amount,
+			Inclusive:     included,
+		})
+	}
+
+	// 结构化促销
+	var promotions []PromotionInput
+	for _, promotion := range row.Promotions {
+		promotionType, _ := parsers.ParsePromotionType(promotion.Type)
+		guestSlots, _ := parsers.ParseGuestSlots(promotion.GuestSlots)
+		stackable, _ := parsers.ParseIncluded(promotion.Stackable)
+		input := PromotionInput{
+			PromotionType:  domain.PromotionType(promotionType),
+			DiscountPct:    promotion.DiscountPct,
+			DiscountAmount: promotion.DiscountAmount,
+			GuestSlots:     guestSlots,
+			Stackable:      stackable,
+		}
+		if promotion.BookingFrom != "" {
+			t, _ := time.Parse("2006-01-02", promotion.BookingFrom)
+			input.BookingFrom = &t
+		}
+		if promotion.BookingUntil != "" {
+			t, _ := time.Parse("2006-01-02", promotion.BookingUntil)
+			input.BookingUntil = &t
+		}
+		promotions = append(promotions, input)
+	}
+
+	// 创建报价，审计日志由报价服务记录
+	quote, err := s.quoteService.CreateQuote(ctx, CreateQuoteInput{
+		SailingID:   sailingID,
+		CabinTypeID: cabinTypeID,
+		Price:       row.Price,
+		Currency:    row.Currency,
+		PricingUnit: domain.PricingUnit(pricingUnit),
+		GuestCount:  guestCount,
+		Promotion:   row.Promotion,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		QuotedAt:    quotedAt,
+		Notes:       row.Notes,
+		Source:      domain.QuoteSourceTemplateImport,
+		SupplierID:  supplierID,
+		UserID:      userID,
+		Components:  components,
+		Promotions:  promotions,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return quote.ID, nil
+}
+
 // createSailing 创建航次
 func (s *TemplateImportService) createSailing(ctx context.Context, row parsers.SailingRowData, userID uint64) (uint64, error) {
 	// 查找邮轮公司
@@ -253,8 +638,9 @@ func (s *TemplateImportService) createSailing(ctx context.Context, row parsers.S
 		}
 	}
 
-	// 创建航次
-	err = s.sailingRepo.Create(ctx, sailing)
+	// 创建航次，同一事务内写入领域事件
+	err = s.sailingRepo.Create(ctx, sailing,
+		catalogEvent(ctx, domain.EntityTypeSailing, domain.DomainEventActionCreated, 0, userID, sailing))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create sailing: %w", err)
 	}
@@ -347,7 +733,8 @@ func (s *TemplateImportService) createCabinType(ctx context.Context, row parsers
 		IsEnabled:   true,
 	}
 
-	err = s.cabinTypeRepo.Create(ctx, cabinType)
+	err = s.cabinTypeRepo.Create(ctx, cabinType,
+		catalogEvent(ctx, domain.EntityTypeCabinType, domain.DomainEventActionCreated, 0, userID, cabinType))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create cabin type: %w", err)
 	}
@@ -356,5 +743,10 @@ func (s *TemplateImportService) createCabinType(ctx context.Context, row parsers
 	// 记录审计日志
 	_ = s.auditService.LogCreate(ctx, userID, nil, "cabin_type", cabinTypeID, cabinType)
 
+	// 更新语义索引，失败时仅记录日志，可通过重建索引补齐
+	if err := s.embeddings.IndexCabinType(ctx, cabinType); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("cabin_type_id", cabinTypeID).Warn("Failed to refresh cabin type embedding")
+	}
+
 	return cabinTypeID, nil
 }
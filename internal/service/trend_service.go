@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
+
+	"github.com/shopspring/decimal"
+)
+
+// TrendService builds per-supplier price histories of a sailing cabin type
+type TrendService struct {
+	quoteRepo      *repo.PriceQuoteRepository
+	sailingRepo    *repo.SailingRepository
+	shipRepo       *repo.ShipRepository
+	cruiseLineRepo *repo.CruiseLineRepository
+	cabinTypeRepo  *repo.CabinTypeRepository
+	categoryRepo   *repo.CabinCategoryRepository
+	supplierRepo   *repo.SupplierRepository
+	fx             *FXService
+}
+
+// NewTrendService creates a new trend service
+func NewTrendService(
+	quoteRepo *repo.PriceQuoteRepository,
+	sailingRepo *repo.SailingRepository,
+	shipRepo *repo.ShipRepository,
+	cruiseLineRepo *repo.CruiseLineRepository,
+	cabinTypeRepo *repo.CabinTypeRepository,
+	categoryRepo *repo.CabinCategoryRepository,
+	supplierRepo *repo.SupplierRepository,
+	fx *FXService,
+) *TrendService {
+	return &TrendService{
+		quoteRepo:      quoteRepo,
+		sailingRepo:    sailingRepo,
+		shipRepo:       shipRepo,
+		cruiseLineRepo: cruiseLineRepo,
+		cabinTypeRepo:  cabinTypeRepo,
+		categoryRepo:   categoryRepo,
+		supplierRepo:   supplierRepo,
+		fx:             fx,
+	}
+}
+
+// CabinTypeInfo summarizes a cabin type for trend views
+type CabinTypeInfo struct {
+	ID           uint64 `json:"id"`
+	Name         string `json:"name"`
+	CategoryName string `json:"category_name"`
+}
+
+// PricePoint is a single quote in a price history, placed at the date the supplier issued it
+type PricePoint struct {
+	QuoteID     uint64             `json:"quote_id"`
+	Timestamp   time.Time          `json:"timestamp"`  // quoted_at
+	CreatedAt   time.Time          `json:"created_at"` // when the quote was entered
+	Price       decimal.Decimal    `json:"price"`
+	Currency    string             `json:"currency"`
+	PricingUnit domain.PricingUnit `json:"pricing_unit"`
+	Status      domain.QuoteStatus `json:"status"`
+	ValidFrom   *time.Time         `json:"valid_from,omitempty"`
+	ValidUntil  *time.Time         `json:"valid_until,omitempty"`
+	Expired     bool               `json:"expired"`
+	Notes       string             `json:"notes,omitempty"`
+	Display     *ConvertedAmount   `json:"display,omitempty"`
+
+	CabinQuantity *int `json:"cabin_quantity,omitempty"` // Cabins held by the supplier, if stated
+
+	// Unchanged resubmissions of the quote
+	LastConfirmedAt   *time.Time `json:"last_confirmed_at,omitempty"`
+	ConfirmationCount int        `json:"confirmation_count"`
+}
+
+// ConfirmationPoint is an unchanged resubmission of a quote in a price history. Unlike a
+// PricePoint it does not change the price; it marks the quoted price as still valid.
+type ConfirmationPoint struct {
+	QuoteID   uint64             `json:"quote_id"`
+	Timestamp time.Time          `json:"timestamp"`
+	Price     decimal.Decimal    `json:"price"`
+	Currency  string             `json:"currency"`
+	Source    domain.QuoteSource `json:"source"`
+	Display   *ConvertedAmount   `json:"display,omitempty"`
+}
+
+// SupplierTrend is the price history of one supplier
+type SupplierTrend struct {
+	SupplierID    uint64              `json:"supplier_id"`
+	SupplierName  string              `json:"supplier_name"`
+	Points        []PricePoint        `json:"points"`
+	Confirmations []ConfirmationPoint `json:"confirmations,omitempty"`
+	Forecast      *PriceForecast      `json:"forecast,omitempty"`
+}
+
+// PriceTrend is the price history of a sailing cabin type across suppliers
+type PriceTrend struct {
+	Sailing         SailingInfo     `json:"sailing"`
+	CabinType       CabinTypeInfo   `json:"cabin_type"`
+	Trends          []SupplierTrend `json:"trends"`
+	DisplayCurrency string          `json:"display_currency,omitempty"`
+	Warnings        []string        `json:"warnings,omitempty"`
+}
+
+// TrendInput represents the input for a price trend
+type TrendInput struct {
+	SailingID            uint64
+	CabinTypeID          uint64
+	SupplierIDs          []uint64 // Optional, defaults to every visible supplier
+	From                 *time.Time
+	To                   *time.Time
+	DisplayCurrency      string // Optional, converts prices into this currency
+	IncludeExpired       bool   // Also include quotes past their validity date
+	IncludePending       bool   // Admins only: also include quotes waiting for approval
+	IncludeConfirmations bool   // Also list the unchanged resubmissions of each quote
+	IncludeForecast      bool   // Also forecast the price of each supplier
+	ForecastWeeks        int    // Weekly bands of the forecasts, defaults to 8
+	UserRole             domain.UserRole
+	UserSupplier         uint64
+}
+
+// GetPriceTrend returns the current quotes, and optionally the expired ones, of each visible supplier for a sailing
+// cabin type quoted within the time range, oldest quote date first
+func (s *TrendService) GetPriceTrend(ctx context.Context, input TrendInput) (*PriceTrend, error) {
+	displayCurrency, err := NormalizeCurrency(input.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	forecastWeeks := input.ForecastWeeks
+	if forecastWeeks == 0 {
+		forecastWeeks = defaultForecastWeeks
+	}
+	if forecastWeeks < 1 || forecastWeeks > maxForecastWeeks {
+		return nil, ErrInvalidForecastWeeks
+	}
+
+	sailing, info, err := loadSailingInfo(ctx, s.sailingRepo, s.shipRepo, s.cruiseLineRepo, input.SailingID)
+	if err != nil {
+		return nil, err
+	}
+
+	cabinType, err := s.cabinTypeRepo.GetByID(ctx, input.CabinTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if cabinType == nil || cabinType.ShipID != sailing.ShipID {
+		return nil, ErrCabinTypeNotFound
+	}
+
+	cabinInfo := CabinTypeInfo{ID: cabinType.ID, Name: cabinType.Name}
+	category, err := s.categoryRepo.GetByID(ctx, cabinType.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category != nil {
+		cabinInfo.CategoryName = category.Name
+	}
+
+	quotes, err := s.quoteRepo.ListForTrend(ctx, sailing.ID, cabinType.ID, input.SupplierIDs, input.From, input.To, input.IncludeExpired,
+		input.IncludePending && input.UserRole == domain.UserRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := visibleSuppliers(ctx, s.supplierRepo, quotes, input.SupplierIDs, input.UserRole, input.UserSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := s.fx.NewConverter(displayCurrency)
+	warnings := newWarningSet()
+	now := time.Now()
+
+	trends := make(map[uint64]*SupplierTrend, len(suppliers))
+	points := make(map[uint64]*ConvertedAmount, len(quotes)) // Display amount by quote ID
+	for i := range quotes {
+		q := &quotes[i]
+		supplier, ok := suppliers[q.SupplierID]
+		if !ok {
+			continue
+		}
+
+		trend, ok := trends[q.SupplierID]
+		if !ok {
+			trend = &SupplierTrend{SupplierID: supplier.ID, SupplierName: supplier.Name, Points: []PricePoint{}}
+			trends[q.SupplierID] = trend
+		}
+
+		display, err := convertForDisplay(ctx, converter, q, warnings)
+		if err != nil {
+			return nil, err
+		}
+
+		points[q.ID] = display
+		trend.Points = append(trend.Points, PricePoint{
+			QuoteID:     q.ID,
+			Timestamp:   q.QuotedAt,
+			CreatedAt:   q.CreatedAt,
+			Price:       q.Price,
+			Currency:    q.Currency,
+			PricingUnit: q.PricingUnit,
+			Status:      q.Status,
+			ValidFrom:   q.ValidFrom,
+			ValidUntil:  q.ValidUntil,
+			Expired:     q.IsExpired(now),
+			Notes:       q.Notes,
+			Display:     display,
+
+			CabinQuantity:     q.CabinQuantity,
+			LastConfirmedAt:   q.LastConfirmedAt,
+			ConfirmationCount: q.ConfirmationCount,
+		})
+	}
+
+	if input.IncludeConfirmations {
+		if err := s.addConfirmations(ctx, trends, quotes, points, input.From, input.To); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.IncludeForecast {
+		if err := s.addForecasts(ctx, sailing, cabinType, forecastWeeks, trends); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &PriceTrend{
+		Sailing:         *info,
+		CabinType:       cabinInfo,
+		Trends:          make([]SupplierTrend, 0, len(trends)),
+		DisplayCurrency: displayCurrency,
+	}
+	for _, trend := range trends {
+		result.Trends = append(result.Trends, *trend)
+	}
+	sort.Slice(result.Trends, func(i, j int) bool {
+		return result.Trends[i].SupplierName < result.Trends[j].SupplierName
+	})
+	result.Warnings = warnings.list()
+
+	return result, nil
+}
+
+// addConfirmations adds the confirmations of the trend quotes within the time range to the
+// trend of their supplier. Confirmations repeat the price of their quote.
+func (s *TrendService) addConfirmations(ctx context.Context, trends map[uint64]*SupplierTrend, quotes []domain.PriceQuote, displays map[uint64]*ConvertedAmount, from, to *time.Time) error {
+	byID := make(map[uint64]*domain.PriceQuote, len(displays))
+	ids := make([]uint64, 0, len(displays))
+	for i := range quotes {
+		if _, ok := displays[quotes[i].ID]; ok && quotes[i].ConfirmationCount > 0 {
+			byID[quotes[i].ID] = &quotes[i]
+			ids = append(ids, quotes[i].ID)
+		}
+	}
+
+	confirmations, err := s.quoteRepo.ListConfirmations(ctx, ids, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range confirmations {
+		q := byID[c.QuoteID]
+		trend := trends[q.SupplierID]
+		trend.Confirmations = append(trend.Confirmations, ConfirmationPoint{
+			QuoteID:   q.ID,
+			Timestamp: c.ConfirmedAt,
+			Price:     q.Price,
+			Currency:  q.Currency,
+			Source:    c.Source,
+			Display:   displays[q.ID],
+		})
+	}
+
+	return nil
+}
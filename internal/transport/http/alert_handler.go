@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertHandler handles alert rule and price alert requests of the current user
+type AlertHandler struct {
+	alertService *service.AlertService
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alertService *service.AlertService) *AlertHandler {
+	return &AlertHandler{alertService: alertService}
+}
+
+// ListRules handles GET /api/v1/alert-rules
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	result, err := h.alertService.ListRules(c.Request.Context(), ParsePagination(c), userCtx.UserID)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_ALERT_RULES", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateRule handles POST /api/v1/alert-rules
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Name            string  `json:"name" binding:"required"`
+		SailingID       *uint64 `json:"sailing_id"`
+		ShipID          *uint64 `json:"ship_id"`
+		CabinTypeID     *uint64 `json:"cabin_type_id"`
+		CabinCategoryID *uint64 `json:"cabin_category_id"`
+		Condition       string  `json:"condition" binding:"required"` // BELOW_PRICE, DROP_PERCENT, NEW_LOWEST_SUPPLIER
+		Threshold       string  `json:"threshold"`                    // Per-person price or percentage
+		Currency        string  `json:"currency"`                     // BELOW_PRICE only
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(c.Request.Context(), service.CreateAlertRuleInput{
+		Name:            req.Name,
+		SailingID:       req.SailingID,
+		ShipID:          req.ShipID,
+		CabinTypeID:     req.CabinTypeID,
+		CabinCategoryID: req.CabinCategoryID,
+		Condition:       domain.AlertCondition(req.Condition),
+		Threshold:       req.Threshold,
+		Currency:        req.Currency,
+		UserID:          userCtx.UserID,
+	})
+	if err != nil {
+		var validationErrs domain.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			RespondValidationErrors(c, validationErrs)
+			return
+		}
+		respondAlertError(c, err, "ERR_CREATE_ALERT_RULE")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// UpdateRule handles PUT /api/v1/alert-rules/:id
+// Body: {"is_enabled": false}
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid alert rule ID")
+		return
+	}
+
+	var req struct {
+		IsEnabled *bool `json:"is_enabled" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	rule, err := h.alertService.SetRuleEnabled(c.Request.Context(), id, *req.IsEnabled, userCtx.UserID)
+	if err != nil {
+		respondAlertError(c, err, "ERR_UPDATE_ALERT_RULE")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// DeleteRule handles DELETE /api/v1/alert-rules/:id
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid alert rule ID")
+		return
+	}
+
+	if err := h.alertService.DeleteRule(c.Request.Context(), id, userCtx.UserID); err != nil {
+		respondAlertError(c, err, "ERR_DELETE_ALERT_RULE")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alert rule deleted successfully"})
+}
+
+// ListAlerts handles GET /api/v1/alerts
+// Query: acknowledged=true|false
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var acknowledged *bool
+	switch c.Query("acknowledged") {
+	case "true":
+		v := true
+		acknowledged = &v
+	case "false":
+		v := false
+		acknowledged = &v
+	}
+
+	result, err := h.alertService.ListAlerts(c.Request.Context(), ParsePagination(c), userCtx.UserID, acknowledged)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_ALERTS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AcknowledgeAlert handles POST /api/v1/alerts/:id/acknowledge
+func (h *AlertHandler) AcknowledgeAlert(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid alert ID")
+		return
+	}
+
+	alert, err := h.alertService.AcknowledgeAlert(c.Request.Context(), id, userCtx.UserID)
+	if err != nil {
+		respondAlertError(c, err, "ERR_ACKNOWLEDGE_ALERT")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": alert})
+}
+
+// AcknowledgeAllAlerts handles POST /api/v1/alerts/acknowledge
+func (h *AlertHandler) AcknowledgeAllAlerts(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	count, err := h.alertService.AcknowledgeAllAlerts(c.Request.Context(), userCtx.UserID)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_ACKNOWLEDGE_ALERT", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"acknowledged": count}})
+}
+
+// respondAlertError maps alert service errors to HTTP responses
+func respondAlertError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrAlertRuleNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Alert rule not found")
+	case errors.Is(err, service.ErrPriceAlertNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Alert not found")
+	case errors.Is(err, service.ErrSailingNotFound),
+		errors.Is(err, service.ErrShipNotFound),
+		errors.Is(err, service.ErrCabinTypeNotFound),
+		errors.Is(err, service.ErrCabinCategoryNotFound):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REFERENCE", err.Error())
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
@@ -0,0 +1,325 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler handles market statistics, supplier metrics and year-over-year endpoints
+type AnalyticsHandler struct {
+	analyticsService       *service.AnalyticsService
+	supplierMetricsService *service.SupplierMetricsService
+	seasonalityService     *service.SeasonalityService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService, supplierMetricsService *service.SupplierMetricsService, seasonalityService *service.SeasonalityService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService:       analyticsService,
+		supplierMetricsService: supplierMetricsService,
+		seasonalityService:     seasonalityService,
+	}
+}
+
+// GetMarketStats handles GET /api/v1/sailings/:id/market-stats
+// Query: cabin_category_id=3&currency=CNY&adults=2&children=1
+func (h *AnalyticsHandler) GetMarketStats(c *gin.Context) {
+	input, ok := parseMarketStatsInput(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.GetMarketStats(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_GET_MARKET_STATS")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetPriceDispersion handles GET /api/v1/sailings/:id/market-stats/dispersion
+// Query: from=YYYY-MM-DD&to=YYYY-MM-DD&cabin_category_id=3&currency=CNY&adults=2&children=1
+func (h *AnalyticsHandler) GetPriceDispersion(c *gin.Context) {
+	input, ok := parseMarketStatsInput(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.GetPriceDispersion(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_GET_PRICE_DISPERSION")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ExportMarketStats handles GET /api/v1/sailings/:id/market-stats/export
+// Query: as GetPriceDispersion; responds with an XLSX workbook
+func (h *AnalyticsHandler) ExportMarketStats(c *gin.Context) {
+	input, ok := parseMarketStatsInput(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.analyticsService.ExportMarketStats(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_EXPORT_MARKET_STATS")
+		return
+	}
+	defer file.Close()
+
+	filename := fmt.Sprintf("market_stats_%d_%s.xlsx", input.SailingID, time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	if err := file.Write(c.Writer); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_WRITE_FILE", err.Error())
+		return
+	}
+}
+
+// GetSupplierMetrics handles GET /api/v1/admin/supplier-metrics
+// Query: from=YYYY-MM-DD&to=YYYY-MM-DD&currency=CNY
+func (h *AnalyticsHandler) GetSupplierMetrics(c *gin.Context) {
+	input, ok := parseSupplierMetricsInput(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.supplierMetricsService.GetSupplierMetrics(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_GET_SUPPLIER_METRICS")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ExportSupplierMetrics handles GET /api/v1/admin/supplier-metrics/export
+// Query: as GetSupplierMetrics; responds with an XLSX workbook
+func (h *AnalyticsHandler) ExportSupplierMetrics(c *gin.Context) {
+	input, ok := parseSupplierMetricsInput(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.supplierMetricsService.ExportSupplierMetrics(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_EXPORT_SUPPLIER_METRICS")
+		return
+	}
+	defer file.Close()
+
+	filename := fmt.Sprintf("supplier_metrics_%s.xlsx", time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	if err := file.Write(c.Writer); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_WRITE_FILE", err.Error())
+		return
+	}
+}
+
+// GetYoYComparison handles GET /api/v1/analytics/yoy
+// Query: route=日本 | ship_id=1&align_by=SEASON&season=WINTER | align_by=HOLIDAY&holiday=CHINESE_NEW_YEAR&holiday_window_days=7
+// | align_by=WEEK&week=6&year=2026&compare_year=2025&nights=5&cabin_category_id=3&currency=CNY&adults=2&children=1
+func (h *AnalyticsHandler) GetYoYComparison(c *gin.Context) {
+	input, ok := parseYoYInput(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.seasonalityService.GetYoYComparison(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_GET_YOY_COMPARISON")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ExportYoYComparison handles GET /api/v1/analytics/yoy/export
+// Query: as GetYoYComparison; responds with an XLSX workbook
+func (h *AnalyticsHandler) ExportYoYComparison(c *gin.Context) {
+	input, ok := parseYoYInput(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.seasonalityService.ExportYoYComparison(c.Request.Context(), input)
+	if err != nil {
+		respondAnalyticsError(c, err, "ERR_EXPORT_YOY_COMPARISON")
+		return
+	}
+	defer file.Close()
+
+	filename := fmt.Sprintf("yoy_comparison_%s.xlsx", time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	if err := file.Write(c.Writer); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_WRITE_FILE", err.Error())
+		return
+	}
+}
+
+// parseYoYInput reads the year-over-year parameters; it responds with an error and returns
+// false when they are invalid
+func parseYoYInput(c *gin.Context) (service.YoYInput, bool) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return service.YoYInput{}, false
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return service.YoYInput{}, false
+	}
+
+	ints := make(map[string]*int)
+	for _, name := range []string{"nights", "holiday_window_days", "week", "year", "compare_year"} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", fmt.Sprintf("Invalid %s", name))
+			return service.YoYInput{}, false
+		}
+		ints[name] = &n
+	}
+	value := func(name string) int {
+		if n := ints[name]; n != nil {
+			return *n
+		}
+		return 0
+	}
+
+	alignBy := service.YoYAlignment(strings.ToUpper(c.DefaultQuery("align_by", string(service.YoYAlignSeason))))
+
+	return service.YoYInput{
+		RouteKeyword:      c.Query("route"),
+		ShipID:            ParseUint64Query(c, "ship_id"),
+		Nights:            ints["nights"],
+		CabinCategoryID:   ParseUint64Query(c, "cabin_category_id"),
+		AlignBy:           alignBy,
+		Season:            service.Season(strings.ToUpper(c.Query("season"))),
+		Holiday:           service.Holiday(strings.ToUpper(c.Query("holiday"))),
+		HolidayWindowDays: ints["holiday_window_days"],
+		Week:              value("week"),
+		Year:              value("year"),
+		CompareYear:       value("compare_year"),
+		DisplayCurrency:   c.Query("currency"),
+		Occupancy:         occupancy,
+		UserRole:          userCtx.Role,
+		UserSupplier:      userCtx.SupplierID,
+	}, true
+}
+
+// parseSupplierMetricsInput reads the supplier metrics period; it responds with an error and
+// returns false when it is invalid
+func parseSupplierMetricsInput(c *gin.Context) (service.SupplierMetricsInput, bool) {
+	from, ok := ParseDateQuery(c, "from")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return service.SupplierMetricsInput{}, false
+	}
+
+	to, ok := ParseDateQuery(c, "to")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return service.SupplierMetricsInput{}, false
+	}
+
+	return service.SupplierMetricsInput{
+		From:            from,
+		To:              to,
+		DisplayCurrency: c.Query("currency"),
+	}, true
+}
+
+// parseMarketStatsInput reads the common market statistics parameters; it responds with an
+// error and returns false when they are invalid
+func parseMarketStatsInput(c *gin.Context) (service.MarketStatsInput, bool) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return service.MarketStatsInput{}, false
+	}
+
+	sailingID, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return service.MarketStatsInput{}, false
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return service.MarketStatsInput{}, false
+	}
+
+	from, ok := ParseDateQuery(c, "from")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return service.MarketStatsInput{}, false
+	}
+
+	to, ok := ParseDateQuery(c, "to")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return service.MarketStatsInput{}, false
+	}
+
+	return service.MarketStatsInput{
+		SailingID:       sailingID,
+		CabinCategoryID: ParseUint64Query(c, "cabin_category_id"),
+		DisplayCurrency: c.Query("currency"),
+		Occupancy:       occupancy,
+		From:            from,
+		To:              to,
+		UserRole:        userCtx.Role,
+		UserSupplier:    userCtx.SupplierID,
+	}, true
+}
+
+// respondAnalyticsError maps analytics service errors to HTTP responses
+func respondAnalyticsError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAnalyticsWindow), errors.Is(err, service.ErrAnalyticsWindowTooLarge):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+	case errors.Is(err, service.ErrYoYScopeRequired),
+		errors.Is(err, service.ErrInvalidYoYAlignment),
+		errors.Is(err, service.ErrInvalidSeason),
+		errors.Is(err, service.ErrInvalidHoliday),
+		errors.Is(err, service.ErrHolidayDateUnknown),
+		errors.Is(err, service.ErrInvalidHolidayWindow),
+		errors.Is(err, service.ErrInvalidYoYWeek),
+		errors.Is(err, service.ErrInvalidYoYYears):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+	case errors.Is(err, service.ErrShipNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Ship not found")
+	default:
+		respondAnalysisError(c, err, code)
+	}
+}
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"cruise-price-compare/internal/auth"
 	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/repo"
 	"cruise-price-compare/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +25,36 @@ func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
 	return &CatalogHandler{catalogService: catalogService}
 }
 
+// Search handlers
+
+// SearchCatalog finds cruise lines, ships and cabin categories by name
+// GET /api/v1/catalog/search?q=
+func (h *CatalogHandler) SearchCatalog(c *gin.Context) {
+	keyword := c.Query("q")
+	if keyword == "" {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Query parameter q is required")
+		return
+	}
+
+	limit := 20
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	hits, err := h.catalogService.Search(c.Request.Context(), keyword, limit)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_SEARCH_CATALOG", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": hits})
+}
+
 // CruiseLine handlers
 
 // ListCruiseLines returns a paginated list of cruise lines
@@ -243,6 +277,7 @@ func (h *CatalogHandler) CreateShip(c *gin.Context) {
 	var req struct {
 		CruiseLineID uint64   `json:"cruise_line_id" binding:"required"`
 		Name         string   `json:"name" binding:"required"`
+		NameEN       string   `json:"name_en"`
 		IMO          *string  `json:"imo"`
 		Aliases      []string `json:"aliases"`
 	}
@@ -254,6 +289,7 @@ func (h *CatalogHandler) CreateShip(c *gin.Context) {
 	ship := &domain.Ship{
 		CruiseLineID: req.CruiseLineID,
 		Name:         req.Name,
+		NameEN:       req.NameEN,
 		IMO:          req.IMO,
 		Aliases:      req.Aliases,
 	}
@@ -292,6 +328,7 @@ func (h *CatalogHandler) UpdateShip(c *gin.Context) {
 	var req struct {
 		CruiseLineID uint64              `json:"cruise_line_id" binding:"required"`
 		Name         string              `json:"name" binding:"required"`
+		NameEN       string              `json:"name_en"`
 		IMO          *string             `json:"imo"`
 		Aliases      []string            `json:"aliases"`
 		Status       domain.EntityStatus `json:"status"`
@@ -305,6 +342,7 @@ func (h *CatalogHandler) UpdateShip(c *gin.Context) {
 		ID:           id,
 		CruiseLineID: req.CruiseLineID,
 		Name:         req.Name,
+		NameEN:       req.NameEN,
 		IMO:          req.IMO,
 		Aliases:      req.Aliases,
 		Status:       req.Status,
@@ -680,6 +718,129 @@ func (h *CatalogHandler) ListSailings(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// SearchSailings searches sailings and returns them with the lowest latest price per cabin category
+// GET /api/v1/sailings/search
+// Query: from=YYYY-MM-DD&to=YYYY-MM-DD&min_nights=4&max_nights=7&cruise_line_id=1&ship_id=2
+// &embark_port_id=3 (or embark_port=上海)&port_id=4 (or port=福冈)&route=日本&quoted_by=5&status=ACTIVE
+// &sort=departure_date|nights|lowest_price&order=asc|desc&price_category_id=6&currency=CNY
+func (h *CatalogHandler) SearchSailings(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	filter := repo.SailingSearchFilter{
+		CruiseLineID:       ParseUint64Query(c, "cruise_line_id"),
+		ShipID:             ParseUint64Query(c, "ship_id"),
+		RouteKeyword:       strings.TrimSpace(c.Query("route")),
+		QuotedBySupplierID: ParseUint64Query(c, "quoted_by"),
+		SortDesc:           c.Query("order") == "desc",
+	}
+
+	var ok bool
+	if filter.FromDate, ok = ParseDateQuery(c, "from"); !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return
+	}
+	if filter.ToDate, ok = ParseDateQuery(c, "to"); !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return
+	}
+
+	parseNights := func(name string) (*int, bool) {
+		v := c.Query(name)
+		if v == "" {
+			return nil, true
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", fmt.Sprintf("Invalid %s", name))
+			return nil, false
+		}
+		return &n, true
+	}
+	if filter.MinNights, ok = parseNights("min_nights"); !ok {
+		return
+	}
+	if filter.MaxNights, ok = parseNights("max_nights"); !ok {
+		return
+	}
+
+	if v := c.Query("status"); v != "" {
+		status := domain.SailingStatus(v)
+		filter.Status = &status
+	}
+
+	switch sortBy := repo.SailingSort(c.DefaultQuery("sort", string(repo.SailingSortDeparture))); sortBy {
+	case repo.SailingSortDeparture, repo.SailingSortNights, repo.SailingSortLowestPrice:
+		filter.Sort = sortBy
+	default:
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "sort must be departure_date, nights or lowest_price")
+		return
+	}
+
+	if filter.EmbarkPortID, ok = h.parsePortQuery(c, "embark_port_id", "embark_port"); !ok {
+		return
+	}
+	if filter.PortID, ok = h.parsePortQuery(c, "port_id", "port"); !ok {
+		return
+	}
+
+	result, err := h.catalogService.SearchSailings(c.Request.Context(), service.SailingSearchInput{
+		Pagination:      ParsePagination(c),
+		Filter:          filter,
+		Currency:        c.Query("currency"),
+		PriceCategoryID: ParseUint64Query(c, "price_category_id"),
+		UserRole:        userCtx.Role,
+		UserSupplier:    userCtx.SupplierID,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCurrency) {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_CURRENCY", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSupplierNotFound) {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Unknown quoted_by supplier")
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_SEARCH_SAILINGS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parsePortQuery reads a port filter given by ID or by name; it responds with an error and
+// returns false when the port cannot be resolved
+func (h *CatalogHandler) parsePortQuery(c *gin.Context, idParam, nameParam string) (*uint64, bool) {
+	if v := c.Query(idParam); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", fmt.Sprintf("Invalid %s", idParam))
+			return nil, false
+		}
+		return &id, true
+	}
+
+	name := strings.TrimSpace(c.Query(nameParam))
+	if name == "" {
+		return nil, true
+	}
+
+	port, err := h.catalogService.ResolvePort(c.Request.Context(), name)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_RESOLVE_PORT", err.Error())
+		return nil, false
+	}
+	if port == nil {
+		RespondError(c, http.StatusBadRequest, "ERR_PORT_NOT_FOUND", fmt.Sprintf("Port '%s' not found", name))
+		return nil, false
+	}
+
+	return &port.ID, true
+}
+
 // GetSailing returns a sailing by ID
 func (h *CatalogHandler) GetSailing(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -792,6 +953,269 @@ func (h *CatalogHandler) DeleteSailing(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// itineraryStopRequest is one day of an itinerary in a request; the port is given either
+// by ID or by any of its names or its UN/LOCODE
+type itineraryStopRequest struct {
+	DayNumber  int     `json:"day_number" binding:"required"`
+	PortID     *uint64 `json:"port_id"`
+	PortName   string  `json:"port_name"`
+	ArriveTime *string `json:"arrive_time"` // HH:MM
+	DepartTime *string `json:"depart_time"` // HH:MM
+	IsSeaDay   bool    `json:"is_sea_day"`
+	Note       string  `json:"note"`
+}
+
+// GetSailingItinerary returns the itinerary of a sailing
+func (h *CatalogHandler) GetSailingItinerary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return
+	}
+
+	stops, err := h.catalogService.GetSailingItinerary(c.Request.Context(), id)
+	if err != nil {
+		if err == service.ErrSailingNotFound {
+			RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Sailing not found")
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_GET_ITINERARY", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, stops)
+}
+
+// SetSailingItinerary replaces the itinerary of a sailing
+func (h *CatalogHandler) SetSailingItinerary(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return
+	}
+
+	var req struct {
+		Stops []itineraryStopRequest `json:"stops"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	stops := make([]domain.ItineraryStop, 0, len(req.Stops))
+	for _, s := range req.Stops {
+		stop := domain.ItineraryStop{
+			DayNumber:  s.DayNumber,
+			PortID:     s.PortID,
+			ArriveTime: s.ArriveTime,
+			DepartTime: s.DepartTime,
+			IsSeaDay:   s.IsSeaDay,
+			Note:       s.Note,
+		}
+		if stop.PortID == nil && s.PortName != "" {
+			port, err := h.catalogService.ResolvePort(c.Request.Context(), s.PortName)
+			if err != nil {
+				RespondError(c, http.StatusInternalServerError, "ERR_RESOLVE_PORT", err.Error())
+				return
+			}
+			if port == nil {
+				RespondError(c, http.StatusBadRequest, "ERR_PORT_NOT_FOUND", fmt.Sprintf("Port '%s' not found", s.PortName))
+				return
+			}
+			stop.PortID = &port.ID
+		}
+		stops = append(stops, stop)
+	}
+
+	result, err := h.catalogService.SetSailingItinerary(c.Request.Context(), userCtx.UserID, id, stops)
+	if err != nil {
+		var validationErrs domain.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrs):
+			RespondValidationErrors(c, validationErrs)
+		case err == service.ErrSailingNotFound:
+			RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Sailing not found")
+		case errors.Is(err, service.ErrPortNotFound):
+			RespondError(c, http.StatusBadRequest, "ERR_PORT_NOT_FOUND", err.Error())
+		default:
+			RespondError(c, http.StatusInternalServerError, "ERR_SET_ITINERARY", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Port handlers
+
+// ListPorts returns a paginated list of ports
+func (h *CatalogHandler) ListPorts(c *gin.Context) {
+	pagination := ParsePagination(c)
+	statusParam := c.Query("status")
+	var status *domain.EntityStatus
+	if statusParam != "" {
+		s := domain.EntityStatus(statusParam)
+		status = &s
+	}
+
+	result, err := h.catalogService.ListPorts(c.Request.Context(), pagination, c.Query("country"), status)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_PORTS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPort returns a port by ID
+func (h *CatalogHandler) GetPort(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid port ID")
+		return
+	}
+
+	port, err := h.catalogService.GetPort(c.Request.Context(), id)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_GET_PORT", err.Error())
+		return
+	}
+	if port == nil {
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Port not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, port)
+}
+
+// portRequest is the body of a port create or update request
+type portRequest struct {
+	Name     string              `json:"name" binding:"required"`
+	NameEN   string              `json:"name_en"`
+	Aliases  []string            `json:"aliases"`
+	Country  string              `json:"country"`
+	UNLocode string              `json:"unlocode"` // e.g. CNSHA or "CN SHA"
+	Status   domain.EntityStatus `json:"status"`
+}
+
+func (r *portRequest) toDomain() *domain.Port {
+	return &domain.Port{
+		Name:     r.Name,
+		NameEN:   r.NameEN,
+		Aliases:  r.Aliases,
+		Country:  r.Country,
+		UNLocode: domain.NormalizeUNLocode(r.UNLocode),
+		Status:   r.Status,
+	}
+}
+
+// CreatePort creates a new port
+func (h *CatalogHandler) CreatePort(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req portRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	port := req.toDomain()
+	if errs := domain.ValidatePort(port); len(errs) > 0 {
+		RespondValidationErrors(c, errs)
+		return
+	}
+
+	if err := h.catalogService.CreatePort(c.Request.Context(), userCtx.UserID, port); err != nil {
+		respondPortError(c, err, "ERR_CREATE_PORT")
+		return
+	}
+
+	c.JSON(http.StatusCreated, port)
+}
+
+// UpdatePort updates an existing port
+func (h *CatalogHandler) UpdatePort(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid port ID")
+		return
+	}
+
+	var req portRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	port := req.toDomain()
+	port.ID = id
+	if errs := domain.ValidatePort(port); len(errs) > 0 {
+		RespondValidationErrors(c, errs)
+		return
+	}
+
+	if err := h.catalogService.UpdatePort(c.Request.Context(), userCtx.UserID, port); err != nil {
+		respondPortError(c, err, "ERR_UPDATE_PORT")
+		return
+	}
+
+	c.JSON(http.StatusOK, port)
+}
+
+// DeletePort deletes a port
+func (h *CatalogHandler) DeletePort(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid port ID")
+		return
+	}
+
+	if err := h.catalogService.DeletePort(c.Request.Context(), userCtx.UserID, id); err != nil {
+		respondPortError(c, err, "ERR_DELETE_PORT")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondPortError maps port service errors to HTTP responses
+func respondPortError(c *gin.Context, err error, code string) {
+	switch err {
+	case service.ErrPortNotFound:
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Port not found")
+	case service.ErrDuplicateName:
+		RespondError(c, http.StatusConflict, "ERR_DUPLICATE_NAME", "Port with this name already exists")
+	case service.ErrDuplicateUNLocode:
+		RespondError(c, http.StatusConflict, "ERR_DUPLICATE_UNLOCODE", "Port with this UN/LOCODE already exists")
+	case service.ErrPortInUse:
+		RespondError(c, http.StatusConflict, "ERR_PORT_IN_USE", "Port is used by sailing itineraries; deactivate it instead")
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
+
 // Supplier handlers
 
 // ListSuppliers returns a paginated list of suppliers
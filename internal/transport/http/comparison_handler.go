@@ -0,0 +1,286 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComparisonHandler handles price comparison, trend and calendar requests
+type ComparisonHandler struct {
+	comparisonService *service.ComparisonService
+	trendService      *service.TrendService
+	calendarService   *service.CalendarService
+}
+
+// NewComparisonHandler creates a new comparison handler
+func NewComparisonHandler(comparisonService *service.ComparisonService, trendService *service.TrendService, calendarService *service.CalendarService) *ComparisonHandler {
+	return &ComparisonHandler{
+		comparisonService: comparisonService,
+		trendService:      trendService,
+		calendarService:   calendarService,
+	}
+}
+
+// GetSailingComparison handles GET /api/v1/sailings/:id/comparison
+// Query: supplier_ids=1,2&cabin_category_id=3&currency=USD&adults=2&children=1&include_expired=true&include_pending=true&promotion_type=EARLY_BIRD
+// &as_of=2026-03-10T15:00:00+08:00 (or a date, meaning the end of that day)
+func (h *ComparisonHandler) GetSailingComparison(c *gin.Context) {
+	input, ok := parseComparisonInput(c)
+	if !ok {
+		return
+	}
+
+	asOf, ok := ParseTimeQuery(c, "as_of")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid as_of time format")
+		return
+	}
+	input.AsOf = asOf
+
+	result, err := h.comparisonService.GetSailingComparison(c.Request.Context(), input)
+	if err != nil {
+		respondAnalysisError(c, err, "ERR_GET_COMPARISON")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetComparisonDiff handles GET /api/v1/sailings/:id/comparison/diff
+// Query: from=2026-03-03&to=2026-03-10T15:00:00+08:00 (both required) plus the comparison filters
+func (h *ComparisonHandler) GetComparisonDiff(c *gin.Context) {
+	input, ok := parseComparisonInput(c)
+	if !ok {
+		return
+	}
+
+	from, ok := ParseTimeQuery(c, "from")
+	if !ok || from == nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "from is required as a date or RFC 3339 time")
+		return
+	}
+
+	to, ok := ParseTimeQuery(c, "to")
+	if !ok || to == nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "to is required as a date or RFC 3339 time")
+		return
+	}
+
+	result, err := h.comparisonService.GetComparisonDiff(c.Request.Context(), service.ComparisonDiffInput{
+		ComparisonInput: input,
+		From:            *from,
+		To:              *to,
+	})
+	if err != nil {
+		respondAnalysisError(c, err, "ERR_GET_COMPARISON_DIFF")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// parseComparisonInput reads the common comparison parameters; it responds with an error and
+// returns false when they are invalid
+func parseComparisonInput(c *gin.Context) (service.ComparisonInput, bool) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return service.ComparisonInput{}, false
+	}
+
+	sailingID, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return service.ComparisonInput{}, false
+	}
+
+	supplierIDs, ok := ParseUint64ListQuery(c, "supplier_ids")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid supplier_ids")
+		return service.ComparisonInput{}, false
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return service.ComparisonInput{}, false
+	}
+
+	var promotionType *domain.PromotionType
+	if v := c.Query("promotion_type"); v != "" {
+		t := domain.PromotionType(v)
+		promotionType = &t
+	}
+
+	return service.ComparisonInput{
+		SailingID:       sailingID,
+		SupplierIDs:     supplierIDs,
+		CabinCategoryID: ParseUint64Query(c, "cabin_category_id"),
+		DisplayCurrency: c.Query("currency"),
+		Occupancy:       occupancy,
+		IncludeExpired:  c.Query("include_expired") == "true",
+		IncludePending:  c.Query("include_pending") == "true",
+		PromotionType:   promotionType,
+		UserRole:        userCtx.Role,
+		UserSupplier:    userCtx.SupplierID,
+	}, true
+}
+
+// GetPriceTrend handles GET /api/v1/sailings/:id/cabin-types/:cabinTypeId/trend
+// Query: supplier_ids=1,2&from=YYYY-MM-DD&to=YYYY-MM-DD&currency=USD&include_expired=true&include_pending=true&include_confirmations=true
+// &include_forecast=true&forecast_weeks=8
+func (h *ComparisonHandler) GetPriceTrend(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	sailingID, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return
+	}
+
+	cabinTypeID, ok := ParseUint64Param(c, "cabinTypeId")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid cabin type ID")
+		return
+	}
+
+	supplierIDs, ok := ParseUint64ListQuery(c, "supplier_ids")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid supplier_ids")
+		return
+	}
+
+	from, ok := ParseDateQuery(c, "from")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return
+	}
+
+	to, ok := ParseDateQuery(c, "to")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return
+	}
+	if to != nil {
+		// Include the whole end day
+		endOfDay := to.Add(24*time.Hour - time.Nanosecond)
+		to = &endOfDay
+	}
+
+	forecastWeeks := 0
+	if v := c.Query("forecast_weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid forecast_weeks")
+			return
+		}
+		forecastWeeks = n
+	}
+
+	result, err := h.trendService.GetPriceTrend(c.Request.Context(), service.TrendInput{
+		SailingID:       sailingID,
+		CabinTypeID:     cabinTypeID,
+		SupplierIDs:     supplierIDs,
+		From:            from,
+		To:              to,
+		DisplayCurrency: c.Query("currency"),
+		IncludeExpired:  c.Query("include_expired") == "true",
+		IncludePending:  c.Query("include_pending") == "true",
+		UserRole:        userCtx.Role,
+		UserSupplier:    userCtx.SupplierID,
+
+		IncludeConfirmations: c.Query("include_confirmations") == "true",
+		IncludeForecast:      c.Query("include_forecast") == "true",
+		ForecastWeeks:        forecastWeeks,
+	})
+	if err != nil {
+		respondAnalysisError(c, err, "ERR_GET_TREND")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetPriceCalendar handles GET /api/v1/price-calendar
+// Query: ship_id=1 | cruise_line_id=2 | route=日本&from=YYYY-MM-DD&to=YYYY-MM-DD&cabin_category_id=3&currency=CNY&adults=2&children=1
+func (h *ComparisonHandler) GetPriceCalendar(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	from, ok := ParseDateQuery(c, "from")
+	if !ok || from == nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "from is required in YYYY-MM-DD format")
+		return
+	}
+
+	to, ok := ParseDateQuery(c, "to")
+	if !ok || to == nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "to is required in YYYY-MM-DD format")
+		return
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return
+	}
+
+	result, err := h.calendarService.GetPriceCalendar(c.Request.Context(), service.CalendarInput{
+		ShipID:          ParseUint64Query(c, "ship_id"),
+		CruiseLineID:    ParseUint64Query(c, "cruise_line_id"),
+		Route:           strings.TrimSpace(c.Query("route")),
+		From:            *from,
+		To:              *to,
+		CabinCategoryID: ParseUint64Query(c, "cabin_category_id"),
+		DisplayCurrency: c.Query("currency"),
+		Occupancy:       occupancy,
+		UserRole:        userCtx.Role,
+		UserSupplier:    userCtx.SupplierID,
+	})
+	if err != nil {
+		respondAnalysisError(c, err, "ERR_GET_PRICE_CALENDAR")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondAnalysisError maps comparison, trend, forecast and calendar service errors to HTTP responses
+func respondAnalysisError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrSailingNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Sailing not found")
+	case errors.Is(err, service.ErrCabinTypeNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Cabin type not found")
+	case errors.Is(err, service.ErrInvalidCurrency):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_CURRENCY", err.Error())
+	case errors.Is(err, service.ErrInvalidOccupancy):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", err.Error())
+	case errors.Is(err, service.ErrInvalidDiffWindow),
+		errors.Is(err, service.ErrCalendarScopeRequired),
+		errors.Is(err, service.ErrInvalidCalendarWindow),
+		errors.Is(err, service.ErrCalendarWindowTooLarge),
+		errors.Is(err, service.ErrInvalidForecastWeeks),
+		errors.Is(err, service.ErrInvalidAnalyticsWindow),
+		errors.Is(err, service.ErrAnalyticsWindowTooLarge):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
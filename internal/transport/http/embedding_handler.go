@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmbeddingHandler handles embedding index administration
+type EmbeddingHandler struct {
+	embeddingService *service.EmbeddingIndexService
+}
+
+// NewEmbeddingHandler creates a new embedding handler
+func NewEmbeddingHandler(embeddingService *service.EmbeddingIndexService) *EmbeddingHandler {
+	return &EmbeddingHandler{embeddingService: embeddingService}
+}
+
+// GetIndexStatus returns the number of indexed cabin types, ships and categories
+// GET /api/v1/admin/embeddings
+func (h *EmbeddingHandler) GetIndexStatus(c *gin.Context) {
+	status, err := h.embeddingService.Status(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_EMBEDDING_STATUS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// RebuildIndex re-embeds the catalog; ?force=true also re-embeds unchanged entities
+// POST /api/v1/admin/embeddings/rebuild
+func (h *EmbeddingHandler) RebuildIndex(c *gin.Context) {
+	force := c.Query("force") == "true"
+
+	result, err := h.embeddingService.Rebuild(c.Request.Context(), force)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_EMBEDDING_REBUILD", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventHandler handles the domain event feed and the cursors of its subscribers
+type EventHandler struct {
+	eventService *service.EventService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService *service.EventService) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+// ListEvents handles GET /api/v1/admin/events
+// Query: after=<event id>&event_type=quote.created,quote.voided&limit=100
+// Pass next_after of the response as after to read the following page.
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	var after uint64
+	if v := c.Query("after"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid after")
+			return
+		}
+		after = n
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	var eventTypes []string
+	for _, t := range strings.Split(c.Query("event_type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+
+	page, err := h.eventService.ListEvents(c.Request.Context(), after, eventTypes, limit)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_EVENTS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListSubscribers handles GET /api/v1/admin/event-subscribers
+func (h *EventHandler) ListSubscribers(c *gin.Context) {
+	subscribers, err := h.eventService.ListSubscribers(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_EVENT_SUBSCRIBERS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscribers})
+}
+
+// ResetSubscriber handles PUT /api/v1/admin/event-subscribers/:name
+// Body: {"last_event_id": 0} replays every event after the given position
+func (h *EventHandler) ResetSubscriber(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		LastEventID *uint64 `json:"last_event_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	err := h.eventService.ResetSubscriber(c.Request.Context(), c.Param("name"), *req.LastEventID, userCtx.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSubscriber) || errors.Is(err, service.ErrInvalidEventCursor) {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_RESET_EVENT_SUBSCRIBER", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event subscriber cursor reset successfully"})
+}
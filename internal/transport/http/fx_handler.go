@@ -0,0 +1,180 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FXHandler handles exchange rate administration
+type FXHandler struct {
+	fxService *service.FXService
+}
+
+// NewFXHandler creates a new FX handler
+func NewFXHandler(fxService *service.FXService) *FXHandler {
+	return &FXHandler{fxService: fxService}
+}
+
+// ListRates handles GET /api/v1/admin/fx-rates
+// Query: base=EUR&quote=CNY&from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *FXHandler) ListRates(c *gin.Context) {
+	pagination := ParsePagination(c)
+
+	var base, quote *string
+	if v := strings.ToUpper(c.Query("base")); v != "" {
+		base = &v
+	}
+	if v := strings.ToUpper(c.Query("quote")); v != "" {
+		quote = &v
+	}
+
+	from, ok := ParseDateQuery(c, "from")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return
+	}
+	to, ok := ParseDateQuery(c, "to")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return
+	}
+
+	result, err := h.fxService.ListRates(c.Request.Context(), pagination, base, quote, from, to)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_FX_RATES", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateRate handles POST /api/v1/admin/fx-rates
+func (h *FXHandler) CreateRate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		RateDate      string `json:"rate_date" binding:"required"` // YYYY-MM-DD
+		BaseCurrency  string `json:"base_currency" binding:"required"`
+		QuoteCurrency string `json:"quote_currency" binding:"required"`
+		Rate          string `json:"rate" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	rateDate, err := time.Parse("2006-01-02", req.RateDate)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid rate_date format")
+		return
+	}
+
+	rate, err := h.fxService.CreateRate(c.Request.Context(), service.CreateFXRateInput{
+		RateDate:      rateDate,
+		BaseCurrency:  req.BaseCurrency,
+		QuoteCurrency: req.QuoteCurrency,
+		Rate:          req.Rate,
+	}, userCtx.UserID)
+	if err != nil {
+		var validationErrs domain.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			RespondValidationErrors(c, validationErrs)
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_CREATE_FX_RATE", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rate})
+}
+
+// DeleteRate handles DELETE /api/v1/admin/fx-rates/:id
+func (h *FXHandler) DeleteRate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid rate ID")
+		return
+	}
+
+	if err := h.fxService.DeleteRate(c.Request.Context(), id, userCtx.UserID); err != nil {
+		if errors.Is(err, service.ErrFXRateNotFound) {
+			RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Exchange rate not found")
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_DELETE_FX_RATE", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rate deleted successfully"})
+}
+
+// ImportRates handles POST /api/v1/admin/fx-rates/import
+// Multipart field "file": a .csv file (date,base,quote,rate) or an ECB-style .xml file
+func (h *FXHandler) ImportRates(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE", "File is required")
+		return
+	}
+
+	var format string
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		format = service.FXFileFormatCSV
+	case ".xml":
+		format = service.FXFileFormatECBXML
+	default:
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE_TYPE", "Only .csv and .xml files are supported")
+		return
+	}
+
+	const maxFileSize = 10 * 1024 * 1024
+	if file.Size > maxFileSize {
+		RespondError(c, http.StatusBadRequest, "ERR_FILE_TOO_LARGE", "File size exceeds 10MB")
+		return
+	}
+
+	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("fx_import_%d_%s", time.Now().UnixNano(), filepath.Base(file.Filename)))
+	if err := c.SaveUploadedFile(file, tempFile); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_SAVE_FILE", "Failed to save uploaded file")
+		return
+	}
+	defer os.Remove(tempFile)
+
+	userID := userCtx.UserID
+	result, err := h.fxService.ImportFile(c.Request.Context(), tempFile, format, &userID)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_IMPORT_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
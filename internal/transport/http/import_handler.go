@@ -60,9 +60,8 @@ func (h *ImportHandler) UploadFile(c *gin.Context) {
 	}
 
 	// Validate file type
-	ext := file.Filename[len(file.Filename)-5:]
-	if ext != ".pdf" && ext != ".docx" && ext[len(ext)-4:] != ".doc" {
-		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE_TYPE", "Only PDF and Word documents are supported")
+	if !service.IsSupportedImportFile(file.Filename) {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE_TYPE", "Only PDF, Word and Excel files are supported")
 		return
 	}
 
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryHandler handles cabin inventory endpoints
+type InventoryHandler struct {
+	inventoryService *service.InventoryService
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(inventoryService *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+// GetSailingInventory handles GET /api/v1/sailings/:id/inventory
+// Query: supplier_ids=1,2
+func (h *InventoryHandler) GetSailingInventory(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	sailingID, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return
+	}
+
+	supplierIDs, ok := ParseUint64ListQuery(c, "supplier_ids")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid supplier_ids")
+		return
+	}
+
+	result, err := h.inventoryService.GetSailingInventory(c.Request.Context(), service.InventoryInput{
+		SailingID:    sailingID,
+		SupplierIDs:  supplierIDs,
+		UserRole:     userCtx.Role,
+		UserSupplier: userCtx.SupplierID,
+	})
+	if err != nil {
+		respondInventoryError(c, err, "ERR_GET_INVENTORY")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetInventoryTrend handles GET /api/v1/sailings/:id/cabin-types/:cabinTypeId/inventory-trend
+// Query: supplier_ids=1,2&from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *InventoryHandler) GetInventoryTrend(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	sailingID, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid sailing ID")
+		return
+	}
+
+	cabinTypeID, ok := ParseUint64Param(c, "cabinTypeId")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid cabin type ID")
+		return
+	}
+
+	supplierIDs, ok := ParseUint64ListQuery(c, "supplier_ids")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid supplier_ids")
+		return
+	}
+
+	from, ok := ParseDateQuery(c, "from")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid from date format")
+		return
+	}
+
+	to, ok := ParseDateQuery(c, "to")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid to date format")
+		return
+	}
+	if to != nil {
+		// Include the whole end day
+		endOfDay := to.Add(24*time.Hour - time.Nanosecond)
+		to = &endOfDay
+	}
+
+	result, err := h.inventoryService.GetInventoryTrend(c.Request.Context(), service.InventoryTrendInput{
+		SailingID:    sailingID,
+		CabinTypeID:  cabinTypeID,
+		SupplierIDs:  supplierIDs,
+		From:         from,
+		To:           to,
+		UserRole:     userCtx.Role,
+		UserSupplier: userCtx.SupplierID,
+	})
+	if err != nil {
+		respondInventoryError(c, err, "ERR_GET_INVENTORY_TREND")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ReportInventory handles POST /api/v1/inventory-reports
+// Body: {"sailing_id": 1, "cabin_type_id": 2, "state": "SOLD_OUT" | "NO_ALLOCATION", "note": "...", "supplier_id": 3}
+// Vendors report for their own supplier; admins name the supplier.
+func (h *InventoryHandler) ReportInventory(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		SailingID   uint64 `json:"sailing_id" binding:"required"`
+		CabinTypeID uint64 `json:"cabin_type_id" binding:"required"`
+		State       string `json:"state" binding:"required"`
+		Note        string `json:"note"`
+		SupplierID  uint64 `json:"supplier_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	supplierID := userCtx.SupplierID
+	if userCtx.Role == domain.UserRoleAdmin {
+		supplierID = req.SupplierID
+	}
+	if supplierID == 0 {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "supplier_id is required")
+		return
+	}
+
+	report, err := h.inventoryService.ReportInventory(c.Request.Context(), service.ReportInventoryInput{
+		SailingID:   req.SailingID,
+		CabinTypeID: req.CabinTypeID,
+		SupplierID:  supplierID,
+		State:       domain.InventoryState(req.State),
+		Note:        req.Note,
+		UserID:      userCtx.UserID,
+	})
+	if err != nil {
+		respondInventoryError(c, err, "ERR_REPORT_INVENTORY")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": report})
+}
+
+// respondInventoryError maps inventory service errors to HTTP responses
+func respondInventoryError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrSailingNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Sailing not found")
+	case errors.Is(err, service.ErrCabinTypeNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Cabin type not found")
+	case errors.Is(err, service.ErrSupplierNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Supplier not found")
+	case errors.Is(err, service.ErrInvalidInventoryState):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"cruise-price-compare/internal/repo"
 
@@ -129,3 +131,65 @@ func ParseUint64Query(c *gin.Context, name string) *uint64 {
 
 	return &n
 }
+
+// ParseUint64ListQuery parses a comma-separated list of uint64 query values, e.g. ?ids=1,2,3.
+// Returns false if any value is invalid.
+func ParseUint64ListQuery(c *gin.Context, name string) ([]uint64, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return nil, true
+	}
+
+	var ids []uint64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		ids = append(ids, n)
+	}
+
+	return ids, true
+}
+
+// ParseDateQuery parses a YYYY-MM-DD query parameter.
+// Returns false if the value is present but invalid.
+func ParseDateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return nil, true
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, false
+	}
+
+	return &t, true
+}
+
+// ParseTimeQuery parses an RFC 3339 time query parameter, or a YYYY-MM-DD date meaning
+// the end of that day (UTC).
+// Returns false if the value is present but invalid.
+func ParseTimeQuery(c *gin.Context, name string) (*time.Time, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return nil, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, true
+	}
+
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, false
+	}
+	t := d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	return &t, true
+}
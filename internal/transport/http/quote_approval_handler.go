@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteApprovalHandler handles the approval of quotes from suppliers whose quotes are reviewed before publication
+type QuoteApprovalHandler struct {
+	approvalService *service.QuoteApprovalService
+}
+
+// NewQuoteApprovalHandler creates a new quote approval handler
+func NewQuoteApprovalHandler(approvalService *service.QuoteApprovalService) *QuoteApprovalHandler {
+	return &QuoteApprovalHandler{approvalService: approvalService}
+}
+
+// SetApprovalPolicy handles PUT /api/v1/admin/suppliers/:id/approval-policy
+// Body: {"requires_approval": true}
+func (h *QuoteApprovalHandler) SetApprovalPolicy(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid supplier ID")
+		return
+	}
+
+	var req struct {
+		RequiresApproval *bool `json:"requires_approval" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	supplier, err := h.approvalService.SetApprovalPolicy(c.Request.Context(), id, *req.RequiresApproval, userCtx.UserID)
+	if err != nil {
+		respondApprovalError(c, err, "ERR_UPDATE_APPROVAL_POLICY")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": supplier})
+}
+
+// ListPending handles GET /api/v1/admin/quote-approvals
+// Query: supplier_id=1&sailing_id=2
+func (h *QuoteApprovalHandler) ListPending(c *gin.Context) {
+	result, err := h.approvalService.ListPending(c.Request.Context(), service.ListPendingInput{
+		Pagination: ParsePagination(c),
+		SupplierID: ParseUint64Query(c, "supplier_id"),
+		SailingID:  ParseUint64Query(c, "sailing_id"),
+	})
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_QUOTE_APPROVALS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ApproveQuotes handles POST /api/v1/admin/quote-approvals/approve
+// Body: {"quote_ids": [1, 2], "reason": "..."}
+func (h *QuoteApprovalHandler) ApproveQuotes(c *gin.Context) {
+	h.reviewQuotes(c, h.approvalService.ApproveQuotes, "ERR_APPROVE_QUOTES")
+}
+
+// RejectQuotes handles POST /api/v1/admin/quote-approvals/reject
+// Body: {"quote_ids": [1, 2], "reason": "Price outside the contract rate"}
+func (h *QuoteApprovalHandler) RejectQuotes(c *gin.Context) {
+	h.reviewQuotes(c, h.approvalService.RejectQuotes, "ERR_REJECT_QUOTES")
+}
+
+// reviewQuotes approves or rejects the quotes of the request body with review
+func (h *QuoteApprovalHandler) reviewQuotes(c *gin.Context, review func(ctx context.Context, input service.ReviewQuotesInput) (*service.ReviewResult, error), code string) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		QuoteIDs []uint64 `json:"quote_ids" binding:"required"`
+		Reason   string   `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	result, err := review(c.Request.Context(), service.ReviewQuotesInput{
+		QuoteIDs: req.QuoteIDs,
+		Reason:   req.Reason,
+		UserID:   userCtx.UserID,
+	})
+	if err != nil {
+		respondApprovalError(c, err, code)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondApprovalError maps quote approval service errors to HTTP responses
+func respondApprovalError(c *gin.Context, err error, code string) {
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		RespondValidationErrors(c, validationErrs)
+	case errors.Is(err, service.ErrSupplierNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Supplier not found")
+	case errors.Is(err, service.ErrReviewReasonRequired):
+		RespondError(c, http.StatusBadRequest, "ERR_REASON_REQUIRED", err.Error())
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
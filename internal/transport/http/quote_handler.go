@@ -1,7 +1,9 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"cruise-price-compare/internal/auth"
@@ -13,15 +15,20 @@ import (
 
 // QuoteHandler handles quote-related HTTP requests
 type QuoteHandler struct {
-	quoteService *service.QuoteService
+	quoteService         *service.QuoteService
+	normalizationService *service.PriceNormalizationService
 }
 
 // NewQuoteHandler creates a new quote handler
-func NewQuoteHandler(quoteService *service.QuoteService) *QuoteHandler {
-	return &QuoteHandler{quoteService: quoteService}
+func NewQuoteHandler(quoteService *service.QuoteService, normalizationService *service.PriceNormalizationService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService:         quoteService,
+		normalizationService: normalizationService,
+	}
 }
 
 // CreateQuote handles POST /api/v1/quotes
+// Responds 201 with the new quote, or 200 with the latest quote when the submission repeats it unchanged
 func (h *QuoteHandler) CreateQuote(c *gin.Context) {
 	userCtx := auth.GetUserContext(c)
 	if userCtx == nil {
@@ -29,37 +36,111 @@ func (h *QuoteHandler) CreateQuote(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		SailingID      uint64  `json:"sailing_id" binding:"required"`
-		CabinTypeID    uint64  `json:"cabin_type_id" binding:"required"`
-		Price          string  `json:"price" binding:"required"`
-		Currency       string  `json:"currency"`
-		PricingUnit    string  `json:"pricing_unit" binding:"required"`
-		Conditions     string  `json:"conditions"`
-		GuestCount     *int    `json:"guest_count"`
-		Promotion      string  `json:"promotion"`
-		CabinQuantity  *int    `json:"cabin_quantity"`
-		ValidUntil     *string `json:"valid_until"` // YYYY-MM-DD
-		Notes          string  `json:"notes"`
-		IdempotencyKey string  `json:"idempotency_key"`
-	}
-
+	var req quoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
 		return
 	}
 
+	input, ok := req.toInput(c)
+	if !ok {
+		return
+	}
+	input.SupplierID = userCtx.SupplierID
+	input.UserID = userCtx.UserID
+
+	quote, confirmed, err := h.quoteService.SubmitQuote(c.Request.Context(), input)
+	if err != nil {
+		var validationErrs domain.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			RespondValidationErrors(c, validationErrs)
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_CREATE_QUOTE", err.Error())
+		return
+	}
+
+	// An unchanged resubmission confirms the latest quote instead of creating one
+	if confirmed {
+		c.JSON(http.StatusOK, quote)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// quoteRequest is the JSON body of a new or corrected quote
+type quoteRequest struct {
+	SailingID      uint64  `json:"sailing_id" binding:"required"`
+	CabinTypeID    uint64  `json:"cabin_type_id" binding:"required"`
+	Price          string  `json:"price" binding:"required"`
+	Currency       string  `json:"currency"`
+	PricingUnit    string  `json:"pricing_unit" binding:"required"`
+	Conditions     string  `json:"conditions"`
+	GuestCount     *int    `json:"guest_count"`
+	MaxOccupancy   *int    `json:"max_occupancy"`
+	Promotion      string  `json:"promotion"`
+	CabinQuantity  *int    `json:"cabin_quantity"`
+	ValidFrom      *string `json:"valid_from"`  // YYYY-MM-DD
+	ValidUntil     *string `json:"valid_until"` // YYYY-MM-DD
+	QuotedAt       *string `json:"quoted_at"`   // RFC 3339 or YYYY-MM-DD, when the supplier issued the quote
+	Notes          string  `json:"notes"`
+	IdempotencyKey string  `json:"idempotency_key"`
+
+	SingleSupplementPct    string `json:"single_supplement_pct"`
+	SingleSupplementAmount string `json:"single_supplement_amount"`
+	OccupancyRates         []struct {
+		GuestSlot   int    `json:"guest_slot" binding:"required"`
+		GuestType   string `json:"guest_type"` // ADULT (default) or CHILD
+		Price       string `json:"price" binding:"required"`
+		MaxChildAge *int   `json:"max_child_age"`
+	} `json:"occupancy_rates"`
+	Components []struct {
+		ComponentType string `json:"component_type" binding:"required"` // BASE_FARE/PORT_TAX/GRATUITY/FUEL_SURCHARGE/OTHER
+		Amount        string `json:"amount" binding:"required"`
+		PricingUnit   string `json:"pricing_unit"` // defaults to the quote's pricing unit
+		PerNight      bool   `json:"per_night"`
+		Inclusive     bool   `json:"inclusive"`
+		Description   string `json:"description"`
+	} `json:"components"`
+	Promotions []struct {
+		PromotionType  string  `json:"promotion_type"` // EARLY_BIRD/LAST_MINUTE/GUEST_DISCOUNT/FLASH_SALE/MEMBER/ONBOARD_CREDIT/OTHER
+		DiscountPct    string  `json:"discount_pct"`
+		DiscountAmount string  `json:"discount_amount"`
+		PricingUnit    string  `json:"pricing_unit"` // of discount_amount, defaults to the quote's pricing unit
+		GuestSlots     []int   `json:"guest_slots"`  // empty for every guest
+		BookingFrom    *string `json:"booking_from"` // YYYY-MM-DD
+		BookingUntil   *string `json:"booking_until"`
+		Stackable      bool    `json:"stackable"`
+		Description    string  `json:"description"`
+	} `json:"promotions"`
+}
+
+// toInput converts the request into the service input; it responds and returns false when the request is invalid
+func (req *quoteRequest) toInput(c *gin.Context) (service.CreateQuoteInput, bool) {
 	// Parse valid_until
 	var validUntil *time.Time
 	if req.ValidUntil != nil && *req.ValidUntil != "" {
 		t, err := time.Parse("2006-01-02", *req.ValidUntil)
 		if err != nil {
 			RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid valid_until date format")
-			return
+			return service.CreateQuoteInput{}, false
 		}
 		validUntil = &t
 	}
 
+	validFrom, ok := parseOptionalDate(req.ValidFrom)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid valid_from date format")
+		return service.CreateQuoteInput{}, false
+	}
+
+	quotedAt, ok := parseQuotedAt(req.QuotedAt)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid quoted_at format, expected RFC 3339 or YYYY-MM-DD")
+		return service.CreateQuoteInput{}, false
+	}
+
 	input := service.CreateQuoteInput{
 		SailingID:      req.SailingID,
 		CabinTypeID:    req.CabinTypeID,
@@ -70,20 +151,82 @@ func (h *QuoteHandler) CreateQuote(c *gin.Context) {
 		GuestCount:     req.GuestCount,
 		Promotion:      req.Promotion,
 		CabinQuantity:  req.CabinQuantity,
+		ValidFrom:      validFrom,
 		ValidUntil:     validUntil,
+		QuotedAt:       quotedAt,
 		Notes:          req.Notes,
 		IdempotencyKey: req.IdempotencyKey,
-		SupplierID:     userCtx.SupplierID,
-		UserID:         userCtx.UserID,
+
+		MaxOccupancy:           req.MaxOccupancy,
+		SingleSupplementPct:    req.SingleSupplementPct,
+		SingleSupplementAmount: req.SingleSupplementAmount,
+	}
+	for _, r := range req.OccupancyRates {
+		input.OccupancyRates = append(input.OccupancyRates, service.OccupancyRateInput{
+			GuestSlot:   r.GuestSlot,
+			GuestType:   domain.GuestType(r.GuestType),
+			Price:       r.Price,
+			MaxChildAge: r.MaxChildAge,
+		})
+	}
+	for _, pc := range req.Components {
+		input.Components = append(input.Components, service.PriceComponentInput{
+			ComponentType: domain.PriceComponentType(pc.ComponentType),
+			Amount:        pc.Amount,
+			PricingUnit:   domain.PricingUnit(pc.PricingUnit),
+			PerNight:      pc.PerNight,
+			Inclusive:     pc.Inclusive,
+			Description:   pc.Description,
+		})
+	}
+	for _, p := range req.Promotions {
+		bookingFrom, ok := parseOptionalDate(p.BookingFrom)
+		if !ok {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid booking_from date format")
+			return service.CreateQuoteInput{}, false
+		}
+		bookingUntil, ok := parseOptionalDate(p.BookingUntil)
+		if !ok {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_DATE", "Invalid booking_until date format")
+			return service.CreateQuoteInput{}, false
+		}
+		input.Promotions = append(input.Promotions, service.PromotionInput{
+			PromotionType:  domain.PromotionType(p.PromotionType),
+			DiscountPct:    p.DiscountPct,
+			DiscountAmount: p.DiscountAmount,
+			PricingUnit:    domain.PricingUnit(p.PricingUnit),
+			GuestSlots:     p.GuestSlots,
+			BookingFrom:    bookingFrom,
+			BookingUntil:   bookingUntil,
+			Stackable:      p.Stackable,
+			Description:    p.Description,
+		})
 	}
 
-	quote, err := h.quoteService.CreateQuote(c.Request.Context(), input)
-	if err != nil {
-		RespondError(c, http.StatusInternalServerError, "ERR_CREATE_QUOTE", err.Error())
-		return
+	return input, true
+}
+
+// parseQuotedAt parses an optional RFC 3339 time or YYYY-MM-DD date; it returns false when malformed
+func parseQuotedAt(value *string) (*time.Time, bool) {
+	if value == nil || *value == "" {
+		return nil, true
 	}
+	if t, err := time.Parse(time.RFC3339, *value); err == nil {
+		return &t, true
+	}
+	return parseOptionalDate(value)
+}
 
-	c.JSON(http.StatusCreated, quote)
+// parseOptionalDate parses an optional YYYY-MM-DD date; it returns false when the date is malformed
+func parseOptionalDate(value *string) (*time.Time, bool) {
+	if value == nil || *value == "" {
+		return nil, true
+	}
+	t, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
 }
 
 // ListQuotes handles GET /api/v1/quotes
@@ -108,15 +251,22 @@ func (h *QuoteHandler) ListQuotes(c *gin.Context) {
 		status = &s
 	}
 
+	var promotionType *domain.PromotionType
+	if v := c.Query("promotion_type"); v != "" {
+		t := domain.PromotionType(v)
+		promotionType = &t
+	}
+
 	input := service.ListQuotesInput{
-		Pagination:   pagination,
-		SailingID:    sailingID,
-		CabinTypeID:  cabinTypeID,
-		SupplierID:   supplierID,
-		Status:       status,
-		UserID:       userCtx.UserID,
-		UserRole:     userCtx.Role,
-		UserSupplier: userCtx.SupplierID,
+		Pagination:    pagination,
+		SailingID:     sailingID,
+		CabinTypeID:   cabinTypeID,
+		SupplierID:    supplierID,
+		Status:        status,
+		PromotionType: promotionType,
+		UserID:        userCtx.UserID,
+		UserRole:      userCtx.Role,
+		UserSupplier:  userCtx.SupplierID,
 	}
 
 	result, err := h.quoteService.ListQuotes(c.Request.Context(), input)
@@ -201,3 +351,129 @@ func (h *QuoteHandler) VoidQuote(c *gin.Context) {
 
 	c.JSON(http.StatusOK, quote)
 }
+
+// CorrectQuote handles PUT /api/v1/quotes/:id/correct
+// Body: same as CreateQuote. The quote is replaced by a new one for the same supplier.
+func (h *QuoteHandler) CorrectQuote(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid quote ID")
+		return
+	}
+
+	var req quoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	input, ok := req.toInput(c)
+	if !ok {
+		return
+	}
+	input.UserID = userCtx.UserID
+
+	quote, err := h.quoteService.CorrectQuote(c.Request.Context(), id, input, userCtx.Role, userCtx.SupplierID)
+	if err != nil {
+		var validationErrs domain.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			RespondValidationErrors(c, validationErrs)
+			return
+		}
+		if err.Error() == "quote not found" {
+			RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Quote not found")
+			return
+		}
+		if err.Error() == "forbidden: cannot correct other supplier's quotes" {
+			RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", err.Error())
+			return
+		}
+		if err.Error() == "quote is not active" {
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_STATE", err.Error())
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, "ERR_CORRECT_QUOTE", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// GetNormalizedPrice handles GET /api/v1/quotes/:id/normalized
+// Query: adults=2&children=1&currency=USD
+func (h *QuoteHandler) GetNormalizedPrice(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid quote ID")
+		return
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return
+	}
+	if occupancy == nil {
+		occupancy = &service.Occupancy{Adults: domain.DefaultBaseOccupancy}
+	}
+
+	result, err := h.normalizationService.NormalizeQuote(c.Request.Context(), id, *occupancy, c.Query("currency"), userCtx.Role, userCtx.SupplierID)
+	if err != nil {
+		switch {
+		case err.Error() == "quote not found":
+			RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Quote not found")
+		case err.Error() == "forbidden: cannot access other supplier's quotes":
+			RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", err.Error())
+		case errors.Is(err, service.ErrInvalidOccupancy), errors.Is(err, service.ErrOccupancyExceeded):
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", err.Error())
+		case errors.Is(err, service.ErrInvalidCurrency):
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_CURRENCY", err.Error())
+		case errors.Is(err, service.ErrFXRateNotFound):
+			RespondError(c, http.StatusUnprocessableEntity, "ERR_FX_RATE_NOT_FOUND", err.Error())
+		default:
+			RespondError(c, http.StatusInternalServerError, "ERR_NORMALIZE_QUOTE", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ParseOccupancyQuery parses ?adults=&children= into an occupancy; nil when neither is given.
+// Returns false if a value is not a number.
+func ParseOccupancyQuery(c *gin.Context) (*service.Occupancy, bool) {
+	adults, children := c.Query("adults"), c.Query("children")
+	if adults == "" && children == "" {
+		return nil, true
+	}
+
+	occupancy := &service.Occupancy{Adults: domain.DefaultBaseOccupancy}
+	if adults != "" {
+		n, err := strconv.Atoi(adults)
+		if err != nil {
+			return nil, false
+		}
+		occupancy.Adults = n
+	}
+	if children != "" {
+		n, err := strconv.Atoi(children)
+		if err != nil {
+			return nil, false
+		}
+		occupancy.Children = n
+	}
+
+	return occupancy, true
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteReviewHandler handles the review queue of quotes flagged as anomalies
+type QuoteReviewHandler struct {
+	anomalyService *service.AnomalyService
+}
+
+// NewQuoteReviewHandler creates a new quote review handler
+func NewQuoteReviewHandler(anomalyService *service.AnomalyService) *QuoteReviewHandler {
+	return &QuoteReviewHandler{anomalyService: anomalyService}
+}
+
+// ListFlags handles GET /api/v1/admin/quote-reviews
+// Query: status=OPEN|DISMISSED|CONFIRMED|ALL (default OPEN)&supplier_id=1&sailing_id=2
+func (h *QuoteReviewHandler) ListFlags(c *gin.Context) {
+	var status *domain.ReviewFlagStatus
+	switch v := c.DefaultQuery("status", string(domain.ReviewFlagOpen)); v {
+	case "ALL":
+	case string(domain.ReviewFlagOpen), string(domain.ReviewFlagDismissed), string(domain.ReviewFlagConfirmed):
+		s := domain.ReviewFlagStatus(v)
+		status = &s
+	default:
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid status")
+		return
+	}
+
+	result, err := h.anomalyService.ListFlags(c.Request.Context(), ParsePagination(c), status,
+		ParseUint64Query(c, "supplier_id"), ParseUint64Query(c, "sailing_id"))
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_QUOTE_REVIEWS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetFlag handles GET /api/v1/admin/quote-reviews/:id
+func (h *QuoteReviewHandler) GetFlag(c *gin.Context) {
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid review flag ID")
+		return
+	}
+
+	flag, err := h.anomalyService.GetFlag(c.Request.Context(), id)
+	if err != nil {
+		respondReviewError(c, err, "ERR_GET_QUOTE_REVIEW")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": flag})
+}
+
+// ReviewFlag handles POST /api/v1/admin/quote-reviews/:id/review
+// Body: {"status": "DISMISSED" | "CONFIRMED", "note": "..."}
+func (h *QuoteReviewHandler) ReviewFlag(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid review flag ID")
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	flag, err := h.anomalyService.ReviewFlag(c.Request.Context(), id, domain.ReviewFlagStatus(req.Status), req.Note, userCtx.UserID)
+	if err != nil {
+		respondReviewError(c, err, "ERR_REVIEW_QUOTE")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": flag})
+}
+
+// respondReviewError maps anomaly service errors to HTTP responses
+func respondReviewError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrReviewFlagNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Review flag not found")
+	case errors.Is(err, service.ErrReviewFlagClosed):
+		RespondError(c, http.StatusConflict, "ERR_ALREADY_REVIEWED", err.Error())
+	case errors.Is(err, service.ErrInvalidReviewStatus):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Status must be DISMISSED or CONFIRMED")
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RFQHandler handles requests for quote: admins issue and compare them, suppliers answer them
+type RFQHandler struct {
+	rfqService *service.RFQService
+}
+
+// NewRFQHandler creates a new RFQ handler
+func NewRFQHandler(rfqService *service.RFQService) *RFQHandler {
+	return &RFQHandler{rfqService: rfqService}
+}
+
+// CreateRFQ handles POST /api/v1/admin/rfqs
+func (h *RFQHandler) CreateRFQ(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Title         string    `json:"title" binding:"required"`
+		Notes         string    `json:"notes"`
+		CabinQuantity int       `json:"cabin_quantity" binding:"required"` // Group size: cabins per requested cabin type
+		Deadline      time.Time `json:"deadline" binding:"required"`       // RFC 3339
+		Items         []struct {
+			SailingID   uint64 `json:"sailing_id" binding:"required"`
+			CabinTypeID uint64 `json:"cabin_type_id" binding:"required"`
+		} `json:"items" binding:"required,dive"`
+		SupplierIDs []uint64 `json:"supplier_ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	input := service.CreateRFQInput{
+		Title:         req.Title,
+		Notes:         req.Notes,
+		CabinQuantity: req.CabinQuantity,
+		Deadline:      req.Deadline,
+		SupplierIDs:   req.SupplierIDs,
+		UserID:        userCtx.UserID,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, service.RFQItemInput{SailingID: item.SailingID, CabinTypeID: item.CabinTypeID})
+	}
+
+	rfq, err := h.rfqService.CreateRFQ(c.Request.Context(), input)
+	if err != nil {
+		respondRFQError(c, err, "ERR_CREATE_RFQ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rfq})
+}
+
+// ListRFQs handles GET /api/v1/rfqs
+// Query: status (vendors: defaults to the RFQs still open for answers)
+func (h *RFQHandler) ListRFQs(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	input := service.ListRFQsInput{
+		Pagination:   ParsePagination(c),
+		UserRole:     userCtx.Role,
+		UserSupplier: userCtx.SupplierID,
+	}
+	if status := c.Query("status"); status != "" {
+		s := domain.RFQStatus(status)
+		switch s {
+		case domain.RFQStatusOpen, domain.RFQStatusClosed, domain.RFQStatusCancelled:
+		default:
+			RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", "Invalid status")
+			return
+		}
+		input.Status = &s
+	}
+
+	result, err := h.rfqService.ListRFQs(c.Request.Context(), input)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_RFQS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetRFQ handles GET /api/v1/rfqs/:id
+func (h *RFQHandler) GetRFQ(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid RFQ ID")
+		return
+	}
+
+	rfq, err := h.rfqService.GetRFQ(c.Request.Context(), id, userCtx.Role, userCtx.SupplierID)
+	if err != nil {
+		respondRFQError(c, err, "ERR_GET_RFQ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rfq})
+}
+
+// RespondWithQuote handles POST /api/v1/rfqs/:id/quotes
+// Body: a quote as for POST /api/v1/quotes, for a requested sailing cabin type
+func (h *RFQHandler) RespondWithQuote(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid RFQ ID")
+		return
+	}
+
+	var req quoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	input, ok := req.toInput(c)
+	if !ok {
+		return
+	}
+	input.SupplierID = userCtx.SupplierID
+	input.UserID = userCtx.UserID
+
+	quote, confirmed, err := h.rfqService.RespondWithQuote(c.Request.Context(), id, input)
+	if err != nil {
+		respondRFQError(c, err, "ERR_CREATE_QUOTE")
+		return
+	}
+
+	// An unchanged resubmission confirms the latest answer instead of creating one
+	if confirmed {
+		c.JSON(http.StatusOK, quote)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// DeclineRFQ handles POST /api/v1/rfqs/:id/decline
+// Body: {"reason": "No allocation left"}
+func (h *RFQHandler) DeclineRFQ(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid RFQ ID")
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	invitation, err := h.rfqService.DeclineRFQ(c.Request.Context(), id, req.Reason, userCtx.UserID, userCtx.SupplierID)
+	if err != nil {
+		respondRFQError(c, err, "ERR_DECLINE_RFQ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitation})
+}
+
+// CloseRFQ handles POST /api/v1/admin/rfqs/:id/close
+func (h *RFQHandler) CloseRFQ(c *gin.Context) {
+	h.finishRFQ(c, h.rfqService.CloseRFQ, "ERR_CLOSE_RFQ")
+}
+
+// CancelRFQ handles POST /api/v1/admin/rfqs/:id/cancel
+func (h *RFQHandler) CancelRFQ(c *gin.Context) {
+	h.finishRFQ(c, h.rfqService.CancelRFQ, "ERR_CANCEL_RFQ")
+}
+
+// finishRFQ moves an open RFQ to a final status with finish
+func (h *RFQHandler) finishRFQ(c *gin.Context, finish func(ctx context.Context, id, userID uint64) (*domain.RFQ, error), code string) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid RFQ ID")
+		return
+	}
+
+	rfq, err := finish(c.Request.Context(), id, userCtx.UserID)
+	if err != nil {
+		respondRFQError(c, err, code)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rfq})
+}
+
+// GetRFQComparison handles GET /api/v1/admin/rfqs/:id/comparison
+// Query: display_currency (defaults to CNY), adults, children (default two adults)
+func (h *RFQHandler) GetRFQComparison(c *gin.Context) {
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid RFQ ID")
+		return
+	}
+
+	occupancy, ok := ParseOccupancyQuery(c)
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", "Invalid adults or children")
+		return
+	}
+
+	comparison, err := h.rfqService.GetRFQComparison(c.Request.Context(), service.RFQComparisonInput{
+		RFQID:           id,
+		DisplayCurrency: c.Query("display_currency"),
+		Occupancy:       occupancy,
+	})
+	if err != nil {
+		respondRFQError(c, err, "ERR_GET_RFQ_COMPARISON")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comparison})
+}
+
+// respondRFQError maps RFQ service errors to HTTP responses
+func respondRFQError(c *gin.Context, err error, code string) {
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		RespondValidationErrors(c, validationErrs)
+	case errors.Is(err, service.ErrRFQNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "RFQ not found")
+	case errors.Is(err, service.ErrRFQNotOpen),
+		errors.Is(err, service.ErrRFQDeclined):
+		RespondError(c, http.StatusConflict, "ERR_RFQ_NOT_OPEN", err.Error())
+	case errors.Is(err, service.ErrRFQItemNotRequested):
+		RespondError(c, http.StatusBadRequest, "ERR_RFQ_ITEM_NOT_REQUESTED", err.Error())
+	case errors.Is(err, service.ErrSailingNotFound),
+		errors.Is(err, service.ErrCabinTypeNotFound),
+		errors.Is(err, service.ErrSupplierNotFound):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REFERENCE", err.Error())
+	case errors.Is(err, service.ErrInvalidCurrency):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_CURRENCY", err.Error())
+	case errors.Is(err, service.ErrInvalidOccupancy):
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_OCCUPANCY", err.Error())
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
@@ -88,6 +88,7 @@ func RegisterRoutes(r *gin.Engine, handlers *Handlers) {
 		protected.PUT("/auth/password", handlers.Auth.ChangePassword)
 
 		// Catalog - read (all authenticated users)
+		protected.GET("/catalog/search", handlers.Catalog.SearchCatalog)
 		protected.GET("/cruise-lines", handlers.Catalog.ListCruiseLines)
 		protected.GET("/cruise-lines/:id", handlers.Catalog.GetCruiseLine)
 		protected.GET("/ships", handlers.Catalog.ListShips)
@@ -97,21 +98,56 @@ func RegisterRoutes(r *gin.Engine, handlers *Handlers) {
 		protected.GET("/cabin-types", handlers.Catalog.ListCabinTypes)
 		protected.GET("/cabin-types/:id", handlers.Catalog.GetCabinType)
 		protected.GET("/sailings", handlers.Catalog.ListSailings)
+		protected.GET("/sailings/search", handlers.Catalog.SearchSailings)
 		protected.GET("/sailings/:id", handlers.Catalog.GetSailing)
+		protected.GET("/sailings/:id/itinerary", handlers.Catalog.GetSailingItinerary)
+		protected.GET("/sailings/:id/comparison", handlers.Comparison.GetSailingComparison)
+		protected.GET("/sailings/:id/comparison/diff", handlers.Comparison.GetComparisonDiff)
+		protected.GET("/sailings/:id/cabin-types/:cabinTypeId/trend", handlers.Comparison.GetPriceTrend)
+		protected.GET("/price-calendar", handlers.Comparison.GetPriceCalendar)
+		protected.GET("/sailings/:id/inventory", handlers.Inventory.GetSailingInventory)
+		protected.GET("/sailings/:id/cabin-types/:cabinTypeId/inventory-trend", handlers.Inventory.GetInventoryTrend)
+		protected.GET("/sailings/:id/market-stats", handlers.Analytics.GetMarketStats)
+		protected.GET("/sailings/:id/market-stats/dispersion", handlers.Analytics.GetPriceDispersion)
+		protected.GET("/sailings/:id/market-stats/export", handlers.Analytics.ExportMarketStats)
+		protected.GET("/analytics/yoy", handlers.Analytics.GetYoYComparison)
+		protected.GET("/analytics/yoy/export", handlers.Analytics.ExportYoYComparison)
+		protected.GET("/ports", handlers.Catalog.ListPorts)
+		protected.GET("/ports/:id", handlers.Catalog.GetPort)
 		protected.GET("/suppliers", handlers.Catalog.ListSuppliers)
 		protected.GET("/suppliers/:id", handlers.Catalog.GetSupplier)
 
 		// Quotes
 		protected.GET("/quotes", handlers.Quote.ListQuotes)
 		protected.GET("/quotes/:id", handlers.Quote.GetQuote)
+		protected.GET("/quotes/:id/normalized", handlers.Quote.GetNormalizedPrice)
 		protected.POST("/quotes", handlers.Quote.CreateQuote)
 		protected.PUT("/quotes/:id/void", handlers.Quote.VoidQuote)
+		protected.PUT("/quotes/:id/correct", handlers.Quote.CorrectQuote)
+
+		// Cabin inventory
+		protected.POST("/inventory-reports", handlers.Inventory.ReportInventory)
 
 		// Import
 		protected.POST("/import/upload", handlers.Import.UploadFile)
 		protected.GET("/import/jobs", handlers.Import.ListJobs)
 		protected.GET("/import/jobs/:id", handlers.Import.GetJob)
 		protected.POST("/import/jobs/:id/retry", handlers.Import.RetryJob)
+
+		// Price alerts
+		protected.GET("/alert-rules", handlers.Alert.ListRules)
+		protected.POST("/alert-rules", handlers.Alert.CreateRule)
+		protected.PUT("/alert-rules/:id", handlers.Alert.UpdateRule)
+		protected.DELETE("/alert-rules/:id", handlers.Alert.DeleteRule)
+		protected.GET("/alerts", handlers.Alert.ListAlerts)
+		protected.POST("/alerts/acknowledge", handlers.Alert.AcknowledgeAllAlerts)
+		protected.POST("/alerts/:id/acknowledge", handlers.Alert.AcknowledgeAlert)
+
+		// Requests for quote
+		protected.GET("/rfqs", handlers.RFQ.ListRFQs)
+		protected.GET("/rfqs/:id", handlers.RFQ.GetRFQ)
+		protected.POST("/rfqs/:id/quotes", handlers.RFQ.RespondWithQuote)
+		protected.POST("/rfqs/:id/decline", handlers.RFQ.DeclineRFQ)
 	}
 
 	// Admin routes
@@ -134,6 +170,10 @@ func RegisterRoutes(r *gin.Engine, handlers *Handlers) {
 		admin.POST("/sailings", handlers.Catalog.CreateSailing)
 		admin.PUT("/sailings/:id", handlers.Catalog.UpdateSailing)
 		admin.DELETE("/sailings/:id", handlers.Catalog.DeleteSailing)
+		admin.PUT("/sailings/:id/itinerary", handlers.Catalog.SetSailingItinerary)
+		admin.POST("/ports", handlers.Catalog.CreatePort)
+		admin.PUT("/ports/:id", handlers.Catalog.UpdatePort)
+		admin.DELETE("/ports/:id", handlers.Catalog.DeletePort)
 		admin.POST("/suppliers", handlers.Catalog.CreateSupplier)
 		admin.PUT("/suppliers/:id", handlers.Catalog.UpdateSupplier)
 		admin.DELETE("/suppliers/:id", handlers.Catalog.DeleteSupplier)
@@ -143,14 +183,75 @@ func RegisterRoutes(r *gin.Engine, handlers *Handlers) {
 		admin.GET("/template/cabin-type/download", handlers.Template.DownloadCabinTypeTemplate)
 		admin.POST("/template/sailing/import", handlers.Template.UploadSailingTemplate)
 		admin.POST("/template/cabin-type/import", handlers.Template.UploadCabinTypeTemplate)
+		admin.GET("/template/quote/download", handlers.Template.DownloadQuoteTemplate)
+		admin.POST("/template/quote/import", handlers.Template.UploadQuoteTemplate)
+		admin.GET("/template/itinerary/download", handlers.Template.DownloadItineraryTemplate)
+		admin.POST("/template/itinerary/import", handlers.Template.UploadItineraryTemplate)
+
+		// Exchange rates
+		admin.GET("/fx-rates", handlers.FX.ListRates)
+		admin.POST("/fx-rates", handlers.FX.CreateRate)
+		admin.POST("/fx-rates/import", handlers.FX.ImportRates)
+		admin.DELETE("/fx-rates/:id", handlers.FX.DeleteRate)
+
+		// Webhooks
+		admin.GET("/webhooks", handlers.Webhook.ListEndpoints)
+		admin.POST("/webhooks", handlers.Webhook.CreateEndpoint)
+		admin.GET("/webhooks/:id", handlers.Webhook.GetEndpoint)
+		admin.PUT("/webhooks/:id", handlers.Webhook.UpdateEndpoint)
+		admin.DELETE("/webhooks/:id", handlers.Webhook.DeleteEndpoint)
+		admin.GET("/webhook-deliveries", handlers.Webhook.ListDeliveries)
+		admin.GET("/webhook-deliveries/:id", handlers.Webhook.GetDelivery)
+		admin.POST("/webhook-deliveries/:id/redeliver", handlers.Webhook.Redeliver)
+
+		// Domain events
+		admin.GET("/events", handlers.Event.ListEvents)
+		admin.GET("/event-subscribers", handlers.Event.ListSubscribers)
+		admin.PUT("/event-subscribers/:name", handlers.Event.ResetSubscriber)
+
+		// Quote review queue
+		admin.GET("/quote-reviews", handlers.QuoteReview.ListFlags)
+		admin.GET("/quote-reviews/:id", handlers.QuoteReview.GetFlag)
+		admin.POST("/quote-reviews/:id/review", handlers.QuoteReview.ReviewFlag)
+
+		// Supplier metrics
+		admin.GET("/supplier-metrics", handlers.Analytics.GetSupplierMetrics)
+		admin.GET("/supplier-metrics/export", handlers.Analytics.ExportSupplierMetrics)
+
+		// Embedding index
+		admin.GET("/embeddings", handlers.Embedding.GetIndexStatus)
+		admin.POST("/embeddings/rebuild", handlers.Embedding.RebuildIndex)
+
+		// Requests for quote
+		admin.POST("/rfqs", handlers.RFQ.CreateRFQ)
+		admin.POST("/rfqs/:id/close", handlers.RFQ.CloseRFQ)
+		admin.POST("/rfqs/:id/cancel", handlers.RFQ.CancelRFQ)
+		admin.GET("/rfqs/:id/comparison", handlers.RFQ.GetRFQComparison)
+
+		// Quote approval
+		admin.PUT("/suppliers/:id/approval-policy", handlers.QuoteApproval.SetApprovalPolicy)
+		admin.GET("/quote-approvals", handlers.QuoteApproval.ListPending)
+		admin.POST("/quote-approvals/approve", handlers.QuoteApproval.ApproveQuotes)
+		admin.POST("/quote-approvals/reject", handlers.QuoteApproval.RejectQuotes)
 	}
 }
 
 // Handlers aggregates all HTTP handlers
 type Handlers struct {
-	Auth     *AuthHandler
-	Catalog  *CatalogHandler
-	Quote    *QuoteHandler
-	Import   *ImportHandler
-	Template *TemplateHandler
+	Auth          *AuthHandler
+	Catalog       *CatalogHandler
+	Quote         *QuoteHandler
+	Import        *ImportHandler
+	Template      *TemplateHandler
+	Embedding     *EmbeddingHandler
+	Comparison    *ComparisonHandler
+	FX            *FXHandler
+	Alert         *AlertHandler
+	Webhook       *WebhookHandler
+	Event         *EventHandler
+	QuoteReview   *QuoteReviewHandler
+	Inventory     *InventoryHandler
+	Analytics     *AnalyticsHandler
+	RFQ           *RFQHandler
+	QuoteApproval *QuoteApprovalHandler
 }
@@ -211,3 +211,189 @@ func (h *TemplateHandler) UploadCabinTypeTemplate(c *gin.Context) {
 		"data": result,
 	})
 }
+
+// DownloadQuoteTemplate 下载报价模板
+// GET /api/v1/template/quote/download
+func (h *TemplateHandler) DownloadQuoteTemplate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	// 只有管理员可以下载模板
+	if userCtx.Role != domain.UserRoleAdmin {
+		RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", "Only admins can download templates")
+		return
+	}
+
+	// 生成模板
+	file, err := h.templateService.GenerateQuoteTemplate(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_GENERATE_TEMPLATE", err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 设置响应头
+	filename := fmt.Sprintf("quote_template_%s.xlsx", time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	// 写入响应
+	if err := file.Write(c.Writer); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_WRITE_FILE", err.Error())
+		return
+	}
+}
+
+// UploadQuoteTemplate 上传并导入报价模板
+// POST /api/v1/template/quote/import
+func (h *TemplateHandler) UploadQuoteTemplate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	// 只有管理员可以导入模板
+	if userCtx.Role != domain.UserRoleAdmin {
+		RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", "Only admins can import templates")
+		return
+	}
+
+	// 解析上传的文件
+	file, err := c.FormFile("file")
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE", "File is required")
+		return
+	}
+
+	// 验证文件类型
+	if filepath.Ext(file.Filename) != ".xlsx" {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE_TYPE", "Only .xlsx files are supported")
+		return
+	}
+
+	// 验证文件大小（最大 5MB）
+	const maxFileSize = 5 * 1024 * 1024
+	if file.Size > maxFileSize {
+		RespondError(c, http.StatusBadRequest, "ERR_FILE_TOO_LARGE", "File size exceeds 5MB")
+		return
+	}
+
+	// 保存临时文件
+	tempDir := os.TempDir()
+	tempFile := filepath.Join(tempDir, fmt.Sprintf("quote_import_%d_%s", time.Now().Unix(), file.Filename))
+	if err := c.SaveUploadedFile(file, tempFile); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_SAVE_FILE", "Failed to save uploaded file")
+		return
+	}
+	defer os.Remove(tempFile)
+
+	// 导入模板
+	result, err := h.templateService.ImportQuoteTemplate(c.Request.Context(), tempFile, userCtx.UserID)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_IMPORT_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// DownloadItineraryTemplate 下载行程模板
+// GET /api/v1/template/itinerary/download
+func (h *TemplateHandler) DownloadItineraryTemplate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	// 只有管理员可以下载模板
+	if userCtx.Role != domain.UserRoleAdmin {
+		RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", "Only admins can download templates")
+		return
+	}
+
+	// 生成模板
+	file, err := h.templateService.GenerateItineraryTemplate(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_GENERATE_TEMPLATE", err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 设置响应头
+	filename := fmt.Sprintf("itinerary_template_%s.xlsx", time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	// 写入响应
+	if err := file.Write(c.Writer); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_WRITE_FILE", err.Error())
+		return
+	}
+}
+
+// UploadItineraryTemplate 上传并导入行程模板
+// POST /api/v1/template/itinerary/import
+func (h *TemplateHandler) UploadItineraryTemplate(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	// 只有管理员可以导入模板
+	if userCtx.Role != domain.UserRoleAdmin {
+		RespondError(c, http.StatusForbidden, "ERR_FORBIDDEN", "Only admins can import templates")
+		return
+	}
+
+	// 解析上传的文件
+	file, err := c.FormFile("file")
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE", "File is required")
+		return
+	}
+
+	// 验证文件类型
+	if filepath.Ext(file.Filename) != ".xlsx" {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_FILE_TYPE", "Only .xlsx files are supported")
+		return
+	}
+
+	// 验证文件大小（最大 5MB）
+	const maxFileSize = 5 * 1024 * 1024
+	if file.Size > maxFileSize {
+		RespondError(c, http.StatusBadRequest, "ERR_FILE_TOO_LARGE", "File size exceeds 5MB")
+		return
+	}
+
+	// 保存临时文件
+	tempDir := os.TempDir()
+	tempFile := filepath.Join(tempDir, fmt.Sprintf("itinerary_import_%d_%s", time.Now().Unix(), file.Filename))
+	if err := c.SaveUploadedFile(file, tempFile); err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_SAVE_FILE", "Failed to save uploaded file")
+		return
+	}
+	defer os.Remove(tempFile)
+
+	// 导入模板
+	result, err := h.templateService.ImportItineraryTemplate(c.Request.Context(), tempFile, userCtx.UserID)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_IMPORT_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
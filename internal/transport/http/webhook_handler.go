@@ -0,0 +1,248 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"cruise-price-compare/internal/auth"
+	"cruise-price-compare/internal/domain"
+	"cruise-price-compare/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook endpoint administration and the delivery log
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// ListEndpoints handles GET /api/v1/admin/webhooks
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	result, err := h.webhookService.ListEndpoints(c.Request.Context(), ParsePagination(c))
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_WEBHOOKS", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetEndpoint handles GET /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) GetEndpoint(c *gin.Context) {
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid webhook ID")
+		return
+	}
+
+	endpoint, err := h.webhookService.GetEndpoint(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err, "ERR_GET_WEBHOOK")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": endpoint})
+}
+
+// CreateEndpoint handles POST /api/v1/admin/webhooks
+// The signing secret is only returned in this response and when it is rotated.
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req struct {
+		Name        string   `json:"name" binding:"required"`
+		URL         string   `json:"url" binding:"required"`
+		Secret      string   `json:"secret"` // Optional, generated when empty
+		Events      []string `json:"events" binding:"required"`
+		SupplierIDs []uint64 `json:"supplier_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request.Context(), service.CreateWebhookEndpointInput{
+		Name:        req.Name,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      parseWebhookEvents(req.Events),
+		SupplierIDs: req.SupplierIDs,
+	}, userCtx.UserID)
+	if err != nil {
+		respondWebhookError(c, err, "ERR_CREATE_WEBHOOK")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": endpoint, "secret": endpoint.Secret})
+}
+
+// UpdateEndpoint handles PUT /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid webhook ID")
+		return
+	}
+
+	var req struct {
+		Name         *string   `json:"name"`
+		URL          *string   `json:"url"`
+		Events       []string  `json:"events"`
+		SupplierIDs  *[]uint64 `json:"supplier_ids"` // [] clears the supplier filter
+		IsEnabled    *bool     `json:"is_enabled"`
+		RotateSecret bool      `json:"rotate_secret"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_REQUEST", err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Request.Context(), id, service.UpdateWebhookEndpointInput{
+		Name:         req.Name,
+		URL:          req.URL,
+		Events:       parseWebhookEvents(req.Events),
+		SupplierIDs:  req.SupplierIDs,
+		IsEnabled:    req.IsEnabled,
+		RotateSecret: req.RotateSecret,
+	}, userCtx.UserID)
+	if err != nil {
+		respondWebhookError(c, err, "ERR_UPDATE_WEBHOOK")
+		return
+	}
+
+	if req.RotateSecret {
+		c.JSON(http.StatusOK, gin.H{"data": endpoint, "secret": endpoint.Secret})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": endpoint})
+}
+
+// DeleteEndpoint handles DELETE /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Request.Context(), id, userCtx.UserID); err != nil {
+		respondWebhookError(c, err, "ERR_DELETE_WEBHOOK")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// ListDeliveries handles GET /api/v1/admin/webhook-deliveries
+// Query: endpoint_id=1&status=PENDING|SUCCEEDED|FAILED&event_type=quote.created
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var status *domain.WebhookDeliveryStatus
+	if v := c.Query("status"); v != "" {
+		s := domain.WebhookDeliveryStatus(v)
+		status = &s
+	}
+
+	var eventType *domain.WebhookEventType
+	if v := c.Query("event_type"); v != "" {
+		t := domain.WebhookEventType(v)
+		eventType = &t
+	}
+
+	result, err := h.webhookService.ListDeliveries(c.Request.Context(), ParsePagination(c), ParseUint64Query(c, "endpoint_id"), status, eventType)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ERR_LIST_WEBHOOK_DELIVERIES", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetDelivery handles GET /api/v1/admin/webhook-deliveries/:id
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err, "ERR_GET_WEBHOOK_DELIVERY")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": delivery})
+}
+
+// Redeliver handles POST /api/v1/admin/webhook-deliveries/:id/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userCtx := auth.GetUserContext(c)
+	if userCtx == nil {
+		RespondError(c, http.StatusUnauthorized, "ERR_UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	id, ok := ParseUint64Param(c, "id")
+	if !ok {
+		RespondError(c, http.StatusBadRequest, "ERR_INVALID_ID", "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), id, userCtx.UserID)
+	if err != nil {
+		respondWebhookError(c, err, "ERR_REDELIVER_WEBHOOK")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": delivery})
+}
+
+// parseWebhookEvents converts event type names; nil stays nil
+func parseWebhookEvents(names []string) []domain.WebhookEventType {
+	if names == nil {
+		return nil
+	}
+	events := make([]domain.WebhookEventType, len(names))
+	for i, name := range names {
+		events[i] = domain.WebhookEventType(name)
+	}
+	return events
+}
+
+// respondWebhookError maps webhook service errors to HTTP responses
+func respondWebhookError(c *gin.Context, err error, code string) {
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		RespondValidationErrors(c, validationErrs)
+	case errors.Is(err, service.ErrWebhookEndpointNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Webhook not found")
+	case errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		RespondError(c, http.StatusNotFound, "ERR_NOT_FOUND", "Webhook delivery not found")
+	default:
+		RespondError(c, http.StatusInternalServerError, code, err.Error())
+	}
+}
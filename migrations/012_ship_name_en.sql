@@ -0,0 +1,6 @@
+-- Migration: 012_ship_name_en.sql
+-- Description: Add English name to ship for bilingual matching and search
+-- Created: 2026-01-22
+
+ALTER TABLE ship
+    ADD COLUMN name_en VARCHAR(100) NULL AFTER name;
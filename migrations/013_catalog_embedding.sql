@@ -0,0 +1,20 @@
+-- Migration: 013_catalog_embedding.sql
+-- Description: Create catalog_embedding table for semantic matching of cabin types, ships and categories
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS catalog_embedding (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    entity_type VARCHAR(50) NOT NULL COMMENT 'Entity type: cabin_type, ship, cabin_category',
+    entity_id BIGINT UNSIGNED NOT NULL,
+    model VARCHAR(100) NOT NULL COMMENT 'Embedding model; vectors of different models are not comparable',
+    content VARCHAR(1000) NOT NULL COMMENT 'Text that was embedded',
+    content_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of content, used to skip unchanged entities',
+    dimensions INT UNSIGNED NOT NULL,
+    vector JSON NOT NULL COMMENT 'Array of floats',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_embedding_entity_model (entity_type, entity_id, model),
+    INDEX idx_embedding_model_type (model, entity_type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
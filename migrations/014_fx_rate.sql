@@ -0,0 +1,20 @@
+-- Migration: 014_fx_rate.sql
+-- Description: Create fx_rate table for converting quotes into a display currency
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS fx_rate (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    rate_date DATE NOT NULL COMMENT 'Date from which the rate is in effect',
+    base_currency CHAR(3) NOT NULL COMMENT 'ISO 4217 code; 1 unit of base = rate units of quote',
+    quote_currency CHAR(3) NOT NULL COMMENT 'ISO 4217 code',
+    rate DECIMAL(20, 10) NOT NULL,
+    source ENUM('MANUAL', 'CSV', 'ECB_XML') NOT NULL DEFAULT 'MANUAL',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    created_by BIGINT UNSIGNED NULL,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_fx_rate_pair_date (base_currency, quote_currency, rate_date),
+    INDEX idx_fx_rate_date (rate_date),
+    CONSTRAINT fk_fx_rate_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
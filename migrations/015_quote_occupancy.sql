@@ -0,0 +1,23 @@
+-- Migration: 015_quote_occupancy.sql
+-- Description: Add occupancy pricing (per guest slot rates, single supplement, child rates) to price quotes
+-- Created: 2026-01-22
+
+ALTER TABLE price_quote
+    ADD COLUMN max_occupancy TINYINT UNSIGNED NULL COMMENT 'Maximum guests the quoted cabin sleeps' AFTER guest_count,
+    ADD COLUMN single_supplement_pct DECIMAL(6, 2) NULL COMMENT 'Single occupancy surcharge as % of the per-person fare' AFTER max_occupancy,
+    ADD COLUMN single_supplement_amount DECIMAL(12, 2) NULL COMMENT 'Single occupancy surcharge as a fixed amount' AFTER single_supplement_pct;
+
+CREATE TABLE IF NOT EXISTS price_quote_occupancy_rate (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    quote_id BIGINT UNSIGNED NOT NULL,
+    guest_slot TINYINT UNSIGNED NOT NULL COMMENT '1-based berth position in the cabin, e.g. 3 = third guest',
+    guest_type ENUM('ADULT', 'CHILD') NOT NULL DEFAULT 'ADULT',
+    price DECIMAL(12, 2) NOT NULL COMMENT 'Per-guest price in the quote currency',
+    max_child_age TINYINT UNSIGNED NULL COMMENT 'Upper age limit of a child rate',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_occupancy_quote_slot_type (quote_id, guest_slot, guest_type),
+    CONSTRAINT fk_occupancy_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE,
+    CONSTRAINT chk_occupancy_price CHECK (price >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
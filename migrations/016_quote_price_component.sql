@@ -0,0 +1,20 @@
+-- Migration: 016_quote_price_component.sql
+-- Description: Add price component lines (base fare, port/tax, gratuity, fuel surcharge, other) to price quotes
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS price_quote_component (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    quote_id BIGINT UNSIGNED NOT NULL,
+    component_type ENUM('BASE_FARE', 'PORT_TAX', 'GRATUITY', 'FUEL_SURCHARGE', 'OTHER') NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL COMMENT 'Amount in the quote currency',
+    pricing_unit ENUM('PER_PERSON', 'PER_CABIN', 'TOTAL') NOT NULL,
+    per_night BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Amount is charged per night, e.g. daily gratuities',
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Amount is already included in the quoted price',
+    description VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    INDEX idx_component_quote (quote_id),
+    CONSTRAINT fk_component_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE,
+    CONSTRAINT chk_component_amount CHECK (amount >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
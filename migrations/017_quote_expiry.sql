@@ -0,0 +1,10 @@
+-- Migration: 017_quote_expiry.sql
+-- Description: Add EXPIRED quote status for quotes past valid_until; allow system audit entries without a user
+-- Created: 2026-01-22
+
+ALTER TABLE price_quote
+    MODIFY COLUMN status ENUM('ACTIVE', 'VOIDED', 'CORRECTED', 'EXPIRED') NOT NULL DEFAULT 'ACTIVE',
+    ADD INDEX idx_quote_status_valid_until (status, valid_until);
+
+ALTER TABLE audit_log
+    MODIFY COLUMN user_id BIGINT UNSIGNED NULL COMMENT 'NULL for system actions such as the quote expiry sweeper';
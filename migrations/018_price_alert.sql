@@ -0,0 +1,53 @@
+-- Migration: 018_price_alert.sql
+-- Description: Create alert_rule and price_alert tables for price-drop and threshold alerts
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS alert_rule (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT 'Owner who receives the alerts',
+    name VARCHAR(100) NOT NULL,
+    sailing_id BIGINT UNSIGNED NULL COMMENT 'Watched sailing; exactly one of sailing_id and ship_id is set',
+    ship_id BIGINT UNSIGNED NULL COMMENT 'Watched ship, covering all its sailings',
+    cabin_type_id BIGINT UNSIGNED NULL COMMENT 'Optional cabin type filter',
+    cabin_category_id BIGINT UNSIGNED NULL COMMENT 'Optional cabin category filter',
+    condition_type ENUM('BELOW_PRICE', 'DROP_PERCENT', 'NEW_LOWEST_SUPPLIER') NOT NULL,
+    threshold DECIMAL(12, 2) NULL COMMENT 'Per-person price for BELOW_PRICE, percentage for DROP_PERCENT',
+    currency CHAR(3) NULL COMMENT 'Currency of a BELOW_PRICE threshold',
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    INDEX idx_alert_rule_user (user_id),
+    INDEX idx_alert_rule_sailing (sailing_id, is_enabled),
+    INDEX idx_alert_rule_ship (ship_id, is_enabled),
+    CONSTRAINT fk_alert_rule_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_alert_rule_sailing FOREIGN KEY (sailing_id) REFERENCES sailing(id) ON DELETE CASCADE,
+    CONSTRAINT fk_alert_rule_ship FOREIGN KEY (ship_id) REFERENCES ship(id) ON DELETE CASCADE,
+    CONSTRAINT fk_alert_rule_cabin_type FOREIGN KEY (cabin_type_id) REFERENCES cabin_type(id) ON DELETE CASCADE,
+    CONSTRAINT fk_alert_rule_category FOREIGN KEY (cabin_category_id) REFERENCES cabin_category(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS price_alert (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    rule_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    quote_id BIGINT UNSIGNED NOT NULL COMMENT 'Quote that triggered the alert',
+    sailing_id BIGINT UNSIGNED NOT NULL,
+    cabin_type_id BIGINT UNSIGNED NOT NULL,
+    supplier_id BIGINT UNSIGNED NOT NULL,
+    condition_type ENUM('BELOW_PRICE', 'DROP_PERCENT', 'NEW_LOWEST_SUPPLIER') NOT NULL,
+    price DECIMAL(12, 2) NOT NULL COMMENT 'Per-person price of the quote in currency',
+    previous_price DECIMAL(12, 2) NULL COMMENT 'Per-person price compared against, in currency',
+    currency CHAR(3) NOT NULL,
+    message VARCHAR(500) NOT NULL,
+    acknowledged_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_price_alert_rule_quote (rule_id, quote_id),
+    INDEX idx_price_alert_user (user_id, acknowledged_at, created_at),
+    CONSTRAINT fk_price_alert_rule FOREIGN KEY (rule_id) REFERENCES alert_rule(id) ON DELETE CASCADE,
+    CONSTRAINT fk_price_alert_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_price_alert_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
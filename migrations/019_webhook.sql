@@ -0,0 +1,44 @@
+-- Migration: 019_webhook.sql
+-- Description: Create webhook_endpoint and webhook_delivery tables for outbound event webhooks
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS webhook_endpoint (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256 signing key',
+    events JSON NOT NULL COMMENT 'Array of subscribed event types',
+    supplier_ids JSON NULL COMMENT 'Array of supplier IDs to filter on; NULL for every supplier',
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    created_by BIGINT UNSIGNED NULL,
+    
+    PRIMARY KEY (id),
+    INDEX idx_webhook_endpoint_enabled (is_enabled),
+    CONSTRAINT fk_webhook_endpoint_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    endpoint_id BIGINT UNSIGNED NOT NULL,
+    event_id CHAR(36) NOT NULL COMMENT 'Event UUID, shared by redeliveries',
+    event_type VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL COMMENT 'Event envelope as sent',
+    status ENUM('PENDING', 'SUCCEEDED', 'FAILED') NOT NULL DEFAULT 'PENDING',
+    attempt_count INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NULL COMMENT 'Due time of the next attempt while PENDING',
+    last_attempt_at TIMESTAMP NULL,
+    response_status INT NULL COMMENT 'HTTP status of the last attempt',
+    response_body VARCHAR(1000) NULL COMMENT 'Truncated response body of the last attempt',
+    last_error VARCHAR(1000) NULL,
+    delivered_at TIMESTAMP NULL,
+    redelivery_of BIGINT UNSIGNED NULL COMMENT 'Delivery this one manually redelivers',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    INDEX idx_webhook_delivery_due (status, next_attempt_at),
+    INDEX idx_webhook_delivery_endpoint (endpoint_id, created_at),
+    INDEX idx_webhook_delivery_event (event_id),
+    CONSTRAINT fk_webhook_delivery_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoint(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,29 @@
+-- Migration: 020_domain_event.sql
+-- Description: Create domain_event outbox and domain_event_cursor tables for in-process event subscribers
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS domain_event (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'Monotonic position used as consumption cursor',
+    event_id CHAR(36) NOT NULL COMMENT 'Event UUID',
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id BIGINT UNSIGNED NOT NULL,
+    supplier_id BIGINT UNSIGNED NULL COMMENT 'Supplier the event belongs to',
+    user_id BIGINT UNSIGNED NULL COMMENT 'User who caused the event; NULL for system actions',
+    payload JSON NOT NULL,
+    trace_id VARCHAR(64) NULL,
+    occurred_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_domain_event_event_id (event_id),
+    INDEX idx_domain_event_aggregate (aggregate_type, aggregate_id),
+    INDEX idx_domain_event_type (event_type, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS domain_event_cursor (
+    subscriber VARCHAR(100) NOT NULL,
+    last_event_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Last domain_event.id handled by the subscriber',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (subscriber)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
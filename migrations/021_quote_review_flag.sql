@@ -0,0 +1,24 @@
+-- Migration: 021_quote_review_flag.sql
+-- Description: Create quote_review_flag table for quotes flagged as statistical anomalies
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS quote_review_flag (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    quote_id BIGINT UNSIGNED NOT NULL,
+    sailing_id BIGINT UNSIGNED NOT NULL,
+    cabin_type_id BIGINT UNSIGNED NOT NULL,
+    supplier_id BIGINT UNSIGNED NOT NULL,
+    reasons JSON NOT NULL COMMENT 'Array of failed anomaly checks with message and reference price',
+    status ENUM('OPEN', 'DISMISSED', 'CONFIRMED') NOT NULL DEFAULT 'OPEN',
+    review_note VARCHAR(500) NULL,
+    reviewed_by BIGINT UNSIGNED NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_quote_review_flag_quote (quote_id),
+    INDEX idx_quote_review_flag_status (status, created_at),
+    INDEX idx_quote_review_flag_supplier (supplier_id, status),
+    CONSTRAINT fk_quote_review_flag_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE,
+    CONSTRAINT fk_quote_review_flag_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,22 @@
+-- Migration: 022_quote_confirmation.sql
+-- Description: Record unchanged resubmissions of a quote as confirmations instead of new price_quote rows
+-- Created: 2026-01-22
+
+ALTER TABLE price_quote
+    ADD COLUMN last_confirmed_at TIMESTAMP NULL COMMENT 'Last time the supplier resubmitted the quote unchanged' AFTER created_by,
+    ADD COLUMN confirmation_count INT NOT NULL DEFAULT 0 AFTER last_confirmed_at;
+
+CREATE TABLE IF NOT EXISTS quote_confirmation (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    quote_id BIGINT UNSIGNED NOT NULL,
+    source ENUM('MANUAL', 'FILE_IMPORT', 'TEXT_IMPORT', 'TEMPLATE_IMPORT') NOT NULL,
+    source_ref VARCHAR(255) NULL,
+    import_job_id BIGINT UNSIGNED NULL,
+    confirmed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by BIGINT UNSIGNED NOT NULL,
+    
+    PRIMARY KEY (id),
+    INDEX idx_confirmation_quote_confirmed (quote_id, confirmed_at),
+    CONSTRAINT fk_confirmation_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE,
+    CONSTRAINT fk_confirmation_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
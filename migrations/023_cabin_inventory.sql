@@ -0,0 +1,22 @@
+-- Migration: 023_cabin_inventory.sql
+-- Description: Create cabin_inventory_report table for supplier-reported sold out / no allocation states
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS cabin_inventory_report (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    sailing_id BIGINT UNSIGNED NOT NULL,
+    cabin_type_id BIGINT UNSIGNED NOT NULL,
+    supplier_id BIGINT UNSIGNED NOT NULL,
+    state ENUM('SOLD_OUT', 'NO_ALLOCATION') NOT NULL COMMENT 'Held cabins are gone, or the supplier holds none for this cabin',
+    note VARCHAR(500) NULL,
+    reported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by BIGINT UNSIGNED NOT NULL,
+    
+    PRIMARY KEY (id),
+    INDEX idx_inventory_report_sailing (sailing_id, cabin_type_id, supplier_id, reported_at),
+    INDEX idx_inventory_report_supplier (supplier_id, reported_at),
+    CONSTRAINT fk_inventory_report_sailing FOREIGN KEY (sailing_id) REFERENCES sailing(id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventory_report_cabin_type FOREIGN KEY (cabin_type_id) REFERENCES cabin_type(id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventory_report_supplier FOREIGN KEY (supplier_id) REFERENCES supplier(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_report_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
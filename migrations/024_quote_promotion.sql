@@ -0,0 +1,25 @@
+-- Migration: 024_quote_promotion.sql
+-- Description: Add structured promotions (type, discount, guest slots, booking window, stackability) to price quotes
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS price_quote_promotion (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    quote_id BIGINT UNSIGNED NOT NULL,
+    promotion_type ENUM('EARLY_BIRD', 'LAST_MINUTE', 'GUEST_DISCOUNT', 'FLASH_SALE', 'MEMBER', 'ONBOARD_CREDIT', 'OTHER') NOT NULL,
+    discount_pct DECIMAL(5, 2) NULL COMMENT 'Percentage off the fare of each applicable guest',
+    discount_amount DECIMAL(12, 2) NULL COMMENT 'Amount off in the quote currency',
+    pricing_unit ENUM('PER_PERSON', 'PER_CABIN', 'TOTAL') NOT NULL COMMENT 'PER_PERSON amounts apply per applicable guest, others once per cabin',
+    guest_slots JSON NULL COMMENT 'Array of 1-based guest slots the discount applies to, NULL for every guest',
+    booking_from DATE NULL,
+    booking_until DATE NULL,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Combinable with the other promotions of the quote',
+    description VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (id),
+    INDEX idx_promotion_quote (quote_id),
+    INDEX idx_promotion_type (promotion_type, quote_id),
+    CONSTRAINT fk_promotion_quote FOREIGN KEY (quote_id) REFERENCES price_quote(id) ON DELETE CASCADE,
+    CONSTRAINT chk_promotion_pct CHECK (discount_pct IS NULL OR (discount_pct > 0 AND discount_pct <= 100)),
+    CONSTRAINT chk_promotion_amount CHECK (discount_amount IS NULL OR discount_amount >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,42 @@
+-- Migration: 025_port_itinerary.sql
+-- Description: Create port catalog and structured day-by-day sailing itineraries
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS port (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    name_en VARCHAR(100) NULL,
+    aliases JSON NULL COMMENT 'Array of alternative names, e.g. 上海吴淞口, Shanghai',
+    country VARCHAR(100) NULL,
+    unlocode CHAR(5) NULL COMMENT 'UN/LOCODE without the space, e.g. CNSHA',
+    status ENUM('ACTIVE', 'INACTIVE') NOT NULL DEFAULT 'ACTIVE',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    created_by BIGINT UNSIGNED NULL,
+    
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_port_name (name),
+    UNIQUE KEY idx_port_unlocode (unlocode),
+    INDEX idx_port_country (country),
+    INDEX idx_port_status (status),
+    CONSTRAINT fk_port_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS sailing_itinerary (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    sailing_id BIGINT UNSIGNED NOT NULL,
+    day_number INT NOT NULL COMMENT '1 is the departure day',
+    port_id BIGINT UNSIGNED NULL COMMENT 'NULL on sea days',
+    arrive_time TIME NULL COMMENT 'Local time, NULL on the departure day',
+    depart_time TIME NULL COMMENT 'Local time, NULL on the return day',
+    is_sea_day BOOLEAN NOT NULL DEFAULT FALSE,
+    note VARCHAR(255) NULL,
+    
+    PRIMARY KEY (id),
+    INDEX idx_itinerary_sailing_day (sailing_id, day_number),
+    INDEX idx_itinerary_port (port_id, sailing_id),
+    CONSTRAINT fk_itinerary_sailing FOREIGN KEY (sailing_id) REFERENCES sailing(id) ON DELETE CASCADE,
+    CONSTRAINT fk_itinerary_port FOREIGN KEY (port_id) REFERENCES port(id) ON DELETE RESTRICT,
+    CONSTRAINT chk_itinerary_day CHECK (day_number > 0),
+    CONSTRAINT chk_itinerary_port CHECK ((is_sea_day AND port_id IS NULL) OR (NOT is_sea_day AND port_id IS NOT NULL))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
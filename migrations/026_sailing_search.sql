@@ -0,0 +1,10 @@
+-- Migration: 026_sailing_search.sql
+-- Description: Add indexes backing the sailing search (date/nights filters, quoted-by-supplier filter); the latest price index needs quoted_at and is added in 028
+-- Created: 2026-01-22
+
+ALTER TABLE sailing
+    ADD INDEX idx_sailing_status_departure (status, departure_date),
+    ADD INDEX idx_sailing_nights (nights, departure_date);
+
+ALTER TABLE price_quote
+    ADD INDEX idx_quote_supplier_sailing (supplier_id, sailing_id, status);
@@ -0,0 +1,41 @@
+-- Migration: 027_quote_status_changed_at.sql
+-- Description: Record when a quote's status last changed, so comparisons can be replayed as of a past time including quotes later voided or corrected
+-- Created: 2026-01-22
+
+ALTER TABLE price_quote
+    ADD COLUMN status_changed_at TIMESTAMP NULL COMMENT 'When the status last changed; for VOIDED and CORRECTED quotes, when they were withdrawn' AFTER status,
+    ADD INDEX idx_quote_sailing_created (sailing_id, created_at);
+
+-- Backfill withdrawn and expired quotes from the domain event outbox
+UPDATE price_quote q
+    JOIN (
+        SELECT aggregate_id AS quote_id, MAX(occurred_at) AS changed_at
+        FROM domain_event
+        WHERE event_type IN ('quote.voided', 'quote.expired')
+        GROUP BY aggregate_id
+    ) e ON e.quote_id = q.id
+SET q.status_changed_at = e.changed_at
+WHERE q.status IN ('VOIDED', 'EXPIRED');
+
+-- quote.corrected events belong to the replacement and name the corrected quote in their payload
+UPDATE price_quote q
+    JOIN (
+        SELECT CAST(JSON_UNQUOTE(JSON_EXTRACT(payload, '$.corrected_quote_id')) AS UNSIGNED) AS quote_id,
+            MAX(occurred_at) AS changed_at
+        FROM domain_event
+        WHERE event_type = 'quote.corrected'
+        GROUP BY quote_id
+    ) e ON e.quote_id = q.id
+SET q.status_changed_at = e.changed_at
+WHERE q.status = 'CORRECTED';
+
+-- Quotes changed before the outbox existed fall back to their latest audited update
+UPDATE price_quote q
+    JOIN (
+        SELECT entity_id AS quote_id, MAX(created_at) AS changed_at
+        FROM audit_log
+        WHERE entity_type = 'PriceQuote' AND action IN ('UPDATE', 'VOID', 'EXPIRE')
+        GROUP BY entity_id
+    ) a ON a.quote_id = q.id
+SET q.status_changed_at = a.changed_at
+WHERE q.status <> 'ACTIVE' AND q.status_changed_at IS NULL;
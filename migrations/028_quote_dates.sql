@@ -0,0 +1,14 @@
+-- Migration: 028_quote_dates.sql
+-- Description: Add quoted_at (when the supplier issued the quote) and valid_from (first day the price applies), separate from created_at; index the latest current quote by quote date
+-- Created: 2026-01-22
+
+ALTER TABLE price_quote
+    ADD COLUMN valid_from DATE NULL COMMENT 'First day the price applies; NULL = as soon as it is quoted' AFTER cabin_quantity,
+    ADD COLUMN quoted_at TIMESTAMP NULL COMMENT 'When the supplier issued the quote, from the document; created_at is when it was entered' AFTER valid_until;
+
+UPDATE price_quote SET quoted_at = created_at WHERE quoted_at IS NULL;
+
+ALTER TABLE price_quote
+    MODIFY COLUMN quoted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the supplier issued the quote, from the document; created_at is when it was entered',
+    ADD INDEX idx_quote_trend (sailing_id, cabin_type_id, quoted_at),
+    ADD INDEX idx_quote_current_latest (status, sailing_id, cabin_type_id, supplier_id, quoted_at, created_at, id);
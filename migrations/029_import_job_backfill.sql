@@ -0,0 +1,16 @@
+-- Migration: 029_import_job_backfill.sql
+-- Description: Let import jobs carry the supplier and default quote date of their file and mark backfills of archived price lists, whose quotes and confirmations get the BACKFILL source and raise no alerts or webhooks; index file_hash for duplicate detection
+-- Created: 2026-01-22
+
+ALTER TABLE import_job
+    ADD COLUMN supplier_id BIGINT UNSIGNED NULL COMMENT 'Supplier that issued the file; NULL = supplier of the creating user' AFTER file_path,
+    ADD COLUMN quoted_at DATE NULL COMMENT 'Quote date of the file when the document does not state one, e.g. inferred from an archive path' AFTER supplier_id,
+    ADD COLUMN backfill BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Archived price list imported by the backfill command' AFTER quoted_at,
+    ADD INDEX idx_import_job_file_hash (file_hash),
+    ADD CONSTRAINT fk_import_job_supplier FOREIGN KEY (supplier_id) REFERENCES supplier(id) ON DELETE SET NULL;
+
+ALTER TABLE price_quote
+    MODIFY COLUMN source ENUM('MANUAL', 'FILE_IMPORT', 'TEXT_IMPORT', 'TEMPLATE_IMPORT', 'BACKFILL') NOT NULL;
+
+ALTER TABLE quote_confirmation
+    MODIFY COLUMN source ENUM('MANUAL', 'FILE_IMPORT', 'TEXT_IMPORT', 'TEMPLATE_IMPORT', 'BACKFILL') NOT NULL;
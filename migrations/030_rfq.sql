@@ -0,0 +1,52 @@
+-- Migration: 030_rfq.sql
+-- Description: Create rfq, rfq_item and rfq_supplier tables for requests for quote issued to chosen suppliers; link quotes to the RFQ they answer
+-- Created: 2026-01-22
+
+CREATE TABLE IF NOT EXISTS rfq (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    title VARCHAR(200) NOT NULL,
+    notes TEXT NULL,
+    cabin_quantity INT NOT NULL COMMENT 'Group size: cabins requested per cabin type',
+    deadline TIMESTAMP NOT NULL COMMENT 'Suppliers respond until then',
+    status ENUM('OPEN', 'CLOSED', 'CANCELLED') NOT NULL DEFAULT 'OPEN',
+    closed_at TIMESTAMP NULL COMMENT 'When the RFQ was closed or cancelled',
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (id),
+    INDEX idx_rfq_status_deadline (status, deadline),
+    CONSTRAINT fk_rfq_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS rfq_item (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    rfq_id BIGINT UNSIGNED NOT NULL,
+    sailing_id BIGINT UNSIGNED NOT NULL,
+    cabin_type_id BIGINT UNSIGNED NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_rfq_item_cabin (rfq_id, sailing_id, cabin_type_id),
+    CONSTRAINT fk_rfq_item_rfq FOREIGN KEY (rfq_id) REFERENCES rfq(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rfq_item_sailing FOREIGN KEY (sailing_id) REFERENCES sailing(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rfq_item_cabin_type FOREIGN KEY (cabin_type_id) REFERENCES cabin_type(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS rfq_supplier (
+    rfq_id BIGINT UNSIGNED NOT NULL,
+    supplier_id BIGINT UNSIGNED NOT NULL,
+    declined_at TIMESTAMP NULL COMMENT 'When the supplier declined to quote',
+    decline_reason VARCHAR(500) NULL,
+    reminded_at TIMESTAMP NULL COMMENT 'When the last deadline reminder was sent',
+    reminder_count INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (rfq_id, supplier_id),
+    INDEX idx_rfq_supplier_supplier (supplier_id),
+    CONSTRAINT fk_rfq_supplier_rfq FOREIGN KEY (rfq_id) REFERENCES rfq(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rfq_supplier_supplier FOREIGN KEY (supplier_id) REFERENCES supplier(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE price_quote
+    ADD COLUMN rfq_id BIGINT UNSIGNED NULL COMMENT 'RFQ the quote answers' AFTER import_job_id,
+    ADD INDEX idx_price_quote_rfq (rfq_id, supplier_id),
+    ADD CONSTRAINT fk_price_quote_rfq FOREIGN KEY (rfq_id) REFERENCES rfq(id) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+-- Migration: 031_quote_approval.sql
+-- Description: Add a per-supplier approval policy; quotes of such suppliers start as PENDING_REVIEW and are approved (ACTIVE) or REJECTED by an admin
+-- Created: 2026-01-22
+
+ALTER TABLE supplier
+    ADD COLUMN requires_approval BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'New quotes wait for admin approval before publication' AFTER visibility;
+
+ALTER TABLE price_quote
+    MODIFY COLUMN status ENUM('PENDING_REVIEW', 'ACTIVE', 'VOIDED', 'CORRECTED', 'EXPIRED', 'REJECTED') NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN reviewed_by BIGINT UNSIGNED NULL COMMENT 'Admin who approved or rejected the quote' AFTER status_changed_at,
+    ADD COLUMN reviewed_at TIMESTAMP NULL COMMENT 'When the quote was approved or rejected' AFTER reviewed_by,
+    ADD COLUMN review_reason VARCHAR(500) NULL AFTER reviewed_at,
+    ADD INDEX idx_price_quote_status_created (status, created_at),
+    ADD CONSTRAINT fk_price_quote_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES users(id);
@@ -26,13 +26,15 @@ export interface PriceQuote {
   guestCount?: number
   promotion?: string
   cabinQuantity?: number
+  validFrom?: string
   validUntil?: string
+  quotedAt: string
   notes?: string
   // Source tracking
   source: string
   sourceRef?: string
   importJobId?: number
-  status: 'ACTIVE' | 'VOIDED' | 'CORRECTED'
+  status: 'ACTIVE' | 'VOIDED' | 'CORRECTED' | 'EXPIRED'
   createdAt: string
   createdBy: number
 }
@@ -47,7 +49,9 @@ export interface CreateQuoteInput {
   guestCount?: number
   promotion?: string
   cabinQuantity?: number
+  validFrom?: string
   validUntil?: string
+  quotedAt?: string
   notes?: string
   idempotencyKey?: string
 }
@@ -87,7 +91,9 @@ export const useQuoteStore = defineStore('quote', () => {
           guest_count: input.guestCount,
           promotion: input.promotion,
           cabin_quantity: input.cabinQuantity,
+          valid_from: input.validFrom,
           valid_until: input.validUntil,
+          quoted_at: input.quotedAt,
           notes: input.notes,
           idempotency_key: input.idempotencyKey,
         }),
@@ -239,7 +245,9 @@ export const useQuoteStore = defineStore('quote', () => {
       guestCount: data.guest_count,
       promotion: data.promotion,
       cabinQuantity: data.cabin_quantity,
+      validFrom: data.valid_from,
       validUntil: data.valid_until,
+      quotedAt: data.quoted_at,
       notes: data.notes,
       source: data.source,
       sourceRef: data.source_ref,